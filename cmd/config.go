@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"bytes"
+	e "errors"
+	"fmt"
+	"os"
+
+	"fiber-admin/internal/pkg/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// configCmd groups the commands working on the config file. They read the config file themselves, so that an invalid
+// one can be reported rather than stopping them.
+var configCmd = &cobra.Command{
+	Use:              "config",
+	Short:            "Manage the config file",
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+}
+
+// validateConfigCmd validates a config file
+var validateConfigCmd = &cobra.Command{
+	Use:   "validate [file]",
+	Short: "Validate a config file",
+	Long: `Read a config file, the one of the --config flag by default, and report every invalid value of it along
+with the FIBER_ADMIN_* environment variables and the flags. Exits with status 1 when the config is not valid.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			configFile = args[0]
+		}
+		v := viper.New()
+		cfg := config.Default()
+		if err := readConfig(v, cfg, cmd.Flags()); err != nil {
+			fmt.Printf("Failed to read config file: %s\n", err)
+			os.Exit(1)
+		}
+
+		source := "Config"
+		if _, err := os.Stat(v.ConfigFileUsed()); err == nil {
+			source = fmt.Sprintf("Config file %s", v.ConfigFileUsed())
+		}
+		var errs config.ValidationErrors
+		if err := config.Validate(cfg); e.As(err, &errs) {
+			fmt.Printf("%s is not valid:\n", source)
+			for _, err := range errs {
+				fmt.Printf("  %s\n", err)
+			}
+			os.Exit(1)
+		} else if err != nil {
+			cobra.CheckErr(err)
+		}
+		fmt.Printf("%s is valid\n", source)
+	},
+}
+
+// printConfigCmd prints the effective config
+var printConfigCmd = &cobra.Command{
+	Use:   "print",
+	Short: "Print the effective config with secrets masked",
+	Long: `Print the config the server would run with: the defaults, merged with the config file, the FIBER_ADMIN_*
+environment variables and the flags.
+Passwords and secrets are masked. The invalid values, if any, are reported on stderr.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.Default()
+		cobra.CheckErr(readConfig(viper.New(), cfg, cmd.Flags()))
+		if err := config.Validate(cfg); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+
+		var buffer bytes.Buffer
+		encoder := yaml.NewEncoder(&buffer)
+		encoder.SetIndent(2)
+		cobra.CheckErr(encoder.Encode(config.Masked(cfg)))
+		cobra.CheckErr(encoder.Close())
+		fmt.Print(buffer.String())
+	},
+}
+
+// defaultConfigCmd prints the default config
+var defaultConfigCmd = &cobra.Command{
+	Use:   "default",
+	Short: "Print the default config file",
+	Long:  `Print a config file holding the default values, with every key commented, e.g. to start a new one from.`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		defaultYAML, err := config.DefaultYAML()
+		cobra.CheckErr(err)
+		fmt.Print(string(defaultYAML))
+	},
+}
+
+func init() {
+	configCmd.AddCommand(validateConfigCmd)
+	configCmd.AddCommand(printConfigCmd)
+	configCmd.AddCommand(defaultConfigCmd)
+	rootCmd.AddCommand(configCmd)
+}
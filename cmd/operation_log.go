@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"fiber-admin/internal/pkg/wire"
+	"github.com/spf13/cobra"
+)
+
+// operationLogCmd groups the commands working on the operation log
+var operationLogCmd = &cobra.Command{
+	Use:   "operation-log",
+	Short: "Manage the operation log",
+}
+
+// verifyOperationLogCmd verifies the operation log hash chain
+var verifyOperationLogCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify the operation log hash chain",
+	Long: `Walk the operation log hash chain and the signed checkpoints, and report the first broken link.
+Exits with status 1 when the chain is broken.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		logsService, err := wire.InitializeLogsService(ctx)
+		cobra.CheckErr(err)
+
+		resp, err := logsService.VerifyOperationLogChain(ctx)
+		cobra.CheckErr(err)
+
+		fmt.Printf(
+			"Checked %d records up to sequence %d against %d checkpoints\n",
+			resp.Checked, resp.LastSequence, resp.Checkpoints,
+		)
+		if resp.Valid {
+			fmt.Println("Operation log chain is intact")
+			return
+		}
+		fmt.Printf(
+			"Operation log chain is broken at sequence %d: %s\n", resp.BrokenLink.Sequence, resp.BrokenLink.Reason,
+		)
+		if resp.BrokenLink.OperationLogID != "" {
+			fmt.Printf("Operation log ID: %s\n", resp.BrokenLink.OperationLogID)
+		}
+		os.Exit(1)
+	},
+}
+
+func init() {
+	operationLogCmd.AddCommand(verifyOperationLogCmd)
+	rootCmd.AddCommand(operationLogCmd)
+}
@@ -2,39 +2,43 @@ package cmd
 
 import (
 	"context"
+	e "errors"
 	"fmt"
 	"os"
 
 	"fiber-admin/internal/app"
 	"fiber-admin/internal/pkg/config"
 	"fiber-admin/internal/pkg/wire"
-	"fiber-admin/pkg/jwt"
-	"fiber-admin/pkg/mongo"
-	"fiber-admin/pkg/redis"
-	"fiber-admin/pkg/utils/check"
-	logging "fiber-admin/pkg/zap"
 	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 )
 
 var (
-	configFile  string // config file path
-	port        string // port to listen on (default is 3000)
-	host        string // host to listen on (default is localhost)
-	logLevel    string // log level (default is info)
-	tls         bool   // enable tls
-	tlsCertFile string // tls cert file path
-	tlsKeyFile  string // tls key file path (default is "")
-	fiberApp    *app.App
+	configFile string // config file path
+	fiberApp   *app.App
 )
 
+// configFlags are the config keys the flags override
+var configFlags = map[string]string{
+	"port":          "base.app_port",
+	"host":          "base.app_host",
+	"log-level":     "zap.zap_level",
+	"tls":           "base.enable_tls",
+	"tls-cert-file": "base.cert_file",
+	"tls-key-file":  "base.key_file",
+}
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "fiber-admin",
 	Short: "Fiber Admin Server",
 	Long:  `Fiber Admin Server is a web server that provides APIs for managing users, notices, and documentation.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		initConfig(cmd.Flags())
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		var err error
 		ctx := context.Background()
@@ -44,6 +48,7 @@ var rootCmd = &cobra.Command{
 		if err != nil {
 			panic(err)
 		}
+		watchConfig(ctx, cmd.Flags())
 
 		// Start the app
 		if fiberApp.Config.BaseConfig.EnableTls {
@@ -84,7 +89,6 @@ func Execute() {
 }
 
 func init() {
-	cobra.OnInitialize(initConfig)
 
 	rootCmd.PersistentFlags().StringVar(
 		&configFile,
@@ -92,136 +96,101 @@ func init() {
 		"",
 		"config file",
 	)
-	rootCmd.PersistentFlags().StringVarP(
-		&port,
+	rootCmd.PersistentFlags().StringP(
 		"port",
 		"p",
 		"",
 		"port to listen on (default is 8080)",
 	)
-	rootCmd.PersistentFlags().StringVarP(
-		&host,
+	rootCmd.PersistentFlags().StringP(
 		"host",
 		"H",
 		"",
 		"host to listen on (default is localhost)",
 	)
-	rootCmd.PersistentFlags().StringVarP(
-		&logLevel,
+	rootCmd.PersistentFlags().StringP(
 		"log-level",
 		"l",
-		"info",
+		"",
 		"log level (default is info)",
 	)
-	rootCmd.PersistentFlags().BoolVar(
-		&tls,
+	rootCmd.PersistentFlags().Bool(
 		"tls",
 		false,
 		"enable tls (default is false)",
 	)
-	rootCmd.PersistentFlags().StringVar(
-		&tlsCertFile,
+	rootCmd.PersistentFlags().String(
 		"tls-cert-file",
 		"",
 		"tls cert file path (default is \"\")",
 	)
-	rootCmd.PersistentFlags().StringVar(
-		&tlsKeyFile,
+	rootCmd.PersistentFlags().String(
 		"tls-key-file",
 		"",
 		"tls key file path (default is \"\")",
 	)
 }
 
-// initConfig creates a new config object and initializes it with the values from the config file and
-// priority: flags > config file > default values
-func initConfig() {
+// initConfig creates a new config object, initializes it with the values from the config file, the environment and
+// the flags, and validates it.
+// priority: flags > environment variables > config file > default values
+func initConfig(flags *pflag.FlagSet) {
 	cfg := config.New()
+	cobra.CheckErr(readConfig(viper.GetViper(), cfg, flags))
+	cobra.CheckErr(config.Validate(cfg))
+	config.Set(cfg)
+}
+
+// watchConfig reloads the config when the config file changes. The config is read as on startup, from the file, the
+// environment and the flags, and applied by the app.
+func watchConfig(ctx context.Context, flags *pflag.FlagSet) {
+	viper.OnConfigChange(
+		func(in fsnotify.Event) {
+			fiberApp.ReloadConfig(
+				ctx, in.Name, func(cfg *config.Config) error {
+					return unmarshalConfig(viper.GetViper(), cfg, flags)
+				},
+			)
+		},
+	)
+	viper.WatchConfig()
+}
+
+// readConfig reads the config file of the flag, or ~/.fiber-admin.yaml, into cfg, with the environment variables and
+// the flags over it. ~/.fiber-admin.yaml may be missing, e.g. when the config is set by the environment.
+func readConfig(v *viper.Viper, cfg *config.Config, flags *pflag.FlagSet) error {
 	if configFile != "" {
 		// Use config file from the flag.
-		viper.SetConfigFile(configFile)
+		v.SetConfigFile(configFile)
 	} else {
 		// Find home directory.
-		var home string
 		home, err := os.UserHomeDir()
-		cobra.CheckErr(err)
+		if err != nil {
+			return err
+		}
 
-		viper.AddConfigPath(home)
-		viper.SetConfigType("yaml")
-		viper.SetConfigName(".fiber-admin")
+		v.AddConfigPath(home)
+		v.SetConfigType("yaml")
+		v.SetConfigName(".fiber-admin")
 	}
 
 	// If a config file is found, read it in.
-	err := viper.ReadInConfig()
-	cobra.CheckErr(err)
-	err = viper.Unmarshal(cfg)
-	cobra.CheckErr(err)
-	if port != "" {
-		if !check.IsValidAppPort(port) {
-			cobra.CheckErr(fmt.Errorf("invalid port: %s", port))
-		}
-		cfg.BaseConfig.AppPort = port
-	}
-	if host != "" {
-		if !check.IsValidAppHost(host) {
-			cobra.CheckErr(fmt.Errorf("invalid host: %s", host))
-		}
-		cfg.BaseConfig.AppHost = host
-	}
-	if logLevel != "" {
-		if !check.IsValidLogLevel(logLevel) {
-			cobra.CheckErr(fmt.Errorf("invalid log level: %s", logLevel))
-		}
-		cfg.ZapConfig.Level = logLevel
-	}
-	if tls {
-		cfg.BaseConfig.EnableTls = true
-	}
-	if tlsCertFile != "" {
-		// Check if the tls cert file exists
-		if _, err := os.Stat(tlsCertFile); os.IsNotExist(err) {
-			cobra.CheckErr(fmt.Errorf("tls cert file does not exist: %s", tlsCertFile))
+	if err := v.ReadInConfig(); err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if !e.As(err, &notFound) {
+			return err
 		}
-		cfg.BaseConfig.TlsCertFile = tlsCertFile
 	}
-	if tlsKeyFile != "" {
-		// Check if the tls key file exists
-		if _, err := os.Stat(tlsKeyFile); os.IsNotExist(err) {
-			cobra.CheckErr(fmt.Errorf("tls key file does not exist: %s", tlsKeyFile))
+	return unmarshalConfig(v, cfg, flags)
+}
+
+// unmarshalConfig unmarshals the config read by v into cfg, with the environment variables and the flags over it. The
+// values are checked by config.Validate.
+func unmarshalConfig(v *viper.Viper, cfg *config.Config, flags *pflag.FlagSet) error {
+	for flag, key := range configFlags {
+		if err := v.BindPFlag(key, flags.Lookup(flag)); err != nil {
+			return err
 		}
-		cfg.BaseConfig.TlsKeyFile = tlsKeyFile
 	}
-	config.Set(cfg)
-	viper.WatchConfig()
-	viper.OnConfigChange(
-		func(in fsnotify.Event) {
-			fmt.Printf("Reloading config file: %s ...\n", in.Name)
-
-			if err := viper.Unmarshal(cfg); err != nil {
-				fmt.Printf("error reading config: %s\n", err)
-			}
-			config.Set(cfg)
-
-			// Reload singletons
-			if err := redis.Update(context.Background(), cfg.CacheConfig.RedisConfig.GetRedisOptions()); err != nil {
-				fmt.Printf("error setting redis: %s\n", err)
-			}
-			if err := logging.Update(cfg.ZapConfig.GetZapConfig()); err != nil {
-				fmt.Printf("error setting zap: %s\n", err)
-			}
-			if err := mongo.Update(
-				context.Background(), cfg.MongoConfig.GetQmgoConfig(), cfg.MongoConfig.PingTimeoutS,
-				cfg.MongoConfig.Database,
-			); err != nil {
-				fmt.Printf("error setting mongo: %s\n", err)
-			}
-			if err := jwt.Update(
-				nil, cfg.JWTConfig.TokenDuration, cfg.JWTConfig.RefreshDuration, cfg.JWTConfig.RefreshBuffer,
-			); err != nil {
-				fmt.Printf("error setting jwt: %s\n", err)
-			}
-
-			fmt.Printf("Config file: %s reloaded\n", in.Name)
-		},
-	)
+	return config.Unmarshal(v, cfg)
 }
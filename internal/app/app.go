@@ -5,13 +5,15 @@ import (
 	"fmt"
 
 	"fiber-admin/internal/pkg/config"
+	"fiber-admin/internal/pkg/dao"
 	"fiber-admin/internal/pkg/errors"
 	"fiber-admin/internal/pkg/middleware"
 	"fiber-admin/internal/pkg/router"
 	"fiber-admin/internal/pkg/tasks"
 	e "fiber-admin/pkg/errors"
+	"fiber-admin/pkg/jwt"
 	"fiber-admin/pkg/mongo"
-	"fiber-admin/pkg/redis"
+	"fiber-admin/pkg/prometheus"
 	logging "fiber-admin/pkg/zap"
 	"github.com/casbin/mongodb-adapter/v3"
 	"github.com/goccy/go-json"
@@ -29,14 +31,17 @@ type App struct {
 	Middleware *middleware.Middleware
 	Tasks      *tasks.Tasks
 	Mongo      *mongo.Mongo
-	Redis      *redis.Redis
+	Cache      dao.CacheBackend
+	Jwt        *jwt.Jwt
+	Prometheus *prometheus.Prometheus
 	Ctx        context.Context
 }
 
 // New factory function that initializes the application and returns a fiber.App instance.
 func New(
 	ctx context.Context, zap *logging.Zap, config *config.Config, router *router.Router,
-	middleware *middleware.Middleware, tasks *tasks.Tasks, mongo *mongo.Mongo, redis *redis.Redis,
+	middleware *middleware.Middleware, tasks *tasks.Tasks, mongo *mongo.Mongo, cache dao.CacheBackend, jwt *jwt.Jwt,
+	prometheus *prometheus.Prometheus,
 ) (*App, error) {
 	app := &App{
 		Zap:        zap,
@@ -45,7 +50,9 @@ func New(
 		Middleware: middleware,
 		Tasks:      tasks,
 		Mongo:      mongo,
-		Redis:      redis,
+		Cache:      cache,
+		Jwt:        jwt,
+		Prometheus: prometheus,
 		Ctx:        ctx,
 	}
 
@@ -124,12 +131,15 @@ func (a *App) Init(ctx context.Context) error {
 	// Register routers
 	a.Router.RegisterRouter(
 		app, c, a.Middleware.IdempotencyMiddleware.IdempotencyMiddleware(),
-		a.Middleware.AuthMiddleware.AuthMiddleware(),
+		a.Middleware.AuthMiddleware.AuthMiddleware(), a.Middleware.OperationLogMiddleware.OperationLog,
 	)
 
 	// Set app
 	a.App = app
 
+	// Apply config reloads
+	a.subscribeConfig()
+
 	// Start scheduled tasks
 	if err := a.Tasks.Start(); err != nil {
 		return err
@@ -0,0 +1,87 @@
+package app
+
+import (
+	"context"
+	"errors"
+
+	"fiber-admin/internal/pkg/config"
+	"fiber-admin/internal/pkg/dao"
+	"go.uber.org/zap"
+)
+
+// subscribeConfig subscribes the components which apply config changes while serving. Those reaching out to Redis or
+// MongoDB come first, as they are the likeliest to fail and so to roll the others back. The other changes take effect
+// on restart.
+func (a *App) subscribeConfig() {
+	if backend, ok := a.Cache.(*dao.RedisCacheBackend); ok {
+		config.Subscribe(
+			"redis", []string{"cache.redis"}, func(ctx context.Context, old, new *config.Config) error {
+				return backend.Redis.Reconnect(ctx, new.CacheConfig.RedisConfig.GetRedisOptions())
+			},
+		)
+	}
+	config.Subscribe(
+		"mongo", []string{
+			"mongo.mongo_uri", "mongo.mongo_connect_timeout_ms", "mongo.mongo_max_pool_size",
+			"mongo.mongo_min_pool_size", "mongo.mongo_socket_timeout_ms", "mongo.mongo_ping_timeout_s",
+		}, func(ctx context.Context, old, new *config.Config) error {
+			return a.Mongo.Reconnect(ctx, new.MongoConfig.GetQmgoConfig(), new.MongoConfig.PingTimeoutS)
+		},
+	)
+	config.Subscribe(
+		"jwt", []string{"jwt"}, func(ctx context.Context, old, new *config.Config) error {
+			return a.Jwt.SetDurations(
+				new.JWTConfig.TokenDuration, new.JWTConfig.RefreshDuration, new.JWTConfig.RefreshBuffer,
+			)
+		},
+	)
+	config.Subscribe(
+		"zap", []string{"zap.zap_level"}, func(ctx context.Context, old, new *config.Config) error {
+			return a.Zap.SetLevel(new.ZapConfig.Level)
+		},
+	)
+	config.Subscribe(
+		"rate_limit", []string{
+			"middleware.limiter.limiter_max", "middleware.limiter.limiter_expiration",
+			"middleware.limiter.api_key_header", "middleware.limiter.rules",
+		}, func(ctx context.Context, old, new *config.Config) error {
+			a.Middleware.RateLimitMiddleware.SetLimiterConfig(new.MiddlewareConfig.LimiterConfig)
+			return nil
+		},
+	)
+}
+
+// ReloadConfig reloads the config load fills in over the defaults, and logs and counts the outcome. The current config
+// stays when the new one cannot be read, is not valid or fails to apply.
+func (a *App) ReloadConfig(ctx context.Context, source string, load func(cfg *config.Config) error) {
+	logger := a.Logger.With(zap.String("source", source))
+
+	cfg := config.Default()
+	if err := load(cfg); err != nil {
+		logger.Error("Failed to read config, keeping the current one", zap.Error(err))
+		a.Prometheus.ObserveConfigReload(config.ReloadInvalid)
+		return
+	}
+
+	result, err := config.Reload(ctx, cfg)
+	var validationErrors config.ValidationErrors
+	switch {
+	case errors.As(err, &validationErrors):
+		logger.Error("Invalid config, keeping the current one", zap.Error(err))
+		a.Prometheus.ObserveConfigReload(config.ReloadInvalid)
+	case err != nil:
+		logger.Error("Failed to apply config, rolled back to the current one", zap.Error(err))
+		a.Prometheus.ObserveConfigReload(config.ReloadRolledBack)
+	case len(result.Changed) == 0:
+		logger.Info("Config unchanged")
+		a.Prometheus.ObserveConfigReload(config.ReloadUnchanged)
+	default:
+		logger.Info(
+			"Config reloaded", zap.Strings("changed", result.Changed), zap.Strings("applied", result.Applied),
+		)
+		if len(result.Restart) > 0 {
+			logger.Warn("Config changes take effect on restart", zap.Strings("keys", result.Restart))
+		}
+		a.Prometheus.ObserveConfigReload(config.ReloadApplied)
+	}
+}
@@ -21,9 +21,9 @@ func ShutdownHandler(ctx context.Context, app *App) error {
 	if err := app.Mongo.Close(ctx); err != nil {
 		fmt.Println("Failed to close mongo")
 	}
-	// Close Redis
-	if err := app.Redis.Close(); err != nil {
-		fmt.Println("Failed to close redis")
+	// Close Cache
+	if err := app.Cache.Close(); err != nil {
+		fmt.Println("Failed to close cache")
 	}
 	// Close Fiber
 	if err := app.App.Shutdown(); err != nil {
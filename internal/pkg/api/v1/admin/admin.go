@@ -9,4 +9,7 @@ type Admin struct {
 	NoticeApi        *mods.NoticeApi
 	DocumentationApi *mods.DocumentationApi
 	LogsApi          *mods.LogsApi
+	StatsApi         *mods.StatsApi
+	CacheApi         *mods.CacheApi
+	RateLimitApi     *mods.RateLimitApi
 }
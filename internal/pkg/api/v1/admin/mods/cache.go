@@ -0,0 +1,127 @@
+package mods
+
+import (
+	"fmt"
+
+	"fiber-admin/internal/pkg/domain/vo"
+	"fiber-admin/internal/pkg/domain/vo/admin"
+	adminservice "fiber-admin/internal/pkg/service/admin/mods"
+	"fiber-admin/pkg/errors"
+	"fiber-admin/pkg/utils/common"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CacheApi struct {
+	CacheService adminservice.CacheService
+	Validator    *validator.Validate
+}
+
+// GetCacheStats returns what the cache holds.
+//
+//	@description	Count the keys of the cache namespace and their memory by prefix. Keys under none of the known prefixes are counted as "other".
+//	@id				admin-get-cache-stats
+//	@summary		get cache stats
+//	@tags			Admin API
+//	@accept			json
+//	@produce		json
+//	@security		Bearer
+//	@success		200				{object}	vo.Response{data=admin.GetCacheStatsResponse}	"Success"
+//	@failure		401				{object}	vo.Response{data=nil}							"Unauthorized"
+//	@failure		403				{object}	vo.Response{data=nil}							"Forbidden"
+//	@failure		500				{object}	vo.Response{data=nil}							"Internal server error"
+//	@router			/admin/cache/stats	[get]
+func (a *CacheApi) GetCacheStats(c *fiber.Ctx) error {
+	resp, err := a.CacheService.GetCacheStats(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(
+		vo.Response{
+			Code:    errors.CodeSuccess,
+			Message: errors.MessageSuccess,
+			Data:    resp,
+		},
+	)
+}
+
+// PurgeCache purges the cache of an entity or a prefix.
+//
+//	@description	Purge the cache of an entity (user, notice, documentation or stats), or the keys under a prefix of the DAO caches (starting with dao:). Other keys cannot be purged.
+//	@id				admin-purge-cache
+//	@summary		purge cache
+//	@tags			Admin API
+//	@accept			json
+//	@produce		json
+//	@param			admin.PurgeCacheRequest	query	admin.PurgeCacheRequest	true	"Purge cache request"
+//	@security		Bearer
+//	@success		200				{object}	vo.Response{data=admin.PurgeCacheResponse}	"Success"
+//	@failure		400				{object}	vo.Response{data=nil}						"Invalid request"
+//	@failure		401				{object}	vo.Response{data=nil}						"Unauthorized"
+//	@failure		403				{object}	vo.Response{data=nil}						"Forbidden"
+//	@failure		500				{object}	vo.Response{data=nil}						"Internal server error"
+//	@router			/admin/cache	[delete]
+func (a *CacheApi) PurgeCache(c *fiber.Ctx) error {
+	req := new(admin.PurgeCacheRequest)
+
+	if err := c.QueryParser(req); err != nil {
+		return errors.InvalidRequest(fmt.Errorf("failed to parse request"))
+	}
+	if errs := a.Validator.Struct(req); errs != nil {
+		return errors.InvalidRequest(common.FormatValidateError(errs))
+	}
+
+	resp, err := a.CacheService.PurgeCache(c.UserContext(), req.Entity, req.Prefix)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(
+		vo.Response{
+			Code:    errors.CodeSuccess,
+			Message: errors.MessageSuccess,
+			Data:    resp,
+		},
+	)
+}
+
+// WarmCache loads the hot lists into the cache.
+//
+//	@description	Load the first pages of the notice and documentation lists into the cache, as set by the warm config. Both are warmed when no entity is given.
+//	@id				admin-warm-cache
+//	@summary		warm cache
+//	@tags			Admin API
+//	@accept			json
+//	@produce		json
+//	@param			admin.WarmCacheRequest	body	admin.WarmCacheRequest	true	"Warm cache request"
+//	@security		Bearer
+//	@success		200					{object}	vo.Response{data=admin.WarmCacheResponse}	"Success"
+//	@failure		400					{object}	vo.Response{data=nil}						"Invalid request"
+//	@failure		401					{object}	vo.Response{data=nil}						"Unauthorized"
+//	@failure		403					{object}	vo.Response{data=nil}						"Forbidden"
+//	@failure		500					{object}	vo.Response{data=nil}						"Internal server error"
+//	@router			/admin/cache/warm	[post]
+func (a *CacheApi) WarmCache(c *fiber.Ctx) error {
+	req := new(admin.WarmCacheRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		return errors.InvalidRequest(fmt.Errorf("failed to parse request"))
+	}
+	if errs := a.Validator.Struct(req); errs != nil {
+		return errors.InvalidRequest(common.FormatValidateError(errs))
+	}
+
+	resp, err := a.CacheService.WarmCache(c.UserContext(), req.Entities)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(
+		vo.Response{
+			Code:    errors.CodeSuccess,
+			Message: errors.MessageSuccess,
+			Data:    resp,
+		},
+	)
+}
@@ -7,9 +7,9 @@ import (
 	"fiber-admin/internal/pkg/domain/vo"
 	"fiber-admin/internal/pkg/domain/vo/admin"
 	adminservice "fiber-admin/internal/pkg/service/admin/mods"
-	sysservice "fiber-admin/internal/pkg/service/sys/mods"
 	"fiber-admin/pkg/errors"
 	"fiber-admin/pkg/utils/common"
+	"fiber-admin/pkg/utils/etag"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -17,7 +17,6 @@ import (
 
 type DocumentationApi struct {
 	DocumentationService adminservice.DocumentationService
-	LogsService          sysservice.LogsService
 	Validator            *validator.Validate
 }
 
@@ -49,34 +48,22 @@ func (d DocumentationApi) InsertDocumentation(c *fiber.Ctx) error {
 		return errors.InvalidRequest(common.FormatValidateError(errs))
 	}
 
-	documentationIDHex, err := d.DocumentationService.InsertDocumentation(ctx, req.Title, req.Content)
-	var (
-		userID, _  = primitive.ObjectIDFromHex(ctx.Value(config.UserIDKey).(string))
-		ipAddr     = c.IP()
-		userAgent  = c.Get(fiber.HeaderUserAgent)
-		operation  = config.OperationTypeCreate
-		entityType = config.EntityTypeDocumentation
+	var parentID *primitive.ObjectID
+	if req.ParentID != nil {
+		id, err := primitive.ObjectIDFromHex(*req.ParentID)
+		if err != nil {
+			return errors.InvalidRequest(fmt.Errorf("invalid parent id"))
+		}
+		parentID = &id
+	}
+
+	documentationIDHex, err := d.DocumentationService.InsertDocumentation(
+		ctx, req.Title, req.Content, req.Slug, parentID, req.Position, req.Category, req.Tags,
 	)
 	if err != nil {
-		var (
-			description = fmt.Sprintf("Insert documentation failed: %s", err.Error())
-			status      = config.OperationStatusFailure
-		)
-		_ = d.LogsService.CacheOperationLog(
-			ctx, &userID, nil, &ipAddr, &userAgent, &operation, &entityType, &description, &status,
-		)
 		return err
 	}
-
-	var (
-		documentationID, _ = primitive.ObjectIDFromHex(documentationIDHex)
-		description        = fmt.Sprintf("Insert documentation: %s", documentationIDHex)
-		status             = config.OperationStatusSuccess
-	)
-
-	_ = d.LogsService.CacheOperationLog(
-		ctx, &userID, &documentationID, &ipAddr, &userAgent, &operation, &entityType, &description, &status,
-	)
+	c.Locals(config.OperationLogEntityIDKey, documentationIDHex)
 
 	return c.JSON(
 		vo.Response{
@@ -96,12 +83,14 @@ func (d DocumentationApi) InsertDocumentation(c *fiber.Ctx) error {
 //	@accept			json
 //	@produce		json
 //	@param			admin.UpdateDocumentationRequest	body	admin.UpdateDocumentationRequest	true	"Update documentation request"
+//	@param			If-Match	header	string	false	"ETag of the version the request is conditional on"
 //	@security		Bearer
 //	@success		200						{object}	vo.Response{data=nil}	"Success"
 //	@failure		400						{object}	vo.Response{data=nil}	"Invalid request"
 //	@failure		401						{object}	vo.Response{data=nil}	"Unauthorized"
 //	@failure		403						{object}	vo.Response{data=nil}	"Forbidden"
 //	@failure		404						{object}	vo.Response{data=nil}	"Not found"
+//	@failure		412						{object}	vo.Response{data=nil}	"Precondition failed"
 //	@failure		500						{object}	vo.Response{data=nil}	"Internal server error"
 //	@router			/admin/documentation	[put]
 func (d DocumentationApi) UpdateDocumentation(c *fiber.Ctx) error {
@@ -119,33 +108,16 @@ func (d DocumentationApi) UpdateDocumentation(c *fiber.Ctx) error {
 	if err != nil {
 		return errors.InvalidRequest(fmt.Errorf("invalid documentation id"))
 	}
-	err = d.DocumentationService.UpdateDocumentation(ctx, &documentationID, req.Title, req.Content)
-	var (
-		userID, _  = primitive.ObjectIDFromHex(ctx.Value(config.UserIDKey).(string))
-		ipAddr     = c.IP()
-		userAgent  = c.Get(fiber.HeaderUserAgent)
-		operation  = config.OperationTypeUpdate
-		entityType = config.EntityTypeDocumentation
+	version, err := etag.ParseIfMatch(c.Get(fiber.HeaderIfMatch))
+	if err != nil {
+		return errors.PreconditionFailed(err)
+	}
+	err = d.DocumentationService.UpdateDocumentation(
+		ctx, &documentationID, version, req.Title, req.Content, req.Slug, req.Category, req.Tags, req.Summary,
 	)
-
 	if err != nil {
-		var (
-			description = fmt.Sprintf("Update documentation failed: %s", err.Error())
-			status      = config.OperationStatusFailure
-		)
-		_ = d.LogsService.CacheOperationLog(
-			ctx, &userID, &documentationID, &ipAddr, &userAgent, &operation, &entityType, &description, &status,
-		)
 		return err
 	}
-
-	var (
-		description = fmt.Sprintf("Update documentation: %s", *req.DocumentationID)
-		status      = config.OperationStatusSuccess
-	)
-	_ = d.LogsService.CacheOperationLog(
-		ctx, &userID, &documentationID, &ipAddr, &userAgent, &operation, &entityType, &description, &status,
-	)
 	return c.JSON(
 		vo.Response{
 			Code:    errors.CodeSuccess,
@@ -164,12 +136,14 @@ func (d DocumentationApi) UpdateDocumentation(c *fiber.Ctx) error {
 //	@accept			json
 //	@produce		json
 //	@param			admin.DeleteDocumentationRequest	query	admin.DeleteDocumentationRequest	true	"Delete documentation request"
+//	@param			If-Match	header	string	false	"ETag of the version the request is conditional on"
 //	@security		Bearer
 //	@success		200						{object}	vo.Response{data=nil}	"Success"
 //	@failure		400						{object}	vo.Response{data=nil}	"Invalid request"
 //	@failure		401						{object}	vo.Response{data=nil}	"Unauthorized"
 //	@failure		403						{object}	vo.Response{data=nil}	"Forbidden"
 //	@failure		404						{object}	vo.Response{data=nil}	"Not found"
+//	@failure		412						{object}	vo.Response{data=nil}	"Precondition failed"
 //	@failure		500						{object}	vo.Response{data=nil}	"Internal server error"
 //	@router			/admin/documentation	[delete]
 func (d DocumentationApi) DeleteDocumentation(c *fiber.Ctx) error {
@@ -187,32 +161,125 @@ func (d DocumentationApi) DeleteDocumentation(c *fiber.Ctx) error {
 	if err != nil {
 		return errors.InvalidRequest(fmt.Errorf("invalid documentation id"))
 	}
-	err = d.DocumentationService.DeleteDocumentation(ctx, &documentationID)
-	var (
-		userID, _  = primitive.ObjectIDFromHex(ctx.Value(config.UserIDKey).(string))
-		ipAddr     = c.IP()
-		userAgent  = c.Get(fiber.HeaderUserAgent)
-		operation  = config.OperationTypeDelete
-		entityType = config.EntityTypeDocumentation
-	)
+	version, err := etag.ParseIfMatch(c.Get(fiber.HeaderIfMatch))
+	if err != nil {
+		return errors.PreconditionFailed(err)
+	}
+	err = d.DocumentationService.DeleteDocumentation(ctx, &documentationID, version)
 	if err != nil {
-		var (
-			description = fmt.Sprintf("Delete documentation failed: %s", err.Error())
-			status      = config.OperationStatusFailure
-		)
-		_ = d.LogsService.CacheOperationLog(
-			ctx, &userID, &documentationID, &ipAddr, &userAgent, &operation, &entityType, &description, &status,
-		)
 		return err
 	}
 
-	var (
-		description = fmt.Sprintf("Delete documentation: %s", *req.DocumentationID)
-		status      = config.OperationStatusSuccess
+	return c.JSON(
+		vo.Response{
+			Code:    errors.CodeSuccess,
+			Message: errors.MessageSuccess,
+			Data:    nil,
+		},
 	)
-	_ = d.LogsService.CacheOperationLog(
-		ctx, &userID, &documentationID, &ipAddr, &userAgent, &operation, &entityType, &description, &status,
+}
+
+// MoveDocumentation Move a documentation under another parent.
+//
+//	@description	Move a documentation under another parent (top level when parent_id is omitted) at the given position.
+//	@id				admin-move-documentation
+//	@summary		move documentation
+//	@tags			Admin API
+//	@accept			json
+//	@produce		json
+//	@param			admin.MoveDocumentationRequest	body	admin.MoveDocumentationRequest	true	"Move documentation request"
+//	@security		Bearer
+//	@success		200							{object}	vo.Response{data=nil}	"Success"
+//	@failure		400							{object}	vo.Response{data=nil}	"Invalid request"
+//	@failure		401							{object}	vo.Response{data=nil}	"Unauthorized"
+//	@failure		403							{object}	vo.Response{data=nil}	"Forbidden"
+//	@failure		404							{object}	vo.Response{data=nil}	"Not found"
+//	@failure		500							{object}	vo.Response{data=nil}	"Internal server error"
+//	@router			/admin/documentation/move	[post]
+func (d DocumentationApi) MoveDocumentation(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	req := new(admin.MoveDocumentationRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		return errors.InvalidRequest(fmt.Errorf("failed to parse request"))
+	}
+	if errs := d.Validator.Struct(req); errs != nil {
+		return errors.InvalidRequest(common.FormatValidateError(errs))
+	}
+	documentationID, err := primitive.ObjectIDFromHex(*req.DocumentationID)
+	if err != nil {
+		return errors.InvalidRequest(fmt.Errorf("invalid documentation id"))
+	}
+	var parentID *primitive.ObjectID
+	if req.ParentID != nil {
+		id, err := primitive.ObjectIDFromHex(*req.ParentID)
+		if err != nil {
+			return errors.InvalidRequest(fmt.Errorf("invalid parent id"))
+		}
+		parentID = &id
+	}
+	err = d.DocumentationService.MoveDocumentation(ctx, &documentationID, parentID, req.Position)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(
+		vo.Response{
+			Code:    errors.CodeSuccess,
+			Message: errors.MessageSuccess,
+			Data:    nil,
+		},
 	)
+}
+
+// ReorderDocumentation Reorder the children of a documentation.
+//
+//	@description	Reorder the children of a documentation (the top level when parent_id is omitted). The list must contain every child exactly once.
+//	@id				admin-reorder-documentation
+//	@summary		reorder documentation
+//	@tags			Admin API
+//	@accept			json
+//	@produce		json
+//	@param			admin.ReorderDocumentationRequest	body	admin.ReorderDocumentationRequest	true	"Reorder documentation request"
+//	@security		Bearer
+//	@success		200								{object}	vo.Response{data=nil}	"Success"
+//	@failure		400								{object}	vo.Response{data=nil}	"Invalid request"
+//	@failure		401								{object}	vo.Response{data=nil}	"Unauthorized"
+//	@failure		403								{object}	vo.Response{data=nil}	"Forbidden"
+//	@failure		404								{object}	vo.Response{data=nil}	"Not found"
+//	@failure		500								{object}	vo.Response{data=nil}	"Internal server error"
+//	@router			/admin/documentation/reorder	[post]
+func (d DocumentationApi) ReorderDocumentation(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	req := new(admin.ReorderDocumentationRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		return errors.InvalidRequest(fmt.Errorf("failed to parse request"))
+	}
+	if errs := d.Validator.Struct(req); errs != nil {
+		return errors.InvalidRequest(common.FormatValidateError(errs))
+	}
+	var parentID *primitive.ObjectID
+	if req.ParentID != nil {
+		id, err := primitive.ObjectIDFromHex(*req.ParentID)
+		if err != nil {
+			return errors.InvalidRequest(fmt.Errorf("invalid parent id"))
+		}
+		parentID = &id
+	}
+	documentationIDList := make([]primitive.ObjectID, 0, len(req.DocumentationIDList))
+	for _, documentationIDHex := range req.DocumentationIDList {
+		documentationID, err := primitive.ObjectIDFromHex(documentationIDHex)
+		if err != nil {
+			return errors.InvalidRequest(fmt.Errorf("invalid documentation id"))
+		}
+		documentationIDList = append(documentationIDList, documentationID)
+	}
+	err := d.DocumentationService.ReorderDocumentation(ctx, parentID, documentationIDList)
+	if err != nil {
+		return err
+	}
+
 	return c.JSON(
 		vo.Response{
 			Code:    errors.CodeSuccess,
@@ -221,3 +288,191 @@ func (d DocumentationApi) DeleteDocumentation(c *fiber.Ctx) error {
 		},
 	)
 }
+
+// GetDocumentationRevision returns a revision of a documentation.
+//
+//	@description	Get a revision of a documentation.
+//	@id				admin-get-documentation-revision
+//	@summary		get documentation revision
+//	@tags			Admin API
+//	@accept			json
+//	@produce		json
+//	@param			admin.GetDocumentationRevisionRequest	query	admin.GetDocumentationRevisionRequest	true	"Get documentation revision request"
+//	@security		Bearer
+//	@success		200									{object}	vo.Response{data=admin.GetDocumentationRevisionResponse}	"Success"
+//	@failure		400									{object}	vo.Response{data=nil}										"Invalid request"
+//	@failure		401									{object}	vo.Response{data=nil}										"Unauthorized"
+//	@failure		403									{object}	vo.Response{data=nil}										"Forbidden"
+//	@failure		404									{object}	vo.Response{data=nil}										"Not found"
+//	@failure		500									{object}	vo.Response{data=nil}										"Internal server error"
+//	@router			/admin/documentation/revision	[get]
+func (d DocumentationApi) GetDocumentationRevision(c *fiber.Ctx) error {
+	req := new(admin.GetDocumentationRevisionRequest)
+
+	if err := c.QueryParser(req); err != nil {
+		return errors.InvalidRequest(fmt.Errorf("failed to parse request"))
+	}
+	if errs := d.Validator.Struct(req); errs != nil {
+		return errors.InvalidRequest(common.FormatValidateError(errs))
+	}
+	documentationID, err := primitive.ObjectIDFromHex(*req.DocumentationID)
+	if err != nil {
+		return errors.InvalidRequest(fmt.Errorf("invalid documentation id"))
+	}
+
+	resp, err := d.DocumentationService.GetDocumentationRevision(c.UserContext(), &documentationID, req.Revision)
+	if err != nil {
+		return err
+	}
+	return c.JSON(
+		vo.Response{
+			Code:    errors.CodeSuccess,
+			Message: errors.MessageSuccess,
+			Data:    resp,
+		},
+	)
+}
+
+// GetDocumentationRevisionList returns the revision list of a documentation.
+//
+//	@description	Get the revision list of a documentation.
+//	@id				admin-get-documentation-revision-list
+//	@summary		get documentation revision list
+//	@tags			Admin API
+//	@accept			json
+//	@produce		json
+//	@param			admin.GetDocumentationRevisionListRequest	query	admin.GetDocumentationRevisionListRequest	true	"Get documentation revision list request"
+//	@security		Bearer
+//	@success		200										{object}	vo.Response{data=admin.GetDocumentationRevisionListResponse}	"Success"
+//	@failure		400										{object}	vo.Response{data=nil}											"Invalid request"
+//	@failure		401										{object}	vo.Response{data=nil}											"Unauthorized"
+//	@failure		403										{object}	vo.Response{data=nil}											"Forbidden"
+//	@failure		500										{object}	vo.Response{data=nil}											"Internal server error"
+//	@router			/admin/documentation/revision/list	[get]
+func (d DocumentationApi) GetDocumentationRevisionList(c *fiber.Ctx) error {
+	req := new(admin.GetDocumentationRevisionListRequest)
+
+	if err := c.QueryParser(req); err != nil {
+		return errors.InvalidRequest(fmt.Errorf("failed to parse request"))
+	}
+	if errs := d.Validator.Struct(req); errs != nil {
+		return errors.InvalidRequest(common.FormatValidateError(errs))
+	}
+	documentationID, err := primitive.ObjectIDFromHex(*req.DocumentationID)
+	if err != nil {
+		return errors.InvalidRequest(fmt.Errorf("invalid documentation id"))
+	}
+
+	resp, err := d.DocumentationService.GetDocumentationRevisionList(
+		c.UserContext(), &documentationID, req.Page, req.PageSize, req.Desc,
+	)
+	if err != nil {
+		return err
+	}
+	return c.JSON(
+		vo.Response{
+			Code:    errors.CodeSuccess,
+			Message: errors.MessageSuccess,
+			Data:    resp,
+		},
+	)
+}
+
+// DiffDocumentationRevision returns the diff between two revisions of a documentation.
+//
+//	@description	Get the unified diff between two revisions of a documentation.
+//	@id				admin-diff-documentation-revision
+//	@summary		diff documentation revisions
+//	@tags			Admin API
+//	@accept			json
+//	@produce		json
+//	@param			admin.DiffDocumentationRevisionRequest	query	admin.DiffDocumentationRevisionRequest	true	"Diff documentation revision request"
+//	@security		Bearer
+//	@success		200										{object}	vo.Response{data=admin.DiffDocumentationRevisionResponse}	"Success"
+//	@failure		400										{object}	vo.Response{data=nil}										"Invalid request"
+//	@failure		401										{object}	vo.Response{data=nil}										"Unauthorized"
+//	@failure		403										{object}	vo.Response{data=nil}										"Forbidden"
+//	@failure		404										{object}	vo.Response{data=nil}										"Not found"
+//	@failure		500										{object}	vo.Response{data=nil}										"Internal server error"
+//	@router			/admin/documentation/revision/diff	[get]
+func (d DocumentationApi) DiffDocumentationRevision(c *fiber.Ctx) error {
+	req := new(admin.DiffDocumentationRevisionRequest)
+
+	if err := c.QueryParser(req); err != nil {
+		return errors.InvalidRequest(fmt.Errorf("failed to parse request"))
+	}
+	if errs := d.Validator.Struct(req); errs != nil {
+		return errors.InvalidRequest(common.FormatValidateError(errs))
+	}
+	documentationID, err := primitive.ObjectIDFromHex(*req.DocumentationID)
+	if err != nil {
+		return errors.InvalidRequest(fmt.Errorf("invalid documentation id"))
+	}
+
+	resp, err := d.DocumentationService.DiffDocumentationRevision(
+		c.UserContext(), &documentationID, req.FromRevision, req.ToRevision,
+	)
+	if err != nil {
+		return err
+	}
+	return c.JSON(
+		vo.Response{
+			Code:    errors.CodeSuccess,
+			Message: errors.MessageSuccess,
+			Data:    resp,
+		},
+	)
+}
+
+// RestoreDocumentationRevision restores an old revision of a documentation as a new revision.
+//
+//	@description	Restore an old revision of a documentation as a new revision.
+//	@id				admin-restore-documentation-revision
+//	@summary		restore documentation revision
+//	@tags			Admin API
+//	@accept			json
+//	@produce		json
+//	@param			admin.RestoreDocumentationRevisionRequest	body	admin.RestoreDocumentationRevisionRequest	true	"Restore documentation revision request"
+//	@param			If-Match									header	string											false	"ETag of the version the request is conditional on"
+//	@security		Bearer
+//	@success		200											{object}	vo.Response{data=admin.RestoreDocumentationRevisionResponse}	"Success"
+//	@failure		400											{object}	vo.Response{data=nil}											"Invalid request"
+//	@failure		401											{object}	vo.Response{data=nil}											"Unauthorized"
+//	@failure		403											{object}	vo.Response{data=nil}											"Forbidden"
+//	@failure		404											{object}	vo.Response{data=nil}											"Not found"
+//	@failure		412											{object}	vo.Response{data=nil}											"Precondition failed"
+//	@failure		500											{object}	vo.Response{data=nil}											"Internal server error"
+//	@router			/admin/documentation/revision/restore	[post]
+func (d DocumentationApi) RestoreDocumentationRevision(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	req := new(admin.RestoreDocumentationRevisionRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		return errors.InvalidRequest(fmt.Errorf("failed to parse request"))
+	}
+	if errs := d.Validator.Struct(req); errs != nil {
+		return errors.InvalidRequest(common.FormatValidateError(errs))
+	}
+	documentationID, err := primitive.ObjectIDFromHex(*req.DocumentationID)
+	if err != nil {
+		return errors.InvalidRequest(fmt.Errorf("invalid documentation id"))
+	}
+	version, err := etag.ParseIfMatch(c.Get(fiber.HeaderIfMatch))
+	if err != nil {
+		return errors.PreconditionFailed(err)
+	}
+	resp, err := d.DocumentationService.RestoreDocumentationRevision(
+		ctx, &documentationID, version, req.Revision, req.Summary,
+	)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(
+		vo.Response{
+			Code:    errors.CodeSuccess,
+			Message: errors.MessageSuccess,
+			Data:    resp,
+		},
+	)
+}
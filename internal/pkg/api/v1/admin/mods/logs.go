@@ -1,21 +1,63 @@
 package mods
 
 import (
+	"bufio"
+	"context"
 	"fmt"
 	"time"
 
+	"fiber-admin/internal/pkg/config"
 	"fiber-admin/internal/pkg/domain/vo"
 	"fiber-admin/internal/pkg/domain/vo/admin"
 	adminservice "fiber-admin/internal/pkg/service/admin/mods"
+	sysservice "fiber-admin/internal/pkg/service/sys/mods"
 	"fiber-admin/pkg/errors"
 	"fiber-admin/pkg/utils/common"
 	"github.com/go-playground/validator/v10"
+	"github.com/goccy/go-json"
 	"github.com/gofiber/fiber/v2"
 )
 
 type LogsApi struct {
-	LogsService adminservice.LogsService
-	Validator   *validator.Validate
+	LogsService    adminservice.LogsService
+	LogTailService sysservice.LogTailService
+	Validator      *validator.Validate
+	Config         *config.Config
+}
+
+// parseCreateTimeRange parses the creation time range of a log list request.
+func parseCreateTimeRange(createStartTime, createEndTime *string) (*time.Time, *time.Time, error) {
+	createdBefore, err := parseTime("create start time", createStartTime)
+	if err != nil {
+		return nil, nil, err
+	}
+	createdAfter, err := parseTime("create end time", createEndTime)
+	if err != nil {
+		return nil, nil, err
+	}
+	return createdBefore, createdAfter, nil
+}
+
+// parseTime parses an optional RFC3339 time parameter.
+func parseTime(name string, value *string) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, *value)
+	if err != nil {
+		return nil, errors.InvalidRequest(fmt.Errorf("invalid %s %s (should be in RFC3339 format)", name, *value))
+	}
+	return &t, nil
+}
+
+// exportAttachment sets the headers of a log export download, named after the logs and the time of the export.
+func exportAttachment(c *fiber.Ctx, name, format string) {
+	c.Attachment(fmt.Sprintf("%s-%s.%s", name, time.Now().UTC().Format("20060102T150405Z"), format))
+	if format == config.ExportFormatNDJSON {
+		c.Set(fiber.HeaderContentType, "application/x-ndjson")
+	} else {
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	}
 }
 
 // GetLoginLogList returns the login log list.
@@ -44,30 +86,9 @@ func (l *LogsApi) GetLoginLogList(c *fiber.Ctx) error {
 		return errors.InvalidRequest(common.FormatValidateError(errs))
 	}
 
-	var (
-		createdBefore, createdAfter *time.Time
-		err                         error
-	)
-
-	if req.CreateStartTime != nil {
-		*createdBefore, err = time.Parse(time.RFC3339, *req.CreateStartTime)
-		if err != nil {
-			return errors.InvalidRequest(
-				fmt.Errorf(
-					"invalid create start time %s (should be in RFC3339 format)", *req.CreateStartTime,
-				),
-			)
-		}
-	}
-	if req.CreateEndTime != nil {
-		*createdAfter, err = time.Parse(time.RFC3339, *req.CreateEndTime)
-		if err != nil {
-			return errors.InvalidRequest(
-				fmt.Errorf(
-					"invalid create end time %s (should be in RFC3339 format)", *req.CreateEndTime,
-				),
-			)
-		}
+	createdBefore, createdAfter, err := parseCreateTimeRange(req.CreateStartTime, req.CreateEndTime)
+	if err != nil {
+		return err
 	}
 
 	resp, err := l.LogsService.GetLoginLogList(
@@ -112,36 +133,290 @@ func (l *LogsApi) GetOperationLogList(c *fiber.Ctx) error {
 		return errors.InvalidRequest(common.FormatValidateError(errs))
 	}
 
-	var (
-		createdBefore, createdAfter *time.Time
-		err                         error
+	createdBefore, createdAfter, err := parseCreateTimeRange(req.CreateStartTime, req.CreateEndTime)
+	if err != nil {
+		return err
+	}
+
+	resp, err := l.LogsService.GetOperationLogList(
+		c.UserContext(), req.Page, req.PageSize, req.Desc, req.Query, req.Operation, req.EntityType, req.Status,
+		createdBefore, createdAfter,
 	)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(
+		vo.Response{
+			Code:    errors.CodeSuccess,
+			Message: errors.MessageSuccess,
+			Data:    resp,
+		},
+	)
+}
 
-	if req.CreateStartTime != nil {
-		*createdBefore, err = time.Parse(time.RFC3339, *req.CreateStartTime)
-		if err != nil {
-			return errors.InvalidRequest(
-				fmt.Errorf(
-					"invalid create start time %s (should be in RFC3339 format)", *req.CreateStartTime,
-				),
+// ExportLoginLogList exports the login log list.
+//
+//	@description	Download every login log matching the filters as CSV or NDJSON. The file is streamed, so any date range can be exported.
+//	@id				admin-export-login-log-list
+//	@summary		export login log list
+//	@tags			Admin API
+//	@accept			json
+//	@produce		text/csv,application/x-ndjson
+//	@param			admin.ExportLoginLogListRequest	query	admin.ExportLoginLogListRequest	true	"Export login log list request"
+//	@security		Bearer
+//	@success		200							{file}		file					"Exported login logs"
+//	@header			200							{string}	Content-Disposition		"Attachment file name"
+//	@failure		400							{object}	vo.Response{data=nil}	"Invalid request"
+//	@failure		401							{object}	vo.Response{data=nil}	"Unauthorized"
+//	@failure		403							{object}	vo.Response{data=nil}	"Forbidden"
+//	@router			/admin/login-log/export		[get]
+func (l *LogsApi) ExportLoginLogList(c *fiber.Ctx) error {
+	req := new(admin.ExportLoginLogListRequest)
+
+	if err := c.QueryParser(req); err != nil {
+		return errors.InvalidRequest(fmt.Errorf("failed to parse request"))
+	}
+	if errs := l.Validator.Struct(req); errs != nil {
+		return errors.InvalidRequest(common.FormatValidateError(errs))
+	}
+	createdBefore, createdAfter, err := parseCreateTimeRange(req.CreateStartTime, req.CreateEndTime)
+	if err != nil {
+		return err
+	}
+
+	ctx, conn := c.UserContext(), c.Context().Conn()
+	exportAttachment(c, "login-log", *req.Format)
+	c.Context().SetBodyStreamWriter(
+		func(w *bufio.Writer) {
+			stream := newStreamWriter(w, conn)
+			// The status is sent by now, so a failure can only cut the export short. The service logs it.
+			_ = l.LogsService.ExportLoginLogList(
+				ctx, stream, req.Format, req.Desc, req.Query, createdBefore, createdAfter,
 			)
-		}
-	}
-	if req.CreateEndTime != nil {
-		*createdAfter, err = time.Parse(time.RFC3339, *req.CreateEndTime)
-		if err != nil {
-			return errors.InvalidRequest(
-				fmt.Errorf(
-					"invalid create end time %s (should be in RFC3339 format)", *req.CreateEndTime,
-				),
+			_ = stream.Flush()
+		},
+	)
+	return nil
+}
+
+// ExportOperationLogList exports the operation log list.
+//
+//	@description	Download every operation log matching the filters as CSV or NDJSON. The file is streamed, so any date range can be exported.
+//	@id				admin-export-operation-log-list
+//	@summary		export operation log list
+//	@tags			Admin API
+//	@accept			json
+//	@produce		text/csv,application/x-ndjson
+//	@param			admin.ExportOperationLogListRequest	query	admin.ExportOperationLogListRequest	true	"Export operation log list request"
+//	@security		Bearer
+//	@success		200							{file}		file					"Exported operation logs"
+//	@header			200							{string}	Content-Disposition		"Attachment file name"
+//	@failure		400							{object}	vo.Response{data=nil}	"Invalid request"
+//	@failure		401							{object}	vo.Response{data=nil}	"Unauthorized"
+//	@failure		403							{object}	vo.Response{data=nil}	"Forbidden"
+//	@router			/admin/operation-log/export	[get]
+func (l *LogsApi) ExportOperationLogList(c *fiber.Ctx) error {
+	req := new(admin.ExportOperationLogListRequest)
+
+	if err := c.QueryParser(req); err != nil {
+		return errors.InvalidRequest(fmt.Errorf("failed to parse request"))
+	}
+	if errs := l.Validator.Struct(req); errs != nil {
+		return errors.InvalidRequest(common.FormatValidateError(errs))
+	}
+	createdBefore, createdAfter, err := parseCreateTimeRange(req.CreateStartTime, req.CreateEndTime)
+	if err != nil {
+		return err
+	}
+
+	ctx, conn := c.UserContext(), c.Context().Conn()
+	exportAttachment(c, "operation-log", *req.Format)
+	c.Context().SetBodyStreamWriter(
+		func(w *bufio.Writer) {
+			stream := newStreamWriter(w, conn)
+			// The status is sent by now, so a failure can only cut the export short. The service logs it.
+			_ = l.LogsService.ExportOperationLogList(
+				ctx, stream, req.Format, req.Desc, req.Query, req.Operation, req.EntityType, req.Status,
+				createdBefore, createdAfter,
 			)
-		}
+			_ = stream.Flush()
+		},
+	)
+	return nil
+}
+
+// TailLogs streams new log entries.
+//
+//	@description	Stream new operation logs, and optionally request logs, as server-sent events until the client disconnects. Each event is named after its type and carries the log entry as JSON. Filters are applied server-side.
+//	@id				admin-tail-logs
+//	@summary		tail logs
+//	@tags			Admin API
+//	@accept			json
+//	@produce		text/event-stream
+//	@param			admin.TailLogsRequest	query	admin.TailLogsRequest	true	"Tail logs request"
+//	@security		Bearer
+//	@success		200				{string}	string					"Stream of log events"
+//	@failure		400				{object}	vo.Response{data=nil}	"Invalid request"
+//	@failure		401				{object}	vo.Response{data=nil}	"Unauthorized"
+//	@failure		403				{object}	vo.Response{data=nil}	"Forbidden"
+//	@failure		500				{object}	vo.Response{data=nil}	"Internal server error"
+//	@router			/admin/log-tail	[get]
+func (l *LogsApi) TailLogs(c *fiber.Ctx) error {
+	req := new(admin.TailLogsRequest)
+
+	if err := c.QueryParser(req); err != nil {
+		return errors.InvalidRequest(fmt.Errorf("failed to parse request"))
+	}
+	if errs := l.Validator.Struct(req); errs != nil {
+		return errors.InvalidRequest(common.FormatValidateError(errs))
 	}
 
-	resp, err := l.LogsService.GetOperationLogList(
-		c.UserContext(), req.Page, req.PageSize, req.Desc, req.Query, req.Operation, req.EntityType, req.Status,
-		createdBefore, createdAfter,
+	eventTypes := []string{config.LogTailTypeOperation}
+	if req.Requests != nil && *req.Requests {
+		eventTypes = append(eventTypes, config.LogTailTypeRequest)
+	}
+	// The subscription outlives the request, so it must not depend on the request context
+	events, stop, err := l.LogTailService.Tail(
+		context.Background(), eventTypes, &sysservice.LogTailFilter{
+			UserID: req.UserID, EntityType: req.EntityType, Status: req.Status, Path: req.Path,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no") // Keep reverse proxies from buffering the stream
+	conn := c.Context().Conn()
+	c.Context().SetBodyStreamWriter(
+		func(w *bufio.Writer) {
+			defer stop()
+			stream := newStreamWriter(w, conn)
+			heartbeat := time.NewTicker(l.Config.LogTailConfig.Heartbeat)
+			defer heartbeat.Stop()
+
+			// Flush the headers right away, so that the client knows the tail has started
+			if _, err := fmt.Fprint(stream, ": tailing\n\n"); err != nil || stream.Flush() != nil {
+				return
+			}
+			for {
+				select {
+				case event, ok := <-events:
+					if !ok {
+						return
+					}
+					eventJSON, err := json.Marshal(event)
+					if err != nil {
+						continue
+					}
+					_, err = fmt.Fprintf(stream, "event: %s\ndata: %s\n\n", event.Type, eventJSON)
+					if err != nil {
+						return
+					}
+				case <-heartbeat.C:
+					// A comment line, ignored by clients. Writing it detects clients which have gone away.
+					if _, err := fmt.Fprint(stream, ": heartbeat\n\n"); err != nil {
+						return
+					}
+				}
+				if err := stream.Flush(); err != nil {
+					return
+				}
+			}
+		},
+	)
+	return nil
+}
+
+// VerifyOperationLogChain verifies the operation log hash chain.
+//
+//	@description	Walk the operation log hash chain and the signed checkpoints, and report the first broken link.
+//	@id				admin-verify-operation-log-chain
+//	@summary		verify operation log chain
+//	@tags			Admin API
+//	@accept			json
+//	@produce		json
+//	@security		Bearer
+//	@success		200							{object}	vo.Response{data=admin.VerifyOperationLogChainResponse}	"Success"
+//	@failure		401							{object}	vo.Response{data=nil}									"Unauthorized"
+//	@failure		403							{object}	vo.Response{data=nil}									"Forbidden"
+//	@failure		500							{object}	vo.Response{data=nil}									"Internal server error"
+//	@router			/admin/operation-log/verify	[get]
+func (l *LogsApi) VerifyOperationLogChain(c *fiber.Ctx) error {
+	resp, err := l.LogsService.VerifyOperationLogChain(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(
+		vo.Response{
+			Code:    errors.CodeSuccess,
+			Message: errors.MessageSuccess,
+			Data:    resp,
+		},
+	)
+}
+
+// GetLogArchiveList returns the log archive list.
+//
+//	@description	Get the archives of the logs removed by the retention policy, newest first.
+//	@id				admin-get-log-archive-list
+//	@summary		get log archive list
+//	@tags			Admin API
+//	@accept			json
+//	@produce		json
+//	@security		Bearer
+//	@success		200						{object}	vo.Response{data=admin.GetLogArchiveListResponse}	"Success"
+//	@failure		401						{object}	vo.Response{data=nil}								"Unauthorized"
+//	@failure		403						{object}	vo.Response{data=nil}								"Forbidden"
+//	@failure		500						{object}	vo.Response{data=nil}								"Internal server error"
+//	@router			/admin/log-archive/list	[get]
+func (l *LogsApi) GetLogArchiveList(c *fiber.Ctx) error {
+	resp, err := l.LogsService.GetLogArchiveList(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(
+		vo.Response{
+			Code:    errors.CodeSuccess,
+			Message: errors.MessageSuccess,
+			Data:    resp,
+		},
 	)
+}
+
+// ImportLogArchive imports a log archive.
+//
+//	@description	Insert the logs of an archive back for investigation, login logs into their collection and operation logs into operation_log_import. Logs still present are skipped.
+//	@id				admin-import-log-archive
+//	@summary		import log archive
+//	@tags			Admin API
+//	@accept			json
+//	@produce		json
+//	@param			admin.ImportLogArchiveRequest	body	admin.ImportLogArchiveRequest	true	"Import log archive request"
+//	@security		Bearer
+//	@success		200							{object}	vo.Response{data=admin.ImportLogArchiveResponse}	"Success"
+//	@failure		400							{object}	vo.Response{data=nil}								"Invalid request"
+//	@failure		401							{object}	vo.Response{data=nil}								"Unauthorized"
+//	@failure		403							{object}	vo.Response{data=nil}								"Forbidden"
+//	@failure		404							{object}	vo.Response{data=nil}								"Log archive not found"
+//	@failure		500							{object}	vo.Response{data=nil}								"Internal server error"
+//	@router			/admin/log-archive/import	[post]
+func (l *LogsApi) ImportLogArchive(c *fiber.Ctx) error {
+	req := new(admin.ImportLogArchiveRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		return errors.InvalidRequest(fmt.Errorf("failed to parse request"))
+	}
+	if errs := l.Validator.Struct(req); errs != nil {
+		return errors.InvalidRequest(common.FormatValidateError(errs))
+	}
+
+	resp, err := l.LogsService.ImportLogArchive(c.UserContext(), req.Name)
 	if err != nil {
 		return err
 	}
@@ -7,9 +7,9 @@ import (
 	"fiber-admin/internal/pkg/domain/vo"
 	"fiber-admin/internal/pkg/domain/vo/admin"
 	adminservice "fiber-admin/internal/pkg/service/admin/mods"
-	sysservice "fiber-admin/internal/pkg/service/sys/mods"
 	"fiber-admin/pkg/errors"
 	"fiber-admin/pkg/utils/common"
+	"fiber-admin/pkg/utils/etag"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -17,7 +17,6 @@ import (
 
 type NoticeApi struct {
 	NoticeService adminservice.NoticeService
-	LogsService   sysservice.LogsService
 	Validator     *validator.Validate
 }
 
@@ -49,32 +48,11 @@ func (n *NoticeApi) InsertNotice(c *fiber.Ctx) error {
 	}
 
 	noticeIDHex, err := n.NoticeService.InsertNotice(ctx, req.Title, req.Content, req.NoticeType)
-	var (
-		userID, _  = primitive.ObjectIDFromHex(ctx.Value(config.UserIDKey).(string))
-		ipAddr     = c.IP()
-		userAgent  = c.Get(fiber.HeaderUserAgent)
-		operation  = config.OperationTypeCreate
-		entityType = config.EntityTypeNotice
-	)
 	if err != nil {
-		var (
-			description = fmt.Sprintf("Insert notice failed: %s", err.Error())
-			status      = config.OperationStatusFailure
-		)
-		_ = n.LogsService.CacheOperationLog(
-			ctx, &userID, nil, &ipAddr, &userAgent, &operation, &entityType, &description, &status,
-		)
 		return err
 	}
+	c.Locals(config.OperationLogEntityIDKey, noticeIDHex)
 
-	var (
-		noticeID, _ = primitive.ObjectIDFromHex(noticeIDHex)
-		description = fmt.Sprintf("Insert notice: %s", noticeIDHex)
-		status      = config.OperationStatusSuccess
-	)
-	_ = n.LogsService.CacheOperationLog(
-		ctx, &userID, &noticeID, &ipAddr, &userAgent, &operation, &entityType, &description, &status,
-	)
 	return c.JSON(
 		vo.Response{
 			Code:    errors.CodeSuccess,
@@ -93,11 +71,13 @@ func (n *NoticeApi) InsertNotice(c *fiber.Ctx) error {
 //	@accept			json
 //	@produce		json
 //	@param			admin.UpdateNoticeRequest	body	admin.UpdateNoticeRequest	true	"Update notice request"
+//	@param			If-Match	header	string	false	"ETag of the version the request is conditional on"
 //	@security		Bearer
 //	@success		200				{object}	vo.Response{data=nil}	"Success"
 //	@failure		400				{object}	vo.Response{data=nil}	"Invalid request"
 //	@failure		401				{object}	vo.Response{data=nil}	"Unauthorized"
 //	@failure		403				{object}	vo.Response{data=nil}	"Forbidden"
+//	@failure		412				{object}	vo.Response{data=nil}	"Precondition failed"
 //	@failure		500				{object}	vo.Response{data=nil}	"Internal server error"
 //	@router			/admin/notice																																																																																																																																																																																		[put]
 func (n *NoticeApi) UpdateNotice(c *fiber.Ctx) error {
@@ -115,33 +95,15 @@ func (n *NoticeApi) UpdateNotice(c *fiber.Ctx) error {
 	if err != nil {
 		return errors.InvalidRequest(fmt.Errorf("invalid notice id"))
 	}
-	err = n.NoticeService.UpdateNotice(ctx, &noticeID, req.Title, req.Content, req.NoticeType)
-	var (
-		userID, _  = primitive.ObjectIDFromHex(ctx.Value(config.UserIDKey).(string))
-		ipAddr     = c.IP()
-		userAgent  = c.Get(fiber.HeaderUserAgent)
-		operation  = config.OperationTypeUpdate
-		entityType = config.EntityTypeNotice
-	)
+	version, err := etag.ParseIfMatch(c.Get(fiber.HeaderIfMatch))
+	if err != nil {
+		return errors.PreconditionFailed(err)
+	}
+	err = n.NoticeService.UpdateNotice(ctx, &noticeID, version, req.Title, req.Content, req.NoticeType)
 	if err != nil {
-		var (
-			description = fmt.Sprintf("Update notice failed: %s", err.Error())
-			status      = config.OperationStatusFailure
-		)
-		_ = n.LogsService.CacheOperationLog(
-			ctx, &userID, &noticeID, &ipAddr, &userAgent, &operation, &entityType, &description, &status,
-		)
 		return err
 	}
 
-	var (
-		description = fmt.Sprintf("Update notice: %s", *req.NoticeID)
-		status      = config.OperationStatusSuccess
-	)
-	_ = n.LogsService.CacheOperationLog(
-		ctx, &userID, &noticeID, &ipAddr, &userAgent, &operation, &entityType, &description, &status,
-	)
-
 	return c.JSON(
 		vo.Response{
 			Code:    errors.CodeSuccess,
@@ -160,9 +122,11 @@ func (n *NoticeApi) UpdateNotice(c *fiber.Ctx) error {
 //	@accept			json
 //	@produce		json
 //	@param			admin.DeleteNoticeRequest	query	admin.DeleteNoticeRequest	true	"Delete notice request"
+//	@param			If-Match	header	string	false	"ETag of the version the request is conditional on"
 //	@security		Bearer
 //	@success		200	{object}	vo.Response{data=nil}	"Success"
 //	@failure		400	{object}	vo.Response{data=nil}	"Invalid request"
+//	@failure		412	{object}	vo.Response{data=nil}	"Precondition failed"
 func (n *NoticeApi) DeleteNotice(c *fiber.Ctx) error {
 	ctx := c.UserContext()
 	req := new(admin.DeleteNoticeRequest)
@@ -178,34 +142,16 @@ func (n *NoticeApi) DeleteNotice(c *fiber.Ctx) error {
 	if err != nil {
 		return errors.InvalidRequest(fmt.Errorf("invalid notice id"))
 	}
+	version, err := etag.ParseIfMatch(c.Get(fiber.HeaderIfMatch))
+	if err != nil {
+		return errors.PreconditionFailed(err)
+	}
 
-	var (
-		userID, _  = primitive.ObjectIDFromHex(ctx.Value(config.UserIDKey).(string))
-		ipAddr     = c.IP()
-		userAgent  = c.Get(fiber.HeaderUserAgent)
-		operation  = config.OperationTypeDelete
-		entityType = config.EntityTypeNotice
-	)
-	err = n.NoticeService.DeleteNotice(ctx, &noticeID)
+	err = n.NoticeService.DeleteNotice(ctx, &noticeID, version)
 	if err != nil {
-		var (
-			description = fmt.Sprintf("Delete notice failed: %s", err.Error())
-			status      = config.OperationStatusFailure
-		)
-		_ = n.LogsService.CacheOperationLog(
-			ctx, &userID, &noticeID, &ipAddr, &userAgent, &operation, &entityType, &description, &status,
-		)
 		return err
 	}
 
-	var (
-		description = fmt.Sprintf("Delete notice: %s", *req.NoticeID)
-		status      = config.OperationStatusSuccess
-	)
-	_ = n.LogsService.CacheOperationLog(
-		ctx, &userID, &noticeID, &ipAddr, &userAgent, &operation, &entityType, &description, &status,
-	)
-
 	return c.JSON(
 		vo.Response{
 			Code:    errors.CodeSuccess,
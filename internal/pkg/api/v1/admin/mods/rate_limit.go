@@ -0,0 +1,147 @@
+package mods
+
+import (
+	"fmt"
+	"time"
+
+	"fiber-admin/internal/pkg/domain/entity"
+	"fiber-admin/internal/pkg/domain/vo"
+	"fiber-admin/internal/pkg/domain/vo/admin"
+	sysservice "fiber-admin/internal/pkg/service/sys/mods"
+	"fiber-admin/pkg/errors"
+	"fiber-admin/pkg/utils/common"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type RateLimitApi struct {
+	RateLimitService sysservice.RateLimitService
+	Validator        *validator.Validate
+}
+
+// ListRateLimitOverrides lists the rate limit overrides of users.
+//
+//	@description	List the users whose limit of the user rate limit rules is overridden.
+//	@id				admin-list-rate-limit-overrides
+//	@summary		list rate limit overrides
+//	@tags			Admin API
+//	@accept			json
+//	@produce		json
+//	@security		Bearer
+//	@success		200								{object}	vo.Response{data=admin.ListRateLimitOverridesResponse}	"Success"
+//	@failure		401								{object}	vo.Response{data=nil}									"Unauthorized"
+//	@failure		403								{object}	vo.Response{data=nil}									"Forbidden"
+//	@failure		500								{object}	vo.Response{data=nil}									"Internal server error"
+//	@router			/admin/rate-limit/override/list	[get]
+func (a *RateLimitApi) ListRateLimitOverrides(c *fiber.Ctx) error {
+	overrides, err := a.RateLimitService.ListOverrides(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	resp := &admin.ListRateLimitOverridesResponse{
+		Total:        int64(len(overrides)),
+		OverrideList: make([]*admin.RateLimitOverrideResponse, 0, len(overrides)),
+	}
+	for _, override := range overrides {
+		resp.OverrideList = append(
+			resp.OverrideList, &admin.RateLimitOverrideResponse{
+				UserID:    override.UserID,
+				Max:       override.Max,
+				Exempt:    override.Exempt,
+				UpdatedAt: override.UpdatedAt.Format(time.RFC3339),
+			},
+		)
+	}
+	return c.JSON(
+		vo.Response{
+			Code:    errors.CodeSuccess,
+			Message: errors.MessageSuccess,
+			Data:    resp,
+		},
+	)
+}
+
+// SetRateLimitOverride overrides the rate limit of a user.
+//
+//	@description	Replace the limit of the user rate limit rules for a user, or exempt the user from them. Other instances apply the override once their cached one expires, after the override cache TTL at most.
+//	@id				admin-set-rate-limit-override
+//	@summary		set rate limit override
+//	@tags			Admin API
+//	@accept			json
+//	@produce		json
+//	@param			admin.SetRateLimitOverrideRequest	body	admin.SetRateLimitOverrideRequest	true	"Set rate limit override request"
+//	@security		Bearer
+//	@success		200							{object}	vo.Response{data=nil}	"Success"
+//	@failure		400							{object}	vo.Response{data=nil}	"Invalid request"
+//	@failure		401							{object}	vo.Response{data=nil}	"Unauthorized"
+//	@failure		403							{object}	vo.Response{data=nil}	"Forbidden"
+//	@failure		500							{object}	vo.Response{data=nil}	"Internal server error"
+//	@router			/admin/rate-limit/override	[put]
+func (a *RateLimitApi) SetRateLimitOverride(c *fiber.Ctx) error {
+	req := new(admin.SetRateLimitOverrideRequest)
+
+	if err := c.BodyParser(req); err != nil {
+		return errors.InvalidRequest(fmt.Errorf("failed to parse request"))
+	}
+	if errs := a.Validator.Struct(req); errs != nil {
+		return errors.InvalidRequest(common.FormatValidateError(errs))
+	}
+
+	override := &entity.RateLimitOverride{UserID: *req.UserID}
+	if req.Exempt != nil && *req.Exempt {
+		override.Exempt = true
+	} else {
+		override.Max = *req.Max
+	}
+	if err := a.RateLimitService.SetOverride(c.UserContext(), override); err != nil {
+		return err
+	}
+
+	return c.JSON(
+		vo.Response{
+			Code:    errors.CodeSuccess,
+			Message: errors.MessageSuccess,
+			Data:    nil,
+		},
+	)
+}
+
+// DeleteRateLimitOverride deletes the rate limit override of a user.
+//
+//	@description	Delete the rate limit override of a user, who is limited by the user rate limit rules again.
+//	@id				admin-delete-rate-limit-override
+//	@summary		delete rate limit override
+//	@tags			Admin API
+//	@accept			json
+//	@produce		json
+//	@param			admin.DeleteRateLimitOverrideRequest	query	admin.DeleteRateLimitOverrideRequest	true	"Delete rate limit override request"
+//	@security		Bearer
+//	@success		200							{object}	vo.Response{data=nil}	"Success"
+//	@failure		400							{object}	vo.Response{data=nil}	"Invalid request"
+//	@failure		401							{object}	vo.Response{data=nil}	"Unauthorized"
+//	@failure		403							{object}	vo.Response{data=nil}	"Forbidden"
+//	@failure		500							{object}	vo.Response{data=nil}	"Internal server error"
+//	@router			/admin/rate-limit/override	[delete]
+func (a *RateLimitApi) DeleteRateLimitOverride(c *fiber.Ctx) error {
+	req := new(admin.DeleteRateLimitOverrideRequest)
+
+	if err := c.QueryParser(req); err != nil {
+		return errors.InvalidRequest(fmt.Errorf("failed to parse request"))
+	}
+	if errs := a.Validator.Struct(req); errs != nil {
+		return errors.InvalidRequest(common.FormatValidateError(errs))
+	}
+
+	if err := a.RateLimitService.DeleteOverride(c.UserContext(), *req.UserID); err != nil {
+		return err
+	}
+
+	return c.JSON(
+		vo.Response{
+			Code:    errors.CodeSuccess,
+			Message: errors.MessageSuccess,
+			Data:    nil,
+		},
+	)
+}
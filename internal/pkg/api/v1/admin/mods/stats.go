@@ -0,0 +1,206 @@
+package mods
+
+import (
+	"fmt"
+	"time"
+
+	"fiber-admin/internal/pkg/domain/vo"
+	"fiber-admin/internal/pkg/domain/vo/admin"
+	adminservice "fiber-admin/internal/pkg/service/admin/mods"
+	"fiber-admin/pkg/errors"
+	"fiber-admin/pkg/utils/common"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type StatsApi struct {
+	StatsService adminservice.StatsService
+	Validator    *validator.Validate
+}
+
+// GetUserStats returns the user statistics.
+//
+//	@description	Count the users by role and by organization.
+//	@id				admin-get-user-stats
+//	@summary		get user stats
+//	@tags			Admin API
+//	@accept			json
+//	@produce		json
+//	@security		Bearer
+//	@success		200					{object}	vo.Response{data=admin.GetUserStatsResponse}	"Success"
+//	@failure		401					{object}	vo.Response{data=nil}							"Unauthorized"
+//	@failure		403					{object}	vo.Response{data=nil}							"Forbidden"
+//	@failure		500					{object}	vo.Response{data=nil}							"Internal server error"
+//	@router			/admin/stats/user	[get]
+func (s *StatsApi) GetUserStats(c *fiber.Ctx) error {
+	resp, err := s.StatsService.GetUserStats(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(
+		vo.Response{
+			Code:    errors.CodeSuccess,
+			Message: errors.MessageSuccess,
+			Data:    resp,
+		},
+	)
+}
+
+// GetNewUserStats returns the number of new users over time.
+//
+//	@description	Count the users created in each time bucket of the range. The range defaults to the last 30 days in daily buckets.
+//	@id				admin-get-new-user-stats
+//	@summary		get new user stats
+//	@tags			Admin API
+//	@accept			json
+//	@produce		json
+//	@param			admin.GetStatsSeriesRequest	query	admin.GetStatsSeriesRequest	true	"Get stats series request"
+//	@security		Bearer
+//	@success		200						{object}	vo.Response{data=admin.GetStatsSeriesResponse}	"Success"
+//	@failure		400						{object}	vo.Response{data=nil}							"Invalid request"
+//	@failure		401						{object}	vo.Response{data=nil}							"Unauthorized"
+//	@failure		403						{object}	vo.Response{data=nil}							"Forbidden"
+//	@failure		500						{object}	vo.Response{data=nil}							"Internal server error"
+//	@router			/admin/stats/user/new	[get]
+func (s *StatsApi) GetNewUserStats(c *fiber.Ctx) error {
+	req, startTime, endTime, err := s.parseSeriesRequest(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.StatsService.GetNewUserStats(c.UserContext(), req.Bucket, startTime, endTime)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(
+		vo.Response{
+			Code:    errors.CodeSuccess,
+			Message: errors.MessageSuccess,
+			Data:    resp,
+		},
+	)
+}
+
+// GetActiveUserStats returns the number of active users over time.
+//
+//	@description	Count the distinct users who logged in in each time bucket of the range. The range defaults to the last 30 days in daily buckets.
+//	@id				admin-get-active-user-stats
+//	@summary		get active user stats
+//	@tags			Admin API
+//	@accept			json
+//	@produce		json
+//	@param			admin.GetStatsSeriesRequest	query	admin.GetStatsSeriesRequest	true	"Get stats series request"
+//	@security		Bearer
+//	@success		200							{object}	vo.Response{data=admin.GetStatsSeriesResponse}	"Success"
+//	@failure		400							{object}	vo.Response{data=nil}							"Invalid request"
+//	@failure		401							{object}	vo.Response{data=nil}							"Unauthorized"
+//	@failure		403							{object}	vo.Response{data=nil}							"Forbidden"
+//	@failure		500							{object}	vo.Response{data=nil}							"Internal server error"
+//	@router			/admin/stats/user/active	[get]
+func (s *StatsApi) GetActiveUserStats(c *fiber.Ctx) error {
+	req, startTime, endTime, err := s.parseSeriesRequest(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.StatsService.GetActiveUserStats(c.UserContext(), req.Bucket, startTime, endTime)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(
+		vo.Response{
+			Code:    errors.CodeSuccess,
+			Message: errors.MessageSuccess,
+			Data:    resp,
+		},
+	)
+}
+
+// GetOperationStats returns the number of operations over time.
+//
+//	@description	Count the operation logs of each operation type and status in each time bucket of the range. The range defaults to the last 30 days in daily buckets.
+//	@id				admin-get-operation-stats
+//	@summary		get operation stats
+//	@tags			Admin API
+//	@accept			json
+//	@produce		json
+//	@param			admin.GetStatsSeriesRequest	query	admin.GetStatsSeriesRequest	true	"Get stats series request"
+//	@security		Bearer
+//	@success		200						{object}	vo.Response{data=admin.GetOperationStatsResponse}	"Success"
+//	@failure		400						{object}	vo.Response{data=nil}								"Invalid request"
+//	@failure		401						{object}	vo.Response{data=nil}								"Unauthorized"
+//	@failure		403						{object}	vo.Response{data=nil}								"Forbidden"
+//	@failure		500						{object}	vo.Response{data=nil}								"Internal server error"
+//	@router			/admin/stats/operation	[get]
+func (s *StatsApi) GetOperationStats(c *fiber.Ctx) error {
+	req, startTime, endTime, err := s.parseSeriesRequest(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.StatsService.GetOperationStats(c.UserContext(), req.Bucket, startTime, endTime)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(
+		vo.Response{
+			Code:    errors.CodeSuccess,
+			Message: errors.MessageSuccess,
+			Data:    resp,
+		},
+	)
+}
+
+// GetContentStats returns the notice and documentation statistics.
+//
+//	@description	Count the notices by type and the documentations by category.
+//	@id				admin-get-content-stats
+//	@summary		get content stats
+//	@tags			Admin API
+//	@accept			json
+//	@produce		json
+//	@security		Bearer
+//	@success		200						{object}	vo.Response{data=admin.GetContentStatsResponse}	"Success"
+//	@failure		401						{object}	vo.Response{data=nil}							"Unauthorized"
+//	@failure		403						{object}	vo.Response{data=nil}							"Forbidden"
+//	@failure		500						{object}	vo.Response{data=nil}							"Internal server error"
+//	@router			/admin/stats/content	[get]
+func (s *StatsApi) GetContentStats(c *fiber.Ctx) error {
+	resp, err := s.StatsService.GetContentStats(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(
+		vo.Response{
+			Code:    errors.CodeSuccess,
+			Message: errors.MessageSuccess,
+			Data:    resp,
+		},
+	)
+}
+
+func (s *StatsApi) parseSeriesRequest(c *fiber.Ctx) (*admin.GetStatsSeriesRequest, *time.Time, *time.Time, error) {
+	req := new(admin.GetStatsSeriesRequest)
+
+	if err := c.QueryParser(req); err != nil {
+		return nil, nil, nil, errors.InvalidRequest(fmt.Errorf("failed to parse request"))
+	}
+	if errs := s.Validator.Struct(req); errs != nil {
+		return nil, nil, nil, errors.InvalidRequest(common.FormatValidateError(errs))
+	}
+
+	startTime, err := parseTime("start time", req.StartTime)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	endTime, err := parseTime("end time", req.EndTime)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return req, startTime, endTime, nil
+}
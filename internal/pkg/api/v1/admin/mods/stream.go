@@ -0,0 +1,37 @@
+package mods
+
+import (
+	"bufio"
+	"net"
+	"time"
+)
+
+// streamWriteTimeout bounds each write of a streamed response. The server write timeout covers the whole response and
+// would cut long exports and tails short, so streams push the deadline forward before every write instead.
+const streamWriteTimeout = 30 * time.Second
+
+// streamWriter writes a streamed response, pushing the write deadline of the connection forward before each write.
+type streamWriter struct {
+	w    *bufio.Writer
+	conn net.Conn
+}
+
+func newStreamWriter(w *bufio.Writer, conn net.Conn) *streamWriter {
+	return &streamWriter{w: w, conn: conn}
+}
+
+func (s *streamWriter) extendDeadline() {
+	if s.conn != nil {
+		_ = s.conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+	}
+}
+
+func (s *streamWriter) Write(p []byte) (int, error) {
+	s.extendDeadline()
+	return s.w.Write(p)
+}
+
+func (s *streamWriter) Flush() error {
+	s.extendDeadline()
+	return s.w.Flush()
+}
@@ -8,9 +8,9 @@ import (
 	"fiber-admin/internal/pkg/domain/vo"
 	"fiber-admin/internal/pkg/domain/vo/admin"
 	adminservice "fiber-admin/internal/pkg/service/admin/mods"
-	sysservice "fiber-admin/internal/pkg/service/sys/mods"
 	"fiber-admin/pkg/errors"
 	"fiber-admin/pkg/utils/common"
+	"fiber-admin/pkg/utils/etag"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -18,7 +18,6 @@ import (
 
 type UserApi struct {
 	UserService adminservice.UserService
-	LogsService sysservice.LogsService
 	Validator   *validator.Validate
 }
 
@@ -52,32 +51,11 @@ func (u *UserApi) InsertUser(c *fiber.Ctx) error {
 	userIDHex, err := u.UserService.InsertUser(
 		ctx, req.Username, req.Email, req.Password, req.Organization,
 	)
-	userID, _ := primitive.ObjectIDFromHex(userIDHex)
-	var (
-		operatorID, _ = primitive.ObjectIDFromHex(ctx.Value(config.UserIDKey).(string))
-		ipAddr        = c.IP()
-		userAgent     = c.Get(fiber.HeaderUserAgent)
-		operation     = config.OperationTypeCreate
-		entityType    = config.EntityTypeUser
-	)
 	if err != nil {
-		var (
-			description = fmt.Sprintf("Failed to insert user %s", *req.Username)
-			status      = config.OperationStatusFailure
-		)
-		_ = u.LogsService.CacheOperationLog(
-			ctx, &operatorID, &userID, &ipAddr, &userAgent, &operation, &entityType, &description, &status,
-		)
 		return err
 	}
+	c.Locals(config.OperationLogEntityIDKey, userIDHex)
 
-	var (
-		description = fmt.Sprintf("Insert user %s", *req.Username)
-		status      = config.OperationStatusSuccess
-	)
-	_ = u.LogsService.CacheOperationLog(
-		c.UserContext(), &operatorID, &userID, &ipAddr, &userAgent, &operation, &entityType, &description, &status,
-	)
 	return c.JSON(
 		vo.Response{
 			Code:    errors.CodeSuccess,
@@ -96,8 +74,11 @@ func (u *UserApi) InsertUser(c *fiber.Ctx) error {
 //	@accept			json
 //	@produce		json
 //	@param			admin.GetUserRequest	query	admin.GetUserRequest	true	"Get user request"
+//	@param			If-None-Match	header	string	false	"ETag of the cached version"
 //	@security		Bearer
 //	@success		200			{object}	vo.Response{data=admin.GetUserResponse}	"Success"
+//	@success		304	"Not modified"
+//	@header			200	{string}	ETag	"Version of the entity"
 //	@failure		400			{object}	vo.Response{data=nil}					"Invalid request"
 //	@failure		401			{object}	vo.Response{data=nil}					"Unauthorized"
 //	@failure		403			{object}	vo.Response{data=nil}					"Forbidden"
@@ -123,6 +104,11 @@ func (u *UserApi) GetUser(c *fiber.Ctx) error {
 	if err != nil {
 		return err
 	}
+	tag := etag.Format(resp.Version)
+	c.Set(fiber.HeaderETag, tag)
+	if !etag.NoneMatch(c.Get(fiber.HeaderIfNoneMatch), tag) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
 
 	return c.JSON(
 		vo.Response{
@@ -234,11 +220,13 @@ func (u *UserApi) GetUserList(c *fiber.Ctx) error {
 //	@accept			json
 //	@produce		json
 //	@param			admin.UpdateUserRequest	body	admin.UpdateUserRequest	true	"Update user request"
+//	@param			If-Match	header	string	false	"ETag of the version the request is conditional on"
 //	@security		Bearer
 //	@success		200			{object}	vo.Response{data=nil}	"Success"
 //	@failure		400			{object}	vo.Response{data=nil}	"Invalid request"
 //	@failure		401			{object}	vo.Response{data=nil}	"Unauthorized"
 //	@failure		403			{object}	vo.Response{data=nil}	"Forbidden"
+//	@failure		412			{object}	vo.Response{data=nil}	"Precondition failed"
 //	@failure		500			{object}	vo.Response{data=nil}	"Internal server error"
 //	@router			/admin/user																																																																																																																																																																																		[put]
 func (u *UserApi) UpdateUser(c *fiber.Ctx) error {
@@ -256,35 +244,15 @@ func (u *UserApi) UpdateUser(c *fiber.Ctx) error {
 	if err != nil {
 		return errors.InvalidRequest(fmt.Errorf("invalid user id"))
 	}
+	version, err := etag.ParseIfMatch(c.Get(fiber.HeaderIfMatch))
+	if err != nil {
+		return errors.PreconditionFailed(err)
+	}
 
-	err = u.UserService.UpdateUser(ctx, &userID, req.Username, req.Email, req.Organization)
-
-	var (
-		operatorID, _ = primitive.ObjectIDFromHex(ctx.Value(config.UserIDKey).(string))
-		ipAddr        = c.IP()
-		userAgent     = c.Get(fiber.HeaderUserAgent)
-		operation     = config.OperationTypeUpdate
-		entityType    = config.EntityTypeUser
-	)
-
+	err = u.UserService.UpdateUser(ctx, &userID, version, req.Username, req.Email, req.Organization)
 	if err != nil {
-		var (
-			description = fmt.Sprintf("Failed to update user %s", *req.Username)
-			status      = config.OperationStatusFailure
-		)
-		_ = u.LogsService.CacheOperationLog(
-			ctx, &operatorID, &userID, &ipAddr, &userAgent, &operation, &entityType, &description, &status,
-		)
 		return err
 	}
-
-	var (
-		description = fmt.Sprintf("Update user %s", *req.Username)
-		status      = config.OperationStatusSuccess
-	)
-	_ = u.LogsService.CacheOperationLog(
-		ctx, &operatorID, &userID, &ipAddr, &userAgent, &operation, &entityType, &description, &status,
-	)
 	return c.JSON(
 		vo.Response{
 			Code:    errors.CodeSuccess,
@@ -303,11 +271,13 @@ func (u *UserApi) UpdateUser(c *fiber.Ctx) error {
 //	@accept			json
 //	@produce		json
 //	@param			admin.DeleteUserRequest	query	admin.DeleteUserRequest	true	"Delete user request"
+//	@param			If-Match	header	string	false	"ETag of the version the request is conditional on"
 //	@security		Bearer
 //	@success		200			{object}	vo.Response{data=nil}	"Success"
 //	@failure		400			{object}	vo.Response{data=nil}	"Invalid request"
 //	@failure		401			{object}	vo.Response{data=nil}	"Unauthorized"
 //	@failure		403			{object}	vo.Response{data=nil}	"Forbidden"
+//	@failure		412			{object}	vo.Response{data=nil}	"Precondition failed"
 //	@failure		500			{object}	vo.Response{data=nil}	"Internal server error"
 //	@router			/admin/user																																																																																																																																																																																					[delete]
 func (u *UserApi) DeleteUser(c *fiber.Ctx) error {
@@ -325,34 +295,14 @@ func (u *UserApi) DeleteUser(c *fiber.Ctx) error {
 	if err != nil {
 		return errors.InvalidRequest(fmt.Errorf("invalid user id"))
 	}
-	err = u.UserService.DeleteUser(ctx, &userID)
-
-	var (
-		operatorID, _ = primitive.ObjectIDFromHex(ctx.Value(config.UserIDKey).(string))
-		ipAddr        = c.IP()
-		userAgent     = c.Get(fiber.HeaderUserAgent)
-		operation     = config.OperationTypeDelete
-		entityType    = config.EntityTypeUser
-	)
-
+	version, err := etag.ParseIfMatch(c.Get(fiber.HeaderIfMatch))
+	if err != nil {
+		return errors.PreconditionFailed(err)
+	}
+	err = u.UserService.DeleteUser(ctx, &userID, version)
 	if err != nil {
-		var (
-			description = fmt.Sprintf("Failed to delete user %s", *req.UserID)
-			status      = config.OperationStatusFailure
-		)
-		_ = u.LogsService.CacheOperationLog(
-			ctx, &operatorID, &userID, &ipAddr, &userAgent, &operation, &entityType, &description, &status,
-		)
 		return err
 	}
-
-	var (
-		description = fmt.Sprintf("Delete user %s", *req.UserID)
-		status      = config.OperationStatusSuccess
-	)
-	_ = u.LogsService.CacheOperationLog(
-		ctx, &operatorID, &userID, &ipAddr, &userAgent, &operation, &entityType, &description, &status,
-	)
 	return c.JSON(
 		vo.Response{
 			Code:    errors.CodeSuccess,
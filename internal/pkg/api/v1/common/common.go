@@ -10,4 +10,5 @@ type Common struct {
 	DocumentationApi *mods.DocumentationApi
 	NoticeApi        *mods.NoticeApi
 	IdempotencyApi   *mods.IdempotencyApi
+	SearchApi        *mods.SearchApi
 }
@@ -4,11 +4,13 @@ import (
 	"fmt"
 	"time"
 
+	"fiber-admin/internal/pkg/config"
 	"fiber-admin/internal/pkg/domain/vo"
 	"fiber-admin/internal/pkg/domain/vo/common"
 	commonservice "fiber-admin/internal/pkg/service/common/mods"
 	"fiber-admin/pkg/errors"
 	utils "fiber-admin/pkg/utils/common"
+	"fiber-admin/pkg/utils/etag"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -19,17 +21,20 @@ type DocumentationApi struct {
 	Validator            *validator.Validate
 }
 
-// GetDocumentation returns the documentation by ID.
+// GetDocumentation returns the documentation by ID or slug.
 //
-//	@description	Get the documentation by ID.
+//	@description	Get the documentation by ID, or by slug when no ID is given. Set format to html to get the content rendered as sanitized HTML with a table of contents.
 //	@id				common-get-documentation
-//	@summary		get documentation by ID
+//	@summary		get documentation by ID or slug
 //	@tags			Documentation API
 //	@accept			json
 //	@produce		json
 //	@param			common.GetDocumentationRequest	query	common.GetDocumentationRequest	true	"Get documentation request"
+//	@param			If-None-Match	header	string	false	"ETag of the cached version"
 //	@security		Bearer
 //	@success		200				{object}	vo.Response{data=common.GetDocumentationResponse}	"Success"
+//	@success		304	"Not modified"
+//	@header			200	{string}	ETag	"Version of the entity, suffixed with -html for the html format"
 //	@failure		400				{object}	vo.Response{data=nil}								"Invalid request"
 //	@failure		401				{object}	vo.Response{data=nil}								"Unauthorized"
 //	@failure		404				{object}	vo.Response{data=nil}								"Documentation not found"
@@ -45,14 +50,26 @@ func (d DocumentationApi) GetDocumentation(c *fiber.Ctx) error {
 		return errors.InvalidRequest(utils.FormatValidateError(errs))
 	}
 
-	documentationID, err := primitive.ObjectIDFromHex(*req.DocumentationID)
-	if err != nil {
-		return errors.InvalidRequest(fmt.Errorf("failed to parse request"))
+	var documentationID *primitive.ObjectID
+	if req.DocumentationID != nil {
+		id, err := primitive.ObjectIDFromHex(*req.DocumentationID)
+		if err != nil {
+			return errors.InvalidRequest(fmt.Errorf("failed to parse request"))
+		}
+		documentationID = &id
 	}
-	resp, err := d.DocumentationService.GetDocumentation(c.UserContext(), &documentationID)
+	resp, err := d.DocumentationService.GetDocumentation(c.UserContext(), documentationID, req.Slug, req.Format)
 	if err != nil {
 		return err
 	}
+	tag := etag.Format(resp.Version)
+	if resp.Format == config.ContentFormatHTML {
+		tag = etag.FormatRepresentation(resp.Version, resp.Format)
+	}
+	c.Set(fiber.HeaderETag, tag)
+	if !etag.NoneMatch(c.Get(fiber.HeaderIfNoneMatch), tag) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
 	return c.JSON(
 		vo.Response{
 			Code:    errors.CodeSuccess,
@@ -107,8 +124,35 @@ func (d DocumentationApi) GetDocumentationList(c *fiber.Ctx) error {
 	}
 
 	resp, err := d.DocumentationService.GetDocumentationList(
-		c.UserContext(), req.Page, req.PageSize, updateBeforePtr, updateAfterPtr,
+		c.UserContext(), req.Page, req.PageSize, updateBeforePtr, updateAfterPtr, req.Category, req.Tag,
+	)
+	if err != nil {
+		return err
+	}
+	return c.JSON(
+		vo.Response{
+			Code:    errors.CodeSuccess,
+			Message: errors.MessageSuccess,
+			Data:    resp,
+		},
 	)
+}
+
+// GetDocumentationTree returns the documentation tree.
+//
+//	@description	Get every documentation nested under its parent and ordered by position, without content.
+//	@id				common-get-documentation-tree
+//	@summary		get documentation tree
+//	@tags			Documentation API
+//	@accept			json
+//	@produce		json
+//	@security		Bearer
+//	@success		200					{object}	vo.Response{data=common.GetDocumentationTreeResponse}	"Success"
+//	@failure		401					{object}	vo.Response{data=nil}									"Unauthorized"
+//	@failure		500					{object}	vo.Response{data=nil}									"Internal server error"
+//	@router			/documentation/tree	[get]
+func (d DocumentationApi) GetDocumentationTree(c *fiber.Ctx) error {
+	resp, err := d.DocumentationService.GetDocumentationTree(c.UserContext())
 	if err != nil {
 		return err
 	}
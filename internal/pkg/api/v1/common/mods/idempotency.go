@@ -13,7 +13,7 @@ type IdempotencyApi struct {
 
 // GenerateIdempotencyToken generates an idempotency token.
 //
-//	@description	Generate an idempotency token.
+//	@description	Generate an idempotency token, for the Idempotency-Key header. Tokens are not issued in advance: any unique key works as well.
 //	@id				common-generate-idempotency-token
 //	@summary		generate idempotency token
 //	@tags			Common API
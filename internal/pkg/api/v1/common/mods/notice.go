@@ -4,11 +4,13 @@ import (
 	"fmt"
 	"time"
 
+	"fiber-admin/internal/pkg/config"
 	"fiber-admin/internal/pkg/domain/vo"
 	"fiber-admin/internal/pkg/domain/vo/common"
 	commonservice "fiber-admin/internal/pkg/service/common/mods"
 	"fiber-admin/pkg/errors"
 	utils "fiber-admin/pkg/utils/common"
+	"fiber-admin/pkg/utils/etag"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -21,15 +23,18 @@ type NoticeApi struct {
 
 // GetNotice returns the notice by ID.
 //
-//	@description	Get the notice by ID.
+//	@description	Get the notice by ID. Set format to html to get the content rendered as sanitized HTML with a table of contents.
 //	@id				common-get-notice
 //	@summary		get notice by ID
 //	@tags			Notice API
 //	@accept			json
 //	@produce		json
 //	@param			common.GetNoticeRequest	query	common.GetNoticeRequest	true	"Get notice request"
+//	@param			If-None-Match	header	string	false	"ETag of the cached version"
 //	@security		Bearer
 //	@success		200		{object}	vo.Response{data=common.GetNoticeResponse}	"Success"
+//	@success		304	"Not modified"
+//	@header			200	{string}	ETag	"Version of the entity, suffixed with -html for the html format"
 //	@failure		400		{object}	vo.Response{data=nil}						"Invalid request"
 //	@failure		401		{object}	vo.Response{data=nil}						"Unauthorized"
 //	@failure		404		{object}	vo.Response{data=nil}						"Notice not found"
@@ -49,10 +54,18 @@ func (n *NoticeApi) GetNotice(c *fiber.Ctx) error {
 	if err != nil {
 		return errors.InvalidRequest(fmt.Errorf("invalid notice id"))
 	}
-	resp, err := n.NoticeService.GetNotice(c.UserContext(), &noticeID)
+	resp, err := n.NoticeService.GetNotice(c.UserContext(), &noticeID, req.Format)
 	if err != nil {
 		return err
 	}
+	tag := etag.Format(resp.Version)
+	if resp.Format == config.ContentFormatHTML {
+		tag = etag.FormatRepresentation(resp.Version, resp.Format)
+	}
+	c.Set(fiber.HeaderETag, tag)
+	if !etag.NoneMatch(c.Get(fiber.HeaderIfNoneMatch), tag) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
 	return c.JSON(
 		vo.Response{
 			Code:    errors.CodeSuccess,
@@ -0,0 +1,56 @@
+package mods
+
+import (
+	"fmt"
+
+	"fiber-admin/internal/pkg/domain/vo"
+	"fiber-admin/internal/pkg/domain/vo/common"
+	commonservice "fiber-admin/internal/pkg/service/common/mods"
+	"fiber-admin/pkg/errors"
+	utils "fiber-admin/pkg/utils/common"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type SearchApi struct {
+	SearchService commonservice.SearchService
+	Validator     *validator.Validate
+}
+
+// Search returns the documentation and notices matching the query, ranked by relevance.
+//
+//	@description	Full-text search across documentation and notices. Titles and snippets are HTML-escaped with the matched terms highlighted.
+//	@id				common-search
+//	@summary		search documentation and notices
+//	@tags			Search API
+//	@accept			json
+//	@produce		json
+//	@param			common.SearchRequest	query	common.SearchRequest	true	"Search request"
+//	@security		Bearer
+//	@success		200		{object}	vo.Response{data=common.SearchResponse}	"Success"
+//	@failure		400		{object}	vo.Response{data=nil}					"Invalid request"
+//	@failure		401		{object}	vo.Response{data=nil}					"Unauthorized"
+//	@failure		500		{object}	vo.Response{data=nil}					"Internal server error"
+//	@router			/search	[get]
+func (s *SearchApi) Search(c *fiber.Ctx) error {
+	req := new(common.SearchRequest)
+
+	if err := c.QueryParser(req); err != nil {
+		return errors.InvalidRequest(fmt.Errorf("failed to parse request"))
+	}
+	if errs := s.Validator.Struct(req); errs != nil {
+		return errors.InvalidRequest(utils.FormatValidateError(errs))
+	}
+
+	resp, err := s.SearchService.Search(c.UserContext(), *req.Query, req.Scope, req.Page, req.PageSize)
+	if err != nil {
+		return err
+	}
+	return c.JSON(
+		vo.Response{
+			Code:    errors.CodeSuccess,
+			Message: errors.MessageSuccess,
+			Data:    resp,
+		},
+	)
+}
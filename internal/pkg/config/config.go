@@ -2,40 +2,58 @@ package config
 
 import (
 	"sync"
+	"sync/atomic"
 
 	"fiber-admin/internal/pkg/config/mods"
 	"github.com/mcuadros/go-defaults"
 )
 
 var (
-	configInstance = &Config{}
+	configInstance atomic.Pointer[Config]
 	once           sync.Once
 )
 
 type Config struct {
-	BaseConfig        mods.BaseConfig        `mapstructure:"base" yaml:"base"`
-	CasbinConfig      mods.CasbinConfig      `mapstructure:"casbin" yaml:"casbin"`
-	FiberConfig       mods.FiberConfig       `mapstructure:"fiber" yaml:"fiber"`
-	JWTConfig         mods.JWTConfig         `mapstructure:"jwt" yaml:"jwt"`
-	MongoConfig       mods.MongoConfig       `mapstructure:"mongo" yaml:"mongo"`
-	PrometheusConfig  mods.PrometheusConfig  `mapstructure:"prometheus" yaml:"prometheus"`
-	MiddlewareConfig  mods.MiddlewareConfig  `mapstructure:"middleware" yaml:"middleware"`
-	CacheConfig       mods.CacheConfig       `mapstructure:"cache" yaml:"cache"`
-	TasksConfig       mods.TasksConfig       `mapstructure:"tasks" yaml:"tasks"`
-	ZapConfig         mods.ZapConfig         `mapstructure:"zap" yaml:"zap"`
-	IdempotencyConfig mods.IdempotencyConfig `mapstructure:"idempotency" yaml:"idempotency"`
+	BaseConfig           mods.BaseConfig           `mapstructure:"base" yaml:"base"`
+	CasbinConfig         mods.CasbinConfig         `mapstructure:"casbin" yaml:"casbin"`
+	FiberConfig          mods.FiberConfig          `mapstructure:"fiber" yaml:"fiber"`
+	JWTConfig            mods.JWTConfig            `mapstructure:"jwt" yaml:"jwt"`
+	MongoConfig          mods.MongoConfig          `mapstructure:"mongo" yaml:"mongo"`
+	PrometheusConfig     mods.PrometheusConfig     `mapstructure:"prometheus" yaml:"prometheus"`
+	MiddlewareConfig     mods.MiddlewareConfig     `mapstructure:"middleware" yaml:"middleware"`
+	CacheConfig          mods.CacheConfig          `mapstructure:"cache" yaml:"cache"`
+	TasksConfig          mods.TasksConfig          `mapstructure:"tasks" yaml:"tasks"`
+	ZapConfig            mods.ZapConfig            `mapstructure:"zap" yaml:"zap"`
+	IdempotencyConfig    mods.IdempotencyConfig    `mapstructure:"idempotency" yaml:"idempotency"`
+	SearchConfig         mods.SearchConfig         `mapstructure:"search" yaml:"search"`
+	MarkdownConfig       mods.MarkdownConfig       `mapstructure:"markdown" yaml:"markdown"`
+	AuditConfig          mods.AuditConfig          `mapstructure:"audit" yaml:"audit"`
+	LogQueueConfig       mods.LogQueueConfig       `mapstructure:"log_queue" yaml:"log_queue"`
+	RetentionConfig      mods.RetentionConfig      `mapstructure:"retention" yaml:"retention"`
+	LoginAnalyticsConfig mods.LoginAnalyticsConfig `mapstructure:"login_analytics" yaml:"login_analytics"`
+	StatsConfig          mods.StatsConfig          `mapstructure:"stats" yaml:"stats"`
+	LogTailConfig        mods.LogTailConfig        `mapstructure:"log_tail" yaml:"log_tail"`
 }
 
 // New returns instance of Config
 func New() *Config {
 	once.Do(
 		func() {
-			defaults.SetDefaults(configInstance)
+			cfg := new(Config)
+			defaults.SetDefaults(cfg)
+			configInstance.CompareAndSwap(nil, cfg)
 		},
 	)
-	return configInstance
+	return configInstance.Load()
 }
 
+// Set replaces the instance of Config. A config is never changed once set, Reload sets a new one instead.
 func Set(cfg *Config) {
-	configInstance = cfg
+	configInstance.Store(cfg)
+}
+
+// Applied returns the config last set, on startup or by Reload, which the next reload is compared against. It is not
+// the config the components read: they keep the one they were built with, and only the subscriptions apply changes.
+func Applied() *Config {
+	return New()
 }
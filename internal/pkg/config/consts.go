@@ -8,6 +8,8 @@ import (
 const (
 	UserIDKey    = zap.UserIDKey
 	RequestIDKey = zap.RequestIDKey
+
+	OperationLogEntityIDKey = "OperationLogEntityID"
 )
 
 // Enum Values
@@ -18,37 +20,104 @@ const (
 	OperationTypeCreate = "CREATE"
 	OperationTypeUpdate = "UPDATE"
 	OperationTypeDelete = "DELETE"
+	OperationTypeRead   = "READ"   // Access to a sensitive resource
+	OperationTypeExport = "EXPORT" // Export of a sensitive resource
 
 	EntityTypeUser          = "USER"
 	EntityTypeDocumentation = "DOCUMENTATION"
 	EntityTypeNotice        = "NOTICE"
+	EntityTypeLoginLog      = "LOGIN_LOG"
+	EntityTypeOperationLog  = "OPERATION_LOG"
+	EntityTypeCache         = "CACHE"
+	EntityTypeRateLimit     = "RATE_LIMIT"
 
 	OperationStatusSuccess = "SUCCESS"
 	OperationStatusFailure = "FAILURE"
 
 	UserRoleUser  = "USER"
 	UserRoleAdmin = "ADMIN"
+
+	SearchScopeAll = "ALL"
+
+	ContentFormatMarkdown = "markdown"
+	ContentFormatHTML     = "html"
+
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+
+	LoginFlagNewCountry       = "NEW_COUNTRY"
+	LoginFlagNewDevice        = "NEW_DEVICE"
+	LoginFlagImpossibleTravel = "IMPOSSIBLE_TRAVEL"
+
+	StatsBucketHour  = "hour"
+	StatsBucketDay   = "day"
+	StatsBucketWeek  = "week"
+	StatsBucketMonth = "month"
+
+	LogTailTypeOperation = "operation"
+	LogTailTypeRequest   = "request"
+
+	CacheEntityUser              = "user"
+	CacheEntityNotice            = "notice"
+	CacheEntityDocumentation     = "documentation"
+	CacheEntityStats             = "stats"
+	CacheEntityRateLimitOverride = "rate_limit_override" // Not cached locally, the rate limit service does
+
+	RateLimitIdentityIP     = "ip"
+	RateLimitIdentityUser   = "user"
+	RateLimitIdentityAPIKey = "api_key"
 )
 
 // MongoDB Collection Name
 const (
-	DocumentationCollectionName = "documentation"
-	NoticeCollectionName        = "notice"
-	LoginLogCollectionName      = "login_log"
-	OperationLogCollectionName  = "operation_log"
-	UserCollectionName          = "user"
+	DocumentationCollectionName          = "documentation"
+	DocumentationRevisionCollectionName  = "documentation_revision"
+	NoticeCollectionName                 = "notice"
+	LoginLogCollectionName               = "login_log"
+	OperationLogCollectionName           = "operation_log"
+	OperationLogChainCollectionName      = "operation_log_chain"
+	OperationLogCheckpointCollectionName = "operation_log_checkpoint"
+	OperationLogImportCollectionName     = "operation_log_import" // Imported archives, apart from the chain
+	RateLimitOverrideCollectionName      = "rate_limit_override"
+	UserCollectionName                   = "user"
+)
+
+// cache Backend
+const (
+	CacheBackendRedis  = "redis"
+	CacheBackendMemory = "memory"
 )
 
-// cache Prefix / Key
+// config Reload result
 const (
-	NoticeCachePrefix         = "dao:notice"
-	UserCachePrefix           = "dao:user"
-	DocumentationCachePrefix  = "dao:documentation"
-	TokenBlacklistCachePrefix = "token:blacklist"
-	IdempotencyCachePrefix    = "idempotency"
-
-	LoginLogCacheKey     = "log:login"
-	OperationLogCacheKey = "log:operation"
+	ReloadApplied    = "applied"
+	ReloadUnchanged  = "unchanged"
+	ReloadInvalid    = "invalid"     // The config could not be read or is not valid
+	ReloadRolledBack = "rolled_back" // A subscription failed to apply the config
+)
+
+// cache Prefix / Key, under the cache namespace
+const (
+	DaoCachePrefix               = "dao" // Prefix of the DAO caches, the only ones which may be purged by prefix
+	NoticeCachePrefix            = "dao:notice"
+	UserCachePrefix              = "dao:user"
+	DocumentationCachePrefix     = "dao:documentation"
+	TokenBlacklistCachePrefix    = "token:blacklist"
+	IdempotencyCachePrefix       = "idempotency"
+	StatsCachePrefix             = "dao:stats"
+	RateLimitCachePrefix         = "ratelimit"              // Counter of each identity and window, e.g. ratelimit:user:/api/v1/admin:1m0s:<id>:<index>
+	RateLimitOverrideCachePrefix = "dao:ratelimit:override" // Override of each user, e.g. dao:ratelimit:override:g3:userID:<user id>
+	CacheGenerationPrefix        = "cache:generation"       // Generation of each cache prefix, e.g. cache:generation:dao:notice
+	CacheInvalidationChannel     = "cache:invalidation"     // Pub/sub channel of the prefixes invalidated by Invalidate
+
+	LoginLogStreamKey          = "log:stream:login"
+	LoginLogDeadLetterKey      = "log:dead-letter:login"
+	OperationLogStreamKey      = "log:stream:operation"
+	OperationLogDeadLetterKey  = "log:dead-letter:operation"
+	LogStreamConsumerGroup     = "log-sync"
+	LegacyLoginLogCacheKey     = "log:login"     // List the login logs were queued in before the stream
+	LegacyOperationLogCacheKey = "log:operation" // List the operation logs were queued in before the stream
+	LogTailChannelPrefix       = "log:tail"      // Pub/sub channel of each log tail type, e.g. log:tail:operation
 
 	CacheTrue = "1"
 )
@@ -0,0 +1,194 @@
+package config
+
+// descriptions are the comments of the default config file, by YAML path. DefaultYAML fails on a key without one, so
+// that a new setting cannot be left undocumented.
+var descriptions = map[string]string{
+	"base":                   "Server",
+	"base.app_name":          "Name of the application",
+	"base.app_port":          "Port to listen on",
+	"base.app_host":          "Host to listen on",
+	"base.app_version":       "Version of the application",
+	"base.enable_tls":        "Serve HTTPS with the cert and key files",
+	"base.cert_file":         "TLS certificate file, required with TLS",
+	"base.key_file":          "TLS key file, required with TLS",
+	"base.enable_cors":       "Enable the CORS middleware",
+	"base.enable_prometheus": "Expose Prometheus metrics",
+
+	"casbin":                           "Casbin RBAC",
+	"casbin.casbin_model_path":         "Casbin model file",
+	"casbin.casbin_policy_adapter_url": "MongoDB connection string of the policy store",
+
+	"fiber":                            "Fiber server",
+	"fiber.prefork":                    "Spawn a child process per CPU core",
+	"fiber.server_header":              "Value of the Server response header, none when empty",
+	"fiber.body_limit":                 "Largest request body in bytes",
+	"fiber.concurrency":                "Most concurrent connections",
+	"fiber.read_timeout":               "Time to read a request, 0 for no limit",
+	"fiber.write_timeout":              "Time to write a response, 0 for no limit",
+	"fiber.idle_timeout":               "Time a keep-alive connection may stay idle, 0 for no limit",
+	"fiber.read_buffer_size":           "Buffer size of requests in bytes, which bounds the header size",
+	"fiber.write_buffer_size":          "Buffer size of responses in bytes",
+	"fiber.proxy_header":               "Header holding the client IP behind a proxy",
+	"fiber.disable_keepalive":          "Close connections after each response",
+	"fiber.disable_startup_message":    "Hide the Fiber banner",
+	"fiber.reduce_memory_usage":        "Trade CPU for memory",
+	"fiber.enable_trusted_proxy_check": "Trust the proxy header only from the trusted proxies",
+	"fiber.trusted_proxies":            "IP addresses or CIDR ranges of the trusted proxies",
+	"fiber.enable_print_routes":        "Print the routes on startup",
+
+	"jwt":                      "JWT",
+	"jwt.jwt_token_duration":   "Lifetime of access tokens",
+	"jwt.jwt_refresh_duration": "Lifetime of refresh tokens, longer than that of access tokens",
+	"jwt.jwt_refresh_buffer":   "Time before expiry an access token may be refreshed, shorter than its lifetime",
+
+	"mongo":                          "MongoDB",
+	"mongo.mongo_uri":                "Connection string",
+	"mongo.mongo_database":           "Database",
+	"mongo.mongo_connect_timeout_ms": "Connection timeout in milliseconds",
+	"mongo.mongo_max_pool_size":      "Most connections, at least the least connections",
+	"mongo.mongo_min_pool_size":      "Least connections",
+	"mongo.mongo_socket_timeout_ms":  "Socket timeout in milliseconds, 0 for no limit",
+	"mongo.mongo_ping_timeout_s":     "Timeout of the ping on connection in seconds",
+
+	"prometheus":                        "Prometheus",
+	"prometheus.prometheus_namespace":   "Namespace of the metrics",
+	"prometheus.prometheus_subsystem":   "Subsystem of the metrics",
+	"prometheus.prometheus_metric_path": "Path the metrics are served at",
+
+	"middleware":                            "Middlewares",
+	"middleware.limiter":                    "Rate limiting",
+	"middleware.limiter.limiter_max":        "Requests per window by IP of the default rule",
+	"middleware.limiter.limiter_expiration": "Window of the default rule",
+	"middleware.limiter.api_key_header":     "Header of the API key the api_key rules limit by",
+	"middleware.limiter.override_cache_ttl": "How long an instance caches a user override",
+	"middleware.limiter.rules": "Rules by path prefix, each with a path, an identity (ip | user | api_key), a max and " +
+		"a window. The rules of the longest prefix matching a request apply, the default rule otherwise",
+	"middleware.cors":                         "CORS",
+	"middleware.cors.allow_origins":           "Allowed origins, comma separated",
+	"middleware.cors.allow_methods":           "Allowed methods, comma separated",
+	"middleware.cors.allow_headers":           "Allowed request headers, comma separated",
+	"middleware.cors.allow_credentials":       "Allow credentials",
+	"middleware.cors.expose_headers":          "Response headers exposed to browsers, comma separated",
+	"middleware.cors.max_age":                 "How long preflight responses may be cached in seconds",
+	"middleware.logging":                      "Request logging",
+	"middleware.logging.redacted_fields":      "Fields masked in bodies, forms and queries, by name or dot-separated path",
+	"middleware.logging.redacted_headers":     "Headers masked",
+	"middleware.logging.redacted_body_routes": "Routes whose bodies are not logged, e.g. \"POST /api/v1/auth/login\"",
+	"middleware.logging.excluded_routes":      "Routes which are not logged",
+	"middleware.logging.max_body_size":        "Bytes of a body logged, 0 to log whole bodies",
+	"middleware.logging.success_sample_rate":  "Fraction of 2xx requests logged",
+
+	"cache":                         "Cache",
+	"cache.cache_backend":           "redis | memory, to run a single instance without Redis",
+	"cache.cache_namespace":         "Prefix of every key, so that the cache can share a Redis with other data",
+	"cache.default_ttl":             "TTL of the keys set without one",
+	"cache.user_cache_ttl":          "TTL of the user cache",
+	"cache.notice_cache_ttl":        "TTL of the notice cache",
+	"cache.documentation_cache_ttl": "TTL of the documentation cache",
+	"cache.token_blacklist_ttl":     "How long revoked tokens are blacklisted",
+	"cache.stats_cache_ttl":         "TTL of the stats cache",
+	"cache.negative_cache_ttl":      "How long missing entities are cached, 0 not to cache them",
+	"cache.ttl_jitter":              "Fraction of the TTL randomly added or removed, so that keys do not expire together",
+
+	"cache.redis":                          "Redis",
+	"cache.redis.redis_addr":               "Address",
+	"cache.redis.redis_client_name":        "Client name",
+	"cache.redis.redis_username":           "Username",
+	"cache.redis.redis_password":           "Password",
+	"cache.redis.redis_db":                 "Database",
+	"cache.redis.redis_max_retries":        "Retries of a failed command, -1 not to retry",
+	"cache.redis.redis_min_retry_backoff":  "Least backoff between retries",
+	"cache.redis.redis_max_retry_backoff":  "Most backoff between retries",
+	"cache.redis.redis_dial_timeout":       "Connection timeout",
+	"cache.redis.redis_read_timeout":       "Read timeout",
+	"cache.redis.redis_write_timeout":      "Write timeout",
+	"cache.redis.redis_pool_size":          "Connections per CPU, 0 for the default",
+	"cache.redis.redis_pool_timeout":       "Time to wait for a connection of the pool",
+	"cache.redis.redis_min_idle_conns":     "Least idle connections",
+	"cache.redis.redis_max_idle_conns":     "Most idle connections, 0 for no limit",
+	"cache.redis.redis_max_active_conns":   "Most connections, 0 for no limit",
+	"cache.redis.redis_conn_max_idle_time": "Time a connection may stay idle",
+	"cache.redis.redis_conn_max_lifetime":  "Time a connection may be reused, 0 for no limit",
+
+	"cache.local":                "In-process cache in front of Redis",
+	"cache.local.local_enabled":  "Enable the local cache",
+	"cache.local.local_size":     "Most entries",
+	"cache.local.local_ttl":      "TTL of the entries, which bounds how stale an instance can be",
+	"cache.local.local_entities": "Entities cached locally: user, notice, documentation or stats",
+
+	"cache.warm":           "Cache warming",
+	"cache.warm.pages":     "Pages of each list loaded",
+	"cache.warm.page_size": "Size of the pages loaded",
+
+	"tasks":                   "Scheduled tasks, as cron specs",
+	"tasks.sync_logs_spec":    "Move the queued logs to MongoDB",
+	"tasks.update_key_spec":   "Rotate the JWT key",
+	"tasks.checkpoint_spec":   "Sign an operation log checkpoint",
+	"tasks.retention_spec":    "Archive and delete old logs",
+	"tasks.notify_login_spec": "Notify the flagged logins",
+
+	"zap":                        "Logging",
+	"zap.zap_level":              "debug | info | warn | error | dpanic | panic | fatal",
+	"zap.zap_encoding":           "json | console",
+	"zap.zap_development":        "Development mode",
+	"zap.zap_output_paths":       "Outputs of the logs",
+	"zap.zap_error_output_paths": "Outputs of the errors of the logger",
+	"zap.zap_caller_skip":        "Frames skipped when reporting the caller",
+	"zap.zap_disable_caller":     "Do not report the caller",
+	"zap.zap_disable_stacktrace": "Do not report stack traces",
+
+	"idempotency":                               "Idempotency keys",
+	"idempotency.idempotency_header_key":        "Header of the idempotency key",
+	"idempotency.idempotency_expiry":            "How long responses are replayed for",
+	"idempotency.idempotency_lock_timeout":      "How long a request in flight holds its key",
+	"idempotency.idempotency_max_response_size": "Largest response stored in bytes",
+
+	"search":                           "Search",
+	"search.search_default_language":   "Default language of the text index",
+	"search.search_snippet_length":     "Length of the snippets",
+	"search.search_highlight_pre_tag":  "Tag before a highlighted term",
+	"search.search_highlight_post_tag": "Tag after a highlighted term",
+
+	"markdown":                          "Markdown rendering",
+	"markdown.markdown_highlight_style": "Chroma style of the code blocks",
+	"markdown.markdown_toc_max_depth":   "Deepest heading of the table of contents, from 1 to 6",
+
+	"audit":                                  "Audit",
+	"audit.audit_checkpoint_secret":          "Key the operation log checkpoints are signed with, none are signed when empty",
+	"audit.audit_access_log":                 "Record the access to sensitive routes",
+	"audit.audit_access_log_excluded_routes": "Sensitive routes whose access is not recorded, e.g. \"GET /api/v1/admin/user/list\"",
+
+	"log_queue":                          "Log queue",
+	"log_queue.log_queue_batch_size":     "Logs moved to MongoDB per batch",
+	"log_queue.log_queue_max_deliveries": "Deliveries of a log before it is dead-lettered",
+	"log_queue.log_queue_claim_idle":     "Time before the logs of a dead consumer are claimed",
+
+	"retention":                                 "Log retention",
+	"retention.retention_archive_dir":           "Directory of the archives",
+	"retention.retention_archive_batch_size":    "Logs archived per batch",
+	"retention.login_log":                       "Login log",
+	"retention.login_log.retention_max_age":     "Age of the deleted logs, 0 to keep them forever",
+	"retention.login_log.retention_archive":     "Archive the logs before deleting them",
+	"retention.operation_log":                   "Operation log",
+	"retention.operation_log.retention_max_age": "Age of the deleted logs, 0 to keep them forever",
+	"retention.operation_log.retention_archive": "Archive the logs before deleting them",
+
+	"login_analytics": "Login analytics",
+	"login_analytics.login_analytics_geoip_database":   "MaxMind DB file logins are located with, none are located when empty",
+	"login_analytics.login_analytics_history_size":     "Previous logins a login is compared with",
+	"login_analytics.login_analytics_max_travel_speed": "Fastest plausible travel between logins in km/h",
+	"login_analytics.login_analytics_notify_webhook":   "URL receiving the flagged logins, none are notified when empty",
+	"login_analytics.login_analytics_notify_timeout":   "Timeout of a notification",
+	"login_analytics.login_analytics_notify_max_age":   "Older flagged logins are not notified",
+
+	"stats":                     "Stats",
+	"stats.stats_timezone":      "Timezone the time buckets start in",
+	"stats.stats_default_range": "Time range of series requested without a start",
+	"stats.stats_max_buckets":   "Most buckets a series may have",
+
+	"log_tail":                           "Log tailing",
+	"log_tail.log_tail_request_log":      "Whether request log events can be tailed",
+	"log_tail.log_tail_heartbeat":        "Interval of the comments keeping idle streams open",
+	"log_tail.log_tail_buffer_size":      "Events buffered per stream before dropping them",
+	"log_tail.log_tail_subscriber_check": "How long an instance trusts its count of tailing admins",
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix prefixes the environment variables overriding the config, e.g. FIBER_ADMIN_CACHE_REDIS_REDIS_PASSWORD
+// overrides cache.redis.redis_password.
+const EnvPrefix = "FIBER_ADMIN"
+
+// fileEnvSuffix suffixes the environment variables holding the path of a file to read a secret from, e.g.
+// FIBER_ADMIN_MONGO_MONGO_URI_FILE.
+const fileEnvSuffix = "_FILE"
+
+// EnvName returns the environment variable overriding the config key, a YAML path such as mongo.mongo_uri.
+func EnvName(key string) string {
+	return fmt.Sprintf("%s_%s", EnvPrefix, strings.ToUpper(strings.ReplaceAll(key, ".", "_")))
+}
+
+// Unmarshal unmarshals the config read by v into cfg, with the precedence flag > env > file > default: the flags bound
+// to v, the FIBER_ADMIN_* environment variables and the *_FILE ones of the secrets, the config file read by v, and the
+// default values. Lists are comma separated in the environment, and lists of objects, such as the limiter rules, are
+// written as YAML or JSON.
+func Unmarshal(v *viper.Viper, cfg *Config) error {
+	if err := bindEnv(v); err != nil {
+		return err
+	}
+	return v.Unmarshal(
+		cfg, viper.DecodeHook(
+			mapstructure.ComposeDecodeHookFunc(
+				stringToStructSliceHookFunc(),
+				mapstructure.StringToTimeDurationHookFunc(),
+				mapstructure.StringToSliceHookFunc(","),
+			),
+		),
+	)
+}
+
+// bindEnv registers every key of the config to v with its default value, so that viper knows of the keys missing from
+// the config file, and binds it to its environment variable. The secrets are read from their files again each time, so
+// that a reload picks up a rotated secret.
+func bindEnv(v *viper.Viper) error {
+	return walkKeys(
+		reflect.ValueOf(Default()).Elem(), "", func(key string, field reflect.StructField, value reflect.Value) error {
+			name := EnvName(key)
+			v.SetDefault(key, value.Interface())
+			if err := v.BindEnv(key, name); err != nil {
+				return err
+			}
+			if field.Tag.Get("secret") == "" {
+				return nil
+			}
+
+			file, ok := os.LookupEnv(name + fileEnvSuffix)
+			if !ok {
+				return nil
+			}
+			if _, ok = os.LookupEnv(name); ok {
+				return fmt.Errorf("config: both %s and %s%s are set", name, name, fileEnvSuffix)
+			}
+			secret, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("config: reading %s%s: %w", name, fileEnvSuffix, err)
+			}
+			// No secret has a flag, so that the value of the file takes the place of the environment variable
+			v.Set(key, strings.TrimRight(string(secret), "\r\n"))
+			return nil
+		},
+	)
+}
+
+// walkKeys calls handle with every leaf key of a config struct, by its YAML path.
+func walkKeys(
+	value reflect.Value, path string, handle func(key string, field reflect.StructField, value reflect.Value) error,
+) error {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		key := strings.SplitN(field.Tag.Get("yaml"), ",", 2)[0]
+		if path != "" {
+			key = fmt.Sprintf("%s.%s", path, key)
+		}
+		var err error
+		if field.Type.Kind() == reflect.Struct {
+			err = walkKeys(value.Field(i), key, handle)
+		} else {
+			err = handle(key, field, value.Field(i))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stringToStructSliceHookFunc decodes the YAML of a list of objects set in an environment variable.
+func stringToStructSliceHookFunc() mapstructure.DecodeHookFuncType {
+	return func(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+		if from.Kind() != reflect.String || to.Kind() != reflect.Slice || to.Elem().Kind() != reflect.Struct {
+			return data, nil
+		}
+		var items []map[string]interface{}
+		if err := yaml.Unmarshal([]byte(data.(string)), &items); err != nil {
+			return nil, fmt.Errorf("config: decoding a list of objects: %w", err)
+		}
+		return items, nil
+	}
+}
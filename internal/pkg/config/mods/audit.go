@@ -0,0 +1,12 @@
+package mods
+
+type AuditConfig struct {
+	// CheckpointSecret is the key the operation log checkpoints are signed with. Checkpoints cannot be created or
+	// verified without it. The placeholder the config files once shipped with is rejected.
+	CheckpointSecret string `mapstructure:"audit_checkpoint_secret" yaml:"audit_checkpoint_secret" default:"" validate:"ne=change-me" secret:"true"`
+	// AccessLog records the READ and EXPORT operations of the routes the router marks as sensitive.
+	AccessLog bool `mapstructure:"audit_access_log" yaml:"audit_access_log" default:"true"`
+	// AccessLogExcludedRoutes are sensitive routes whose access is not recorded anyway, written as the method and the
+	// full path, e.g. "GET /api/v1/admin/user/list".
+	AccessLogExcludedRoutes []string `mapstructure:"audit_access_log_excluded_routes" yaml:"audit_access_log_excluded_routes" default:""`
+}
@@ -7,10 +7,17 @@ import (
 )
 
 type CacheConfig struct {
-	DefaultTTL            time.Duration     `mapstructure:"default_ttl" yaml:"default_ttl" default:"5m"`
-	UserCacheTTL          time.Duration     `mapstructure:"user_cache_ttl" yaml:"user_cache_ttl" default:"5m"`
-	NoticeCacheTTL        time.Duration     `mapstructure:"notice_cache_ttl" yaml:"notice_cache_ttl" default:"5m"`
-	DocumentationCacheTTL time.Duration     `mapstructure:"documentation_cache_ttl" yaml:"documentation_cache_ttl" default:"5m"`
-	TokenBlacklistTTL     time.Duration     `mapstructure:"token_blacklist_ttl" yaml:"token_blacklist_ttl" default:"1h"`
+	Backend               string            `mapstructure:"cache_backend" yaml:"cache_backend" default:"redis" validate:"oneof=redis memory"` // redis | memory
+	Namespace             string            `mapstructure:"cache_namespace" yaml:"cache_namespace" default:"fiber-admin"`
+	DefaultTTL            time.Duration     `mapstructure:"default_ttl" yaml:"default_ttl" default:"5m" validate:"gt=0"`
+	UserCacheTTL          time.Duration     `mapstructure:"user_cache_ttl" yaml:"user_cache_ttl" default:"5m" validate:"gt=0"`
+	NoticeCacheTTL        time.Duration     `mapstructure:"notice_cache_ttl" yaml:"notice_cache_ttl" default:"5m" validate:"gt=0"`
+	DocumentationCacheTTL time.Duration     `mapstructure:"documentation_cache_ttl" yaml:"documentation_cache_ttl" default:"5m" validate:"gt=0"`
+	TokenBlacklistTTL     time.Duration     `mapstructure:"token_blacklist_ttl" yaml:"token_blacklist_ttl" default:"1h" validate:"gt=0"`
+	StatsCacheTTL         time.Duration     `mapstructure:"stats_cache_ttl" yaml:"stats_cache_ttl" default:"1m" validate:"gt=0"`
+	NegativeCacheTTL      time.Duration     `mapstructure:"negative_cache_ttl" yaml:"negative_cache_ttl" default:"30s" validate:"gte=0"`
+	TTLJitter             float64           `mapstructure:"ttl_jitter" yaml:"ttl_jitter" default:"0.1" validate:"gte=0,lt=1"`
 	RedisConfig           cache.RedisConfig `mapstructure:"redis" yaml:"redis"`
+	LocalConfig           cache.LocalConfig `mapstructure:"local" yaml:"local"`
+	WarmConfig            cache.WarmConfig  `mapstructure:"warm" yaml:"warm"`
 }
@@ -0,0 +1,12 @@
+package cache
+
+import "time"
+
+// LocalConfig configures the in-process cache in front of Redis. Entries live for the TTL at most, which bounds how
+// stale an instance can be when it misses an invalidation.
+type LocalConfig struct {
+	Enabled  bool          `mapstructure:"local_enabled" yaml:"local_enabled" default:"false"`
+	Size     int           `mapstructure:"local_size" yaml:"local_size" default:"10000" validate:"gte=0"`
+	TTL      time.Duration `mapstructure:"local_ttl" yaml:"local_ttl" default:"10s" validate:"gte=0"`
+	Entities []string      `mapstructure:"local_entities" yaml:"local_entities" default:"[user,notice,documentation]" validate:"dive,oneof=user notice documentation stats"`
+}
@@ -7,22 +7,22 @@ import (
 )
 
 type RedisConfig struct {
-	Addr            string        `mapstructure:"redis_addr" yaml:"redis_addr" default:"localhost:6379"`
+	Addr            string        `mapstructure:"redis_addr" yaml:"redis_addr" default:"localhost:6379" validate:"required,hostname_port"`
 	ClientName      string        `mapstructure:"redis_client_name" yaml:"redis_client_name" default:""`
 	Username        string        `mapstructure:"redis_username" yaml:"redis_username" default:""`
-	Password        string        `mapstructure:"redis_password" yaml:"redis_password" default:""`
-	DB              int           `mapstructure:"redis_db" yaml:"redis_db" default:"0"`
-	MaxRetries      int           `mapstructure:"redis_max_retries" yaml:"redis_max_retries" default:"5"`
+	Password        string        `mapstructure:"redis_password" yaml:"redis_password" default:"" secret:"true"`
+	DB              int           `mapstructure:"redis_db" yaml:"redis_db" default:"0" validate:"gte=0"`
+	MaxRetries      int           `mapstructure:"redis_max_retries" yaml:"redis_max_retries" default:"5" validate:"gte=-1"`
 	MinRetryBackoff time.Duration `mapstructure:"redis_min_retry_backoff" yaml:"redis_min_retry_backoff" default:"8ms"`
 	MaxRetryBackoff time.Duration `mapstructure:"redis_max_retry_backoff" yaml:"redis_max_retry_backoff" default:"512ms"`
 	DialTimeout     time.Duration `mapstructure:"redis_dial_timeout" yaml:"redis_dial_timeout" default:"5s"`
 	ReadTimeout     time.Duration `mapstructure:"redis_read_timeout" yaml:"redis_read_timeout" default:"3s"`
 	WriteTimeout    time.Duration `mapstructure:"redis_write_timeout" yaml:"redis_write_timeout" default:"3s"`
-	PoolSize        int           `mapstructure:"redis_pool_size" yaml:"redis_pool_size" default:"10"`
+	PoolSize        int           `mapstructure:"redis_pool_size" yaml:"redis_pool_size" default:"10" validate:"gte=0"`
 	PoolTimeout     time.Duration `mapstructure:"redis_pool_timeout" yaml:"redis_pool_timeout" default:"4s"`
-	MinIdleConns    int           `mapstructure:"redis_min_idle_conns" yaml:"redis_min_idle_conns" default:"0"`
-	MaxIdleConns    int           `mapstructure:"redis_max_idle_conns" yaml:"redis_max_idle_conns" default:"0"`
-	MaxActiveConns  int           `mapstructure:"redis_max_active_conns" yaml:"redis_max_active_conns" default:"0"`
+	MinIdleConns    int           `mapstructure:"redis_min_idle_conns" yaml:"redis_min_idle_conns" default:"0" validate:"gte=0"`
+	MaxIdleConns    int           `mapstructure:"redis_max_idle_conns" yaml:"redis_max_idle_conns" default:"0" validate:"gte=0"`
+	MaxActiveConns  int           `mapstructure:"redis_max_active_conns" yaml:"redis_max_active_conns" default:"0" validate:"gte=0"`
 	ConnMaxIdleTime time.Duration `mapstructure:"redis_conn_max_idle_time" yaml:"redis_conn_max_idle_time" default:"30m"`
 	ConnMaxLifetime time.Duration `mapstructure:"redis_conn_max_lifetime" yaml:"redis_conn_max_lifetime" default:"-1"`
 }
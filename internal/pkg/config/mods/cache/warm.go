@@ -0,0 +1,7 @@
+package cache
+
+// WarmConfig configures what warming the cache loads: the first pages of the hot lists.
+type WarmConfig struct {
+	Pages    int64 `mapstructure:"pages" yaml:"pages" default:"1" validate:"gt=0"`
+	PageSize int64 `mapstructure:"page_size" yaml:"page_size" default:"10" validate:"gt=0"`
+}
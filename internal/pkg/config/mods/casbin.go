@@ -1,6 +1,6 @@
 package mods
 
 type CasbinConfig struct {
-	ModelPath        string `mapstructure:"casbin_model_path" yaml:"casbin_model_path" default:"./configs/casbin_model.test.conf"`
-	PolicyAdapterUrl string `mapstructure:"casbin_policy_adapter_url" yaml:"casbin_policy_adapter_url" default:"mongodb://localhost:27017/fiber-admin"`
+	ModelPath        string `mapstructure:"casbin_model_path" yaml:"casbin_model_path" default:"./configs/casbin_model.conf" validate:"required,file"`
+	PolicyAdapterUrl string `mapstructure:"casbin_policy_adapter_url" yaml:"casbin_policy_adapter_url" default:"mongodb://localhost:27017/fiber-admin" validate:"required,mongouri" secret:"uri"`
 }
@@ -5,6 +5,8 @@ import (
 )
 
 type IdempotencyConfig struct {
-	IdempotencyTokenHeader string        `mapstructure:"idempotency_header_key" yaml:"idempotency_header_key" default:"Idempotency-Key"`
-	TTL                    time.Duration `mapstructure:"idempotency_expiry" yaml:"idempotency_expiry" default:"5m"`
+	IdempotencyTokenHeader string        `mapstructure:"idempotency_header_key" yaml:"idempotency_header_key" default:"Idempotency-Key" validate:"required"`
+	TTL                    time.Duration `mapstructure:"idempotency_expiry" yaml:"idempotency_expiry" default:"5m" validate:"gt=0"`                            // How long responses are replayed for
+	LockTimeout            time.Duration `mapstructure:"idempotency_lock_timeout" yaml:"idempotency_lock_timeout" default:"1m" validate:"gt=0"`                // How long a request in flight holds its key
+	MaxResponseSize        int           `mapstructure:"idempotency_max_response_size" yaml:"idempotency_max_response_size" default:"1048576" validate:"gt=0"` // Larger responses are not stored
 }
@@ -5,7 +5,7 @@ import (
 )
 
 type JWTConfig struct {
-	TokenDuration   time.Duration `mapstructure:"jwt_token_duration" yaml:"jwt_token_duration" default:"7200s"`
-	RefreshDuration time.Duration `mapstructure:"jwt_refresh_duration" yaml:"jwt_refresh_duration" default:"14400s"`
-	RefreshBuffer   time.Duration `mapstructure:"jwt_refresh_buffer" yaml:"jwt_refresh_buffer" default:"300s"`
+	TokenDuration   time.Duration `mapstructure:"jwt_token_duration" yaml:"jwt_token_duration" default:"7200s" validate:"gt=0"`
+	RefreshDuration time.Duration `mapstructure:"jwt_refresh_duration" yaml:"jwt_refresh_duration" default:"14400s" validate:"gtfield=TokenDuration"`
+	RefreshBuffer   time.Duration `mapstructure:"jwt_refresh_buffer" yaml:"jwt_refresh_buffer" default:"300s" validate:"gt=0,ltfield=TokenDuration"`
 }
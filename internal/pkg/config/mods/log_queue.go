@@ -0,0 +1,11 @@
+package mods
+
+import (
+	"time"
+)
+
+type LogQueueConfig struct {
+	BatchSize     int64         `mapstructure:"log_queue_batch_size" yaml:"log_queue_batch_size" default:"500" validate:"gt=0"`
+	MaxDeliveries int64         `mapstructure:"log_queue_max_deliveries" yaml:"log_queue_max_deliveries" default:"5" validate:"gt=0"`
+	ClaimIdle     time.Duration `mapstructure:"log_queue_claim_idle" yaml:"log_queue_claim_idle" default:"60s" validate:"gt=0"`
+}
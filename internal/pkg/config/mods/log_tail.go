@@ -0,0 +1,12 @@
+package mods
+
+import (
+	"time"
+)
+
+type LogTailConfig struct {
+	RequestLog      bool          `mapstructure:"log_tail_request_log" yaml:"log_tail_request_log" default:"true"`                          // Whether request log events can be tailed
+	Heartbeat       time.Duration `mapstructure:"log_tail_heartbeat" yaml:"log_tail_heartbeat" default:"15s" validate:"gt=0"`               // Interval of the comments keeping idle streams open
+	BufferSize      int           `mapstructure:"log_tail_buffer_size" yaml:"log_tail_buffer_size" default:"256" validate:"gt=0"`           // Events buffered per stream before dropping them
+	SubscriberCheck time.Duration `mapstructure:"log_tail_subscriber_check" yaml:"log_tail_subscriber_check" default:"1s" validate:"gte=0"` // How long an instance trusts its count of tailing admins
+}
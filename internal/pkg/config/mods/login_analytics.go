@@ -0,0 +1,18 @@
+package mods
+
+import (
+	"time"
+)
+
+type LoginAnalyticsConfig struct {
+	// GeoIPDatabase is the MaxMind DB file (e.g. GeoLite2-City.mmdb) logins are located with. Logins are not located
+	// and new countries and impossible travel are not flagged without it.
+	GeoIPDatabase  string  `mapstructure:"login_analytics_geoip_database" yaml:"login_analytics_geoip_database" default:"" validate:"omitempty,file"`
+	HistorySize    int64   `mapstructure:"login_analytics_history_size" yaml:"login_analytics_history_size" default:"50" validate:"gt=0"`           // Number of previous logins a login is compared with
+	MaxTravelSpeed float64 `mapstructure:"login_analytics_max_travel_speed" yaml:"login_analytics_max_travel_speed" default:"1000" validate:"gt=0"` // Fastest plausible travel between logins in km/h
+	// NotifyWebhook receives a JSON POST for every flagged login, so that the user can be told about it, e.g. by mail.
+	// Flagged logins are only shown to admins when it is empty.
+	NotifyWebhook string        `mapstructure:"login_analytics_notify_webhook" yaml:"login_analytics_notify_webhook" default:"" validate:"omitempty,url" secret:"uri"`
+	NotifyTimeout time.Duration `mapstructure:"login_analytics_notify_timeout" yaml:"login_analytics_notify_timeout" default:"5s" validate:"gt=0"`
+	NotifyMaxAge  time.Duration `mapstructure:"login_analytics_notify_max_age" yaml:"login_analytics_notify_max_age" default:"24h" validate:"gt=0"` // Older flagged logins are not notified
+}
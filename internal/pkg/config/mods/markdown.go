@@ -0,0 +1,6 @@
+package mods
+
+type MarkdownConfig struct {
+	HighlightStyle string `mapstructure:"markdown_highlight_style" yaml:"markdown_highlight_style" default:"github" validate:"required"`
+	TOCMaxDepth    int    `mapstructure:"markdown_toc_max_depth" yaml:"markdown_toc_max_depth" default:"3" validate:"min=1,max=6"`
+}
@@ -7,4 +7,5 @@ import (
 type MiddlewareConfig struct {
 	LimiterConfig middleware.LimiterConfig `mapstructure:"limiter" yaml:"limiter"`
 	CorsConfig    middleware.CorsConfig    `mapstructure:"cors" yaml:"cors"`
+	LoggingConfig middleware.LoggingConfig `mapstructure:"logging" yaml:"logging"`
 }
@@ -4,7 +4,21 @@ import (
 	"time"
 )
 
+// LimiterConfig limits the requests by identity. The rules of the longest path prefix matching a request apply to it;
+// the default rule, of limiter_max requests by IP per limiter_expiration, applies to the requests no rule matches.
 type LimiterConfig struct {
-	Max        int           `mapstructure:"limiter_max" yaml:"limiter_max" default:"20"`
-	Expiration time.Duration `mapstructure:"limiter_expiration" yaml:"limiter_expiration" default:"30s"`
+	Max              int           `mapstructure:"limiter_max" yaml:"limiter_max" default:"20" validate:"gt=0"`
+	Expiration       time.Duration `mapstructure:"limiter_expiration" yaml:"limiter_expiration" default:"30s" validate:"gt=0"`
+	APIKeyHeader     string        `mapstructure:"api_key_header" yaml:"api_key_header" default:"X-Api-Key" validate:"required"`
+	OverrideCacheTTL time.Duration `mapstructure:"override_cache_ttl" yaml:"override_cache_ttl" default:"10s" validate:"gte=0"` // How long an instance caches a user override
+	Rules            []LimiterRule `mapstructure:"rules" yaml:"rules" validate:"dive"`
+}
+
+// LimiterRule limits the requests under Path to Max per Window for each identity. Requests without the identity, e.g.
+// anonymous ones under a user rule, are limited by IP.
+type LimiterRule struct {
+	Path     string        `mapstructure:"path" yaml:"path" validate:"required,startswith=/"`         // Path prefix, e.g. /api/v1/admin
+	Identity string        `mapstructure:"identity" yaml:"identity" validate:"oneof=ip user api_key"` // ip | user | api_key
+	Max      int           `mapstructure:"max" yaml:"max" validate:"gt=0"`
+	Window   time.Duration `mapstructure:"window" yaml:"window" validate:"gt=0"`
 }
@@ -0,0 +1,18 @@
+package middleware
+
+type LoggingConfig struct {
+	// RedactedFields are masked in JSON bodies, form values and query parameters. A name such as "password" matches
+	// the field at any depth, a dot-separated path such as "data.*.token" matches from the root of the document.
+	RedactedFields []string `mapstructure:"redacted_fields" yaml:"redacted_fields" default:"[password,old_password,new_password,access_token,refresh_token,token,secret]"`
+	// RedactedHeaders are masked in the logged request headers.
+	RedactedHeaders []string `mapstructure:"redacted_headers" yaml:"redacted_headers" default:"[Authorization,Cookie,Set-Cookie,X-Api-Key]"`
+	// RedactedBodyRoutes are routes whose request and response bodies are not logged at all, written as the method
+	// and the full path, e.g. "POST /api/v1/auth/login".
+	RedactedBodyRoutes []string `mapstructure:"redacted_body_routes" yaml:"redacted_body_routes" default:"[]"`
+	// ExcludedRoutes are routes which are not logged, written like RedactedBodyRoutes.
+	ExcludedRoutes []string `mapstructure:"excluded_routes" yaml:"excluded_routes" default:"[]"`
+	// MaxBodySize is the number of bytes of a body logged, 0 to log whole bodies.
+	MaxBodySize int `mapstructure:"max_body_size" yaml:"max_body_size" default:"4096" validate:"gte=0"`
+	// SuccessSampleRate is the fraction of 2xx requests logged. Other requests are always logged.
+	SuccessSampleRate float64 `mapstructure:"success_sample_rate" yaml:"success_sample_rate" default:"1" validate:"gte=0,lte=1"`
+}
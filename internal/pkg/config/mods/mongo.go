@@ -5,13 +5,13 @@ import (
 )
 
 type MongoConfig struct {
-	Uri              string `mapstructure:"mongo_uri" yaml:"mongo_uri" default:"mongodb://localhost:27017"`
-	Database         string `mapstructure:"mongo_database" yaml:"mongo_database" default:"test"`
-	ConnectTimeoutMS int64  `mapstructure:"mongo_connect_timeout_ms" yaml:"mongo_connect_timeout_ms" default:"10000"`
-	MaxPoolSize      uint64 `mapstructure:"mongo_max_pool_size" yaml:"mongo_max_pool_size" default:"10"`
+	Uri              string `mapstructure:"mongo_uri" yaml:"mongo_uri" default:"mongodb://localhost:27017" validate:"required,mongouri" secret:"uri"`
+	Database         string `mapstructure:"mongo_database" yaml:"mongo_database" default:"test" validate:"required"`
+	ConnectTimeoutMS int64  `mapstructure:"mongo_connect_timeout_ms" yaml:"mongo_connect_timeout_ms" default:"10000" validate:"gt=0"`
+	MaxPoolSize      uint64 `mapstructure:"mongo_max_pool_size" yaml:"mongo_max_pool_size" default:"10" validate:"gt=0,gtefield=MinPoolSize"`
 	MinPoolSize      uint64 `mapstructure:"mongo_min_pool_size" yaml:"mongo_min_pool_size" default:"1"`
-	SocketTimeoutMS  int64  `mapstructure:"mongo_socket_timeout_ms" yaml:"mongo_socket_timeout_ms" default:"10000"`
-	PingTimeoutS     int64  `mapstructure:"mongo_ping_timeout_s" yaml:"mongo_ping_timeout_s" default:"10"`
+	SocketTimeoutMS  int64  `mapstructure:"mongo_socket_timeout_ms" yaml:"mongo_socket_timeout_ms" default:"10000" validate:"gte=0"`
+	PingTimeoutS     int64  `mapstructure:"mongo_ping_timeout_s" yaml:"mongo_ping_timeout_s" default:"10" validate:"gt=0"`
 }
 
 func (c *MongoConfig) GetQmgoConfig() *qmgo.Config {
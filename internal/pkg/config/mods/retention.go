@@ -0,0 +1,18 @@
+package mods
+
+import (
+	"time"
+)
+
+type RetentionConfig struct {
+	ArchiveDir       string             `mapstructure:"retention_archive_dir" yaml:"retention_archive_dir" default:"archives" validate:"required"`
+	ArchiveBatchSize int64              `mapstructure:"retention_archive_batch_size" yaml:"retention_archive_batch_size" default:"1000" validate:"gt=0"`
+	LoginLog         LogRetentionConfig `mapstructure:"login_log" yaml:"login_log"`
+	OperationLog     LogRetentionConfig `mapstructure:"operation_log" yaml:"operation_log"`
+}
+
+// LogRetentionConfig is the retention of a log collection. A zero MaxAge keeps the logs forever.
+type LogRetentionConfig struct {
+	MaxAge  time.Duration `mapstructure:"retention_max_age" yaml:"retention_max_age" default:"2160h" validate:"gte=0"`
+	Archive bool          `mapstructure:"retention_archive" yaml:"retention_archive" default:"true"` // Archive the logs before deleting them
+}
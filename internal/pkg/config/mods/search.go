@@ -0,0 +1,8 @@
+package mods
+
+type SearchConfig struct {
+	DefaultLanguage  string `mapstructure:"search_default_language" yaml:"search_default_language" default:"none" validate:"required"`
+	SnippetLength    int    `mapstructure:"search_snippet_length" yaml:"search_snippet_length" default:"160" validate:"gt=0"`
+	HighlightPreTag  string `mapstructure:"search_highlight_pre_tag" yaml:"search_highlight_pre_tag" default:"<mark>"`
+	HighlightPostTag string `mapstructure:"search_highlight_post_tag" yaml:"search_highlight_post_tag" default:"</mark>"`
+}
@@ -0,0 +1,11 @@
+package mods
+
+import (
+	"time"
+)
+
+type StatsConfig struct {
+	Timezone     string        `mapstructure:"stats_timezone" yaml:"stats_timezone" default:"UTC" validate:"required,timezone"` // Timezone the time buckets start in
+	DefaultRange time.Duration `mapstructure:"stats_default_range" yaml:"stats_default_range" default:"720h" validate:"gt=0"`   // Time range of series requested without a start
+	MaxBuckets   int           `mapstructure:"stats_max_buckets" yaml:"stats_max_buckets" default:"1000" validate:"gt=0"`       // Most buckets a series may have
+}
@@ -1,6 +1,9 @@
 package mods
 
 type TasksConfig struct {
-	SyncLogsSpec  string `mapstructure:"sync_logs_spec" yaml:"sync_logs_spec" default:"@hourly"`
-	UpdateKeySpec string `mapstructure:"update_key_spec" yaml:"update_key_spec" default:"@weekly"`
+	SyncLogsSpec    string `mapstructure:"sync_logs_spec" yaml:"sync_logs_spec" default:"@every 5s" validate:"required,cron"`
+	UpdateKeySpec   string `mapstructure:"update_key_spec" yaml:"update_key_spec" default:"@weekly" validate:"required,cron"`
+	CheckpointSpec  string `mapstructure:"checkpoint_spec" yaml:"checkpoint_spec" default:"@daily" validate:"required,cron"`
+	RetentionSpec   string `mapstructure:"retention_spec" yaml:"retention_spec" default:"@daily" validate:"required,cron"`
+	NotifyLoginSpec string `mapstructure:"notify_login_spec" yaml:"notify_login_spec" default:"@every 1m" validate:"required,cron"`
 }
@@ -6,12 +6,12 @@ import (
 )
 
 type ZapConfig struct {
-	Level             string   `mapstructure:"zap_level" yaml:"zap_level" default:"info"`
-	Encoding          string   `mapstructure:"zap_encoding" yaml:"zap_encoding" default:"console"`
+	Level             string   `mapstructure:"zap_level" yaml:"zap_level" default:"info" validate:"oneof=debug info warn error dpanic panic fatal"`
+	Encoding          string   `mapstructure:"zap_encoding" yaml:"zap_encoding" default:"console" validate:"oneof=json console"`
 	Development       bool     `mapstructure:"zap_development" yaml:"zap_development" default:"false"`
-	OutputPaths       []string `mapstructure:"zap_output_paths" yaml:"zap_output_paths" default:"[stdout]"`
-	ErrorOutputPaths  []string `mapstructure:"zap_error_output_paths" yaml:"zap_error_output_paths" default:"[stderr]"`
-	CallerSkip        int      `mapstructure:"zap_caller_skip" yaml:"zap_caller_skip" default:"1"`
+	OutputPaths       []string `mapstructure:"zap_output_paths" yaml:"zap_output_paths" default:"[stdout]" validate:"min=1"`
+	ErrorOutputPaths  []string `mapstructure:"zap_error_output_paths" yaml:"zap_error_output_paths" default:"[stderr]" validate:"min=1"`
+	CallerSkip        int      `mapstructure:"zap_caller_skip" yaml:"zap_caller_skip" default:"1" validate:"gte=0"`
 	DisableCaller     bool     `mapstructure:"zap_disable_caller" yaml:"zap_disable_caller" default:"false"`
 	DisableStacktrace bool     `mapstructure:"zap_disable_stacktrace" yaml:"zap_disable_stacktrace" default:"false"`
 }
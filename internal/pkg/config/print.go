@@ -0,0 +1,121 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/mcuadros/go-defaults"
+	"gopkg.in/yaml.v3"
+)
+
+// maskedValue replaces the secrets of a printed config
+const maskedValue = "******"
+
+// Default returns a new config holding the default values.
+func Default() *Config {
+	cfg := new(Config)
+	defaults.SetDefaults(cfg)
+	return cfg
+}
+
+// Masked returns a copy of the config whose secrets are masked, so that it can be printed. Fields tagged secret:"true"
+// are masked entirely, and those tagged secret:"uri" keep everything but the password and the query.
+func Masked(cfg *Config) *Config {
+	masked := *cfg
+	maskSecrets(reflect.ValueOf(&masked).Elem())
+	return &masked
+}
+
+func maskSecrets(value reflect.Value) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		switch secret := value.Type().Field(i).Tag.Get("secret"); {
+		case field.Kind() == reflect.Struct:
+			maskSecrets(field)
+		case field.Kind() != reflect.String || field.String() == "":
+		case secret == "true":
+			field.SetString(maskedValue)
+		case secret == "uri":
+			field.SetString(maskURI(field.String()))
+		}
+	}
+}
+
+func maskURI(uri string) string {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return maskedValue
+	}
+	if parsed.RawQuery != "" {
+		parsed.RawQuery = maskedValue
+	}
+	if _, ok := parsed.User.Password(); ok {
+		// The mask is put in after the URI is escaped, so that it stays readable
+		parsed.User = url.User(parsed.User.Username())
+		return strings.Replace(parsed.String(), "@", ":"+maskedValue+"@", 1)
+	}
+	return parsed.String()
+}
+
+// DefaultYAML returns the default config as a YAML file with every key commented.
+func DefaultYAML() ([]byte, error) {
+	node, err := commentedNode(reflect.ValueOf(Default()).Elem(), "")
+	if err != nil {
+		return nil, err
+	}
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	if err = encoder.Encode(node); err != nil {
+		return nil, err
+	}
+	if err = encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// commentedNode returns the mapping node of a config struct, whose keys are commented with their descriptions.
+func commentedNode(value reflect.Value, path string) (*yaml.Node, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for i := 0; i < value.NumField(); i++ {
+		name := strings.SplitN(value.Type().Field(i).Tag.Get("yaml"), ",", 2)[0]
+		fieldPath := name
+		if path != "" {
+			fieldPath = fmt.Sprintf("%s.%s", path, name)
+		}
+		description, ok := descriptions[fieldPath]
+		if !ok {
+			return nil, fmt.Errorf("config: no description of %s", fieldPath)
+		}
+
+		var (
+			field     = value.Field(i)
+			valueNode *yaml.Node
+			err       error
+		)
+		if field.Kind() == reflect.Struct {
+			valueNode, err = commentedNode(field, fieldPath)
+		} else {
+			valueNode = new(yaml.Node)
+			err = valueNode.Encode(field.Interface())
+			if field.Kind() == reflect.Slice {
+				valueNode.Style = yaml.FlowStyle
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		keyNode := &yaml.Node{Kind: yaml.ScalarNode, Value: name}
+		if valueNode.Kind == yaml.MappingNode {
+			keyNode.HeadComment = description
+		} else {
+			valueNode.LineComment = description
+		}
+		node.Content = append(node.Content, keyNode, valueNode)
+	}
+	return node, nil
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"context"
+	e "errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	subscriptions []subscription
+	reloadMutex   sync.Mutex // Serializes the reloads and the subscriptions
+)
+
+// ApplyFunc applies a new config to a component. It returns an error, with the component left as it was, when the
+// new config cannot be applied. It is also called with the configs swapped to roll a change back.
+type ApplyFunc func(ctx context.Context, old, new *Config) error
+
+type subscription struct {
+	name  string
+	keys  []string
+	apply ApplyFunc
+}
+
+// ReloadResult describes a reload which succeeded.
+type ReloadResult struct {
+	Changed []string // The keys which changed
+	Applied []string // The subscriptions applied, in order
+	Restart []string // The keys which changed but no subscription applies, which take effect on restart
+}
+
+// ReloadError is a reload which failed to apply and was rolled back, entirely unless Rollback holds errors.
+type ReloadError struct {
+	Subscription string
+	Err          error
+	Rollback     []error
+}
+
+func (r *ReloadError) Error() string {
+	message := fmt.Sprintf("config: applying %s: %s", r.Subscription, r.Err)
+	if len(r.Rollback) > 0 {
+		message = fmt.Sprintf("%s, then rolling back: %s", message, e.Join(r.Rollback...))
+	}
+	return message
+}
+
+func (r *ReloadError) Unwrap() error {
+	return r.Err
+}
+
+// Subscribe calls apply on each reload which changes any of the keys, which are YAML paths of the config such as
+// zap.zap_level or cache.redis, a section. Subscriptions are applied in the order they are made.
+func Subscribe(name string, keys []string, apply ApplyFunc) {
+	reloadMutex.Lock()
+	defer reloadMutex.Unlock()
+	subscriptions = append(subscriptions, subscription{name: name, keys: keys, apply: apply})
+}
+
+// Reload validates cfg, applies it to the subscriptions of the keys which changed, and sets it as the applied config
+// once they all succeeded. Only the subscriptions change anything while serving; the keys without one are reported to
+// take effect on restart. When a subscription fails, those applied before it are rolled back in reverse order and the
+// applied config stays. Invalid configs are rejected with ValidationErrors, and failed ones with a *ReloadError.
+func Reload(ctx context.Context, cfg *Config) (*ReloadResult, error) {
+	if err := Validate(cfg); err != nil {
+		return nil, err
+	}
+
+	reloadMutex.Lock()
+	defer reloadMutex.Unlock()
+	current := Applied()
+	result := &ReloadResult{Changed: changedKeys(current, cfg)}
+	if len(result.Changed) == 0 {
+		return result, nil
+	}
+
+	subscribed := make(map[string]struct{})
+	var applied []subscription
+	for _, sub := range subscriptions {
+		keys := sub.matches(result.Changed)
+		if len(keys) == 0 {
+			continue
+		}
+		if err := sub.apply(ctx, current, cfg); err != nil {
+			reloadErr := &ReloadError{Subscription: sub.name, Err: err}
+			for i := len(applied) - 1; i >= 0; i-- {
+				if err = applied[i].apply(ctx, cfg, current); err != nil {
+					reloadErr.Rollback = append(reloadErr.Rollback, fmt.Errorf("%s: %w", applied[i].name, err))
+				}
+			}
+			return nil, reloadErr
+		}
+		applied = append(applied, sub)
+		result.Applied = append(result.Applied, sub.name)
+		for _, key := range keys {
+			subscribed[key] = struct{}{}
+		}
+	}
+	for _, key := range result.Changed {
+		if _, ok := subscribed[key]; !ok {
+			result.Restart = append(result.Restart, key)
+		}
+	}
+	Set(cfg)
+	return result, nil
+}
+
+// matches returns the keys the subscription is to.
+func (s subscription) matches(keys []string) []string {
+	var matched []string
+	for _, key := range keys {
+		for _, prefix := range s.keys {
+			if key == prefix || strings.HasPrefix(key, prefix+".") {
+				matched = append(matched, key)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// changedKeys returns the keys whose values differ between the configs, sorted.
+func changedKeys(old, new *Config) []string {
+	oldValues := make(map[string]reflect.Value)
+	_ = walkKeys(
+		reflect.ValueOf(old).Elem(), "", func(key string, _ reflect.StructField, value reflect.Value) error {
+			oldValues[key] = value
+			return nil
+		},
+	)
+	var changed []string
+	_ = walkKeys(
+		reflect.ValueOf(new).Elem(), "", func(key string, _ reflect.StructField, value reflect.Value) error {
+			oldValue := oldValues[key]
+			// An empty list is the same as none
+			if value.Kind() == reflect.Slice && value.Len() == 0 && oldValue.Len() == 0 {
+				return nil
+			}
+			if !reflect.DeepEqual(oldValue.Interface(), value.Interface()) {
+				changed = append(changed, key)
+			}
+			return nil
+		},
+	)
+	sort.Strings(changed)
+	return changed
+}
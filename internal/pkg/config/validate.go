@@ -0,0 +1,183 @@
+package config
+
+import (
+	e "errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"fiber-admin/pkg/utils/check"
+	"github.com/go-playground/validator/v10"
+	"github.com/robfig/cron/v3"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/connstring"
+)
+
+var (
+	configValidator     *validator.Validate
+	configValidatorOnce sync.Once
+)
+
+// ValidationError is an invalid value of the config, at its path in the config file, e.g. jwt.jwt_refresh_duration.
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationErrors are every invalid value of a config.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	lines := make([]string, 0, len(e))
+	for _, err := range e {
+		lines = append(lines, err.Error())
+	}
+	return fmt.Sprintf("invalid config:\n  %s", strings.Join(lines, "\n  "))
+}
+
+// Validate checks the whole config against the validate tags of its fields, and returns every invalid value at once
+// as ValidationErrors. Files the config points to, such as the Casbin model, have to exist.
+func Validate(cfg *Config) error {
+	err := getValidator().Struct(cfg)
+	if err == nil {
+		return nil
+	}
+	var fieldErrors validator.ValidationErrors
+	if !e.As(err, &fieldErrors) {
+		return err
+	}
+	errs := make(ValidationErrors, 0, len(fieldErrors))
+	for _, fieldError := range fieldErrors {
+		errs = append(
+			errs, ValidationError{
+				Field:   strings.TrimPrefix(fieldError.Namespace(), "Config."),
+				Message: validationMessage(fieldError),
+			},
+		)
+	}
+	return errs
+}
+
+// getValidator returns the validator of the config, which names the fields after their YAML keys. It is not the
+// validator of the requests, whose rules depend on the config.
+func getValidator() *validator.Validate {
+	configValidatorOnce.Do(
+		func() {
+			configValidator = validator.New()
+			configValidator.RegisterTagNameFunc(
+				func(field reflect.StructField) string {
+					return strings.SplitN(field.Tag.Get("yaml"), ",", 2)[0]
+				},
+			)
+			for tag, fn := range map[string]validator.Func{
+				"port":     isPort,
+				"cron":     isCronSpec,
+				"mongouri": isMongoURI,
+			} {
+				if err := configValidator.RegisterValidation(tag, fn); err != nil {
+					panic(err)
+				}
+			}
+		},
+	)
+	return configValidator
+}
+
+func isPort(fl validator.FieldLevel) bool {
+	return check.IsValidAppPort(fl.Field().String())
+}
+
+func isCronSpec(fl validator.FieldLevel) bool {
+	_, err := cron.ParseStandard(fl.Field().String())
+	return err == nil
+}
+
+func isMongoURI(fl validator.FieldLevel) bool {
+	_, err := connstring.ParseAndValidate(fl.Field().String())
+	return err == nil
+}
+
+// validationMessage describes the rule the value broke.
+func validationMessage(fieldError validator.FieldError) string {
+	param := fieldError.Param()
+	switch fieldError.Tag() {
+	case "required":
+		return "is required"
+	case "required_if":
+		condition := strings.Fields(param) // The field and the value it is required when the other field has
+		return fmt.Sprintf("is required when %s is %s", siblingName(fieldError, condition[0]), condition[1])
+	case "gt":
+		return fmt.Sprintf("must be greater than %s", param)
+	case "gte":
+		return fmt.Sprintf("must be at least %s", param)
+	case "lt":
+		return fmt.Sprintf("must be less than %s", param)
+	case "lte":
+		return fmt.Sprintf("must be at most %s", param)
+	case "min":
+		if kind := fieldError.Kind(); kind == reflect.Slice || kind == reflect.Map {
+			return fmt.Sprintf("must have at least %s items", param)
+		}
+		return fmt.Sprintf("must be at least %s", param)
+	case "max":
+		return fmt.Sprintf("must be at most %s", param)
+	case "gtfield":
+		return fmt.Sprintf("must be greater than %s", siblingName(fieldError, param))
+	case "gtefield":
+		return fmt.Sprintf("must be at least %s", siblingName(fieldError, param))
+	case "ltfield":
+		return fmt.Sprintf("must be less than %s", siblingName(fieldError, param))
+	case "ne":
+		return fmt.Sprintf("must not be %q", param)
+	case "oneof":
+		return fmt.Sprintf("must be one of [%s], not %q", param, fieldError.Value())
+	case "startswith":
+		return fmt.Sprintf("must start with %q", param)
+	case "file":
+		return fmt.Sprintf("file %q does not exist", fieldError.Value())
+	case "port":
+		return fmt.Sprintf("%q is not a port", fieldError.Value())
+	case "cron":
+		return fmt.Sprintf("%q is not a cron spec", fieldError.Value())
+	case "mongouri":
+		return "is not a MongoDB connection string"
+	case "hostname_port":
+		return fmt.Sprintf("%q is not a host:port address", fieldError.Value())
+	case "hostname|ip":
+		return fmt.Sprintf("%q is not a host name or IP address", fieldError.Value())
+	case "ip|cidr":
+		return fmt.Sprintf("%q is not an IP address or CIDR range", fieldError.Value())
+	case "url":
+		return "is not a URL"
+	case "timezone":
+		return fmt.Sprintf("%q is not a time zone", fieldError.Value())
+	default:
+		return fmt.Sprintf("failed on the %s rule", fieldError.Tag())
+	}
+}
+
+// siblingName returns the YAML key of the field named name next to the invalid one. Cross-field rules name the field
+// they compare with by its Go name.
+func siblingName(fieldError validator.FieldError, name string) string {
+	typ := reflect.TypeOf(Config{})
+	path := strings.Split(fieldError.StructNamespace(), ".")
+	for _, name := range path[1 : len(path)-1] {
+		field, ok := typ.FieldByName(strings.SplitN(name, "[", 2)[0])
+		if !ok {
+			return name
+		}
+		typ = field.Type
+		for typ.Kind() == reflect.Slice || typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+	}
+	field, ok := typ.FieldByName(name)
+	if !ok {
+		return name
+	}
+	return strings.SplitN(field.Tag.Get("yaml"), ",", 2)[0]
+}
@@ -3,18 +3,25 @@ package dao
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"fiber-admin/internal/pkg/config"
-	rds "fiber-admin/pkg/redis"
+	"fiber-admin/pkg/prometheus"
 	"github.com/goccy/go-json"
-	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/sync/singleflight"
 )
 
 type Cache struct {
-	Redis  *rds.Redis
-	Config *config.Config
-	Nil    CacheNil
+	Backend    CacheBackend
+	Config     *config.Config
+	Prometheus *prometheus.Prometheus
+	Nil        CacheNil
+	loads      singleflight.Group // Loads of GetOrLoad in flight, by key
+	local      *localCache        // Nil unless the local cache is enabled
 }
 
 type CacheNil struct{}
@@ -23,19 +30,31 @@ func (c CacheNil) Error() string {
 	return "cache: nil"
 }
 
-func NewCache(redis *rds.Redis, config *config.Config) *Cache {
-	return &Cache{
-		Redis:  redis,
-		Config: config,
-		Nil:    CacheNil{},
+func NewCache(backend CacheBackend, config *config.Config, prometheus *prometheus.Prometheus) *Cache {
+	c := &Cache{
+		Backend:    backend,
+		Config:     config,
+		Prometheus: prometheus,
+		Nil:        CacheNil{},
+		local:      newLocalCache(config.CacheConfig.LocalConfig),
 	}
+	if c.local != nil {
+		go c.listenInvalidations()
+	}
+	return c
+}
+
+// namespaced puts key under the namespace of the cache config, which every key and channel of the cache is in.
+func (c *Cache) namespaced(key string) string {
+	if c.Config.CacheConfig.Namespace == "" {
+		return key
+	}
+	return fmt.Sprintf("%s:%s", c.Config.CacheConfig.Namespace, key)
 }
 
 func (c *Cache) Get(ctx context.Context, key string) (*string, error) {
-	result, err := c.Redis.RedisClient.Get(ctx, key).Result()
-	if errors.Is(err, redis.Nil) {
-		return nil, c.Nil
-	} else if err != nil {
+	result, err := c.Backend.Get(ctx, c.namespaced(key))
+	if err != nil {
 		return nil, err
 	}
 	return &result, nil
@@ -43,16 +62,22 @@ func (c *Cache) Get(ctx context.Context, key string) (*string, error) {
 
 func (c *Cache) Set(ctx context.Context, key string, value string, ttl *time.Duration) error {
 	if ttl == nil {
-		return c.Redis.RedisClient.Set(ctx, key, value, c.Config.CacheConfig.DefaultTTL).Err()
+		return c.Backend.Set(ctx, c.namespaced(key), value, c.Config.CacheConfig.DefaultTTL)
 	}
-	return c.Redis.RedisClient.Set(ctx, key, value, *ttl).Err()
+	return c.Backend.Set(ctx, c.namespaced(key), value, *ttl)
+}
+
+// SetNX sets key as Set does unless it exists, and reports whether it did.
+func (c *Cache) SetNX(ctx context.Context, key string, value string, ttl *time.Duration) (bool, error) {
+	if ttl == nil {
+		ttl = &c.Config.CacheConfig.DefaultTTL
+	}
+	return c.Backend.SetNX(ctx, c.namespaced(key), value, *ttl)
 }
 
 func (c *Cache) GetList(ctx context.Context, key string, cacheList interface{}) error {
-	result, err := c.Redis.RedisClient.Get(ctx, key).Result()
-	if errors.Is(err, redis.Nil) {
-		return c.Nil
-	} else if err != nil {
+	result, err := c.Backend.Get(ctx, c.namespaced(key))
+	if err != nil {
 		return err
 	}
 	err = json.Unmarshal([]byte(result), cacheList)
@@ -70,40 +95,141 @@ func (c *Cache) SetList(ctx context.Context, key string, cacheList interface{},
 	if err != nil {
 		return err
 	}
-	return c.Redis.RedisClient.Set(ctx, key, cacheListJSON, *ttl).Err()
+	return c.Backend.Set(ctx, c.namespaced(key), string(cacheListJSON), *ttl)
 }
 
 func (c *Cache) RightPush(ctx context.Context, key string, value string) error {
-	return c.Redis.RedisClient.RPush(ctx, key, value).Err()
+	return c.Backend.RightPush(ctx, c.namespaced(key), value)
 }
 
 func (c *Cache) LeftPop(ctx context.Context, key string) (*string, error) {
-	result, err := c.Redis.RedisClient.LPop(ctx, key).Result()
-	if errors.Is(err, redis.Nil) {
-		return nil, c.Nil
-	} else if err != nil {
+	result, err := c.Backend.LeftPop(ctx, c.namespaced(key))
+	if err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
+// Delete deletes the key. Redis unlinks it, freeing its memory in the background.
 func (c *Cache) Delete(ctx context.Context, key string) error {
-	return c.Redis.RedisClient.Del(ctx, key).Err()
+	return c.Backend.Delete(ctx, c.namespaced(key))
+}
+
+// GetUnnamespaced reads key outside of the namespace, for the keys written before the namespace was configured.
+func (c *Cache) GetUnnamespaced(ctx context.Context, key string) (*string, error) {
+	result, err := c.Backend.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
 }
 
-func (c *Cache) Flush(ctx context.Context, prefix *string) error {
-	if prefix == nil {
-		return c.Redis.RedisClient.FlushAll(ctx).Err()
+// Flush deletes every key starting with prefix, or every key of the namespace when prefix is nil, and returns how
+// many there were. Keys outside of the namespace are never deleted, so without a namespace prefix is required. The
+// local cache of this instance is purged as well; the other instances drop their entries within the local TTL.
+func (c *Cache) Flush(ctx context.Context, prefix *string) (int64, error) {
+	var deleted int64
+	var err error
+	switch {
+	case prefix != nil:
+		deleted, err = c.Backend.DeletePrefix(ctx, c.namespaced(*prefix))
+	case c.Config.CacheConfig.Namespace == "":
+		return 0, errors.New("cache: flushing every key requires a namespace")
+	default:
+		deleted, err = c.Backend.DeletePrefix(ctx, c.namespaced(""))
+	}
+	if c.local != nil {
+		c.local.purge()
 	}
-	keys, err := c.Redis.RedisClient.Keys(ctx, *prefix+"*").Result()
+	return deleted, err
+}
+
+func generationKey(prefix string) string {
+	return fmt.Sprintf("%s:%s", config.CacheGenerationPrefix, prefix)
+}
+
+// Key returns the key of the current generation of prefix for the formatted suffix, e.g. dao:notice:g3:noticeID:...
+// Invalidate bumps the generation, so that the keys of the previous generation are no longer read and expire with
+// their TTL. When the generation cannot be read the key is unique, so that nothing stale is read and nothing
+// shared is written. The generation is cached locally when the local cache is enabled.
+func (c *Cache) Key(ctx context.Context, prefix, format string, args ...any) string {
+	suffix := fmt.Sprintf(format, args...)
+	var epoch uint64
+	if c.local != nil {
+		var (
+			generation int64
+			ok         bool
+		)
+		if generation, epoch, ok = c.local.generation(prefix); ok {
+			return fmt.Sprintf("%s:g%d:%s", prefix, generation, suffix)
+		}
+	}
+	generation, err := c.generation(ctx, prefix)
 	if err != nil {
+		return fmt.Sprintf("%s:uncached:%s:%s", prefix, primitive.NewObjectID().Hex(), suffix)
+	}
+	if c.local != nil {
+		c.local.setGeneration(prefix, generation, epoch)
+	}
+	return fmt.Sprintf("%s:g%d:%s", prefix, generation, suffix)
+}
+
+// IncrExpire increments key, setting it to expire after ttl when it creates it, and returns it.
+func (c *Cache) IncrExpire(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return c.Backend.IncrExpire(ctx, c.namespaced(key), ttl)
+}
+
+// Scan hands the keys starting with prefix to handle in batches, without the namespace.
+func (c *Cache) Scan(ctx context.Context, prefix string, handle func(keys []string) error) error {
+	namespace := c.namespaced("")
+	return c.Backend.Scan(
+		ctx, c.namespaced(prefix), func(keys []string) error {
+			for i, key := range keys {
+				keys[i] = strings.TrimPrefix(key, namespace)
+			}
+			return handle(keys)
+		},
+	)
+}
+
+// Invalidate bumps the generation of prefix, invalidating every key Key returned for it at once. The local caches of
+// every instance are told to drop their generation of prefix.
+func (c *Cache) Invalidate(ctx context.Context, prefix string) error {
+	if _, err := c.Backend.Incr(ctx, c.namespaced(generationKey(prefix))); err != nil {
 		return err
 	}
-	for _, key := range keys {
-		err := c.Redis.RedisClient.Del(ctx, key).Err()
-		if err != nil {
-			return err
-		}
+	if c.local == nil {
+		return nil
 	}
-	return nil
+	c.local.invalidate(prefix)
+	return c.Publish(ctx, config.CacheInvalidationChannel, prefix)
+}
+
+// generation returns the generation of prefix, 0 until it is first invalidated.
+func (c *Cache) generation(ctx context.Context, prefix string) (int64, error) {
+	generation, err := c.Backend.Get(ctx, c.namespaced(generationKey(prefix)))
+	if errors.Is(err, c.Nil) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(generation, 10, 64)
+}
+
+func (c *Cache) Publish(ctx context.Context, channel string, message string) error {
+	return c.Backend.Publish(ctx, c.namespaced(channel), message)
+}
+
+// Subscribe subscribes to the channels. The caller closes the subscription.
+func (c *Cache) Subscribe(ctx context.Context, channels ...string) (CacheSubscription, error) {
+	namespaced := make([]string, 0, len(channels))
+	for _, channel := range channels {
+		namespaced = append(namespaced, c.namespaced(channel))
+	}
+	return c.Backend.Subscribe(ctx, namespaced...)
+}
+
+// SubscriberCount returns the number of clients subscribed to the channel, across every instance.
+func (c *Cache) SubscriberCount(ctx context.Context, channel string) (int64, error) {
+	return c.Backend.SubscriberCount(ctx, c.namespaced(channel))
 }
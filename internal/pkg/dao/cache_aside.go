@@ -0,0 +1,148 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/goccy/go-json"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// Results of the cache-aside reads, counted by entity
+const (
+	CacheResultHit         = "hit"
+	CacheResultLocalHit    = "local_hit"
+	CacheResultNegativeHit = "negative_hit"
+	CacheResultMiss        = "miss"
+	CacheResultError       = "error"
+)
+
+// cacheNotFound is cached for documents which do not exist. It is not JSON, so no cached value can be mistaken for it.
+const cacheNotFound = "!not-found"
+
+// GetOrLoad reads the value cached under key, calling load on a miss and caching what it returns for about ttl:
+//   - Concurrent misses of the same key share one load, so that an expired hot key sends one query to MongoDB rather
+//     than one per request. The shared load is not cancelled when the request which started it is.
+//   - The TTL is spread by the TTL jitter of the cache config, so that keys cached together do not expire together.
+//   - When load finds no document (mongo.ErrNoDocuments), that is cached for the negative cache TTL and returned as
+//     mongo.ErrNoDocuments, so that lookups of missing documents do not reach MongoDB either.
+//
+// When the local cache is enabled for the entity, values are read from it before Redis and cached in it for the local
+// TTL. Redis errors are logged and fall back to load. Reads are counted by entity and result.
+func GetOrLoad[T any](
+	ctx context.Context, c *Cache, logger *zap.Logger, entity, key string, ttl time.Duration,
+	load func(ctx context.Context) (*T, error),
+) (*T, error) {
+	var (
+		cached    *string
+		err       error
+		hitResult = CacheResultHit
+		local     = c.local.enabled(entity)
+	)
+	if local {
+		if value, ok := c.local.get(key); ok {
+			cached, hitResult = &value, CacheResultLocalHit
+		}
+	}
+	if cached == nil {
+		if cached, err = c.Get(ctx, key); err == nil && local {
+			c.local.set(key, *cached, ttl)
+		}
+	}
+	switch {
+	case errors.Is(err, c.Nil):
+		c.observe(entity, CacheResultMiss)
+		logger.Info("Cache.GetOrLoad: cache miss", zap.String("entity", entity), zap.String("key", key))
+	case err != nil:
+		c.observe(entity, CacheResultError)
+		logger.Error(
+			"Cache.GetOrLoad: failed to get cache", zap.Error(err),
+			zap.String("entity", entity), zap.String("key", key),
+		)
+	case *cached == cacheNotFound:
+		c.observe(entity, CacheResultNegativeHit)
+		logger.Info("Cache.GetOrLoad: negative cache hit", zap.String("entity", entity), zap.String("key", key))
+		return nil, mongo.ErrNoDocuments
+	default:
+		value := new(T)
+		if err := json.Unmarshal([]byte(*cached), value); err != nil {
+			c.observe(entity, CacheResultError)
+			logger.Error(
+				"Cache.GetOrLoad: failed to unmarshal cache", zap.Error(err),
+				zap.String("entity", entity), zap.String("key", key),
+			)
+			break
+		}
+		c.observe(entity, hitResult)
+		logger.Info("Cache.GetOrLoad: cache hit", zap.String("entity", entity), zap.String("key", key))
+		return value, nil
+	}
+
+	loaded, err, shared := c.loads.Do(
+		key, func() (any, error) {
+			ctx := context.WithoutCancel(ctx)
+			value, err := load(ctx)
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				if negativeTTL := c.Config.CacheConfig.NegativeCacheTTL; negativeTTL > 0 {
+					if local {
+						c.local.set(key, cacheNotFound, negativeTTL)
+					}
+					if err := c.Set(ctx, key, cacheNotFound, &negativeTTL); err != nil {
+						logger.Error(
+							"Cache.GetOrLoad: failed to set negative cache", zap.Error(err),
+							zap.String("entity", entity), zap.String("key", key),
+						)
+					}
+				}
+				return nil, err
+			} else if err != nil {
+				return nil, err
+			}
+			valueJSON, err := json.Marshal(value)
+			if err == nil {
+				jittered := c.jitter(ttl)
+				if local {
+					c.local.set(key, string(valueJSON), jittered)
+				}
+				err = c.Set(ctx, key, string(valueJSON), &jittered)
+			}
+			if err != nil {
+				logger.Error(
+					"Cache.GetOrLoad: failed to set cache", zap.Error(err),
+					zap.String("entity", entity), zap.String("key", key),
+				)
+			} else {
+				logger.Info("Cache.GetOrLoad: cache set", zap.String("entity", entity), zap.String("key", key))
+			}
+			return value, nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	value := loaded.(*T)
+	if shared {
+		// Every caller gets its own copy, so that one changing the value does not change it for the others
+		shallow := *value
+		value = &shallow
+	}
+	return value, nil
+}
+
+// jitter spreads ttl by up to the TTL jitter of the cache config either way, e.g. 0.1 for ±10%.
+func (c *Cache) jitter(ttl time.Duration) time.Duration {
+	spread := c.Config.CacheConfig.TTLJitter
+	if spread <= 0 {
+		return ttl
+	}
+	return ttl + time.Duration((rand.Float64()*2-1)*spread*float64(ttl))
+}
+
+func (c *Cache) observe(entity, result string) {
+	if c.Prometheus != nil {
+		c.Prometheus.ObserveCache(entity, result)
+	}
+}
@@ -0,0 +1,53 @@
+package dao
+
+import (
+	"context"
+	"time"
+)
+
+// CacheBackend stores the cache. Redis backs a cluster of instances; the in-memory backend lets a single instance run
+// without Redis. Missing keys are reported as CacheNil.
+type CacheBackend interface {
+	Get(ctx context.Context, key string) (string, error)
+	// Set stores value under key for ttl, or without expiry when ttl is 0.
+	Set(ctx context.Context, key string, value string, ttl time.Duration) error
+	// SetNX sets key as Set does unless it exists, and reports whether it did.
+	SetNX(ctx context.Context, key string, value string, ttl time.Duration) (bool, error)
+	// TTL returns the time key lives for, 0 when it does not expire.
+	TTL(ctx context.Context, key string) (time.Duration, error)
+	// Incr increments the integer under key, starting from 0, and returns it.
+	Incr(ctx context.Context, key string) (int64, error)
+	// IncrExpire increments key as Incr does, and sets it to expire after ttl when it creates it.
+	IncrExpire(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	RightPush(ctx context.Context, key string, value string) error
+	LeftPush(ctx context.Context, key string, value string) error
+	LeftPop(ctx context.Context, key string) (string, error)
+	// Len returns the length of the list under key, 0 when it does not exist.
+	Len(ctx context.Context, key string) (int64, error)
+	Delete(ctx context.Context, keys ...string) error
+	// DeletePrefix deletes every key starting with prefix and returns how many there were.
+	DeletePrefix(ctx context.Context, prefix string) (int64, error)
+	// Scan hands the keys starting with prefix to handle in batches, without blocking the backend meanwhile.
+	Scan(ctx context.Context, prefix string, handle func(keys []string) error) error
+	// MemoryUsage returns the number of bytes the keys take, roughly. Keys which do not exist take none.
+	MemoryUsage(ctx context.Context, keys ...string) (int64, error)
+	Publish(ctx context.Context, channel string, message string) error
+	// Subscribe subscribes to the channels. The subscription is active once Subscribe returns.
+	Subscribe(ctx context.Context, channels ...string) (CacheSubscription, error)
+	// SubscriberCount returns the number of subscriptions to the channel, across every instance.
+	SubscriberCount(ctx context.Context, channel string) (int64, error)
+	Close() error
+}
+
+// CacheMessage is a message published on a channel.
+type CacheMessage struct {
+	Channel string
+	Payload string
+}
+
+// CacheSubscription receives the messages published on the channels it subscribed to.
+type CacheSubscription interface {
+	// Channel returns the messages. It is closed once the subscription is.
+	Channel() <-chan *CacheMessage
+	Close() error
+}
@@ -0,0 +1,348 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memorySweepInterval is how often the expired keys of the in-memory backend are dropped. Expired keys are never
+// read in between, sweeping only frees their memory.
+const memorySweepInterval = time.Minute
+
+// memorySubscriptionBuffer is the number of messages a subscription of the in-memory backend holds for its reader.
+// Messages published while it is full are dropped, as Redis drops them for clients which cannot keep up.
+const memorySubscriptionBuffer = 100
+
+// errWrongType is returned for list operations on strings and string operations on lists, as Redis does.
+var errWrongType = errors.New("WRONGTYPE Operation against a key holding the wrong kind of value")
+
+type memoryItem struct {
+	value     string
+	list      []string
+	isList    bool
+	expiresAt time.Time // Zero when the item does not expire
+}
+
+func (i *memoryItem) expired(now time.Time) bool {
+	return !i.expiresAt.IsZero() && now.After(i.expiresAt)
+}
+
+// MemoryCacheBackend stores the cache in the memory of the process, for a single instance running without Redis.
+// Nothing is shared with other instances, and nothing survives a restart.
+type MemoryCacheBackend struct {
+	mutex         sync.Mutex
+	items         map[string]*memoryItem
+	subscriptions map[string]map[*memorySubscription]struct{} // By channel
+	done          chan struct{}
+	closeOnce     sync.Once
+}
+
+func NewMemoryCacheBackend() *MemoryCacheBackend {
+	m := &MemoryCacheBackend{
+		items:         make(map[string]*memoryItem),
+		subscriptions: make(map[string]map[*memorySubscription]struct{}),
+		done:          make(chan struct{}),
+	}
+	go m.sweep()
+	return m
+}
+
+func (m *MemoryCacheBackend) sweep() {
+	ticker := time.NewTicker(memorySweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.done:
+			return
+		case now := <-ticker.C:
+			m.mutex.Lock()
+			for key, item := range m.items {
+				if item.expired(now) {
+					delete(m.items, key)
+				}
+			}
+			m.mutex.Unlock()
+		}
+	}
+}
+
+// item returns the item under key unless it expired. The caller holds the mutex.
+func (m *MemoryCacheBackend) item(key string) (*memoryItem, bool) {
+	item, ok := m.items[key]
+	if !ok {
+		return nil, false
+	}
+	if item.expired(time.Now()) {
+		delete(m.items, key)
+		return nil, false
+	}
+	return item, true
+}
+
+func (m *MemoryCacheBackend) Get(_ context.Context, key string) (string, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	item, ok := m.item(key)
+	if !ok {
+		return "", CacheNil{}
+	} else if item.isList {
+		return "", errWrongType
+	}
+	return item.value, nil
+}
+
+func (m *MemoryCacheBackend) Set(_ context.Context, key string, value string, ttl time.Duration) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	item := &memoryItem{value: value}
+	if ttl > 0 {
+		item.expiresAt = time.Now().Add(ttl)
+	}
+	m.items[key] = item
+	return nil
+}
+
+func (m *MemoryCacheBackend) SetNX(_ context.Context, key string, value string, ttl time.Duration) (bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if _, ok := m.item(key); ok {
+		return false, nil
+	}
+	item := &memoryItem{value: value}
+	if ttl > 0 {
+		item.expiresAt = time.Now().Add(ttl)
+	}
+	m.items[key] = item
+	return true, nil
+}
+
+func (m *MemoryCacheBackend) TTL(_ context.Context, key string) (time.Duration, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	item, ok := m.item(key)
+	if !ok {
+		return 0, CacheNil{}
+	} else if item.expiresAt.IsZero() {
+		return 0, nil
+	}
+	return time.Until(item.expiresAt), nil
+}
+
+func (m *MemoryCacheBackend) Incr(ctx context.Context, key string) (int64, error) {
+	return m.IncrExpire(ctx, key, 0)
+}
+
+func (m *MemoryCacheBackend) IncrExpire(_ context.Context, key string, ttl time.Duration) (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	item, ok := m.item(key)
+	if !ok {
+		item = &memoryItem{value: "0"}
+		if ttl > 0 {
+			item.expiresAt = time.Now().Add(ttl)
+		}
+		m.items[key] = item
+	} else if item.isList {
+		return 0, errWrongType
+	}
+	value, err := strconv.ParseInt(item.value, 10, 64)
+	if err != nil {
+		return 0, errors.New("ERR value is not an integer or out of range")
+	}
+	value++
+	item.value = strconv.FormatInt(value, 10)
+	return value, nil
+}
+
+// list returns the list under key, creating it when create is set. The caller holds the mutex.
+func (m *MemoryCacheBackend) list(key string, create bool) (*memoryItem, error) {
+	item, ok := m.item(key)
+	if !ok {
+		if !create {
+			return nil, nil
+		}
+		item = &memoryItem{isList: true}
+		m.items[key] = item
+	} else if !item.isList {
+		return nil, errWrongType
+	}
+	return item, nil
+}
+
+func (m *MemoryCacheBackend) RightPush(_ context.Context, key string, value string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	item, err := m.list(key, true)
+	if err != nil {
+		return err
+	}
+	item.list = append(item.list, value)
+	return nil
+}
+
+func (m *MemoryCacheBackend) LeftPush(_ context.Context, key string, value string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	item, err := m.list(key, true)
+	if err != nil {
+		return err
+	}
+	item.list = append([]string{value}, item.list...)
+	return nil
+}
+
+func (m *MemoryCacheBackend) LeftPop(_ context.Context, key string) (string, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	item, err := m.list(key, false)
+	if err != nil {
+		return "", err
+	} else if item == nil {
+		return "", CacheNil{}
+	}
+	value := item.list[0]
+	item.list = item.list[1:]
+	if len(item.list) == 0 {
+		delete(m.items, key) // Redis deletes emptied lists
+	}
+	return value, nil
+}
+
+func (m *MemoryCacheBackend) Len(_ context.Context, key string) (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	item, err := m.list(key, false)
+	if err != nil || item == nil {
+		return 0, err
+	}
+	return int64(len(item.list)), nil
+}
+
+func (m *MemoryCacheBackend) Delete(_ context.Context, keys ...string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for _, key := range keys {
+		delete(m.items, key)
+	}
+	return nil
+}
+
+func (m *MemoryCacheBackend) DeletePrefix(_ context.Context, prefix string) (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	var deleted int64
+	for key := range m.items {
+		if strings.HasPrefix(key, prefix) {
+			delete(m.items, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// Scan hands the keys over in a single batch, collected before handle runs so that it may use the backend.
+func (m *MemoryCacheBackend) Scan(_ context.Context, prefix string, handle func(keys []string) error) error {
+	m.mutex.Lock()
+	now := time.Now()
+	keys := make([]string, 0)
+	for key, item := range m.items {
+		if strings.HasPrefix(key, prefix) && !item.expired(now) {
+			keys = append(keys, key)
+		}
+	}
+	m.mutex.Unlock()
+	if len(keys) == 0 {
+		return nil
+	}
+	return handle(keys)
+}
+
+// MemoryUsage counts the bytes of the keys and their values, leaving out the overhead of the maps.
+func (m *MemoryCacheBackend) MemoryUsage(_ context.Context, keys ...string) (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	var total int64
+	for _, key := range keys {
+		item, ok := m.item(key)
+		if !ok {
+			continue
+		}
+		total += int64(len(key) + len(item.value))
+		for _, value := range item.list {
+			total += int64(len(value))
+		}
+	}
+	return total, nil
+}
+
+func (m *MemoryCacheBackend) Publish(_ context.Context, channel string, message string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for subscription := range m.subscriptions[channel] {
+		select {
+		case subscription.messages <- &CacheMessage{Channel: channel, Payload: message}:
+		default:
+		}
+	}
+	return nil
+}
+
+func (m *MemoryCacheBackend) Subscribe(_ context.Context, channels ...string) (CacheSubscription, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	subscription := &memorySubscription{
+		backend:  m,
+		channels: channels,
+		messages: make(chan *CacheMessage, memorySubscriptionBuffer),
+	}
+	for _, channel := range channels {
+		if m.subscriptions[channel] == nil {
+			m.subscriptions[channel] = make(map[*memorySubscription]struct{})
+		}
+		m.subscriptions[channel][subscription] = struct{}{}
+	}
+	return subscription, nil
+}
+
+func (m *MemoryCacheBackend) SubscriberCount(_ context.Context, channel string) (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return int64(len(m.subscriptions[channel])), nil
+}
+
+func (m *MemoryCacheBackend) Close() error {
+	m.closeOnce.Do(func() { close(m.done) })
+	return nil
+}
+
+type memorySubscription struct {
+	backend  *MemoryCacheBackend
+	channels []string
+	messages chan *CacheMessage
+	closed   bool
+}
+
+func (s *memorySubscription) Channel() <-chan *CacheMessage {
+	return s.messages
+}
+
+func (s *memorySubscription) Close() error {
+	s.backend.mutex.Lock()
+	defer s.backend.mutex.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	for _, channel := range s.channels {
+		delete(s.backend.subscriptions[channel], s)
+		if len(s.backend.subscriptions[channel]) == 0 {
+			delete(s.backend.subscriptions, channel)
+		}
+	}
+	close(s.messages)
+	return nil
+}
@@ -0,0 +1,229 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	rds "fiber-admin/pkg/redis"
+	"github.com/redis/go-redis/v9"
+)
+
+// scanBatchSize is the number of keys Scan hands over at a time
+const scanBatchSize = 500
+
+// globEscaper escapes the characters which have a meaning in the patterns of SCAN
+var globEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
+// incrExpireScript increments KEYS[1] and sets it to expire after ARGV[1] milliseconds when it creates it
+var incrExpireScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return count
+`)
+
+// RedisCacheBackend stores the cache in Redis, shared by every instance.
+type RedisCacheBackend struct {
+	Redis *rds.Redis
+}
+
+func NewRedisCacheBackend(redis *rds.Redis) *RedisCacheBackend {
+	return &RedisCacheBackend{Redis: redis}
+}
+
+// Client returns the client of the backend, for the features only Redis has such as streams.
+func (r *RedisCacheBackend) Client() *redis.Client {
+	return r.Redis.Client()
+}
+
+func (r *RedisCacheBackend) Get(ctx context.Context, key string) (string, error) {
+	result, err := r.Client().Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", CacheNil{}
+	}
+	return result, err
+}
+
+func (r *RedisCacheBackend) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
+	return r.Client().Set(ctx, key, value, ttl).Err()
+}
+
+func (r *RedisCacheBackend) SetNX(ctx context.Context, key string, value string, ttl time.Duration) (bool, error) {
+	return r.Client().SetNX(ctx, key, value, ttl).Result()
+}
+
+func (r *RedisCacheBackend) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := r.Client().TTL(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	switch ttl {
+	case -2: // The key does not exist
+		return 0, CacheNil{}
+	case -1: // The key does not expire
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+func (r *RedisCacheBackend) Incr(ctx context.Context, key string) (int64, error) {
+	return r.Client().Incr(ctx, key).Result()
+}
+
+// IncrExpire runs as a script, so that a key is never left without its expiry.
+func (r *RedisCacheBackend) IncrExpire(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return incrExpireScript.Run(ctx, r.Client(), []string{key}, ttl.Milliseconds()).Int64()
+}
+
+func (r *RedisCacheBackend) RightPush(ctx context.Context, key string, value string) error {
+	return r.Client().RPush(ctx, key, value).Err()
+}
+
+func (r *RedisCacheBackend) LeftPush(ctx context.Context, key string, value string) error {
+	return r.Client().LPush(ctx, key, value).Err()
+}
+
+func (r *RedisCacheBackend) LeftPop(ctx context.Context, key string) (string, error) {
+	result, err := r.Client().LPop(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", CacheNil{}
+	}
+	return result, err
+}
+
+func (r *RedisCacheBackend) Len(ctx context.Context, key string) (int64, error) {
+	return r.Client().LLen(ctx, key).Result()
+}
+
+// Delete unlinks the keys, freeing their memory in the background.
+func (r *RedisCacheBackend) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return r.Client().Unlink(ctx, keys...).Err()
+}
+
+// DeletePrefix unlinks the keys in the batches Scan finds them in.
+func (r *RedisCacheBackend) DeletePrefix(ctx context.Context, prefix string) (int64, error) {
+	var deleted int64
+	err := r.Scan(
+		ctx, prefix, func(keys []string) error {
+			count, err := r.Client().Unlink(ctx, keys...).Result()
+			deleted += count
+			return err
+		},
+	)
+	return deleted, err
+}
+
+// Scan finds the keys with SCAN rather than KEYS, so that Redis is not blocked while a large keyspace is walked. The
+// glob characters of prefix are escaped, so that it only matches itself.
+func (r *RedisCacheBackend) Scan(ctx context.Context, prefix string, handle func(keys []string) error) error {
+	iter := r.Client().Scan(ctx, 0, globEscaper.Replace(prefix)+"*", scanBatchSize).Iterator()
+	keys := make([]string, 0, scanBatchSize)
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+		if len(keys) == scanBatchSize {
+			if err := handle(keys); err != nil {
+				return err
+			}
+			keys = keys[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	return handle(keys)
+}
+
+// MemoryUsage asks MEMORY USAGE of every key in one pipeline.
+func (r *RedisCacheBackend) MemoryUsage(ctx context.Context, keys ...string) (int64, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	pipeline := r.Client().Pipeline()
+	usages := make([]*redis.IntCmd, 0, len(keys))
+	for _, key := range keys {
+		usages = append(usages, pipeline.MemoryUsage(ctx, key))
+	}
+	if _, err := pipeline.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return 0, err
+	}
+	var total int64
+	for _, usage := range usages {
+		total += usage.Val() // 0 for the keys which expired meanwhile
+	}
+	return total, nil
+}
+
+func (r *RedisCacheBackend) Publish(ctx context.Context, channel string, message string) error {
+	return r.Client().Publish(ctx, channel, message).Err()
+}
+
+// Subscribe waits for Redis to confirm the subscription, so that the messages published once it returns are not
+// missed.
+func (r *RedisCacheBackend) Subscribe(ctx context.Context, channels ...string) (CacheSubscription, error) {
+	client := r.Client()
+	if client == nil {
+		return nil, redis.ErrClosed
+	}
+	pubsub := client.Subscribe(ctx, channels...)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, err
+	}
+	subscription := &redisSubscription{
+		pubsub:   pubsub,
+		messages: make(chan *CacheMessage),
+		closed:   make(chan struct{}),
+	}
+	go subscription.relay()
+	return subscription, nil
+}
+
+func (r *RedisCacheBackend) SubscriberCount(ctx context.Context, channel string) (int64, error) {
+	counts, err := r.Client().PubSubNumSub(ctx, channel).Result()
+	if err != nil {
+		return 0, err
+	}
+	return counts[channel], nil
+}
+
+func (r *RedisCacheBackend) Close() error {
+	return r.Redis.Close()
+}
+
+type redisSubscription struct {
+	pubsub    *redis.PubSub
+	messages  chan *CacheMessage
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// relay hands the messages of the subscription on until it is closed.
+func (s *redisSubscription) relay() {
+	defer close(s.messages)
+	for message := range s.pubsub.Channel() {
+		select {
+		case s.messages <- &CacheMessage{Channel: message.Channel, Payload: message.Payload}:
+		case <-s.closed:
+			return
+		}
+	}
+}
+
+func (s *redisSubscription) Channel() <-chan *CacheMessage {
+	return s.messages
+}
+
+func (s *redisSubscription) Close() error {
+	s.closeOnce.Do(func() { close(s.closed) })
+	return s.pubsub.Close()
+}
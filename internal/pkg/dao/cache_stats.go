@@ -0,0 +1,75 @@
+package dao
+
+import (
+	"context"
+	"strings"
+)
+
+// CacheOtherPrefix groups the keys under none of the prefixes Stats is asked for.
+const CacheOtherPrefix = "other"
+
+// CachePrefixStats counts the keys under a prefix of the namespace.
+type CachePrefixStats struct {
+	Prefix string
+	Keys   int64
+	Memory int64 // Bytes, as estimated by the backend
+}
+
+// CacheStats describes what the cache holds.
+type CacheStats struct {
+	Prefixes     []CachePrefixStats // In the order the prefixes were given in, followed by CacheOtherPrefix
+	LocalEntries int                // Entries of the local cache, including expired ones not evicted yet
+}
+
+// Stats counts the keys of the namespace and their memory by prefix. Every key counts towards the longest of the
+// prefixes it is under, or towards CacheOtherPrefix. The keys are scanned in batches, so the counts of a busy cache
+// are approximate.
+func (c *Cache) Stats(ctx context.Context, prefixes []string) (*CacheStats, error) {
+	groups := append(append(make([]string, 0, len(prefixes)+1), prefixes...), CacheOtherPrefix)
+	byPrefix := make(map[string]*CachePrefixStats, len(groups))
+	for _, prefix := range groups {
+		byPrefix[prefix] = &CachePrefixStats{Prefix: prefix}
+	}
+	namespace := c.namespaced("")
+	err := c.Backend.Scan(
+		ctx, namespace, func(keys []string) error {
+			grouped := make(map[string][]string)
+			for _, key := range keys {
+				prefix := longestPrefix(strings.TrimPrefix(key, namespace), prefixes)
+				grouped[prefix] = append(grouped[prefix], key)
+			}
+			for prefix, keys := range grouped {
+				memory, err := c.Backend.MemoryUsage(ctx, keys...)
+				if err != nil {
+					return err
+				}
+				byPrefix[prefix].Keys += int64(len(keys))
+				byPrefix[prefix].Memory += memory
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	stats := &CacheStats{Prefixes: make([]CachePrefixStats, 0, len(groups))}
+	for _, prefix := range groups {
+		stats.Prefixes = append(stats.Prefixes, *byPrefix[prefix])
+	}
+	if c.local != nil {
+		stats.LocalEntries = c.local.values.Len()
+	}
+	return stats, nil
+}
+
+// longestPrefix returns the longest of the prefixes key is under, or CacheOtherPrefix.
+func longestPrefix(key string, prefixes []string) string {
+	longest := CacheOtherPrefix
+	for _, prefix := range prefixes {
+		if (key == prefix || strings.HasPrefix(key, prefix+":")) &&
+			(longest == CacheOtherPrefix || len(prefix) > len(longest)) {
+			longest = prefix
+		}
+	}
+	return longest
+}
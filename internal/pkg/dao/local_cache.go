@@ -0,0 +1,116 @@
+package dao
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"fiber-admin/internal/pkg/config"
+	"fiber-admin/internal/pkg/config/mods/cache"
+	"fiber-admin/pkg/lru"
+)
+
+// invalidationRetryInterval is how long to wait before subscribing to the invalidations again when it failed
+const invalidationRetryInterval = time.Second
+
+// localCache is the in-process cache in front of Redis. It holds the values GetOrLoad reads and the generations Key
+// reads, so that hot keys are served without a Redis round trip. Invalidate publishes the invalidated prefix to every
+// instance, which drop their generation of it, so that the entries of the previous generation are no longer read.
+// Entries live for the local TTL at most, which bounds how stale an instance missing an invalidation can be.
+type localCache struct {
+	values   *lru.Cache[string, string]
+	entities map[string]struct{}
+	ttl      time.Duration
+	mutex    sync.Mutex // Orders caching generations against invalidations
+	epoch    uint64     // Bumped by every invalidation
+}
+
+func newLocalCache(localConfig cache.LocalConfig) *localCache {
+	if !localConfig.Enabled || localConfig.Size <= 0 || localConfig.TTL <= 0 {
+		return nil
+	}
+	local := &localCache{
+		values:   lru.New[string, string](localConfig.Size),
+		entities: make(map[string]struct{}),
+		ttl:      localConfig.TTL,
+	}
+	for _, entity := range localConfig.Entities {
+		local.entities[entity] = struct{}{}
+	}
+	return local
+}
+
+// listenInvalidations drops the generations invalidated by any instance. The subscription lives as long as the
+// process; it is retried until it succeeds, and reconnects by itself when the connection to Redis drops. It is made
+// again when it ends, e.g. once the Redis client is replaced by a config reload, and the local cache is purged of the
+// invalidations it may have missed meanwhile.
+func (c *Cache) listenInvalidations() {
+	for {
+		subscription, err := c.Subscribe(context.Background(), config.CacheInvalidationChannel)
+		if err != nil {
+			time.Sleep(invalidationRetryInterval)
+			continue
+		}
+		c.local.purge()
+		for message := range subscription.Channel() {
+			c.local.invalidate(message.Payload)
+		}
+		_ = subscription.Close()
+	}
+}
+
+// enabled reports whether the values of the entity are cached locally.
+func (l *localCache) enabled(entity string) bool {
+	if l == nil {
+		return false
+	}
+	_, ok := l.entities[entity]
+	return ok
+}
+
+func (l *localCache) get(key string) (string, bool) {
+	return l.values.Get(key)
+}
+
+// set caches value for the local TTL, or for ttl when it is shorter.
+func (l *localCache) set(key, value string, ttl time.Duration) {
+	l.values.Set(key, value, min(ttl, l.ttl))
+}
+
+// generation returns the cached generation of prefix, and the epoch to cache a generation read from Redis with.
+func (l *localCache) generation(prefix string) (int64, uint64, bool) {
+	l.mutex.Lock()
+	epoch := l.epoch
+	l.mutex.Unlock()
+	cached, ok := l.values.Get(generationKey(prefix))
+	if !ok {
+		return 0, epoch, false
+	}
+	generation, err := strconv.ParseInt(cached, 10, 64)
+	return generation, epoch, err == nil
+}
+
+// setGeneration caches the generation of prefix read at epoch, unless an invalidation came in since.
+func (l *localCache) setGeneration(prefix string, generation int64, epoch uint64) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.epoch == epoch {
+		l.values.Set(generationKey(prefix), strconv.FormatInt(generation, 10), l.ttl)
+	}
+}
+
+func (l *localCache) invalidate(prefix string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.epoch++
+	l.values.Remove(generationKey(prefix))
+}
+
+// purge drops every entry, the generations included.
+func (l *localCache) purge() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.epoch++
+	l.values.Purge()
+}
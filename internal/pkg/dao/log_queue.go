@@ -0,0 +1,458 @@
+package dao
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"fiber-admin/internal/pkg/config"
+	"github.com/goccy/go-json"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// logQueueValueField is the stream entry field holding the queued log.
+const logQueueValueField = "value"
+
+// ErrLogQueueEntryInvalid marks an entry that can never be stored, so it is dead-lettered without being retried.
+var ErrLogQueueEntryInvalid = errors.New("log queue entry is invalid")
+
+// LogQueue is a Redis stream read through a consumer group. An entry is only removed once its handler has stored it,
+// so entries read by a consumer that failed or crashed are delivered again, and entries that keep failing are moved to
+// a dead-letter stream.
+//
+// Without Redis the queue is a list of the cache backend instead. Entries which could not be stored are put back in
+// front of it, and lost when the process exits.
+type LogQueue struct {
+	cache            *Cache
+	stream           string // Namespaced, as are the other keys of the cache
+	deadLetter       string
+	legacyStream     string // The streams before the namespace was configured
+	legacyDeadLetter string
+	legacyList       string
+	consumer         string
+	sequence         atomic.Uint64 // Sequence of the entry IDs of a list queue
+}
+
+// LogQueueEntry is a log read from a LogQueue.
+type LogQueueEntry struct {
+	ID    string
+	Value string
+}
+
+// LogQueueStats describes the entries of a LogQueue that have not been stored yet.
+type LogQueueStats struct {
+	Backlog     int64         // Entries not stored yet, including pending ones
+	Pending     int64         // Entries read by a consumer but not stored yet
+	DeadLetters int64         // Entries in the dead-letter stream
+	Lag         time.Duration // Age of the oldest entry not stored yet
+}
+
+// NewLogQueue returns the queue on the given stream, under the namespace of the cache. Entries still queued in
+// legacyList, the list the logs were queued in before, and in the streams outside of the namespace are moved to the
+// stream when it is consumed.
+func NewLogQueue(cache *Cache, stream, deadLetter, legacyList string) *LogQueue {
+	hostname, _ := os.Hostname()
+	return &LogQueue{
+		cache:            cache,
+		stream:           cache.namespaced(stream),
+		deadLetter:       cache.namespaced(deadLetter),
+		legacyStream:     stream,
+		legacyDeadLetter: deadLetter,
+		legacyList:       legacyList,
+		consumer:         fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+	}
+}
+
+// ObjectID derives an ObjectID from the entry ID, so that storing a redelivered entry again collides with the copy
+// stored before instead of duplicating it.
+func (e LogQueueEntry) ObjectID() (primitive.ObjectID, error) {
+	var id primitive.ObjectID
+	millis, sequence, ok := strings.Cut(e.ID, "-")
+	if !ok {
+		return id, fmt.Errorf("invalid stream entry ID %s", e.ID)
+	}
+	ms, err := strconv.ParseUint(millis, 10, 64)
+	if err != nil {
+		return id, fmt.Errorf("invalid stream entry ID %s", e.ID)
+	}
+	seq, err := strconv.ParseUint(sequence, 10, 64)
+	if err != nil {
+		return id, fmt.Errorf("invalid stream entry ID %s", e.ID)
+	}
+	binary.BigEndian.PutUint32(id[0:4], uint32(ms/1000))
+	binary.BigEndian.PutUint16(id[4:6], uint16(ms%1000))
+	id[6], id[7] = byte(seq>>40), byte(seq>>32)
+	binary.BigEndian.PutUint32(id[8:12], uint32(seq))
+	return id, nil
+}
+
+// client returns the Redis client of the queue, nil when the cache is not backed by Redis.
+func (q *LogQueue) client() *redis.Client {
+	if backend, ok := q.cache.Backend.(*RedisCacheBackend); ok {
+		return backend.Client()
+	}
+	return nil
+}
+
+func (q *LogQueue) Push(ctx context.Context, value string) error {
+	if q.client() == nil {
+		return q.cache.Backend.RightPush(ctx, q.stream, value)
+	}
+	return q.client().XAdd(
+		ctx, &redis.XAddArgs{Stream: q.stream, Values: map[string]any{logQueueValueField: value}},
+	).Err()
+}
+
+// Consume hands the queued entries to handle in batches until the queue is drained. handle returns the error of every
+// entry it could not store. Entries without an error are removed; entries failing with ErrLogQueueEntryInvalid or
+// having been delivered too often are dead-lettered; the others stay pending and are retried by the next Consume.
+// Consume stops early when a whole batch failed, as the store is most likely unavailable.
+func (q *LogQueue) Consume(
+	ctx context.Context, handle func(ctx context.Context, entries []LogQueueEntry) map[string]error,
+) error {
+	cfg := q.cache.Config.LogQueueConfig
+	if q.client() == nil {
+		return q.consumeList(ctx, cfg.BatchSize, handle)
+	}
+	if err := q.init(ctx); err != nil {
+		return err
+	}
+
+	// Retry the entries a consumer read but did not store in time, starting with the ones of dead consumers.
+	retries, err := q.claim(ctx, cfg.ClaimIdle, cfg.MaxDeliveries, cfg.BatchSize)
+	if err != nil {
+		return err
+	}
+	for len(retries) > 0 {
+		if !q.process(ctx, retries, handle) {
+			return nil
+		}
+		if retries, err = q.claim(ctx, cfg.ClaimIdle, cfg.MaxDeliveries, cfg.BatchSize); err != nil {
+			return err
+		}
+	}
+
+	for {
+		streams, err := q.client().XReadGroup(
+			ctx, &redis.XReadGroupArgs{
+				Group:    q.group(),
+				Consumer: q.consumer,
+				Streams:  []string{q.stream, ">"},
+				Count:    cfg.BatchSize,
+				Block:    -1,
+			},
+		).Result()
+		if errors.Is(err, redis.Nil) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(streams) == 0 || len(streams[0].Messages) == 0 {
+			return nil
+		}
+		if !q.process(ctx, toLogQueueEntries(streams[0].Messages), handle) {
+			return nil
+		}
+	}
+}
+
+// Stats returns the entries of the queue that have not been stored yet.
+func (q *LogQueue) Stats(ctx context.Context) (*LogQueueStats, error) {
+	client := q.client()
+	if client == nil {
+		return q.listStats(ctx)
+	}
+	stats := &LogQueueStats{}
+	var err error
+	if stats.Backlog, err = client.XLen(ctx, q.stream).Result(); err != nil {
+		return nil, err
+	}
+	if stats.DeadLetters, err = client.XLen(ctx, q.deadLetter).Result(); err != nil {
+		return nil, err
+	}
+	pending, err := client.XPending(ctx, q.stream, q.group()).Result()
+	if err == nil {
+		stats.Pending = pending.Count
+	} else if !errors.Is(err, redis.Nil) && !strings.HasPrefix(err.Error(), "NOGROUP") {
+		return nil, err
+	}
+	// Stored entries are deleted from the stream, so its first entry is the oldest one not stored yet.
+	oldest, err := client.XRangeN(ctx, q.stream, "-", "+", 1).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(oldest) > 0 {
+		millis, _, _ := strings.Cut(oldest[0].ID, "-")
+		if ms, err := strconv.ParseInt(millis, 10, 64); err == nil {
+			stats.Lag = time.Since(time.UnixMilli(ms))
+		}
+	}
+	return stats, nil
+}
+
+func (q *LogQueue) group() string {
+	return config.LogStreamConsumerGroup
+}
+
+// init creates the consumer group and moves the entries left in the legacy streams and list to the streams.
+func (q *LogQueue) init(ctx context.Context) error {
+	client := q.client()
+	err := client.XGroupCreateMkStream(ctx, q.stream, q.group(), "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+	if err := q.migrate(ctx, q.legacyStream, q.stream); err != nil {
+		return err
+	}
+	if err := q.migrate(ctx, q.legacyDeadLetter, q.deadLetter); err != nil {
+		return err
+	}
+	if q.legacyList == "" {
+		return nil
+	}
+	for {
+		value, err := q.cache.Backend.LeftPop(ctx, q.legacyList)
+		if errors.Is(err, q.cache.Nil) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := q.Push(ctx, value); err != nil {
+			// Put it back rather than losing it.
+			_ = client.LPush(ctx, q.legacyList, value).Err()
+			return err
+		}
+	}
+}
+
+// migrate moves the entries of the stream from to the stream to, and deletes from once it is empty, consumer group
+// included. Instances not upgraded yet keep adding to from, so it is migrated on every Consume. The moved entries get
+// new IDs, so an entry a consumer of from was storing meanwhile may be stored twice.
+func (q *LogQueue) migrate(ctx context.Context, from, to string) error {
+	if from == to {
+		return nil
+	}
+	client := q.client()
+	for {
+		messages, err := client.XRangeN(ctx, from, "-", "+", q.cache.Config.LogQueueConfig.BatchSize).Result()
+		if err != nil {
+			return err
+		}
+		if len(messages) == 0 {
+			return client.Del(ctx, from).Err()
+		}
+		ids := make([]string, 0, len(messages))
+		for _, message := range messages {
+			if err := client.XAdd(ctx, &redis.XAddArgs{Stream: to, Values: message.Values}).Err(); err != nil {
+				return err
+			}
+			ids = append(ids, message.ID)
+		}
+		if err := client.XDel(ctx, from, ids...).Err(); err != nil {
+			return err
+		}
+	}
+}
+
+// claim takes over the pending entries idle for longer than minIdle. Entries delivered maxDeliveries times already are
+// dead-lettered instead.
+func (q *LogQueue) claim(ctx context.Context, minIdle time.Duration, maxDeliveries, count int64) (
+	[]LogQueueEntry, error,
+) {
+	client := q.client()
+	pending, err := client.XPendingExt(
+		ctx, &redis.XPendingExtArgs{
+			Stream: q.stream, Group: q.group(), Idle: minIdle, Start: "-", End: "+", Count: count,
+		},
+	).Result()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(pending))
+	exhausted := make(map[string]bool)
+	for _, entry := range pending {
+		ids = append(ids, entry.ID)
+		if entry.RetryCount >= maxDeliveries {
+			exhausted[entry.ID] = true
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	messages, err := client.XClaim(
+		ctx, &redis.XClaimArgs{
+			Stream: q.stream, Group: q.group(), Consumer: q.consumer, MinIdle: minIdle, Messages: ids,
+		},
+	).Result()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]LogQueueEntry, 0, len(messages))
+	for _, entry := range toLogQueueEntries(messages) {
+		if exhausted[entry.ID] {
+			if err := q.bury(ctx, entry, fmt.Errorf("delivered %d times", maxDeliveries)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// process hands a batch to handle and settles its entries. It reports whether any entry of the batch was settled.
+func (q *LogQueue) process(
+	ctx context.Context, entries []LogQueueEntry,
+	handle func(ctx context.Context, entries []LogQueueEntry) map[string]error,
+) bool {
+	failures := handle(ctx, entries)
+	done := make([]string, 0, len(entries))
+	settled := false
+	for _, entry := range entries {
+		err, failed := failures[entry.ID]
+		switch {
+		case !failed || err == nil:
+			done = append(done, entry.ID)
+		case errors.Is(err, ErrLogQueueEntryInvalid):
+			if q.bury(ctx, entry, err) == nil {
+				settled = true
+			}
+		}
+	}
+	if len(done) > 0 && q.remove(ctx, done...) == nil {
+		settled = true
+	}
+	return settled
+}
+
+// bury moves an entry to the dead-letter stream along with the reason it could not be stored.
+func (q *LogQueue) bury(ctx context.Context, entry LogQueueEntry, reason error) error {
+	err := q.client().XAdd(
+		ctx, &redis.XAddArgs{
+			Stream: q.deadLetter,
+			Values: map[string]any{"id": entry.ID, logQueueValueField: entry.Value, "error": reason.Error()},
+		},
+	).Err()
+	if err != nil {
+		return err
+	}
+	return q.remove(ctx, entry.ID)
+}
+
+func (q *LogQueue) remove(ctx context.Context, ids ...string) error {
+	client := q.client()
+	if err := client.XAck(ctx, q.stream, q.group(), ids...).Err(); err != nil {
+		return err
+	}
+	return client.XDel(ctx, q.stream, ids...).Err()
+}
+
+// consumeList is Consume for a list queue. Entries failing with ErrLogQueueEntryInvalid are dead-lettered, the other
+// failed ones are put back in front of the list.
+func (q *LogQueue) consumeList(
+	ctx context.Context, batchSize int64,
+	handle func(ctx context.Context, entries []LogQueueEntry) map[string]error,
+) error {
+	for {
+		entries := make([]LogQueueEntry, 0, batchSize)
+		for int64(len(entries)) < batchSize {
+			value, err := q.cache.Backend.LeftPop(ctx, q.stream)
+			if errors.Is(err, q.cache.Nil) {
+				break
+			} else if err != nil {
+				return err
+			}
+			// Entry IDs look like stream entry IDs, so that ObjectID works alike
+			id := fmt.Sprintf("%d-%d", time.Now().UnixMilli(), q.sequence.Add(1))
+			entries = append(entries, LogQueueEntry{ID: id, Value: value})
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		failures := handle(ctx, entries)
+		settled := false
+		retries := make([]LogQueueEntry, 0)
+		for _, entry := range entries {
+			err, failed := failures[entry.ID]
+			switch {
+			case !failed || err == nil:
+				settled = true
+			case errors.Is(err, ErrLogQueueEntryInvalid):
+				deadLetter, _ := json.Marshal(
+					map[string]string{"id": entry.ID, logQueueValueField: entry.Value, "error": err.Error()},
+				)
+				if err := q.cache.Backend.RightPush(ctx, q.deadLetter, string(deadLetter)); err != nil {
+					return err
+				}
+				settled = true
+			default:
+				retries = append(retries, entry)
+			}
+		}
+		for i := len(retries) - 1; i >= 0; i-- {
+			if err := q.cache.Backend.LeftPush(ctx, q.stream, retries[i].Value); err != nil {
+				return err
+			}
+		}
+		if !settled {
+			return nil
+		}
+	}
+}
+
+// listStats is Stats for a list queue. Entries are not read before they are stored, so none is pending, and their
+// age is not known.
+func (q *LogQueue) listStats(ctx context.Context) (*LogQueueStats, error) {
+	stats := &LogQueueStats{}
+	var err error
+	if stats.Backlog, err = q.cache.Backend.Len(ctx, q.stream); err != nil {
+		return nil, err
+	}
+	if stats.DeadLetters, err = q.cache.Backend.Len(ctx, q.deadLetter); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func toLogQueueEntries(messages []redis.XMessage) []LogQueueEntry {
+	entries := make([]LogQueueEntry, 0, len(messages))
+	for _, message := range messages {
+		value, _ := message.Values[logQueueValueField].(string)
+		entries = append(entries, LogQueueEntry{ID: message.ID, Value: value})
+	}
+	return entries
+}
+
+// InsertManyFailures maps the error of an unordered InsertMany of the given entries, in insertion order, to the
+// entries that were not inserted. Entries rejected as duplicates were stored by an earlier delivery and count as
+// inserted.
+func InsertManyFailures(entries []LogQueueEntry, err error) map[string]error {
+	failures := make(map[string]error)
+	if err == nil {
+		return failures
+	}
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil {
+		for _, entry := range entries {
+			failures[entry.ID] = err
+		}
+		return failures
+	}
+	for _, writeErr := range bulkErr.WriteErrors {
+		if writeErr.Index < 0 || writeErr.Index >= len(entries) || mongo.IsDuplicateKeyError(writeErr.WriteError) {
+			continue
+		}
+		failures[entries[writeErr.Index].ID] = writeErr
+	}
+	return failures
+}
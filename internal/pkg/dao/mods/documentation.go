@@ -10,6 +10,7 @@ import (
 	"fiber-admin/internal/pkg/dao"
 	"fiber-admin/internal/pkg/domain/entity"
 	"github.com/goccy/go-json"
+	"github.com/qiniu/qmgo"
 	"github.com/qiniu/qmgo/options"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -20,13 +21,28 @@ import (
 // DocumentationDao defines the crud methods that the infrastructure layer should implement
 type DocumentationDao interface {
 	GetDocumentationByID(ctx context.Context, documentationID primitive.ObjectID) (*entity.DocumentationModel, error)
+	GetDocumentationBySlug(ctx context.Context, slug string) (*entity.DocumentationModel, error)
 	GetDocumentationList(
 		ctx context.Context,
 		offset, limit int64, desc bool, createStartTime, createEndTime, updateStartTime, updateEndTime *time.Time,
+		category, tag *string,
 	) ([]entity.DocumentationModel, *int64, error)
-	InsertDocumentation(ctx context.Context, title, content string) (primitive.ObjectID, error)
-	UpdateDocumentation(ctx context.Context, documentationID primitive.ObjectID, title, content *string) error
-	DeleteDocumentation(ctx context.Context, documentationID primitive.ObjectID) error
+	GetDocumentationOutline(ctx context.Context) ([]entity.DocumentationModel, error)
+	SearchDocumentation(
+		ctx context.Context, query string, offset, limit int64,
+	) ([]entity.DocumentationSearchResult, *int64, error)
+	InsertDocumentation(
+		ctx context.Context, title, content, slug string, parentID *primitive.ObjectID, position int64,
+		category string, tags []string,
+	) (primitive.ObjectID, error)
+	UpdateDocumentation(
+		ctx context.Context, documentationID primitive.ObjectID, version *int64, title, content, slug, category *string,
+		tags []string,
+	) (*entity.DocumentationModel, error)
+	UpdateDocumentationPosition(
+		ctx context.Context, parentID, movedID *primitive.ObjectID, documentationIDList []primitive.ObjectID,
+	) error
+	DeleteDocumentation(ctx context.Context, documentationID primitive.ObjectID, version *int64) error
 	DeleteDocumentationList(
 		ctx context.Context, createStartTime, createEndTime, updateStartTime, updateEndTime *time.Time,
 	) (*int64, error)
@@ -41,13 +57,24 @@ type DocumentationDaoImpl struct {
 // NewDocumentationDao creates a new instance of DocumentationDaoImpl with the qmgo.Collection instance
 func NewDocumentationDao(ctx context.Context, core *dao.Core, cache *dao.Cache) (DocumentationDao, error) {
 	var _ DocumentationDao = (*DocumentationDaoImpl)(nil) // Ensure that the interface is implemented
-	coll := core.Mongo.MongoClient.Database(core.Mongo.DatabaseName).Collection(config.DocumentationCollectionName)
+	coll := core.Mongo.Client().Database(core.Mongo.DatabaseName).Collection(config.DocumentationCollectionName)
+	if err := dropUniqueTitleIndex(ctx, coll); err != nil {
+		core.Logger.Error(
+			fmt.Sprintf("Failed to drop unique title index for %s", config.DocumentationCollectionName), zap.Error(err),
+		)
+		return nil, err
+	}
 	if err := coll.CreateIndexes(
 		ctx, []options.IndexModel{
 			{
-				Key:          []string{"title"},
-				IndexOptions: opt.Index().SetUnique(true),
+				// Documents created before slugs were introduced have none, so only string slugs must be unique.
+				Key: []string{"slug"},
+				IndexOptions: opt.Index().SetUnique(true).SetPartialFilterExpression(
+					bson.M{"slug": bson.M{"$type": "string"}},
+				),
 			},
+			{Key: []string{"parent_id", "position"}},
+			{Key: []string{"title"}}, {Key: []string{"category"}}, {Key: []string{"tags"}},
 			{Key: []string{"created_at"}}, {Key: []string{"updated_at"}},
 		},
 	); err != nil {
@@ -56,73 +83,93 @@ func NewDocumentationDao(ctx context.Context, core *dao.Core, cache *dao.Cache)
 		)
 		return nil, err
 	}
+	if err := createTextIndex(ctx, coll, core.Config.SearchConfig.DefaultLanguage); err != nil {
+		core.Logger.Error(
+			fmt.Sprintf("Failed to create text index for %s", config.DocumentationCollectionName), zap.Error(err),
+		)
+		return nil, err
+	}
 	return &DocumentationDaoImpl{core, cache}, nil
 }
 
+// dropUniqueTitleIndex drops the unique title index of earlier versions, documents in different sections may share a
+// title now that they are identified by their slug.
+func dropUniqueTitleIndex(ctx context.Context, coll *qmgo.Collection) error {
+	collection, err := coll.CloneCollection()
+	if err != nil {
+		return err
+	}
+	cursor, err := collection.Indexes().List(ctx)
+	if err != nil {
+		return err
+	}
+	var indexes []bson.M
+	if err = cursor.All(ctx, &indexes); err != nil {
+		return err
+	}
+	for _, index := range indexes {
+		if index["name"] == "title_1" && index["unique"] == true {
+			_, err = collection.Indexes().DropOne(ctx, "title_1")
+			return err
+		}
+	}
+	return nil
+}
+
 func (d *DocumentationDaoImpl) GetDocumentationByID(
 	ctx context.Context, documentationID primitive.ObjectID,
 ) (*entity.DocumentationModel, error) {
-	var documentation entity.DocumentationModel
-	key := fmt.Sprintf("%s:documentationID:%s", config.DocumentationCachePrefix, documentationID.Hex())
-	cache, err := d.Cache.Get(ctx, key)
-	if errors.Is(err, dao.CacheNil{}) {
-		d.Dao.Logger.Info("DocumentationDaoImpl.GetDocumentationByID: cache miss", zap.String("key", key))
-	} else if err != nil {
-		d.Dao.Logger.Error(
-			"DocumentationDaoImpl.GetDocumentationByID: failed to get cache",
-			zap.Error(err), zap.String("key", key),
-		)
-	} else {
-		err = json.Unmarshal([]byte(*cache), &documentation)
-		if err != nil {
-			d.Dao.Logger.Error(
-				"DocumentationDaoImpl.GetDocumentationByID: failed to unmarshal cache",
-				zap.Error(err), zap.String("key", key),
-			)
-		} else {
-			d.Dao.Logger.Info(
-				"DocumentationDaoImpl.GetDocumentationByID: cache hit",
+	key := d.Cache.Key(ctx, config.DocumentationCachePrefix, "documentationID:%s", documentationID.Hex())
+	return dao.GetOrLoad(
+		ctx, d.Cache, d.Dao.Logger, config.CacheEntityDocumentation, key,
+		d.Dao.Config.CacheConfig.DocumentationCacheTTL,
+		func(ctx context.Context) (*entity.DocumentationModel, error) {
+			return d.findDocumentation(
+				ctx, "DocumentationDaoImpl.GetDocumentationByID", bson.M{"_id": documentationID},
 				zap.String("documentationID", documentationID.Hex()),
 			)
-			return &documentation, nil
-		}
-	}
-	coll := d.Dao.Mongo.MongoClient.Database(d.Dao.Mongo.DatabaseName).Collection(config.DocumentationCollectionName)
-	if err = coll.Find(ctx, bson.M{"_id": documentationID}).One(&documentation); err != nil {
-		d.Dao.Logger.Error(
-			"DocumentationDaoImpl.GetDocumentationByID: failed to find documentation",
-			zap.Error(err), zap.String("documentationID", documentationID.Hex()),
-		)
-		return nil, err
-	} else {
-		docJSON, _ := json.Marshal(documentation)
-		if err = d.Cache.Set(ctx, key, string(docJSON), &d.Dao.Config.CacheConfig.DocumentationCacheTTL); err != nil {
-			d.Dao.Logger.Error(
-				"DocumentationDaoImpl.GetDocumentationByID: failed to set cache",
-				zap.Error(err), zap.String("key", key),
-			)
-		} else {
-			d.Dao.Logger.Info(
-				"DocumentationDaoImpl.GetDocumentationByID: cache set",
-				zap.String("key", key), zap.ByteString(config.DocumentationCollectionName, docJSON),
+		},
+	)
+}
+
+func (d *DocumentationDaoImpl) GetDocumentationBySlug(
+	ctx context.Context, slug string,
+) (*entity.DocumentationModel, error) {
+	key := d.Cache.Key(ctx, config.DocumentationCachePrefix, "slug:%s", slug)
+	return dao.GetOrLoad(
+		ctx, d.Cache, d.Dao.Logger, config.CacheEntityDocumentation, key,
+		d.Dao.Config.CacheConfig.DocumentationCacheTTL,
+		func(ctx context.Context) (*entity.DocumentationModel, error) {
+			return d.findDocumentation(
+				ctx, "DocumentationDaoImpl.GetDocumentationBySlug", bson.M{"slug": slug}, zap.String("slug", slug),
 			)
-		}
-		d.Dao.Logger.Info(
-			"DocumentationDaoImpl.GetDocumentationByID: success",
-			zap.String("documentationID", documentationID.Hex()),
-		)
-		return &documentation, nil
+		},
+	)
+}
+
+// findDocumentation finds the documentation matching filter, logging as method with field.
+func (d *DocumentationDaoImpl) findDocumentation(
+	ctx context.Context, method string, filter bson.M, field zap.Field,
+) (*entity.DocumentationModel, error) {
+	var documentation entity.DocumentationModel
+	coll := d.Dao.Mongo.Client().Database(d.Dao.Mongo.DatabaseName).Collection(config.DocumentationCollectionName)
+	if err := coll.Find(ctx, filter).One(&documentation); err != nil {
+		d.Dao.Logger.Error(method+": failed to find documentation", zap.Error(err), field)
+		return nil, err
 	}
+	d.Dao.Logger.Info(method+": success", field)
+	return &documentation, nil
 }
 
 func (d *DocumentationDaoImpl) GetDocumentationList(
 	ctx context.Context,
 	offset, limit int64, desc bool, createStartTime, createEndTime, updateStartTime, updateEndTime *time.Time,
+	category, tag *string,
 ) ([]entity.DocumentationModel, *int64, error) {
 	var documentationList []entity.DocumentationModel
 	var err error
 	doc := bson.M{}
-	key := fmt.Sprintf("%s:offset:%d:limit:%d", config.DocumentationCachePrefix, offset, limit)
+	key := d.Cache.Key(ctx, config.DocumentationCachePrefix, "offset:%d:limit:%d", offset, limit)
 	if createStartTime != nil && createEndTime != nil {
 		doc["created_at"] = bson.M{"$gte": createStartTime, "$lte": createEndTime}
 		key += fmt.Sprintf(":createStartTime:%s:createEndTime:%s", createStartTime, createEndTime)
@@ -131,6 +178,14 @@ func (d *DocumentationDaoImpl) GetDocumentationList(
 		doc["updated_at"] = bson.M{"$gte": updateStartTime, "$lte": updateEndTime}
 		key += fmt.Sprintf(":updateStartTime:%s:updateEndTime:%s", updateStartTime, updateEndTime)
 	}
+	if category != nil {
+		doc["category"] = *category
+		key += fmt.Sprintf(":category:%s", *category)
+	}
+	if tag != nil {
+		doc["tags"] = *tag
+		key += fmt.Sprintf(":tag:%s", *tag)
+	}
 	docJSON, _ := json.Marshal(doc)
 
 	if desc {
@@ -160,14 +215,16 @@ func (d *DocumentationDaoImpl) GetDocumentationList(
 			}
 			documentationList = append(
 				documentationList, entity.DocumentationModel{
-					DocumentID: documentationID, Title: documentation.Title, Content: documentation.Content,
-					CreatedAt: documentation.CreatedAt, UpdatedAt: documentation.UpdatedAt,
+					DocumentID: documentationID, ParentID: parentIDFromHex(documentation.ParentID),
+					Position: documentation.Position, Slug: documentation.Slug, Title: documentation.Title,
+					Content: documentation.Content, Category: documentation.Category, Tags: documentation.Tags,
+					CreatedAt: documentation.CreatedAt, UpdatedAt: documentation.UpdatedAt, Version: documentation.Version,
 				},
 			)
 		}
 		return documentationList, &cache.Total, nil
 	}
-	coll := d.Dao.Mongo.MongoClient.Database(d.Dao.Mongo.DatabaseName).Collection(config.DocumentationCollectionName)
+	coll := d.Dao.Mongo.Client().Database(d.Dao.Mongo.DatabaseName).Collection(config.DocumentationCollectionName)
 	if desc {
 		err = coll.Find(ctx, doc).Sort("-created_at").Skip(offset).Limit(limit).All(&documentationList)
 	} else {
@@ -193,8 +250,10 @@ func (d *DocumentationDaoImpl) GetDocumentationList(
 	for _, documentation := range documentationList {
 		documentationCacheList = append(
 			documentationCacheList, entity.DocumentationCache{
-				DocumentID: documentation.DocumentID.Hex(), Title: documentation.Title, Content: documentation.Content,
-				CreatedAt: documentation.CreatedAt, UpdatedAt: documentation.UpdatedAt,
+				DocumentID: documentation.DocumentID.Hex(), ParentID: parentIDHex(documentation.ParentID),
+				Position: documentation.Position, Slug: documentation.Slug, Title: documentation.Title,
+				Content: documentation.Content, Category: documentation.Category, Tags: documentation.Tags,
+				CreatedAt: documentation.CreatedAt, UpdatedAt: documentation.UpdatedAt, Version: documentation.Version,
 			},
 		)
 	}
@@ -215,12 +274,93 @@ func (d *DocumentationDaoImpl) GetDocumentationList(
 	return documentationList, &count, nil
 }
 
+// GetDocumentationOutline returns every documentation without its content, ordered by position, for building the
+// documentation tree.
+func (d *DocumentationDaoImpl) GetDocumentationOutline(ctx context.Context) ([]entity.DocumentationModel, error) {
+	var documentationList []entity.DocumentationModel
+	key := d.Cache.Key(ctx, config.DocumentationCachePrefix, "outline")
+	cache, err := d.Cache.Get(ctx, key)
+	if errors.Is(err, dao.CacheNil{}) {
+		d.Dao.Logger.Info("DocumentationDaoImpl.GetDocumentationOutline: cache miss", zap.String("key", key))
+	} else if err != nil {
+		d.Dao.Logger.Error(
+			"DocumentationDaoImpl.GetDocumentationOutline: failed to get cache",
+			zap.Error(err), zap.String("key", key),
+		)
+	} else {
+		if err = json.Unmarshal([]byte(*cache), &documentationList); err != nil {
+			d.Dao.Logger.Error(
+				"DocumentationDaoImpl.GetDocumentationOutline: failed to unmarshal cache",
+				zap.Error(err), zap.String("key", key),
+			)
+		} else {
+			d.Dao.Logger.Info(
+				"DocumentationDaoImpl.GetDocumentationOutline: cache hit",
+				zap.String("key", key), zap.Int("count", len(documentationList)),
+			)
+			return documentationList, nil
+		}
+	}
+	coll := d.Dao.Mongo.Client().Database(d.Dao.Mongo.DatabaseName).Collection(config.DocumentationCollectionName)
+	err = coll.Find(ctx, bson.M{}).Select(bson.M{"content": 0}).Sort("position", "_id").All(&documentationList)
+	if err != nil {
+		d.Dao.Logger.Error("DocumentationDaoImpl.GetDocumentationOutline: failed to find documents", zap.Error(err))
+		return nil, err
+	}
+	docJSON, _ := json.Marshal(documentationList)
+	if err = d.Cache.Set(ctx, key, string(docJSON), &d.Dao.Config.CacheConfig.DocumentationCacheTTL); err != nil {
+		d.Dao.Logger.Error(
+			"DocumentationDaoImpl.GetDocumentationOutline: failed to set cache",
+			zap.Error(err), zap.String("key", key),
+		)
+	} else {
+		d.Dao.Logger.Info("DocumentationDaoImpl.GetDocumentationOutline: cache set", zap.String("key", key))
+	}
+	d.Dao.Logger.Info(
+		"DocumentationDaoImpl.GetDocumentationOutline: success", zap.Int("count", len(documentationList)),
+	)
+	return documentationList, nil
+}
+
+func (d *DocumentationDaoImpl) SearchDocumentation(
+	ctx context.Context, query string, offset, limit int64,
+) ([]entity.DocumentationSearchResult, *int64, error) {
+	var result []textSearchResult[entity.DocumentationSearchResult]
+	coll := d.Dao.Mongo.Client().Database(d.Dao.Mongo.DatabaseName).Collection(config.DocumentationCollectionName)
+	err := coll.Aggregate(ctx, textSearchPipeline(query, offset, limit)).All(&result)
+	if err != nil {
+		d.Dao.Logger.Error(
+			"DocumentationDaoImpl.SearchDocumentation: failed to search documents",
+			zap.Error(err), zap.String("query", query),
+		)
+		return nil, nil, err
+	}
+	var (
+		documentationList []entity.DocumentationSearchResult
+		count             int64
+	)
+	if len(result) > 0 {
+		documentationList, count = result[0].List, result[0].count()
+	}
+	d.Dao.Logger.Info(
+		"DocumentationDaoImpl.SearchDocumentation: success",
+		zap.String("query", query), zap.Int64("count", count),
+	)
+	return documentationList, &count, nil
+}
+
 func (d *DocumentationDaoImpl) InsertDocumentation(
-	ctx context.Context, title, content string,
+	ctx context.Context, title, content, slug string, parentID *primitive.ObjectID, position int64,
+	category string, tags []string,
 ) (primitive.ObjectID, error) {
-	coll := d.Dao.Mongo.MongoClient.Database(d.Dao.Mongo.DatabaseName).Collection(config.DocumentationCollectionName)
+	coll := d.Dao.Mongo.Client().Database(d.Dao.Mongo.DatabaseName).Collection(config.DocumentationCollectionName)
+	if tags == nil {
+		tags = []string{}
+	}
 	doc := bson.M{
-		"title": title, "content": content, "created_at": time.Now(), "updated_at": time.Now(),
+		"title": title, "content": content, "slug": slug, "parent_id": parentID, "position": position,
+		"category": category, "tags": tags, "created_at": time.Now(), "updated_at": time.Now(),
+		"version": int64(1),
 	}
 	docJSON, _ := json.Marshal(doc)
 	result, err := coll.InsertOne(ctx, doc)
@@ -236,20 +376,22 @@ func (d *DocumentationDaoImpl) InsertDocumentation(
 		zap.String("documentation_id", result.InsertedID.(primitive.ObjectID).Hex()),
 		zap.ByteString(config.DocumentationCollectionName, docJSON),
 	)
-	prefix := config.DocumentationCachePrefix
-	err = d.Cache.Flush(ctx, &prefix)
+	err = d.Cache.Invalidate(ctx, config.DocumentationCachePrefix)
 	if err != nil {
-		d.Dao.Logger.Error("DocumentationDaoImpl.InsertDocumentation: failed to flush cache", zap.Error(err))
+		d.Dao.Logger.Error("DocumentationDaoImpl.InsertDocumentation: failed to invalidate cache", zap.Error(err))
 	} else {
-		d.Dao.Logger.Info("DocumentationDaoImpl.InsertDocumentation: cache flushed")
+		d.Dao.Logger.Info("DocumentationDaoImpl.InsertDocumentation: cache invalidated")
 	}
 	return result.InsertedID.(primitive.ObjectID), nil
 }
 
+// UpdateDocumentation updates the documentation, only if it still has the given version unless version is nil, and
+// returns it as updated.
 func (d *DocumentationDaoImpl) UpdateDocumentation(
-	ctx context.Context, documentationID primitive.ObjectID, title, content *string,
-) error {
-	coll := d.Dao.Mongo.MongoClient.Database(d.Dao.Mongo.DatabaseName).Collection(config.DocumentationCollectionName)
+	ctx context.Context, documentationID primitive.ObjectID, version *int64, title, content, slug, category *string,
+	tags []string,
+) (*entity.DocumentationModel, error) {
+	coll := d.Dao.Mongo.Client().Database(d.Dao.Mongo.DatabaseName).Collection(config.DocumentationCollectionName)
 	doc := bson.M{"updated_at": time.Now()}
 	if title != nil {
 		doc["title"] = *title
@@ -257,36 +399,90 @@ func (d *DocumentationDaoImpl) UpdateDocumentation(
 	if content != nil {
 		doc["content"] = *content
 	}
+	if slug != nil {
+		doc["slug"] = *slug
+	}
+	if category != nil {
+		doc["category"] = *category
+	}
+	if tags != nil {
+		doc["tags"] = tags
+	}
 	docJSON, _ := json.Marshal(doc)
-	err := coll.UpdateId(ctx, documentationID, bson.M{"$set": doc})
+	var documentation entity.DocumentationModel
+	err := coll.Find(ctx, versionFilter(documentationID, version)).Apply(
+		qmgo.Change{Update: versionUpdate(doc), ReturnNew: true}, &documentation,
+	)
+	err = versionConflict(ctx, coll, documentationID, version, err)
 	if err != nil {
 		d.Dao.Logger.Error(
 			"DocumentationDaoImpl.UpdateDocumentation: failed to update documentation",
 			zap.Error(err), zap.String("documentationID", documentationID.Hex()),
 			zap.ByteString(config.DocumentationCollectionName, docJSON),
 		)
-		return err
+		return nil, err
 	}
 	d.Dao.Logger.Info(
 		"DocumentationDaoImpl.UpdateDocumentation: success",
 		zap.String("documentationID", documentationID.Hex()),
 		zap.ByteString(config.DocumentationCollectionName, docJSON),
 	)
-	prefix := config.DocumentationCachePrefix
-	err = d.Cache.Flush(ctx, &prefix)
+	err = d.Cache.Invalidate(ctx, config.DocumentationCachePrefix)
 	if err != nil {
-		d.Dao.Logger.Error("DocumentationDaoImpl.UpdateDocumentation: failed to flush cache", zap.Error(err))
+		d.Dao.Logger.Error("DocumentationDaoImpl.UpdateDocumentation: failed to invalidate cache", zap.Error(err))
+	} else {
+		d.Dao.Logger.Info("DocumentationDaoImpl.UpdateDocumentation: cache invalidated")
+	}
+	return &documentation, nil
+}
+
+// UpdateDocumentationPosition numbers the positions of the documentation in documentationIDList, the children of
+// parentID, in list order, in a single bulk write. movedID, unless nil, is the one among them moved under parentID,
+// which alone gets a new version: renumbering its siblings does not edit them.
+func (d *DocumentationDaoImpl) UpdateDocumentationPosition(
+	ctx context.Context, parentID, movedID *primitive.ObjectID, documentationIDList []primitive.ObjectID,
+) error {
+	if len(documentationIDList) == 0 {
+		return nil
+	}
+	coll := d.Dao.Mongo.Client().Database(d.Dao.Mongo.DatabaseName).Collection(config.DocumentationCollectionName)
+	bulk := coll.Bulk().SetOrdered(false)
+	for position, documentationID := range documentationIDList {
+		if movedID != nil && documentationID == *movedID {
+			bulk.UpdateId(documentationID, versionUpdate(bson.M{"parent_id": parentID, "position": int64(position)}))
+		} else {
+			bulk.UpdateId(documentationID, bson.M{"$set": bson.M{"position": int64(position)}})
+		}
+	}
+	if _, err := bulk.Run(ctx); err != nil {
+		d.Dao.Logger.Error(
+			"DocumentationDaoImpl.UpdateDocumentationPosition: failed to update documentation",
+			zap.Error(err), zap.String("parentID", parentIDHex(parentID)),
+			zap.Int("count", len(documentationIDList)),
+		)
+		return err
+	}
+	d.Dao.Logger.Info(
+		"DocumentationDaoImpl.UpdateDocumentationPosition: success",
+		zap.String("parentID", parentIDHex(parentID)), zap.Int("count", len(documentationIDList)),
+	)
+	if err := d.Cache.Invalidate(ctx, config.DocumentationCachePrefix); err != nil {
+		d.Dao.Logger.Error(
+			"DocumentationDaoImpl.UpdateDocumentationPosition: failed to invalidate cache", zap.Error(err),
+		)
 	} else {
-		d.Dao.Logger.Info("DocumentationDaoImpl.UpdateDocumentation: cache flushed")
+		d.Dao.Logger.Info("DocumentationDaoImpl.UpdateDocumentationPosition: cache invalidated")
 	}
 	return nil
 }
 
+// DeleteDocumentation deletes the documentation, only if it still has the given version unless version is nil.
 func (d *DocumentationDaoImpl) DeleteDocumentation(
-	ctx context.Context, documentationID primitive.ObjectID,
+	ctx context.Context, documentationID primitive.ObjectID, version *int64,
 ) error {
-	coll := d.Dao.Mongo.MongoClient.Database(d.Dao.Mongo.DatabaseName).Collection(config.DocumentationCollectionName)
-	if err := coll.RemoveId(ctx, documentationID); err != nil {
+	coll := d.Dao.Mongo.Client().Database(d.Dao.Mongo.DatabaseName).Collection(config.DocumentationCollectionName)
+	err := coll.Remove(ctx, versionFilter(documentationID, version))
+	if err = versionConflict(ctx, coll, documentationID, version, err); err != nil {
 		d.Dao.Logger.Error(
 			"DocumentationDaoImpl.DeleteDocumentation: failed to delete documentation",
 			zap.Error(err), zap.String("documentationID", documentationID.Hex()),
@@ -297,11 +493,10 @@ func (d *DocumentationDaoImpl) DeleteDocumentation(
 		"DocumentationDaoImpl.DeleteDocumentation: success",
 		zap.String("documentationID", documentationID.Hex()),
 	)
-	prefix := config.DocumentationCachePrefix
-	if err := d.Cache.Flush(ctx, &prefix); err != nil {
-		d.Dao.Logger.Error("DocumentationDaoImpl.DeleteDocumentation: failed to flush cache", zap.Error(err))
+	if err := d.Cache.Invalidate(ctx, config.DocumentationCachePrefix); err != nil {
+		d.Dao.Logger.Error("DocumentationDaoImpl.DeleteDocumentation: failed to invalidate cache", zap.Error(err))
 	} else {
-		d.Dao.Logger.Info("DocumentationDaoImpl.DeleteDocumentation: cache flushed")
+		d.Dao.Logger.Info("DocumentationDaoImpl.DeleteDocumentation: cache invalidated")
 	}
 	return nil
 }
@@ -309,7 +504,7 @@ func (d *DocumentationDaoImpl) DeleteDocumentation(
 func (d *DocumentationDaoImpl) DeleteDocumentationList(
 	ctx context.Context, createStartTime, createEndTime, updateStartTime, updateEndTime *time.Time,
 ) (*int64, error) {
-	coll := d.Dao.Mongo.MongoClient.Database(d.Dao.Mongo.DatabaseName).Collection(config.DocumentationCollectionName)
+	coll := d.Dao.Mongo.Client().Database(d.Dao.Mongo.DatabaseName).Collection(config.DocumentationCollectionName)
 	doc := bson.M{}
 	if createStartTime != nil && createEndTime != nil {
 		doc["created_at"] = bson.M{"$gte": createStartTime, "$lte": createEndTime}
@@ -330,11 +525,25 @@ func (d *DocumentationDaoImpl) DeleteDocumentationList(
 		"DocumentationDaoImpl.DeleteDocumentationList: success",
 		zap.Int64("count", result.DeletedCount), zap.ByteString(config.DocumentationCollectionName, docJSON),
 	)
-	prefix := config.DocumentationCachePrefix
-	if err = d.Cache.Flush(ctx, &prefix); err != nil {
-		d.Dao.Logger.Error("DocumentationDaoImpl.DeleteDocumentationList: failed to flush cache", zap.Error(err))
+	if err = d.Cache.Invalidate(ctx, config.DocumentationCachePrefix); err != nil {
+		d.Dao.Logger.Error("DocumentationDaoImpl.DeleteDocumentationList: failed to invalidate cache", zap.Error(err))
 	} else {
-		d.Dao.Logger.Info("DocumentationDaoImpl.DeleteDocumentationList: cache flushed")
+		d.Dao.Logger.Info("DocumentationDaoImpl.DeleteDocumentationList: cache invalidated")
 	}
 	return &result.DeletedCount, nil
 }
+
+func parentIDHex(parentID *primitive.ObjectID) string {
+	if parentID == nil {
+		return ""
+	}
+	return parentID.Hex()
+}
+
+func parentIDFromHex(parentIDHex string) *primitive.ObjectID {
+	parentID, err := primitive.ObjectIDFromHex(parentIDHex)
+	if err != nil {
+		return nil
+	}
+	return &parentID
+}
@@ -0,0 +1,211 @@
+package mods
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"fiber-admin/internal/pkg/config"
+	"fiber-admin/internal/pkg/dao"
+	"fiber-admin/internal/pkg/domain/entity"
+	"github.com/goccy/go-json"
+	"github.com/qiniu/qmgo/options"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	opt "go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// DocumentationRevisionDao defines the crud methods for documentation revisions. Revisions are append-only snapshots
+// of a documentation, numbered by the version of the documentation they snapshot.
+type DocumentationRevisionDao interface {
+	GetDocumentationRevision(
+		ctx context.Context, documentationID primitive.ObjectID, revision int64,
+	) (*entity.DocumentationRevisionModel, error)
+	GetLatestDocumentationRevision(
+		ctx context.Context, documentationID primitive.ObjectID,
+	) (*entity.DocumentationRevisionModel, error)
+	GetDocumentationRevisionList(
+		ctx context.Context, documentationID primitive.ObjectID, offset, limit int64, desc bool,
+	) ([]entity.DocumentationRevisionModel, *int64, error)
+	InsertDocumentationRevision(
+		ctx context.Context, documentationID primitive.ObjectID, revision int64, authorID primitive.ObjectID,
+		title, content, summary string,
+	) error
+	DeleteDocumentationRevisionList(ctx context.Context, documentationID *primitive.ObjectID) (*int64, error)
+}
+
+// DocumentationRevisionDaoImpl implements the DocumentationRevisionDao interface
+type DocumentationRevisionDaoImpl struct {
+	core *dao.Core
+}
+
+// NewDocumentationRevisionDao creates a new instance of DocumentationRevisionDaoImpl and ensures the indexes
+func NewDocumentationRevisionDao(ctx context.Context, core *dao.Core) (DocumentationRevisionDao, error) {
+	var _ DocumentationRevisionDao = (*DocumentationRevisionDaoImpl)(nil) // Ensure that the interface is implemented
+	coll := core.Mongo.Client().Database(core.Mongo.DatabaseName).Collection(
+		config.DocumentationRevisionCollectionName,
+	)
+	if err := coll.CreateIndexes(
+		ctx, []options.IndexModel{
+			{
+				Key:          []string{"document_id", "revision"},
+				IndexOptions: opt.Index().SetUnique(true),
+			},
+			{Key: []string{"created_at"}},
+		},
+	); err != nil {
+		core.Logger.Error(
+			fmt.Sprintf("Failed to create indexes for %s", config.DocumentationRevisionCollectionName),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	return &DocumentationRevisionDaoImpl{core: core}, nil
+}
+
+func (d *DocumentationRevisionDaoImpl) GetDocumentationRevision(
+	ctx context.Context, documentationID primitive.ObjectID, revision int64,
+) (*entity.DocumentationRevisionModel, error) {
+	var documentationRevision entity.DocumentationRevisionModel
+	coll := d.core.Mongo.Client().Database(d.core.Mongo.DatabaseName).Collection(
+		config.DocumentationRevisionCollectionName,
+	)
+	err := coll.Find(ctx, bson.M{"document_id": documentationID, "revision": revision}).One(&documentationRevision)
+	if err != nil {
+		d.core.Logger.Error(
+			"DocumentationRevisionDaoImpl.GetDocumentationRevision: failed to find revision",
+			zap.Error(err), zap.String("documentationID", documentationID.Hex()), zap.Int64("revision", revision),
+		)
+		return nil, err
+	}
+	d.core.Logger.Info(
+		"DocumentationRevisionDaoImpl.GetDocumentationRevision: success",
+		zap.String("documentationID", documentationID.Hex()), zap.Int64("revision", revision),
+	)
+	return &documentationRevision, nil
+}
+
+func (d *DocumentationRevisionDaoImpl) GetLatestDocumentationRevision(
+	ctx context.Context, documentationID primitive.ObjectID,
+) (*entity.DocumentationRevisionModel, error) {
+	var documentationRevision entity.DocumentationRevisionModel
+	coll := d.core.Mongo.Client().Database(d.core.Mongo.DatabaseName).Collection(
+		config.DocumentationRevisionCollectionName,
+	)
+	err := coll.Find(ctx, bson.M{"document_id": documentationID}).Sort("-revision").One(&documentationRevision)
+	if err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			d.core.Logger.Error(
+				"DocumentationRevisionDaoImpl.GetLatestDocumentationRevision: failed to find revision",
+				zap.Error(err), zap.String("documentationID", documentationID.Hex()),
+			)
+		}
+		return nil, err
+	}
+	d.core.Logger.Info(
+		"DocumentationRevisionDaoImpl.GetLatestDocumentationRevision: success",
+		zap.String("documentationID", documentationID.Hex()), zap.Int64("revision", documentationRevision.Revision),
+	)
+	return &documentationRevision, nil
+}
+
+func (d *DocumentationRevisionDaoImpl) GetDocumentationRevisionList(
+	ctx context.Context, documentationID primitive.ObjectID, offset, limit int64, desc bool,
+) ([]entity.DocumentationRevisionModel, *int64, error) {
+	var (
+		documentationRevisionList []entity.DocumentationRevisionModel
+		err                       error
+	)
+	coll := d.core.Mongo.Client().Database(d.core.Mongo.DatabaseName).Collection(
+		config.DocumentationRevisionCollectionName,
+	)
+	doc := bson.M{"document_id": documentationID}
+	if desc {
+		err = coll.Find(ctx, doc).Sort("-revision").Skip(offset).Limit(limit).All(&documentationRevisionList)
+	} else {
+		err = coll.Find(ctx, doc).Sort("revision").Skip(offset).Limit(limit).All(&documentationRevisionList)
+	}
+	if err != nil {
+		d.core.Logger.Error(
+			"DocumentationRevisionDaoImpl.GetDocumentationRevisionList: failed to find revisions",
+			zap.Error(err), zap.String("documentationID", documentationID.Hex()),
+		)
+		return nil, nil, err
+	}
+	count, err := coll.Find(ctx, doc).Count()
+	if err != nil {
+		d.core.Logger.Error(
+			"DocumentationRevisionDaoImpl.GetDocumentationRevisionList: failed to count revisions",
+			zap.Error(err), zap.String("documentationID", documentationID.Hex()),
+		)
+		return nil, nil, err
+	}
+	d.core.Logger.Info(
+		"DocumentationRevisionDaoImpl.GetDocumentationRevisionList: success",
+		zap.String("documentationID", documentationID.Hex()), zap.Int64("count", count),
+	)
+	return documentationRevisionList, &count, nil
+}
+
+// InsertDocumentationRevision inserts the given revision of a documentation. The unique (document_id, revision) index
+// rejects a revision already taken.
+func (d *DocumentationRevisionDaoImpl) InsertDocumentationRevision(
+	ctx context.Context, documentationID primitive.ObjectID, revision int64, authorID primitive.ObjectID,
+	title, content, summary string,
+) error {
+	coll := d.core.Mongo.Client().Database(d.core.Mongo.DatabaseName).Collection(
+		config.DocumentationRevisionCollectionName,
+	)
+	doc := bson.M{
+		"document_id": documentationID,
+		"revision":    revision,
+		"title":       title,
+		"content":     content,
+		"author_id":   authorID,
+		"summary":     summary,
+		"created_at":  time.Now(),
+	}
+	docJSON, _ := json.Marshal(doc)
+	if _, err := coll.InsertOne(ctx, doc); err != nil {
+		d.core.Logger.Error(
+			"DocumentationRevisionDaoImpl.InsertDocumentationRevision: failed to insert revision",
+			zap.Error(err), zap.ByteString(config.DocumentationRevisionCollectionName, docJSON),
+		)
+		return err
+	}
+	d.core.Logger.Info(
+		"DocumentationRevisionDaoImpl.InsertDocumentationRevision: success",
+		zap.String("documentationID", documentationID.Hex()), zap.Int64("revision", revision),
+	)
+	return nil
+}
+
+func (d *DocumentationRevisionDaoImpl) DeleteDocumentationRevisionList(
+	ctx context.Context, documentationID *primitive.ObjectID,
+) (*int64, error) {
+	coll := d.core.Mongo.Client().Database(d.core.Mongo.DatabaseName).Collection(
+		config.DocumentationRevisionCollectionName,
+	)
+	doc := bson.M{}
+	if documentationID != nil {
+		doc["document_id"] = *documentationID
+	}
+	docJSON, _ := json.Marshal(doc)
+	result, err := coll.RemoveAll(ctx, doc)
+	if err != nil {
+		d.core.Logger.Error(
+			"DocumentationRevisionDaoImpl.DeleteDocumentationRevisionList: failed to delete revisions",
+			zap.Error(err), zap.ByteString(config.DocumentationRevisionCollectionName, docJSON),
+		)
+		return nil, err
+	}
+	d.core.Logger.Info(
+		"DocumentationRevisionDaoImpl.DeleteDocumentationRevisionList: success",
+		zap.Int64("count", result.DeletedCount),
+		zap.ByteString(config.DocumentationRevisionCollectionName, docJSON),
+	)
+	return &result.DeletedCount, nil
+}
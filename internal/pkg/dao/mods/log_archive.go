@@ -0,0 +1,32 @@
+package mods
+
+import (
+	"context"
+	"errors"
+
+	"github.com/qiniu/qmgo"
+	"github.com/qiniu/qmgo/options"
+	"go.mongodb.org/mongo-driver/mongo"
+	opt "go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// insertManyIgnoringDuplicates inserts the n documents of docs and returns how many were inserted. Documents whose ID
+// is already taken are skipped, so importing the same logs twice does not duplicate them.
+func insertManyIgnoringDuplicates(ctx context.Context, collection *qmgo.Collection, docs any, n int) (int64, error) {
+	_, err := collection.InsertMany(
+		ctx, docs, options.InsertManyOptions{InsertManyOptions: opt.InsertMany().SetOrdered(false)},
+	)
+	if err == nil {
+		return int64(n), nil
+	}
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil {
+		return 0, err
+	}
+	for _, writeErr := range bulkErr.WriteErrors {
+		if !mongo.IsDuplicateKeyError(writeErr.WriteError) {
+			return 0, err
+		}
+	}
+	return int64(n - len(bulkErr.WriteErrors)), nil
+}
@@ -9,11 +9,14 @@ import (
 	"fiber-admin/internal/pkg/config"
 	"fiber-admin/internal/pkg/dao"
 	"fiber-admin/internal/pkg/domain/entity"
+	"fiber-admin/pkg/geoip"
 	"fiber-admin/pkg/utils/common"
 	"github.com/goccy/go-json"
+	"github.com/qiniu/qmgo"
 	"github.com/qiniu/qmgo/options"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	opt "go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
 )
 
@@ -24,6 +27,11 @@ type LoginLogDao interface {
 		offset, limit int64, desc bool, startTime, endTime *time.Time, userID *primitive.ObjectID,
 		ipAddress, userAgent, query *string,
 	) ([]entity.LoginLogModel, *int64, error)
+	IterateLoginLogList(
+		ctx context.Context,
+		desc bool, startTime, endTime *time.Time, userID *primitive.ObjectID, ipAddress, userAgent, query *string,
+		fn func(loginLog *entity.LoginLogModel) error,
+	) error
 	InsertLoginLog(
 		ctx context.Context,
 		UserID primitive.ObjectID, IPAddress, UserAgent string,
@@ -32,24 +40,39 @@ type LoginLogDao interface {
 		ctx context.Context, userID primitive.ObjectID, IPAddress, UserAgent string,
 	) error
 	SyncLoginLog(ctx context.Context)
+	GetLoginLogQueueStats(ctx context.Context) (*dao.LogQueueStats, error)
 	DeleteLoginLog(ctx context.Context, LoginLogID primitive.ObjectID) error
 	DeleteLoginLogList(
 		ctx context.Context, startTime, endTime *time.Time, userID *primitive.ObjectID,
 		ipAddress, userAgent *string,
 	) (*int64, error)
+	IterateExpiredLoginLogList(
+		ctx context.Context, endTime time.Time, fn func(loginLog *entity.LoginLogModel) error,
+	) error
+	DeleteLoginLogByIDList(ctx context.Context, loginLogIDs []primitive.ObjectID) (*int64, error)
+	ImportLoginLogList(ctx context.Context, loginLogs []entity.LoginLogModel) (*int64, error)
+	GetUnnotifiedLoginLogList(ctx context.Context, since time.Time, limit int64) ([]entity.LoginLogModel, error)
+	SetLoginLogNotified(ctx context.Context, loginLogIDs []primitive.ObjectID) error
 }
 
 type LoginLogDaoImpl struct {
 	core    *dao.Core
 	cache   *dao.Cache
+	queue   *dao.LogQueue
 	userDao UserDao
+	geoIP   *geoip.Reader // Nil when no GeoIP database is configured
 }
 
-func NewLoginLogDao(ctx context.Context, core *dao.Core, cache *dao.Cache, userDao UserDao) (LoginLogDao, error) {
+func NewLoginLogDao(
+	ctx context.Context, core *dao.Core, cache *dao.Cache, userDao UserDao, geoIP *geoip.Reader,
+) (LoginLogDao, error) {
 	var _ LoginLogDao = (*LoginLogDaoImpl)(nil) // Ensure that the interface is implemented
-	coll := core.Mongo.MongoClient.Database(core.Mongo.DatabaseName).Collection(config.LoginLogCollectionName)
+	coll := core.Mongo.Client().Database(core.Mongo.DatabaseName).Collection(config.LoginLogCollectionName)
 	err := coll.CreateIndexes(
-		ctx, []options.IndexModel{{Key: []string{"created_at"}}, {Key: []string{"user_id"}}},
+		ctx, []options.IndexModel{
+			{Key: []string{"created_at"}}, {Key: []string{"user_id"}}, {Key: []string{"user_id", "-created_at"}},
+			{Key: []string{"flags"}},
+		},
 	)
 	if err != nil {
 		core.Logger.Error(
@@ -62,13 +85,17 @@ func NewLoginLogDao(ctx context.Context, core *dao.Core, cache *dao.Cache, userD
 		core:    core,
 		userDao: userDao,
 		cache:   cache,
+		geoIP:   geoIP,
+		queue: dao.NewLogQueue(
+			cache, config.LoginLogStreamKey, config.LoginLogDeadLetterKey, config.LegacyLoginLogCacheKey,
+		),
 	}, nil
 }
 
 func (l *LoginLogDaoImpl) GetLoginLogByID(
 	ctx context.Context, loginLogID primitive.ObjectID,
 ) (*entity.LoginLogModel, error) {
-	coll := l.core.Mongo.MongoClient.Database(l.core.Mongo.DatabaseName).Collection(config.LoginLogCollectionName)
+	coll := l.core.Mongo.Client().Database(l.core.Mongo.DatabaseName).Collection(config.LoginLogCollectionName)
 	var loginLog entity.LoginLogModel
 	err := coll.Find(ctx, bson.M{"_id": loginLogID}).One(&loginLog)
 	if err != nil {
@@ -88,9 +115,42 @@ func (l *LoginLogDaoImpl) GetLoginLogList(
 	offset, limit int64, desc bool, startTime, endTime *time.Time, userID *primitive.ObjectID,
 	ipAddress, userAgent, query *string,
 ) ([]entity.LoginLogModel, *int64, error) {
-	coll := l.core.Mongo.MongoClient.Database(l.core.Mongo.DatabaseName).Collection(config.LoginLogCollectionName)
+	coll := l.core.Mongo.Client().Database(l.core.Mongo.DatabaseName).Collection(config.LoginLogCollectionName)
 	var loginLogList []entity.LoginLogModel
 	var err error
+	doc := loginLogFilter(startTime, endTime, userID, ipAddress, userAgent, query)
+	docJSON, _ := json.Marshal(doc)
+	if desc {
+		err = coll.Find(ctx, doc).Sort("-created_at").Skip(offset).Limit(limit).All(&loginLogList)
+	} else {
+		err = coll.Find(ctx, doc).Skip(offset).Limit(limit).All(&loginLogList)
+	}
+	if err != nil {
+		l.core.Logger.Error(
+			"LoginLogDaoImpl.GetLoginLogList: failed to find login logs",
+			zap.Error(err), zap.ByteString(config.LoginLogCollectionName, docJSON),
+		)
+		return nil, nil, err
+	}
+	count, err := coll.Find(ctx, doc).Count()
+	if err != nil {
+		l.core.Logger.Error(
+			"LoginLogDaoImpl.GetLoginLogList: failed to count login logs",
+			zap.Error(err), zap.ByteString(config.LoginLogCollectionName, docJSON),
+		)
+		return nil, nil, err
+	}
+	l.core.Logger.Info(
+		"LoginLogDaoImpl.GetLoginLogList: success",
+		zap.Int64("count", count), zap.ByteString(config.LoginLogCollectionName, docJSON),
+	)
+	return loginLogList, &count, nil
+}
+
+// loginLogFilter returns the filter matching the login logs of GetLoginLogList
+func loginLogFilter(
+	startTime, endTime *time.Time, userID *primitive.ObjectID, ipAddress, userAgent, query *string,
+) bson.M {
 	doc := bson.M{}
 	if startTime != nil && endTime != nil {
 		doc["created_at"] = bson.M{"$gte": startTime, "$lte": endTime}
@@ -114,38 +174,48 @@ func (l *LoginLogDaoImpl) GetLoginLogList(
 			{"user_agent": bson.M{"$regex": primitive.Regex{Pattern: pattern, Options: "i"}}},
 		}
 	}
-	docJSON, _ := json.Marshal(doc)
+	return doc
+}
+
+// IterateLoginLogList calls fn with every login log matching the filters of GetLoginLogList, reading them from a
+// cursor instead of loading them at once
+func (l *LoginLogDaoImpl) IterateLoginLogList(
+	ctx context.Context,
+	desc bool, startTime, endTime *time.Time, userID *primitive.ObjectID, ipAddress, userAgent, query *string,
+	fn func(loginLog *entity.LoginLogModel) error,
+) error {
+	coll := l.core.Mongo.Client().Database(l.core.Mongo.DatabaseName).Collection(config.LoginLogCollectionName)
+	doc := loginLogFilter(startTime, endTime, userID, ipAddress, userAgent, query)
+	sort := "created_at"
 	if desc {
-		err = coll.Find(ctx, doc).Sort("-created_at").Skip(offset).Limit(limit).All(&loginLogList)
-	} else {
-		err = coll.Find(ctx, doc).Skip(offset).Limit(limit).All(&loginLogList)
+		sort = "-created_at"
 	}
-	if err != nil {
-		l.core.Logger.Error(
-			"LoginLogDaoImpl.GetLoginLogList: failed to find login logs",
-			zap.Error(err), zap.ByteString(config.LoginLogCollectionName, docJSON),
-		)
-		return nil, nil, err
+	cursor := coll.Find(ctx, doc).Sort(sort).Cursor()
+	defer func() {
+		_ = cursor.Close()
+	}()
+	var loginLog entity.LoginLogModel
+	for cursor.Next(&loginLog) {
+		if err := fn(&loginLog); err != nil {
+			return err
+		}
+		loginLog = entity.LoginLogModel{}
 	}
-	count, err := coll.Find(ctx, doc).Count()
-	if err != nil {
+	if err := cursor.Err(); err != nil {
+		docJSON, _ := json.Marshal(doc)
 		l.core.Logger.Error(
-			"LoginLogDaoImpl.GetLoginLogList: failed to count login logs",
+			"LoginLogDaoImpl.IterateLoginLogList: failed to iterate login logs",
 			zap.Error(err), zap.ByteString(config.LoginLogCollectionName, docJSON),
 		)
-		return nil, nil, err
+		return err
 	}
-	l.core.Logger.Info(
-		"LoginLogDaoImpl.GetLoginLogList: success",
-		zap.Int64("count", count), zap.ByteString(config.LoginLogCollectionName, docJSON),
-	)
-	return loginLogList, &count, nil
+	return nil
 }
 
 func (l *LoginLogDaoImpl) InsertLoginLog(
 	ctx context.Context, userID primitive.ObjectID, ipAddress, userAgent string,
 ) (primitive.ObjectID, error) {
-	coll := l.core.Mongo.MongoClient.Database(l.core.Mongo.DatabaseName).Collection(config.LoginLogCollectionName)
+	coll := l.core.Mongo.Client().Database(l.core.Mongo.DatabaseName).Collection(config.LoginLogCollectionName)
 	user, err := l.userDao.GetUserByID(ctx, userID)
 	if err != nil {
 		l.core.Logger.Error(
@@ -154,32 +224,34 @@ func (l *LoginLogDaoImpl) InsertLoginLog(
 		)
 		return primitive.NilObjectID, err
 	}
-	doc := bson.M{
-		"user_id":    userID,
-		"username":   user.Username,
-		"email":      user.Email,
-		"ip_address": ipAddress,
-		"user_agent": userAgent,
-		"created_at": time.Now(),
+	loginLog := &entity.LoginLogModel{
+		LoginLogID: primitive.NewObjectID(),
+		UserID:     userID,
+		Username:   user.Username,
+		Email:      user.Email,
+		IPAddress:  ipAddress,
+		UserAgent:  userAgent,
+		CreatedAt:  time.Now(),
 	}
-	docJSON, _ := json.Marshal(doc)
-	result, err := coll.InsertOne(ctx, doc)
+	l.analyzeLoginLogs(ctx, []*entity.LoginLogModel{loginLog})
+	docJSON, _ := json.Marshal(loginLog)
+	_, err = coll.InsertOne(ctx, loginLog)
 	if err != nil {
 		l.core.Logger.Error(
 			"LoginLogDaoImpl.InsertLoginLog: failed to insert login log",
 			zap.Error(err), zap.ByteString(config.LoginLogCollectionName, docJSON),
 		)
-	} else {
-		l.core.Logger.Info(
-			"LoginLogDaoImpl.InsertLoginLog: success",
-			zap.String("loginLogID", result.InsertedID.(primitive.ObjectID).Hex()),
-			zap.ByteString(config.LoginLogCollectionName, docJSON),
-		)
+		return primitive.NilObjectID, err
 	}
-	return result.InsertedID.(primitive.ObjectID), err
+	l.core.Logger.Info(
+		"LoginLogDaoImpl.InsertLoginLog: success",
+		zap.String("loginLogID", loginLog.LoginLogID.Hex()),
+		zap.ByteString(config.LoginLogCollectionName, docJSON),
+	)
+	return loginLog.LoginLogID, nil
 }
 
-// CacheLoginLog caches login logs in cache
+// CacheLoginLog queues login logs in cache
 func (l *LoginLogDaoImpl) CacheLoginLog(
 	ctx context.Context, userID primitive.ObjectID, IPAddress, UserAgent string,
 ) error {
@@ -194,51 +266,96 @@ func (l *LoginLogDaoImpl) CacheLoginLog(
 		l.core.Logger.Error("LoginLogDaoImpl.CacheLoginLog: failed to marshal login log", zap.Error(err))
 		return err
 	}
-	return l.cache.RightPush(ctx, config.LoginLogCacheKey, string(loginLogJSON))
+	return l.queue.Push(ctx, string(loginLogJSON))
 }
 
-// SyncLoginLog syncs login logs from cache to database
+// SyncLoginLog syncs the queued login logs from cache to database in batches
 func (l *LoginLogDaoImpl) SyncLoginLog(ctx context.Context) {
-	for {
-		loginLogJSON, err := l.cache.LeftPop(ctx, config.LoginLogCacheKey)
+	if err := l.queue.Consume(ctx, l.insertQueuedLoginLogs); err != nil {
+		l.core.Logger.Error("LoginLogDaoImpl.SyncLoginLog: failed to consume login logs", zap.Error(err))
+		return
+	}
+	l.core.Logger.Info("LoginLogDaoImpl.SyncLoginLog: success")
+}
+
+// GetLoginLogQueueStats returns the login logs queued in cache and not synced yet
+func (l *LoginLogDaoImpl) GetLoginLogQueueStats(ctx context.Context) (*dao.LogQueueStats, error) {
+	stats, err := l.queue.Stats(ctx)
+	if err != nil {
+		l.core.Logger.Error("LoginLogDaoImpl.GetLoginLogQueueStats: failed to get queue stats", zap.Error(err))
+		return nil, err
+	}
+	return stats, nil
+}
+
+// insertQueuedLoginLogs inserts a batch of queued login logs and returns the error of every one not inserted.
+func (l *LoginLogDaoImpl) insertQueuedLoginLogs(
+	ctx context.Context, entries []dao.LogQueueEntry,
+) map[string]error {
+	failures := make(map[string]error)
+	users := make(map[primitive.ObjectID]*entity.UserModel)
+	inserted := make([]dao.LogQueueEntry, 0, len(entries))
+	docs := make([]*entity.LoginLogModel, 0, len(entries))
+	for _, entry := range entries {
+		loginLogID, err := entry.ObjectID()
 		if err != nil {
-			if errors.Is(err, dao.CacheNil{}) {
-				break
-			}
-			l.core.Logger.Error(
-				"LoginLogDaoImpl.SyncLoginLog: failed to pop login log from cache", zap.Error(err),
-			)
-			return
+			failures[entry.ID] = fmt.Errorf("%w: %v", dao.ErrLogQueueEntryInvalid, err)
+			continue
 		}
 		var loginLog entity.LoginLogCache
-		if err := json.Unmarshal([]byte(*loginLogJSON), &loginLog); err != nil {
-			l.core.Logger.Error(
-				"LoginLogDaoImpl.SyncLoginLog: failed to unmarshal login log",
-				zap.Error(err), zap.String("loginLogJSON", *loginLogJSON),
-			)
+		if err := json.Unmarshal([]byte(entry.Value), &loginLog); err != nil {
+			failures[entry.ID] = fmt.Errorf("%w: %v", dao.ErrLogQueueEntryInvalid, err)
 			continue
 		}
 		userID, err := primitive.ObjectIDFromHex(loginLog.UserIDHex)
 		if err != nil {
-			l.core.Logger.Error(
-				"LoginLogDaoImpl.SyncLoginLog: failed to convert user ID",
-				zap.Error(err), zap.String("loginLogJSON", *loginLogJSON),
-			)
+			failures[entry.ID] = fmt.Errorf("%w: %v", dao.ErrLogQueueEntryInvalid, err)
 			continue
 		}
-		if _, err := l.InsertLoginLog(
-			ctx, userID, loginLog.IPAddress, loginLog.UserAgent,
-		); err != nil {
-			l.core.Logger.Error(
-				"LoginLogDaoImpl.SyncLoginLog: failed to insert login log",
-				zap.Error(err), zap.String("loginLogJSON", *loginLogJSON),
-			)
+		user, ok := users[userID]
+		if !ok {
+			if user, err = l.userDao.GetUserByID(ctx, userID); errors.Is(err, qmgo.ErrNoSuchDocuments) {
+				failures[entry.ID] = fmt.Errorf("%w: user %s not found", dao.ErrLogQueueEntryInvalid, userID.Hex())
+				continue
+			} else if err != nil {
+				failures[entry.ID] = err
+				continue
+			}
+			users[userID] = user
 		}
+		inserted = append(inserted, entry)
+		docs = append(
+			docs, &entity.LoginLogModel{
+				LoginLogID: loginLogID,
+				UserID:     userID,
+				Username:   user.Username,
+				Email:      user.Email,
+				IPAddress:  loginLog.IPAddress,
+				UserAgent:  loginLog.UserAgent,
+				CreatedAt:  loginLog.CreatedAt,
+			},
+		)
+	}
+	if len(docs) > 0 {
+		l.analyzeLoginLogs(ctx, docs)
+		coll := l.core.Mongo.Client().Database(l.core.Mongo.DatabaseName).Collection(config.LoginLogCollectionName)
+		_, err := coll.InsertMany(
+			ctx, docs, options.InsertManyOptions{InsertManyOptions: opt.InsertMany().SetOrdered(false)},
+		)
+		for id, err := range dao.InsertManyFailures(inserted, err) {
+			failures[id] = err
+		}
+	}
+	for id, err := range failures {
+		l.core.Logger.Error(
+			"LoginLogDaoImpl.SyncLoginLog: failed to insert login log", zap.Error(err), zap.String("entryID", id),
+		)
 	}
+	return failures
 }
 
 func (l *LoginLogDaoImpl) DeleteLoginLog(ctx context.Context, loginLogID primitive.ObjectID) error {
-	coll := l.core.Mongo.MongoClient.Database(l.core.Mongo.DatabaseName).Collection(config.LoginLogCollectionName)
+	coll := l.core.Mongo.Client().Database(l.core.Mongo.DatabaseName).Collection(config.LoginLogCollectionName)
 	err := coll.RemoveId(ctx, loginLogID)
 	if err != nil {
 		l.core.Logger.Error(
@@ -255,7 +372,7 @@ func (l *LoginLogDaoImpl) DeleteLoginLogList(
 	ctx context.Context, startTime, endTime *time.Time, userID *primitive.ObjectID,
 	ipAddress, userAgent *string,
 ) (*int64, error) {
-	coll := l.core.Mongo.MongoClient.Database(l.core.Mongo.DatabaseName).Collection(config.LoginLogCollectionName)
+	coll := l.core.Mongo.Client().Database(l.core.Mongo.DatabaseName).Collection(config.LoginLogCollectionName)
 	doc := bson.M{}
 	if startTime != nil && endTime != nil {
 		doc["created_at"] = bson.M{"$gte": startTime, "$lte": endTime}
@@ -284,3 +401,66 @@ func (l *LoginLogDaoImpl) DeleteLoginLogList(
 	}
 	return &result.DeletedCount, err
 }
+
+// IterateExpiredLoginLogList calls fn with every login log created before endTime, oldest first
+func (l *LoginLogDaoImpl) IterateExpiredLoginLogList(
+	ctx context.Context, endTime time.Time, fn func(loginLog *entity.LoginLogModel) error,
+) error {
+	coll := l.core.Mongo.Client().Database(l.core.Mongo.DatabaseName).Collection(config.LoginLogCollectionName)
+	cursor := coll.Find(ctx, bson.M{"created_at": bson.M{"$lt": endTime}}).Sort("created_at").Cursor()
+	defer func() {
+		_ = cursor.Close()
+	}()
+	var loginLog entity.LoginLogModel
+	for cursor.Next(&loginLog) {
+		if err := fn(&loginLog); err != nil {
+			return err
+		}
+		loginLog = entity.LoginLogModel{}
+	}
+	if err := cursor.Err(); err != nil {
+		l.core.Logger.Error(
+			"LoginLogDaoImpl.IterateExpiredLoginLogList: failed to iterate login logs",
+			zap.Error(err), zap.Time("endTime", endTime),
+		)
+		return err
+	}
+	return nil
+}
+
+func (l *LoginLogDaoImpl) DeleteLoginLogByIDList(
+	ctx context.Context, loginLogIDs []primitive.ObjectID,
+) (*int64, error) {
+	coll := l.core.Mongo.Client().Database(l.core.Mongo.DatabaseName).Collection(config.LoginLogCollectionName)
+	result, err := coll.RemoveAll(ctx, bson.M{"_id": bson.M{"$in": loginLogIDs}})
+	if err != nil {
+		l.core.Logger.Error(
+			"LoginLogDaoImpl.DeleteLoginLogByIDList: failed to delete login logs",
+			zap.Error(err), zap.Int("count", len(loginLogIDs)),
+		)
+		return nil, err
+	}
+	l.core.Logger.Info("LoginLogDaoImpl.DeleteLoginLogByIDList: success", zap.Int64("count", result.DeletedCount))
+	return &result.DeletedCount, nil
+}
+
+// ImportLoginLogList inserts archived login logs back, skipping the ones still present
+func (l *LoginLogDaoImpl) ImportLoginLogList(
+	ctx context.Context, loginLogs []entity.LoginLogModel,
+) (*int64, error) {
+	if len(loginLogs) == 0 {
+		var count int64
+		return &count, nil
+	}
+	coll := l.core.Mongo.Client().Database(l.core.Mongo.DatabaseName).Collection(config.LoginLogCollectionName)
+	count, err := insertManyIgnoringDuplicates(ctx, coll, loginLogs, len(loginLogs))
+	if err != nil {
+		l.core.Logger.Error(
+			"LoginLogDaoImpl.ImportLoginLogList: failed to insert login logs",
+			zap.Error(err), zap.Int("count", len(loginLogs)),
+		)
+		return nil, err
+	}
+	l.core.Logger.Info("LoginLogDaoImpl.ImportLoginLogList: success", zap.Int64("count", count))
+	return &count, nil
+}
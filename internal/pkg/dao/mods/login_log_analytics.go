@@ -0,0 +1,190 @@
+package mods
+
+import (
+	"context"
+	"math"
+	"net"
+	"time"
+
+	"fiber-admin/internal/pkg/config"
+	"fiber-admin/internal/pkg/domain/entity"
+	"fiber-admin/pkg/useragent"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// minTravelDistance is the distance in km below which logins are never impossible travel, since GeoIP locations are
+// only accurate to a city or region.
+const minTravelDistance = 100
+
+// earthRadius is the mean radius of the Earth in km.
+const earthRadius = 6371
+
+// analyzeLoginLogs fills in the browser, device and location of the login logs and flags the suspicious ones by
+// comparing them with the previous logins of their users. The login logs must be in the order they happened.
+func (l *LoginLogDaoImpl) analyzeLoginLogs(ctx context.Context, loginLogs []*entity.LoginLogModel) {
+	byUser := make(map[primitive.ObjectID][]*entity.LoginLogModel)
+	for _, loginLog := range loginLogs {
+		l.enrichLoginLog(loginLog)
+		byUser[loginLog.UserID] = append(byUser[loginLog.UserID], loginLog)
+	}
+	for userID, userLoginLogs := range byUser {
+		history, err := l.loginHistory(ctx, userID, userLoginLogs)
+		if err != nil {
+			// Losing the flags is better than losing the logins.
+			l.core.Logger.Error(
+				"LoginLogDaoImpl.analyzeLoginLogs: failed to get login history",
+				zap.Error(err), zap.String("userID", userID.Hex()),
+			)
+			continue
+		}
+		for _, loginLog := range userLoginLogs {
+			loginLog.Flags = l.flagLoginLog(loginLog, history)
+			history = append([]*entity.LoginLogModel{loginLog}, history...)
+		}
+	}
+}
+
+// enrichLoginLog parses the user agent of the login log and locates its IP address.
+func (l *LoginLogDaoImpl) enrichLoginLog(loginLog *entity.LoginLogModel) {
+	agent := useragent.Parse(loginLog.UserAgent)
+	loginLog.Browser, loginLog.BrowserVersion = agent.Browser, agent.BrowserVersion
+	loginLog.OS, loginLog.OSVersion = agent.OS, agent.OSVersion
+	loginLog.Device = agent.Device
+
+	ip := net.ParseIP(loginLog.IPAddress)
+	if l.geoIP == nil || ip == nil {
+		return
+	}
+	location, err := l.geoIP.Lookup(ip)
+	if err != nil {
+		l.core.Logger.Warn(
+			"LoginLogDaoImpl.enrichLoginLog: failed to locate IP address",
+			zap.Error(err), zap.String("ipAddress", loginLog.IPAddress),
+		)
+		return
+	}
+	if location == nil {
+		return // Private or unknown address
+	}
+	loginLog.CountryCode, loginLog.Country, loginLog.City = location.CountryCode, location.Country, location.City
+	loginLog.Latitude, loginLog.Longitude = location.Latitude, location.Longitude
+}
+
+// loginHistory returns the latest logins of the user before the given ones, newest first.
+func (l *LoginLogDaoImpl) loginHistory(
+	ctx context.Context, userID primitive.ObjectID, loginLogs []*entity.LoginLogModel,
+) ([]*entity.LoginLogModel, error) {
+	ids := make([]primitive.ObjectID, 0, len(loginLogs))
+	for _, loginLog := range loginLogs {
+		ids = append(ids, loginLog.LoginLogID)
+	}
+	coll := l.core.Mongo.Client().Database(l.core.Mongo.DatabaseName).Collection(config.LoginLogCollectionName)
+	var history []entity.LoginLogModel
+	err := coll.Find(
+		ctx, bson.M{
+			"user_id":    userID,
+			"_id":        bson.M{"$nin": ids}, // Redelivered logins are already stored
+			"created_at": bson.M{"$lte": loginLogs[0].CreatedAt},
+		},
+	).Sort("-created_at").Limit(l.core.Config.LoginAnalyticsConfig.HistorySize).All(&history)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*entity.LoginLogModel, 0, len(history))
+	for i := range history {
+		result = append(result, &history[i])
+	}
+	return result, nil
+}
+
+// flagLoginLog returns the anomaly flags of a login given the previous logins of the user, newest first. The first
+// login of a user is never flagged, and neither are logins compared with ones logged before the analytics existed.
+func (l *LoginLogDaoImpl) flagLoginLog(loginLog *entity.LoginLogModel, history []*entity.LoginLogModel) []string {
+	var (
+		flags        []string
+		knownCountry bool
+		seenCountry  bool
+		knownDevice  bool
+		seenDevice   bool
+		lastLocated  *entity.LoginLogModel
+		device       = useragent.Agent{Browser: loginLog.Browser, OS: loginLog.OS, Device: loginLog.Device}.Key()
+	)
+	for _, previous := range history {
+		if previous.CountryCode != "" {
+			knownCountry = true
+			seenCountry = seenCountry || previous.CountryCode == loginLog.CountryCode
+		}
+		if previous.Device != "" {
+			knownDevice = true
+			seenDevice = seenDevice ||
+				useragent.Agent{Browser: previous.Browser, OS: previous.OS, Device: previous.Device}.Key() == device
+		}
+		if lastLocated == nil && previous.Latitude != nil && previous.Longitude != nil {
+			lastLocated = previous
+		}
+	}
+	if loginLog.CountryCode != "" && knownCountry && !seenCountry {
+		flags = append(flags, config.LoginFlagNewCountry)
+	}
+	if loginLog.Device != "" && knownDevice && !seenDevice {
+		flags = append(flags, config.LoginFlagNewDevice)
+	}
+	if lastLocated != nil && loginLog.Latitude != nil && loginLog.Longitude != nil {
+		distance := haversine(*lastLocated.Latitude, *lastLocated.Longitude, *loginLog.Latitude, *loginLog.Longitude)
+		hours := loginLog.CreatedAt.Sub(lastLocated.CreatedAt).Hours()
+		if distance > minTravelDistance &&
+			(hours <= 0 || distance/hours > l.core.Config.LoginAnalyticsConfig.MaxTravelSpeed) {
+			flags = append(flags, config.LoginFlagImpossibleTravel)
+		}
+	}
+	return flags
+}
+
+// haversine returns the great-circle distance in km between two coordinates.
+func haversine(lat1, lon1, lat2, lon2 float64) float64 {
+	toRadians := func(degrees float64) float64 { return degrees * math.Pi / 180 }
+	dLat, dLon := toRadians(lat2-lat1), toRadians(lon2-lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
+
+// GetUnnotifiedLoginLogList returns the flagged login logs created since the given time whose users have not been
+// notified yet, oldest first
+func (l *LoginLogDaoImpl) GetUnnotifiedLoginLogList(
+	ctx context.Context, since time.Time, limit int64,
+) ([]entity.LoginLogModel, error) {
+	coll := l.core.Mongo.Client().Database(l.core.Mongo.DatabaseName).Collection(config.LoginLogCollectionName)
+	var loginLogList []entity.LoginLogModel
+	err := coll.Find(
+		ctx, bson.M{
+			"flags":       bson.M{"$exists": true, "$ne": bson.A{}},
+			"notified_at": bson.M{"$exists": false},
+			"created_at":  bson.M{"$gte": since},
+		},
+	).Sort("created_at").Limit(limit).All(&loginLogList)
+	if err != nil {
+		l.core.Logger.Error("LoginLogDaoImpl.GetUnnotifiedLoginLogList: failed to find login logs", zap.Error(err))
+		return nil, err
+	}
+	return loginLogList, nil
+}
+
+// SetLoginLogNotified records that the users of the login logs were notified of their flags
+func (l *LoginLogDaoImpl) SetLoginLogNotified(ctx context.Context, loginLogIDs []primitive.ObjectID) error {
+	coll := l.core.Mongo.Client().Database(l.core.Mongo.DatabaseName).Collection(config.LoginLogCollectionName)
+	_, err := coll.UpdateAll(
+		ctx, bson.M{"_id": bson.M{"$in": loginLogIDs}}, bson.M{"$set": bson.M{"notified_at": time.Now()}},
+	)
+	if err != nil {
+		l.core.Logger.Error(
+			"LoginLogDaoImpl.SetLoginLogNotified: failed to update login logs",
+			zap.Error(err), zap.Int("count", len(loginLogIDs)),
+		)
+		return err
+	}
+	l.core.Logger.Info("LoginLogDaoImpl.SetLoginLogNotified: success", zap.Int("count", len(loginLogIDs)))
+	return nil
+}
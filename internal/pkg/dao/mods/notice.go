@@ -24,9 +24,12 @@ type NoticeDao interface {
 		offset, limit int64, desc bool, createStartTime, createEndTime, updateStartTime, updateEndTime *time.Time,
 		noticeType *string,
 	) ([]entity.NoticeModel, *int64, error)
+	SearchNotice(ctx context.Context, query string, offset, limit int64) ([]entity.NoticeSearchResult, *int64, error)
 	InsertNotice(ctx context.Context, title, content, noticeType string) (primitive.ObjectID, error)
-	UpdateNotice(ctx context.Context, noticeID primitive.ObjectID, title, content, noticeType *string) error
-	DeleteNotice(ctx context.Context, noticeID primitive.ObjectID) error
+	UpdateNotice(
+		ctx context.Context, noticeID primitive.ObjectID, version *int64, title, content, noticeType *string,
+	) error
+	DeleteNotice(ctx context.Context, noticeID primitive.ObjectID, version *int64) error
 	DeleteNoticeList(
 		ctx context.Context,
 		createStartTime, createEndTime, updateStartTime, updateEndTime *time.Time,
@@ -41,7 +44,7 @@ type NoticeDaoImpl struct {
 
 func NewNoticeDao(ctx context.Context, core *dao.Core, cache *dao.Cache) (NoticeDao, error) {
 	var _ NoticeDao = (*NoticeDaoImpl)(nil)
-	collection := core.Mongo.MongoClient.Database(core.Mongo.DatabaseName).Collection(config.NoticeCollectionName)
+	collection := core.Mongo.Client().Database(core.Mongo.DatabaseName).Collection(config.NoticeCollectionName)
 	err := collection.CreateIndexes(
 		ctx, []options.IndexModel{
 			{
@@ -55,52 +58,32 @@ func NewNoticeDao(ctx context.Context, core *dao.Core, cache *dao.Cache) (Notice
 		core.Logger.Error(fmt.Sprintf("Failed to create indexes for %s", config.NoticeCollectionName), zap.Error(err))
 		return nil, err
 	}
+	err = createTextIndex(ctx, collection, core.Config.SearchConfig.DefaultLanguage)
+	if err != nil {
+		core.Logger.Error(fmt.Sprintf("Failed to create text index for %s", config.NoticeCollectionName), zap.Error(err))
+		return nil, err
+	}
 	return &NoticeDaoImpl{core, cache}, nil
 }
 
 func (n *NoticeDaoImpl) GetNoticeByID(ctx context.Context, noticeID primitive.ObjectID) (*entity.NoticeModel, error) {
-	var notice entity.NoticeModel
-	key := fmt.Sprintf("%s:noticeID:%s", config.NoticeCachePrefix, noticeID.Hex())
-	cache, err := n.cache.Get(ctx, key)
-	if errors.Is(err, dao.CacheNil{}) {
-		n.core.Logger.Info("NoticeDaoImpl.GetNoticeByID: cache miss", zap.String("noticeID", noticeID.Hex()))
-	} else if err != nil {
-		n.core.Logger.Error("NoticeDaoImpl.GetNoticeByID: failed to get cache", zap.Error(err), zap.String("key", key))
-	} else {
-		n.core.Logger.Info("NoticeDaoImpl.GetNoticeByID: cache hit", zap.String("noticeID", noticeID.Hex()))
-		err = json.Unmarshal([]byte(*cache), &notice)
-		if err != nil {
-			n.core.Logger.Error(
-				"NoticeDaoImpl.GetNoticeByID: failed to unmarshal cache", zap.Error(err),
-				zap.String("noticeID", noticeID.Hex()), zap.String("cache", *cache),
-			)
-			return nil, err
-		}
-		return &notice, nil
-	}
-	coll := n.core.Mongo.MongoClient.Database(n.core.Mongo.DatabaseName).Collection(config.NoticeCollectionName)
-	if err = coll.Find(ctx, bson.M{"_id": noticeID}).One(&notice); err != nil {
-		n.core.Logger.Error(
-			"NoticeDaoImpl.GetNoticeByID: failed to find notice", zap.Error(err),
-			zap.String("noticeID", noticeID.Hex()),
-		)
-		return nil, err
-	} else {
-		docJSON, _ := json.Marshal(notice)
-		if err := n.cache.Set(ctx, key, string(docJSON), &n.core.Config.CacheConfig.NoticeCacheTTL); err != nil {
-			n.core.Logger.Error(
-				"NoticeDaoImpl.GetNoticeByID: failed to set cache", zap.Error(err),
-				zap.String("key", key), zap.ByteString(config.NoticeCollectionName, docJSON),
-			)
-		} else {
-			n.core.Logger.Info(
-				"NoticeDaoImpl.GetNoticeByID: cache set", zap.String("key", key),
-				zap.ByteString(config.NoticeCollectionName, docJSON),
-			)
-		}
-		n.core.Logger.Info("NoticeDaoImpl.GetNoticeByID: success", zap.String("noticeID", noticeID.Hex()))
-		return &notice, nil
-	}
+	key := n.cache.Key(ctx, config.NoticeCachePrefix, "noticeID:%s", noticeID.Hex())
+	return dao.GetOrLoad(
+		ctx, n.cache, n.core.Logger, config.CacheEntityNotice, key, n.core.Config.CacheConfig.NoticeCacheTTL,
+		func(ctx context.Context) (*entity.NoticeModel, error) {
+			var notice entity.NoticeModel
+			coll := n.core.Mongo.Client().Database(n.core.Mongo.DatabaseName).Collection(config.NoticeCollectionName)
+			if err := coll.Find(ctx, bson.M{"_id": noticeID}).One(&notice); err != nil {
+				n.core.Logger.Error(
+					"NoticeDaoImpl.GetNoticeByID: failed to find notice", zap.Error(err),
+					zap.String("noticeID", noticeID.Hex()),
+				)
+				return nil, err
+			}
+			n.core.Logger.Info("NoticeDaoImpl.GetNoticeByID: success", zap.String("noticeID", noticeID.Hex()))
+			return &notice, nil
+		},
+	)
 }
 
 func (n *NoticeDaoImpl) GetNoticeList(
@@ -111,7 +94,7 @@ func (n *NoticeDaoImpl) GetNoticeList(
 	var noticeList []entity.NoticeModel
 	var err error
 	doc := bson.M{}
-	key := fmt.Sprintf("%s:offset:%d:limit:%d", config.NoticeCachePrefix, offset, limit)
+	key := n.cache.Key(ctx, config.NoticeCachePrefix, "offset:%d:limit:%d", offset, limit)
 	if createStartTime != nil && createEndTime != nil {
 		doc["created_at"] = bson.M{"$gte": createStartTime, "$lte": createEndTime}
 		key += fmt.Sprintf(
@@ -159,13 +142,14 @@ func (n *NoticeDaoImpl) GetNoticeList(
 					NoticeType: noticeCache.NoticeType,
 					CreatedAt:  noticeCache.CreatedAt,
 					UpdatedAt:  noticeCache.UpdatedAt,
+					Version:    noticeCache.Version,
 				},
 			)
 		}
 		return noticeList, &cache.Total, nil
 	}
 
-	collection := n.core.Mongo.MongoClient.Database(n.core.Mongo.DatabaseName).Collection(config.NoticeCollectionName)
+	collection := n.core.Mongo.Client().Database(n.core.Mongo.DatabaseName).Collection(config.NoticeCollectionName)
 	if desc {
 		err = collection.Find(ctx, doc).Sort("-created_at").Skip(offset).Limit(limit).All(&noticeList)
 	} else {
@@ -201,6 +185,7 @@ func (n *NoticeDaoImpl) GetNoticeList(
 				NoticeType: notice.NoticeType,
 				CreatedAt:  notice.CreatedAt,
 				UpdatedAt:  notice.UpdatedAt,
+				Version:    notice.Version,
 			},
 		)
 	}
@@ -220,16 +205,40 @@ func (n *NoticeDaoImpl) GetNoticeList(
 	return noticeList, &count, nil
 }
 
+func (n *NoticeDaoImpl) SearchNotice(
+	ctx context.Context, query string, offset, limit int64,
+) ([]entity.NoticeSearchResult, *int64, error) {
+	var result []textSearchResult[entity.NoticeSearchResult]
+	coll := n.core.Mongo.Client().Database(n.core.Mongo.DatabaseName).Collection(config.NoticeCollectionName)
+	err := coll.Aggregate(ctx, textSearchPipeline(query, offset, limit)).All(&result)
+	if err != nil {
+		n.core.Logger.Error(
+			"NoticeDaoImpl.SearchNotice: failed to search notices", zap.Error(err), zap.String("query", query),
+		)
+		return nil, nil, err
+	}
+	var (
+		noticeList []entity.NoticeSearchResult
+		count      int64
+	)
+	if len(result) > 0 {
+		noticeList, count = result[0].List, result[0].count()
+	}
+	n.core.Logger.Info("NoticeDaoImpl.SearchNotice: success", zap.String("query", query), zap.Int64("count", count))
+	return noticeList, &count, nil
+}
+
 func (n *NoticeDaoImpl) InsertNotice(
 	ctx context.Context, title, content, noticeType string,
 ) (primitive.ObjectID, error) {
-	collection := n.core.Mongo.MongoClient.Database(n.core.Mongo.DatabaseName).Collection(config.NoticeCollectionName)
+	collection := n.core.Mongo.Client().Database(n.core.Mongo.DatabaseName).Collection(config.NoticeCollectionName)
 	doc := bson.M{
 		"title":       title,
 		"content":     content,
 		"notice_type": noticeType,
 		"created_at":  time.Now(),
 		"updated_at":  time.Now(),
+		"version":     int64(1),
 	}
 	docJSON, err := json.Marshal(doc)
 	if err != nil {
@@ -252,19 +261,19 @@ func (n *NoticeDaoImpl) InsertNotice(
 		zap.String("noticeID", result.InsertedID.(primitive.ObjectID).Hex()),
 		zap.ByteString(config.NoticeCollectionName, docJSON),
 	)
-	prefix := config.NoticeCachePrefix
-	if err = n.cache.Flush(ctx, &prefix); err != nil {
-		n.core.Logger.Error("NoticeDaoImpl.InsertNotice: failed to flush cache", zap.Error(err))
+	if err = n.cache.Invalidate(ctx, config.NoticeCachePrefix); err != nil {
+		n.core.Logger.Error("NoticeDaoImpl.InsertNotice: failed to invalidate cache", zap.Error(err))
 	} else {
-		n.core.Logger.Info("NoticeDaoImpl.InsertNotice: cache flush success")
+		n.core.Logger.Info("NoticeDaoImpl.InsertNotice: cache invalidated")
 	}
 	return result.InsertedID.(primitive.ObjectID), nil
 }
 
+// UpdateNotice updates the notice, only if it still has the given version unless version is nil.
 func (n *NoticeDaoImpl) UpdateNotice(
-	ctx context.Context, noticeID primitive.ObjectID, title, content, noticeType *string,
+	ctx context.Context, noticeID primitive.ObjectID, version *int64, title, content, noticeType *string,
 ) error {
-	collection := n.core.Mongo.MongoClient.Database(n.core.Mongo.DatabaseName).Collection(config.NoticeCollectionName)
+	collection := n.core.Mongo.Client().Database(n.core.Mongo.DatabaseName).Collection(config.NoticeCollectionName)
 	doc := bson.M{"updated_at": time.Now()}
 	if title != nil {
 		doc["title"] = *title
@@ -276,7 +285,8 @@ func (n *NoticeDaoImpl) UpdateNotice(
 		doc["notice_type"] = *noticeType
 	}
 	docJSON, _ := json.Marshal(doc)
-	err := collection.UpdateId(ctx, noticeID, bson.M{"$set": doc})
+	err := collection.UpdateOne(ctx, versionFilter(noticeID, version), versionUpdate(doc))
+	err = versionConflict(ctx, collection, noticeID, version, err)
 
 	if err != nil {
 		n.core.Logger.Error(
@@ -289,31 +299,31 @@ func (n *NoticeDaoImpl) UpdateNotice(
 			"NoticeDaoImpl.UpdateNotice: success",
 			zap.String("noticeID", noticeID.Hex()), zap.ByteString(config.NoticeCollectionName, docJSON),
 		)
-		prefix := config.NoticeCachePrefix
-		if err = n.cache.Flush(ctx, &prefix); err != nil {
-			n.core.Logger.Error("NoticeDaoImpl.UpdateNotice: failed to flush cache", zap.Error(err))
+		if err = n.cache.Invalidate(ctx, config.NoticeCachePrefix); err != nil {
+			n.core.Logger.Error("NoticeDaoImpl.UpdateNotice: failed to invalidate cache", zap.Error(err))
 		} else {
-			n.core.Logger.Info("NoticeDaoImpl.UpdateNotice: cache flush success")
+			n.core.Logger.Info("NoticeDaoImpl.UpdateNotice: cache invalidated")
 		}
 	}
 	return err
 }
 
-func (n *NoticeDaoImpl) DeleteNotice(ctx context.Context, noticeID primitive.ObjectID) error {
-	collection := n.core.Mongo.MongoClient.Database(n.core.Mongo.DatabaseName).Collection(config.NoticeCollectionName)
-	err := collection.RemoveId(ctx, noticeID)
+// DeleteNotice deletes the notice, only if it still has the given version unless version is nil.
+func (n *NoticeDaoImpl) DeleteNotice(ctx context.Context, noticeID primitive.ObjectID, version *int64) error {
+	collection := n.core.Mongo.Client().Database(n.core.Mongo.DatabaseName).Collection(config.NoticeCollectionName)
+	err := collection.Remove(ctx, versionFilter(noticeID, version))
+	err = versionConflict(ctx, collection, noticeID, version, err)
 	if err != nil {
 		n.core.Logger.Error(
 			"NoticeDaoImpl.DeleteNotice: failed to delete notice",
 			zap.Error(err), zap.String("noticeID", noticeID.Hex()),
 		)
 	} else {
-		prefix := config.NoticeCachePrefix
 		n.core.Logger.Info("NoticeDaoImpl.DeleteNotice, success", zap.String("noticeID", noticeID.Hex()))
-		if err = n.cache.Flush(ctx, &prefix); err != nil {
-			n.core.Logger.Error("NoticeDaoImpl.DeleteNotice: failed to flush cache", zap.Error(err))
+		if err = n.cache.Invalidate(ctx, config.NoticeCachePrefix); err != nil {
+			n.core.Logger.Error("NoticeDaoImpl.DeleteNotice: failed to invalidate cache", zap.Error(err))
 		} else {
-			n.core.Logger.Info("NoticeDaoImpl.DeleteNotice: cache flush success")
+			n.core.Logger.Info("NoticeDaoImpl.DeleteNotice: cache invalidated")
 		}
 	}
 	return err
@@ -324,7 +334,7 @@ func (n *NoticeDaoImpl) DeleteNoticeList(
 	createStartTime, createEndTime, updateStartTime, updateEndTime *time.Time,
 	noticeType *string,
 ) (*int64, error) {
-	collection := n.core.Mongo.MongoClient.Database(n.core.Mongo.DatabaseName).Collection(config.NoticeCollectionName)
+	collection := n.core.Mongo.Client().Database(n.core.Mongo.DatabaseName).Collection(config.NoticeCollectionName)
 	doc := bson.M{}
 	if createStartTime != nil && createEndTime != nil {
 		doc["created_at"] = bson.M{"$gte": createStartTime, "$lte": createEndTime}
@@ -346,11 +356,10 @@ func (n *NoticeDaoImpl) DeleteNoticeList(
 		n.core.Logger.Info(
 			"NoticeDaoImpl.DeleteNoticeList: success", zap.ByteString(config.NoticeCollectionName, docJSON),
 		)
-		prefix := config.NoticeCachePrefix
-		if err = n.cache.Flush(ctx, &prefix); err != nil {
-			n.core.Logger.Error("NoticeDaoImpl.DeleteNoticeList: failed to flush cache", zap.Error(err))
+		if err = n.cache.Invalidate(ctx, config.NoticeCachePrefix); err != nil {
+			n.core.Logger.Error("NoticeDaoImpl.DeleteNoticeList: failed to invalidate cache", zap.Error(err))
 		} else {
-			n.core.Logger.Info("NoticeDaoImpl.DeleteNoticeList: cache flush success")
+			n.core.Logger.Info("NoticeDaoImpl.DeleteNoticeList: cache invalidated")
 		}
 	}
 	return &result.DeletedCount, err
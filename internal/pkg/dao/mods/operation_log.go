@@ -11,9 +11,11 @@ import (
 	"fiber-admin/internal/pkg/domain/entity"
 	"fiber-admin/pkg/utils/common"
 	"github.com/goccy/go-json"
+	"github.com/qiniu/qmgo"
 	"github.com/qiniu/qmgo/options"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	opt "go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
 )
 
@@ -24,25 +26,42 @@ type OperationLogDao interface {
 		offset, limit int64, desc bool, startTime, endTime *time.Time, userID, entityID *primitive.ObjectID,
 		ipAddress, operation, entityType, status, query *string,
 	) ([]entity.OperationLogModel, *int64, error)
+	IterateOperationLogList(
+		ctx context.Context,
+		desc bool, startTime, endTime *time.Time, userID, entityID *primitive.ObjectID,
+		ipAddress, operation, entityType, status, query *string,
+		fn func(operationLog *entity.OperationLogModel) error,
+	) error
 	InsertOperationLog(
 		ctx context.Context, userID, entityID primitive.ObjectID,
-		ipAddress, userAgent, operation, entityType, description, status string,
+		ipAddress, userAgent, requestID, operation, entityType, description, status string,
 	) (primitive.ObjectID, error)
 	CacheOperationLog(
 		ctx context.Context, userID, entityID primitive.ObjectID,
-		ipAddress, userAgent, operation, entityType, description, status string,
+		ipAddress, userAgent, requestID, operation, entityType, description, status string,
 	) error
 	SyncOperationLog(ctx context.Context)
+	GetOperationLogQueueStats(ctx context.Context) (*dao.LogQueueStats, error)
 	DeleteOperationLog(ctx context.Context, operationLogID primitive.ObjectID) error
 	DeleteOperationLogList(
 		ctx context.Context, startTime, endTime *time.Time, userID, entityID *primitive.ObjectID,
 		ipAddress, operation, entityType, status *string,
 	) (*int64, error)
+	GetOperationLogChainHead(ctx context.Context) (*entity.OperationLogChainHeadModel, error)
+	IterateOperationLogChain(ctx context.Context, fn func(operationLog *entity.OperationLogModel) error) error
+	InsertOperationLogCheckpoint(ctx context.Context) (*entity.OperationLogCheckpointModel, error)
+	GetOperationLogCheckpointList(ctx context.Context) ([]entity.OperationLogCheckpointModel, error)
+	IterateExpiredOperationLogList(
+		ctx context.Context, endTime time.Time, fn func(operationLog *entity.OperationLogModel) error,
+	) error
+	DeleteOperationLogByIDList(ctx context.Context, operationLogIDs []primitive.ObjectID) (*int64, error)
+	ImportOperationLogList(ctx context.Context, operationLogs []entity.OperationLogModel) (*int64, error)
 }
 
 type OperationLogDaoImpl struct {
 	core    *dao.Core
 	cache   *dao.Cache
+	queue   *dao.LogQueue
 	userDao UserDao
 }
 
@@ -50,11 +69,18 @@ func NewOperationLogDao(ctx context.Context, core *dao.Core, cache *dao.Cache, u
 	OperationLogDao, error,
 ) {
 	var _ OperationLogDao = (*OperationLogDaoImpl)(nil) // Ensure that the interface is implemented
-	collection := core.Mongo.MongoClient.Database(core.Mongo.DatabaseName).Collection(config.OperationLogCollectionName)
+	collection := core.Mongo.Client().Database(core.Mongo.DatabaseName).Collection(config.OperationLogCollectionName)
 	err := collection.CreateIndexes(
 		ctx, []options.IndexModel{
 			{Key: []string{"created_at"}}, {Key: []string{"operation"}}, {Key: []string{"entity_type"}},
 			{Key: []string{"status"}},
+			{
+				// Each position in the hash chain is taken once. Records logged before chaining have no sequence.
+				Key: []string{"sequence"},
+				IndexOptions: opt.Index().SetUnique(true).SetPartialFilterExpression(
+					bson.M{"sequence": bson.M{"$gt": 0}},
+				),
+			},
 		},
 	)
 	if err != nil {
@@ -65,8 +91,11 @@ func NewOperationLogDao(ctx context.Context, core *dao.Core, cache *dao.Cache, u
 		return nil, err
 	}
 	return &OperationLogDaoImpl{
-		core:    core,
-		cache:   cache,
+		core:  core,
+		cache: cache,
+		queue: dao.NewLogQueue(
+			cache, config.OperationLogStreamKey, config.OperationLogDeadLetterKey, config.LegacyOperationLogCacheKey,
+		),
 		userDao: userDao,
 	}, nil
 }
@@ -74,7 +103,7 @@ func NewOperationLogDao(ctx context.Context, core *dao.Core, cache *dao.Cache, u
 func (o *OperationLogDaoImpl) GetOperationLogByID(
 	ctx context.Context, operationLogID primitive.ObjectID,
 ) (*entity.OperationLogModel, error) {
-	collection := o.core.Mongo.MongoClient.Database(o.core.Mongo.DatabaseName).Collection(config.OperationLogCollectionName)
+	collection := o.core.Mongo.Client().Database(o.core.Mongo.DatabaseName).Collection(config.OperationLogCollectionName)
 	var operationLog entity.OperationLogModel
 	err := collection.Find(ctx, bson.M{"_id": operationLogID}).One(&operationLog)
 	if err != nil {
@@ -98,7 +127,42 @@ func (o *OperationLogDaoImpl) GetOperationLogList(
 ) ([]entity.OperationLogModel, *int64, error) {
 	var operationLogList []entity.OperationLogModel
 	var err error
-	collection := o.core.Mongo.MongoClient.Database(o.core.Mongo.DatabaseName).Collection(config.OperationLogCollectionName)
+	collection := o.core.Mongo.Client().Database(o.core.Mongo.DatabaseName).Collection(config.OperationLogCollectionName)
+	doc := operationLogFilter(startTime, endTime, userID, entityID, ipAddress, operation, entityType, status, query)
+	docJSON, _ := json.Marshal(doc)
+	if desc {
+		err = collection.Find(ctx, doc).Sort("-created_at").Skip(offset).Limit(limit).All(&operationLogList)
+	} else {
+		err = collection.Find(ctx, doc).Skip(offset).Limit(limit).All(&operationLogList)
+	}
+
+	if err != nil {
+		o.core.Logger.Error(
+			"OperationLogDaoImpl.GetOperationLogList",
+			zap.Error(err), zap.ByteString(config.OperationLogCollectionName, docJSON),
+		)
+		return nil, nil, err
+	}
+	count, err := collection.Find(ctx, doc).Count()
+	if err != nil {
+		o.core.Logger.Error(
+			"OperationLogDaoImpl.GetOperationLogList",
+			zap.Error(err), zap.ByteString(config.OperationLogCollectionName, docJSON),
+		)
+		return nil, nil, err
+	}
+	o.core.Logger.Info(
+		"OperationLogDaoImpl.GetOperationLogList",
+		zap.Int64("count", count), zap.ByteString(config.OperationLogCollectionName, docJSON),
+	)
+	return operationLogList, &count, nil
+}
+
+// operationLogFilter returns the filter matching the operation logs of GetOperationLogList
+func operationLogFilter(
+	startTime, endTime *time.Time, userID, entityID *primitive.ObjectID,
+	ipAddress, operation, entityType, status, query *string,
+) bson.M {
 	doc := bson.M{}
 	if startTime != nil && endTime != nil {
 		doc["created_at"] = bson.M{"$gte": startTime, "$lte": endTime}
@@ -129,41 +193,50 @@ func (o *OperationLogDaoImpl) GetOperationLogList(
 			{"email": bson.M{"$regex": primitive.Regex{Pattern: pattern, Options: "i"}}},
 		}
 	}
-	docJSON, _ := json.Marshal(doc)
+	return doc
+}
+
+// IterateOperationLogList calls fn with every operation log matching the filters of GetOperationLogList, reading them
+// from a cursor instead of loading them at once
+func (o *OperationLogDaoImpl) IterateOperationLogList(
+	ctx context.Context,
+	desc bool, startTime, endTime *time.Time, userID, entityID *primitive.ObjectID,
+	ipAddress, operation, entityType, status, query *string,
+	fn func(operationLog *entity.OperationLogModel) error,
+) error {
+	collection := o.core.Mongo.Client().Database(o.core.Mongo.DatabaseName).Collection(config.OperationLogCollectionName)
+	doc := operationLogFilter(startTime, endTime, userID, entityID, ipAddress, operation, entityType, status, query)
+	sort := "created_at"
 	if desc {
-		err = collection.Find(ctx, doc).Sort("-created_at").Skip(offset).Limit(limit).All(&operationLogList)
-	} else {
-		err = collection.Find(ctx, doc).Skip(offset).Limit(limit).All(&operationLogList)
+		sort = "-created_at"
 	}
-
-	if err != nil {
-		o.core.Logger.Error(
-			"OperationLogDaoImpl.GetOperationLogList",
-			zap.Error(err), zap.ByteString(config.OperationLogCollectionName, docJSON),
-		)
-		return nil, nil, err
+	cursor := collection.Find(ctx, doc).Sort(sort).Cursor()
+	defer func() {
+		_ = cursor.Close()
+	}()
+	var operationLog entity.OperationLogModel
+	for cursor.Next(&operationLog) {
+		if err := fn(&operationLog); err != nil {
+			return err
+		}
+		operationLog = entity.OperationLogModel{}
 	}
-	count, err := collection.Find(ctx, doc).Count()
-	if err != nil {
+	if err := cursor.Err(); err != nil {
+		docJSON, _ := json.Marshal(doc)
 		o.core.Logger.Error(
-			"OperationLogDaoImpl.GetOperationLogList",
+			"OperationLogDaoImpl.IterateOperationLogList: failed to iterate operation logs",
 			zap.Error(err), zap.ByteString(config.OperationLogCollectionName, docJSON),
 		)
-		return nil, nil, err
+		return err
 	}
-	o.core.Logger.Info(
-		"OperationLogDaoImpl.GetOperationLogList",
-		zap.Int64("count", count), zap.ByteString(config.OperationLogCollectionName, docJSON),
-	)
-	return operationLogList, &count, nil
+	return nil
 }
 
 func (o *OperationLogDaoImpl) InsertOperationLog(
 	ctx context.Context,
 	userID, entityID primitive.ObjectID,
-	ipAddress, userAgent, operation, entityType, description, status string,
+	ipAddress, userAgent, requestID, operation, entityType, description, status string,
 ) (primitive.ObjectID, error) {
-	collection := o.core.Mongo.MongoClient.Database(o.core.Mongo.DatabaseName).Collection(config.OperationLogCollectionName)
 	user, err := o.userDao.GetUserByID(ctx, userID)
 	if err != nil {
 		o.core.Logger.Error(
@@ -172,43 +245,48 @@ func (o *OperationLogDaoImpl) InsertOperationLog(
 		)
 		return primitive.NilObjectID, err
 	}
-	doc := bson.M{
-		"user_id":     userID,
-		"entity_id":   entityID,
-		"username":    user.Username,
-		"email":       user.Email,
-		"ip_address":  ipAddress,
-		"user_agent":  userAgent,
-		"operation":   operation,
-		"entity_type": entityType,
-		"description": description,
-		"status":      status,
-		"created_at":  time.Now(),
+	operationLog := &entity.OperationLogModel{
+		OperationLogID: primitive.NewObjectID(),
+		UserID:         userID,
+		Username:       user.Username,
+		Email:          user.Email,
+		IPAddress:      ipAddress,
+		UserAgent:      userAgent,
+		RequestID:      requestID,
+		Operation:      operation,
+		EntityID:       entityID,
+		EntityType:     entityType,
+		Description:    description,
+		Status:         status,
+		// Mongo stores milliseconds, so truncate to hash the time as it will be read back.
+		CreatedAt: time.Now().UTC().Truncate(time.Millisecond),
 	}
-	docJSON, _ := json.Marshal(doc)
-	result, err := collection.InsertOne(ctx, doc)
+	docJSON, _ := json.Marshal(operationLog)
+	_, err = o.insertChained(ctx, []*entity.OperationLogModel{operationLog})
 	if err != nil {
 		o.core.Logger.Error(
 			"OperationLogDaoImpl.InsertOperationLog: failed to insert operation log",
 			zap.ByteString("doc", docJSON), zap.Error(err),
 		)
-	} else {
-		o.core.Logger.Info(
-			"OperationLogDaoImpl.InsertOperationLog: success",
-			zap.ByteString("doc", docJSON), zap.String("operationLogID", result.InsertedID.(primitive.ObjectID).Hex()),
-		)
+		return primitive.NilObjectID, err
 	}
-	return result.InsertedID.(primitive.ObjectID), err
+	o.core.Logger.Info(
+		"OperationLogDaoImpl.InsertOperationLog: success",
+		zap.ByteString("doc", docJSON), zap.String("operationLogID", operationLog.OperationLogID.Hex()),
+		zap.Int64("sequence", operationLog.Sequence),
+	)
+	return operationLog.OperationLogID, nil
 }
 
 func (o *OperationLogDaoImpl) CacheOperationLog(
 	ctx context.Context, userID, entityID primitive.ObjectID,
-	ipAddress, userAgent, operation, entityType, description, status string,
+	ipAddress, userAgent, requestID, operation, entityType, description, status string,
 ) error {
 	operationLog := entity.OperationLogCache{
 		UserIDHex:   userID.Hex(),
 		IPAddress:   ipAddress,
 		UserAgent:   userAgent,
+		RequestID:   requestID,
 		Operation:   operation,
 		EntityIDHex: entityID.Hex(),
 		EntityType:  entityType,
@@ -224,62 +302,110 @@ func (o *OperationLogDaoImpl) CacheOperationLog(
 		)
 		return err
 	}
-	return o.cache.RightPush(ctx, config.OperationLogCacheKey, string(operationLogJSON))
+	return o.queue.Push(ctx, string(operationLogJSON))
 }
 
+// SyncOperationLog syncs the queued operation logs from cache to database in batches
 func (o *OperationLogDaoImpl) SyncOperationLog(ctx context.Context) {
-	for {
-		operationLogJSON, err := o.cache.LeftPop(ctx, config.OperationLogCacheKey)
+	if err := o.queue.Consume(ctx, o.insertQueuedOperationLogs); err != nil {
+		o.core.Logger.Error("OperationLogDaoImpl.SyncOperationLog: failed to consume operation logs", zap.Error(err))
+		return
+	}
+	o.core.Logger.Info("OperationLogDaoImpl.SyncOperationLog: success")
+}
+
+// GetOperationLogQueueStats returns the operation logs queued in cache and not synced yet
+func (o *OperationLogDaoImpl) GetOperationLogQueueStats(ctx context.Context) (*dao.LogQueueStats, error) {
+	stats, err := o.queue.Stats(ctx)
+	if err != nil {
+		o.core.Logger.Error(
+			"OperationLogDaoImpl.GetOperationLogQueueStats: failed to get queue stats", zap.Error(err),
+		)
+		return nil, err
+	}
+	return stats, nil
+}
+
+// insertQueuedOperationLogs chains and inserts a batch of queued operation logs and returns the error of every one
+// not inserted.
+func (o *OperationLogDaoImpl) insertQueuedOperationLogs(
+	ctx context.Context, entries []dao.LogQueueEntry,
+) map[string]error {
+	failures := make(map[string]error)
+	users := make(map[primitive.ObjectID]*entity.UserModel)
+	inserted := make([]dao.LogQueueEntry, 0, len(entries))
+	operationLogs := make([]*entity.OperationLogModel, 0, len(entries))
+	for _, entry := range entries {
+		operationLogID, err := entry.ObjectID()
 		if err != nil {
-			if errors.Is(err, dao.CacheNil{}) {
-				break
-			}
-			o.core.Logger.Error(
-				"OperationLogDaoImpl.SyncOperationLog: failed to left pop operation log",
-				zap.Error(err),
-			)
+			failures[entry.ID] = fmt.Errorf("%w: %v", dao.ErrLogQueueEntryInvalid, err)
+			continue
 		}
 		var operationLog entity.OperationLogCache
-		err = json.Unmarshal([]byte(*operationLogJSON), &operationLog)
-		if err != nil {
-			o.core.Logger.Error(
-				"OperationLogDaoImpl.SyncOperationLog: failed to unmarshal operation log",
-				zap.Error(err), zap.String("operationLogJSON", *operationLogJSON),
-			)
+		if err := json.Unmarshal([]byte(entry.Value), &operationLog); err != nil {
+			failures[entry.ID] = fmt.Errorf("%w: %v", dao.ErrLogQueueEntryInvalid, err)
 			continue
 		}
 		userID, err := primitive.ObjectIDFromHex(operationLog.UserIDHex)
 		if err != nil {
-			o.core.Logger.Error(
-				"OperationLogDaoImpl.SyncOperationLog: failed to get user ID",
-				zap.Error(err), zap.String("operationLogJSON", *operationLogJSON),
-			)
+			failures[entry.ID] = fmt.Errorf("%w: %v", dao.ErrLogQueueEntryInvalid, err)
 			continue
 		}
 		entityID, err := primitive.ObjectIDFromHex(operationLog.EntityIDHex)
 		if err != nil {
-			o.core.Logger.Error(
-				"OperationLogDaoImpl.SyncOperationLog: failed to get entity ID",
-				zap.Error(err), zap.String("operationLogJSON", *operationLogJSON),
-			)
+			failures[entry.ID] = fmt.Errorf("%w: %v", dao.ErrLogQueueEntryInvalid, err)
 			continue
 		}
-		_, err = o.InsertOperationLog(
-			ctx, userID, entityID,
-			operationLog.IPAddress, operationLog.UserAgent,
-			operationLog.Operation, operationLog.EntityType, operationLog.Description, operationLog.Status,
+		user, ok := users[userID]
+		if !ok {
+			if user, err = o.userDao.GetUserByID(ctx, userID); errors.Is(err, qmgo.ErrNoSuchDocuments) {
+				failures[entry.ID] = fmt.Errorf("%w: user %s not found", dao.ErrLogQueueEntryInvalid, userID.Hex())
+				continue
+			} else if err != nil {
+				failures[entry.ID] = err
+				continue
+			}
+			users[userID] = user
+		}
+		inserted = append(inserted, entry)
+		operationLogs = append(
+			operationLogs, &entity.OperationLogModel{
+				OperationLogID: operationLogID,
+				UserID:         userID,
+				Username:       user.Username,
+				Email:          user.Email,
+				IPAddress:      operationLog.IPAddress,
+				UserAgent:      operationLog.UserAgent,
+				RequestID:      operationLog.RequestID,
+				Operation:      operationLog.Operation,
+				EntityID:       entityID,
+				EntityType:     operationLog.EntityType,
+				Description:    operationLog.Description,
+				Status:         operationLog.Status,
+				// Mongo stores milliseconds, so truncate to hash the time as it will be read back.
+				CreatedAt: operationLog.CreatedAt.UTC().Truncate(time.Millisecond),
+			},
 		)
-		if err != nil {
-			o.core.Logger.Error(
-				"OperationLogDaoImpl.SyncOperationLog: failed to insert operation log",
-				zap.Error(err), zap.String("operationLogJSON", *operationLogJSON),
-			)
+	}
+	if len(operationLogs) > 0 {
+		stored, err := o.insertChained(ctx, operationLogs)
+		for i, operationLog := range operationLogs {
+			if !stored[operationLog.OperationLogID] {
+				failures[inserted[i].ID] = err
+			}
 		}
 	}
+	for id, err := range failures {
+		o.core.Logger.Error(
+			"OperationLogDaoImpl.SyncOperationLog: failed to insert operation log",
+			zap.Error(err), zap.String("entryID", id),
+		)
+	}
+	return failures
 }
 
 func (o *OperationLogDaoImpl) DeleteOperationLog(ctx context.Context, operationLogID primitive.ObjectID) error {
-	collection := o.core.Mongo.MongoClient.Database(o.core.Mongo.DatabaseName).Collection(config.OperationLogCollectionName)
+	collection := o.core.Mongo.Client().Database(o.core.Mongo.DatabaseName).Collection(config.OperationLogCollectionName)
 	err := collection.RemoveId(ctx, operationLogID)
 	if err != nil {
 		o.core.Logger.Error(
@@ -298,7 +424,7 @@ func (o *OperationLogDaoImpl) DeleteOperationLogList(
 	ctx context.Context, startTime, endTime *time.Time, userID, entityID *primitive.ObjectID,
 	ipAddress, operation, entityType, status *string,
 ) (*int64, error) {
-	collection := o.core.Mongo.MongoClient.Database(o.core.Mongo.DatabaseName).Collection(config.OperationLogCollectionName)
+	collection := o.core.Mongo.Client().Database(o.core.Mongo.DatabaseName).Collection(config.OperationLogCollectionName)
 	doc := bson.M{}
 	if startTime != nil && endTime != nil {
 		doc["created_at"] = bson.M{"$gte": startTime, "$lte": endTime}
@@ -338,3 +464,104 @@ func (o *OperationLogDaoImpl) DeleteOperationLogList(
 	}
 	return &result.DeletedCount, err
 }
+
+// IterateExpiredOperationLogList calls fn with every operation log created before endTime that can be removed without
+// breaking the hash chain, oldest first. Chained operation logs are only expired up to the last checkpoint covering
+// them, which anchors the chain once the operation logs before it are gone.
+func (o *OperationLogDaoImpl) IterateExpiredOperationLogList(
+	ctx context.Context, endTime time.Time, fn func(operationLog *entity.OperationLogModel) error,
+) error {
+	collection := o.core.Mongo.Client().Database(o.core.Mongo.DatabaseName).Collection(config.OperationLogCollectionName)
+	var sequence int64
+	var last entity.OperationLogModel
+	err := collection.Find(
+		ctx, bson.M{"sequence": bson.M{"$gt": 0}, "created_at": bson.M{"$lt": endTime}},
+	).Sort("-sequence").One(&last)
+	if err != nil && !errors.Is(err, qmgo.ErrNoSuchDocuments) {
+		o.core.Logger.Error(
+			"OperationLogDaoImpl.IterateExpiredOperationLogList: failed to find last expired operation log",
+			zap.Error(err),
+		)
+		return err
+	}
+	if err == nil {
+		checkpoints := o.core.Mongo.Client().Database(o.core.Mongo.DatabaseName).Collection(config.OperationLogCheckpointCollectionName)
+		var checkpoint entity.OperationLogCheckpointModel
+		err = checkpoints.Find(ctx, bson.M{"sequence": bson.M{"$lte": last.Sequence}}).Sort("-sequence").One(&checkpoint)
+		if err != nil && !errors.Is(err, qmgo.ErrNoSuchDocuments) {
+			o.core.Logger.Error(
+				"OperationLogDaoImpl.IterateExpiredOperationLogList: failed to find checkpoint", zap.Error(err),
+			)
+			return err
+		}
+		sequence = checkpoint.Sequence
+	}
+
+	filter := bson.M{
+		"$or": []bson.M{
+			// Records written before chaining was introduced have no sequence.
+			{"sequence": bson.M{"$exists": false}, "created_at": bson.M{"$lt": endTime}},
+			{"sequence": bson.M{"$gt": 0, "$lte": sequence}},
+		},
+	}
+	cursor := collection.Find(ctx, filter).Sort("created_at").Cursor()
+	defer func() {
+		_ = cursor.Close()
+	}()
+	var operationLog entity.OperationLogModel
+	for cursor.Next(&operationLog) {
+		if err := fn(&operationLog); err != nil {
+			return err
+		}
+		operationLog = entity.OperationLogModel{}
+	}
+	if err := cursor.Err(); err != nil {
+		o.core.Logger.Error(
+			"OperationLogDaoImpl.IterateExpiredOperationLogList: failed to iterate operation logs",
+			zap.Error(err), zap.Time("endTime", endTime), zap.Int64("sequence", sequence),
+		)
+		return err
+	}
+	return nil
+}
+
+func (o *OperationLogDaoImpl) DeleteOperationLogByIDList(
+	ctx context.Context, operationLogIDs []primitive.ObjectID,
+) (*int64, error) {
+	collection := o.core.Mongo.Client().Database(o.core.Mongo.DatabaseName).Collection(config.OperationLogCollectionName)
+	result, err := collection.RemoveAll(ctx, bson.M{"_id": bson.M{"$in": operationLogIDs}})
+	if err != nil {
+		o.core.Logger.Error(
+			"OperationLogDaoImpl.DeleteOperationLogByIDList: failed to delete operation logs",
+			zap.Error(err), zap.Int("count", len(operationLogIDs)),
+		)
+		return nil, err
+	}
+	o.core.Logger.Info(
+		"OperationLogDaoImpl.DeleteOperationLogByIDList: success", zap.Int64("count", result.DeletedCount),
+	)
+	return &result.DeletedCount, nil
+}
+
+// ImportOperationLogList inserts archived operation logs into a collection of their own, skipping the ones already
+// imported. They are kept apart from the operation logs, so neither the chain verification nor the retention sees
+// them.
+func (o *OperationLogDaoImpl) ImportOperationLogList(
+	ctx context.Context, operationLogs []entity.OperationLogModel,
+) (*int64, error) {
+	if len(operationLogs) == 0 {
+		var count int64
+		return &count, nil
+	}
+	collection := o.core.Mongo.Client().Database(o.core.Mongo.DatabaseName).Collection(config.OperationLogImportCollectionName)
+	count, err := insertManyIgnoringDuplicates(ctx, collection, operationLogs, len(operationLogs))
+	if err != nil {
+		o.core.Logger.Error(
+			"OperationLogDaoImpl.ImportOperationLogList: failed to insert operation logs",
+			zap.Error(err), zap.Int("count", len(operationLogs)),
+		)
+		return nil, err
+	}
+	o.core.Logger.Info("OperationLogDaoImpl.ImportOperationLogList: success", zap.Int64("count", count))
+	return &count, nil
+}
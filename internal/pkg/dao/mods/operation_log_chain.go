@@ -0,0 +1,258 @@
+package mods
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"fiber-admin/internal/pkg/config"
+	"fiber-admin/internal/pkg/domain/entity"
+	"fiber-admin/pkg/utils/crypt"
+	"github.com/goccy/go-json"
+	"github.com/qiniu/qmgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// operationLogChainHeadID is the ID of the single operation log chain head document.
+const operationLogChainHeadID = "head"
+
+// operationLogChainRetries bounds how often an insert is retried after losing the race for the next sequence.
+const operationLogChainRetries = 10
+
+// ErrCheckpointSecretMissing is returned when checkpoints are created or verified without a configured secret.
+var ErrCheckpointSecretMissing = errors.New("audit checkpoint secret is not configured")
+
+// OperationLogHash returns the hash chaining the operation log to the previous one. It covers every field of the
+// record except its ID and the hash itself, so changing any of them breaks the chain.
+func OperationLogHash(operationLog *entity.OperationLogModel) string {
+	// Marshalling a list keeps the field order fixed and the field boundaries unambiguous.
+	content, _ := json.Marshal(
+		[]any{
+			operationLog.Sequence, operationLog.PrevHash, operationLog.UserID.Hex(), operationLog.Username,
+			operationLog.Email, operationLog.IPAddress, operationLog.UserAgent, operationLog.RequestID,
+			operationLog.Operation, operationLog.EntityID.Hex(), operationLog.EntityType, operationLog.Description,
+			operationLog.Status, operationLog.CreatedAt.UTC().Format(time.RFC3339Nano),
+		},
+	)
+	return crypt.SHA256(string(content))
+}
+
+// OperationLogCheckpointSignature returns the signature of a checkpoint under the given secret.
+func OperationLogCheckpointSignature(secret string, checkpoint *entity.OperationLogCheckpointModel) string {
+	return crypt.HMACSHA256(
+		secret,
+		strconv.FormatInt(checkpoint.Sequence, 10)+":"+checkpoint.Hash+":"+
+			strconv.FormatInt(checkpoint.CreatedAt.UnixMilli(), 10),
+	)
+}
+
+// chainTip returns the sequence and hash the next chained record links to. Records newer than the head document win,
+// since the head is only advanced after the record is inserted and may lag behind a crashed or concurrent writer.
+func (o *OperationLogDaoImpl) chainTip(ctx context.Context) (int64, string, error) {
+	head, err := o.GetOperationLogChainHead(ctx)
+	if err != nil {
+		return 0, "", err
+	}
+	collection := o.core.Mongo.Client().Database(o.core.Mongo.DatabaseName).Collection(config.OperationLogCollectionName)
+	var last entity.OperationLogModel
+	err = collection.Find(ctx, bson.M{"sequence": bson.M{"$gt": head.Sequence}}).Sort("-sequence").One(&last)
+	if err == nil {
+		return last.Sequence, last.Hash, nil
+	}
+	if !errors.Is(err, qmgo.ErrNoSuchDocuments) {
+		return 0, "", err
+	}
+	return head.Sequence, head.Hash, nil
+}
+
+// insertChained links the operation logs to the chain tip in order and inserts them, retrying the rest when another
+// writer took a sequence first. Operation logs already inserted under the same ID are skipped. It returns the IDs of
+// the operation logs that are stored, which are all of them unless an error is returned.
+func (o *OperationLogDaoImpl) insertChained(ctx context.Context, operationLogs []*entity.OperationLogModel) (
+	map[primitive.ObjectID]bool, error,
+) {
+	collection := o.core.Mongo.Client().Database(o.core.Mongo.DatabaseName).Collection(config.OperationLogCollectionName)
+	stored := make(map[primitive.ObjectID]bool, len(operationLogs))
+	remaining := operationLogs
+	for attempt := 0; attempt < operationLogChainRetries; attempt++ {
+		ids := make([]primitive.ObjectID, 0, len(remaining))
+		for _, operationLog := range remaining {
+			ids = append(ids, operationLog.OperationLogID)
+		}
+		var existing []entity.OperationLogModel
+		err := collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}}).Select(bson.M{"_id": 1}).All(&existing)
+		if err != nil {
+			return stored, err
+		}
+		for _, operationLog := range existing {
+			stored[operationLog.OperationLogID] = true
+		}
+		pending := make([]*entity.OperationLogModel, 0, len(remaining))
+		for _, operationLog := range remaining {
+			if !stored[operationLog.OperationLogID] {
+				pending = append(pending, operationLog)
+			}
+		}
+		if len(pending) == 0 {
+			return stored, nil
+		}
+
+		sequence, prevHash, err := o.chainTip(ctx)
+		if err != nil {
+			return stored, err
+		}
+		for _, operationLog := range pending {
+			sequence++
+			operationLog.Sequence = sequence
+			operationLog.PrevHash = prevHash
+			operationLog.Hash = OperationLogHash(operationLog)
+			prevHash = operationLog.Hash
+		}
+		// The insert is ordered, so everything before the first failing record is inserted.
+		_, err = collection.InsertMany(ctx, pending)
+		inserted := len(pending)
+		if err != nil {
+			var bulkErr mongo.BulkWriteException
+			if errors.As(err, &bulkErr) && len(bulkErr.WriteErrors) > 0 {
+				inserted = bulkErr.WriteErrors[0].Index
+			} else {
+				inserted = 0
+			}
+		}
+		for _, operationLog := range pending[:inserted] {
+			stored[operationLog.OperationLogID] = true
+		}
+		if inserted > 0 {
+			last := pending[inserted-1]
+			if err := o.advanceChainHead(ctx, last.Sequence, last.Hash); err != nil {
+				return stored, err
+			}
+		}
+		if err == nil {
+			return stored, nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return stored, err
+		}
+		remaining = pending[inserted:]
+	}
+	return stored, fmt.Errorf("failed to chain operation logs after %d attempts", operationLogChainRetries)
+}
+
+// advanceChainHead moves the chain head forward to the given record, leaving it alone if it is already further.
+func (o *OperationLogDaoImpl) advanceChainHead(ctx context.Context, sequence int64, hash string) error {
+	collection := o.core.Mongo.Client().Database(o.core.Mongo.DatabaseName).Collection(config.OperationLogChainCollectionName)
+	_, err := collection.Upsert(
+		ctx,
+		bson.M{"_id": operationLogChainHeadID, "sequence": bson.M{"$lt": sequence}},
+		bson.M{"_id": operationLogChainHeadID, "sequence": sequence, "hash": hash},
+	)
+	// The upsert collides with the existing head when it is already at or past this record.
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
+
+func (o *OperationLogDaoImpl) GetOperationLogChainHead(ctx context.Context) (
+	*entity.OperationLogChainHeadModel, error,
+) {
+	collection := o.core.Mongo.Client().Database(o.core.Mongo.DatabaseName).Collection(config.OperationLogChainCollectionName)
+	var head entity.OperationLogChainHeadModel
+	err := collection.Find(ctx, bson.M{"_id": operationLogChainHeadID}).One(&head)
+	if errors.Is(err, qmgo.ErrNoSuchDocuments) {
+		return &entity.OperationLogChainHeadModel{ChainHeadID: operationLogChainHeadID}, nil
+	}
+	if err != nil {
+		o.core.Logger.Error("OperationLogDaoImpl.GetOperationLogChainHead: error", zap.Error(err))
+		return nil, err
+	}
+	return &head, nil
+}
+
+func (o *OperationLogDaoImpl) IterateOperationLogChain(
+	ctx context.Context, fn func(operationLog *entity.OperationLogModel) error,
+) error {
+	collection := o.core.Mongo.Client().Database(o.core.Mongo.DatabaseName).Collection(config.OperationLogCollectionName)
+	// Records written before chaining was introduced have no sequence and are not part of the chain.
+	cursor := collection.Find(ctx, bson.M{"sequence": bson.M{"$gt": 0}}).Sort("sequence").Cursor()
+	defer func() {
+		_ = cursor.Close()
+	}()
+	var operationLog entity.OperationLogModel
+	for cursor.Next(&operationLog) {
+		if err := fn(&operationLog); err != nil {
+			return err
+		}
+		operationLog = entity.OperationLogModel{}
+	}
+	if err := cursor.Err(); err != nil {
+		o.core.Logger.Error("OperationLogDaoImpl.IterateOperationLogChain: error", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (o *OperationLogDaoImpl) InsertOperationLogCheckpoint(ctx context.Context) (
+	*entity.OperationLogCheckpointModel, error,
+) {
+	secret := o.core.Config.AuditConfig.CheckpointSecret
+	if secret == "" {
+		return nil, ErrCheckpointSecretMissing
+	}
+	sequence, hash, err := o.chainTip(ctx)
+	if err != nil {
+		o.core.Logger.Error("OperationLogDaoImpl.InsertOperationLogCheckpoint: failed to get chain tip", zap.Error(err))
+		return nil, err
+	}
+	if sequence == 0 {
+		return nil, nil
+	}
+	collection := o.core.Mongo.Client().Database(o.core.Mongo.DatabaseName).Collection(config.OperationLogCheckpointCollectionName)
+	var last entity.OperationLogCheckpointModel
+	err = collection.Find(ctx, bson.M{}).Sort("-sequence").One(&last)
+	if err == nil && last.Sequence == sequence {
+		return nil, nil // Nothing has been logged since the last checkpoint
+	}
+	if err != nil && !errors.Is(err, qmgo.ErrNoSuchDocuments) {
+		o.core.Logger.Error("OperationLogDaoImpl.InsertOperationLogCheckpoint: failed to get checkpoint", zap.Error(err))
+		return nil, err
+	}
+
+	checkpoint := &entity.OperationLogCheckpointModel{
+		CheckpointID: primitive.NewObjectID(),
+		Sequence:     sequence,
+		Hash:         hash,
+		CreatedAt:    time.Now().UTC().Truncate(time.Millisecond),
+	}
+	checkpoint.Signature = OperationLogCheckpointSignature(secret, checkpoint)
+	if _, err = collection.InsertOne(ctx, checkpoint); err != nil {
+		o.core.Logger.Error(
+			"OperationLogDaoImpl.InsertOperationLogCheckpoint: failed to insert checkpoint",
+			zap.Error(err), zap.Int64("sequence", sequence),
+		)
+		return nil, err
+	}
+	o.core.Logger.Info(
+		"OperationLogDaoImpl.InsertOperationLogCheckpoint: success",
+		zap.Int64("sequence", sequence), zap.String("hash", hash),
+	)
+	return checkpoint, nil
+}
+
+func (o *OperationLogDaoImpl) GetOperationLogCheckpointList(ctx context.Context) (
+	[]entity.OperationLogCheckpointModel, error,
+) {
+	collection := o.core.Mongo.Client().Database(o.core.Mongo.DatabaseName).Collection(config.OperationLogCheckpointCollectionName)
+	var checkpointList []entity.OperationLogCheckpointModel
+	if err := collection.Find(ctx, bson.M{}).Sort("sequence").All(&checkpointList); err != nil {
+		o.core.Logger.Error("OperationLogDaoImpl.GetOperationLogCheckpointList: error", zap.Error(err))
+		return nil, err
+	}
+	return checkpointList, nil
+}
@@ -0,0 +1,121 @@
+package mods
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"fiber-admin/internal/pkg/config"
+	"fiber-admin/internal/pkg/dao"
+	"fiber-admin/internal/pkg/domain/entity"
+	"github.com/qiniu/qmgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+)
+
+// rateLimitOverrideCacheTTL is how long an override is cached. Changes invalidate the cache, so it may be long.
+const rateLimitOverrideCacheTTL = time.Hour
+
+// RateLimitOverrideDao stores the rate limit overrides of users, under the ID of the user. Overrides are read through
+// the cache, which every change invalidates.
+type RateLimitOverrideDao interface {
+	// GetRateLimitOverride returns the override of the user, or mongo.ErrNoDocuments when the user has none.
+	GetRateLimitOverride(ctx context.Context, userID string) (*entity.RateLimitOverride, error)
+	GetRateLimitOverrideList(ctx context.Context) ([]entity.RateLimitOverride, error)
+	UpsertRateLimitOverride(ctx context.Context, override *entity.RateLimitOverride) error
+	// DeleteRateLimitOverride deletes the override of the user, if any.
+	DeleteRateLimitOverride(ctx context.Context, userID string) error
+}
+
+type RateLimitOverrideDaoImpl struct {
+	core  *dao.Core
+	cache *dao.Cache
+}
+
+func NewRateLimitOverrideDao(core *dao.Core, cache *dao.Cache) RateLimitOverrideDao {
+	var _ RateLimitOverrideDao = (*RateLimitOverrideDaoImpl)(nil) // Ensure that the interface is implemented
+	return &RateLimitOverrideDaoImpl{core: core, cache: cache}
+}
+
+func (r *RateLimitOverrideDaoImpl) GetRateLimitOverride(
+	ctx context.Context, userID string,
+) (*entity.RateLimitOverride, error) {
+	key := r.cache.Key(ctx, config.RateLimitOverrideCachePrefix, "userID:%s", userID)
+	return dao.GetOrLoad(
+		ctx, r.cache, r.core.Logger, config.CacheEntityRateLimitOverride, key, rateLimitOverrideCacheTTL,
+		func(ctx context.Context) (*entity.RateLimitOverride, error) {
+			var override entity.RateLimitOverride
+			coll := r.core.Mongo.Client().Database(r.core.Mongo.DatabaseName).Collection(
+				config.RateLimitOverrideCollectionName,
+			)
+			if err := coll.Find(ctx, bson.M{"_id": userID}).One(&override); err != nil {
+				if !errors.Is(err, qmgo.ErrNoSuchDocuments) {
+					r.core.Logger.Error(
+						"RateLimitOverrideDaoImpl.GetRateLimitOverride: failed to find override", zap.Error(err),
+						zap.String("userID", userID),
+					)
+				}
+				return nil, err
+			}
+			r.core.Logger.Info("RateLimitOverrideDaoImpl.GetRateLimitOverride: success", zap.String("userID", userID))
+			return &override, nil
+		},
+	)
+}
+
+func (r *RateLimitOverrideDaoImpl) GetRateLimitOverrideList(ctx context.Context) ([]entity.RateLimitOverride, error) {
+	overrideList := make([]entity.RateLimitOverride, 0)
+	coll := r.core.Mongo.Client().Database(r.core.Mongo.DatabaseName).Collection(config.RateLimitOverrideCollectionName)
+	if err := coll.Find(ctx, bson.M{}).Sort("_id").All(&overrideList); err != nil {
+		r.core.Logger.Error("RateLimitOverrideDaoImpl.GetRateLimitOverrideList: failed to find overrides", zap.Error(err))
+		return nil, err
+	}
+	r.core.Logger.Info(
+		"RateLimitOverrideDaoImpl.GetRateLimitOverrideList: success", zap.Int("count", len(overrideList)),
+	)
+	return overrideList, nil
+}
+
+func (r *RateLimitOverrideDaoImpl) UpsertRateLimitOverride(
+	ctx context.Context, override *entity.RateLimitOverride,
+) error {
+	coll := r.core.Mongo.Client().Database(r.core.Mongo.DatabaseName).Collection(config.RateLimitOverrideCollectionName)
+	if _, err := coll.UpsertId(ctx, override.UserID, override); err != nil {
+		r.core.Logger.Error(
+			"RateLimitOverrideDaoImpl.UpsertRateLimitOverride: failed to upsert override", zap.Error(err),
+			zap.String("userID", override.UserID),
+		)
+		return err
+	}
+	r.core.Logger.Info(
+		"RateLimitOverrideDaoImpl.UpsertRateLimitOverride: success", zap.String("userID", override.UserID),
+	)
+	if err := r.cache.Invalidate(ctx, config.RateLimitOverrideCachePrefix); err != nil {
+		r.core.Logger.Error(
+			"RateLimitOverrideDaoImpl.UpsertRateLimitOverride: failed to invalidate cache", zap.Error(err),
+		)
+	} else {
+		r.core.Logger.Info("RateLimitOverrideDaoImpl.UpsertRateLimitOverride: cache invalidated")
+	}
+	return nil
+}
+
+func (r *RateLimitOverrideDaoImpl) DeleteRateLimitOverride(ctx context.Context, userID string) error {
+	coll := r.core.Mongo.Client().Database(r.core.Mongo.DatabaseName).Collection(config.RateLimitOverrideCollectionName)
+	if err := coll.RemoveId(ctx, userID); err != nil && !errors.Is(err, qmgo.ErrNoSuchDocuments) {
+		r.core.Logger.Error(
+			"RateLimitOverrideDaoImpl.DeleteRateLimitOverride: failed to delete override", zap.Error(err),
+			zap.String("userID", userID),
+		)
+		return err
+	}
+	r.core.Logger.Info("RateLimitOverrideDaoImpl.DeleteRateLimitOverride: success", zap.String("userID", userID))
+	if err := r.cache.Invalidate(ctx, config.RateLimitOverrideCachePrefix); err != nil {
+		r.core.Logger.Error(
+			"RateLimitOverrideDaoImpl.DeleteRateLimitOverride: failed to invalidate cache", zap.Error(err),
+		)
+	} else {
+		r.core.Logger.Info("RateLimitOverrideDaoImpl.DeleteRateLimitOverride: cache invalidated")
+	}
+	return nil
+}
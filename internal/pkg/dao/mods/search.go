@@ -0,0 +1,63 @@
+package mods
+
+import (
+	"context"
+
+	"github.com/qiniu/qmgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	opt "go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// textIndexName is the name of the full-text index on the title and content of searchable collections
+const textIndexName = "title_content_text"
+
+// createTextIndex ensures the weighted full-text index on title and content. qmgo's IndexModel cannot express text
+// indexes, so the index is created through the underlying driver collection.
+func createTextIndex(ctx context.Context, coll *qmgo.Collection, defaultLanguage string) error {
+	collection, err := coll.CloneCollection()
+	if err != nil {
+		return err
+	}
+	_, err = collection.Indexes().CreateOne(
+		ctx, mongo.IndexModel{
+			Keys: bson.D{{Key: "title", Value: "text"}, {Key: "content", Value: "text"}},
+			Options: opt.Index().
+				SetName(textIndexName).
+				SetWeights(bson.D{{Key: "title", Value: 5}, {Key: "content", Value: 1}}).
+				SetDefaultLanguage(defaultLanguage),
+		},
+	)
+	return err
+}
+
+// textSearchPipeline builds an aggregation that matches query against the text index, ranks the matches by textScore
+// and returns a single document holding the total count and the requested page.
+func textSearchPipeline(query string, offset, limit int64) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"$text": bson.M{"$search": query}}}},
+		{{Key: "$addFields", Value: bson.M{"score": bson.M{"$meta": "textScore"}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "score", Value: -1}, {Key: "_id", Value: -1}}}},
+		{{
+			Key: "$facet", Value: bson.M{
+				"total": bson.A{bson.M{"$count": "count"}},
+				"list":  bson.A{bson.M{"$skip": offset}, bson.M{"$limit": limit}},
+			},
+		}},
+	}
+}
+
+// textSearchResult is the single document produced by textSearchPipeline
+type textSearchResult[T any] struct {
+	Total []struct {
+		Count int64 `bson:"count"`
+	} `bson:"total"`
+	List []T `bson:"list"`
+}
+
+func (r *textSearchResult[T]) count() int64 {
+	if len(r.Total) == 0 {
+		return 0
+	}
+	return r.Total[0].Count
+}
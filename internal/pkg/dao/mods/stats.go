@@ -0,0 +1,184 @@
+package mods
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"fiber-admin/internal/pkg/config"
+	"fiber-admin/internal/pkg/dao"
+	"fiber-admin/internal/pkg/domain/entity"
+	"github.com/goccy/go-json"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// StatsDao aggregates statistics for the admin dashboard. Results are cached briefly, since dashboards poll them and
+// the aggregations scan whole collections. Time series are bucketed with $dateTrunc, which needs MongoDB 5.0.
+type StatsDao interface {
+	CountUserByRole(ctx context.Context) ([]entity.CountModel, error)
+	CountUserByOrganization(ctx context.Context) ([]entity.CountModel, error)
+	CountNewUser(ctx context.Context, startTime, endTime time.Time, bucket string) ([]entity.BucketCountModel, error)
+	CountActiveUser(ctx context.Context, startTime, endTime time.Time, bucket string) ([]entity.BucketCountModel, error)
+	CountOperation(
+		ctx context.Context, startTime, endTime time.Time, bucket string,
+	) ([]entity.OperationCountModel, error)
+	CountNoticeByType(ctx context.Context) ([]entity.CountModel, error)
+	CountDocumentationByCategory(ctx context.Context) ([]entity.CountModel, error)
+}
+
+type StatsDaoImpl struct {
+	core  *dao.Core
+	cache *dao.Cache
+}
+
+func NewStatsDao(core *dao.Core, cache *dao.Cache) StatsDao {
+	var _ StatsDao = (*StatsDaoImpl)(nil) // Ensure that the interface is implemented
+	return &StatsDaoImpl{core: core, cache: cache}
+}
+
+func (s *StatsDaoImpl) CountUserByRole(ctx context.Context) ([]entity.CountModel, error) {
+	return aggregateStats[entity.CountModel](
+		ctx, s, "CountUserByRole", config.UserCollectionName, "user:role",
+		countByPipeline(bson.M{"deleted": bson.M{"$ne": true}}, "$role"),
+	)
+}
+
+func (s *StatsDaoImpl) CountUserByOrganization(ctx context.Context) ([]entity.CountModel, error) {
+	return aggregateStats[entity.CountModel](
+		ctx, s, "CountUserByOrganization", config.UserCollectionName, "user:organization",
+		countByPipeline(bson.M{"deleted": bson.M{"$ne": true}}, "$organization"),
+	)
+}
+
+// CountNewUser counts the users created in each time bucket
+func (s *StatsDaoImpl) CountNewUser(
+	ctx context.Context, startTime, endTime time.Time, bucket string,
+) ([]entity.BucketCountModel, error) {
+	return aggregateStats[entity.BucketCountModel](
+		ctx, s, "CountNewUser", config.UserCollectionName, seriesKey("user:new", startTime, endTime, bucket),
+		mongo.Pipeline{
+			{{Key: "$match", Value: bson.M{
+				"deleted":    bson.M{"$ne": true},
+				"created_at": bson.M{"$gte": startTime, "$lt": endTime},
+			}}},
+			{{Key: "$group", Value: bson.M{"_id": s.dateTrunc(bucket), "count": bson.M{"$sum": 1}}}},
+			{{Key: "$project", Value: bson.M{"_id": 0, "bucket": "$_id", "count": 1}}},
+			{{Key: "$sort", Value: bson.M{"bucket": 1}}},
+		},
+	)
+}
+
+// CountActiveUser counts the distinct users who logged in in each time bucket
+func (s *StatsDaoImpl) CountActiveUser(
+	ctx context.Context, startTime, endTime time.Time, bucket string,
+) ([]entity.BucketCountModel, error) {
+	return aggregateStats[entity.BucketCountModel](
+		ctx, s, "CountActiveUser", config.LoginLogCollectionName,
+		seriesKey("user:active", startTime, endTime, bucket),
+		mongo.Pipeline{
+			{{Key: "$match", Value: bson.M{"created_at": bson.M{"$gte": startTime, "$lt": endTime}}}},
+			{{Key: "$group", Value: bson.M{"_id": bson.M{"bucket": s.dateTrunc(bucket), "user_id": "$user_id"}}}},
+			{{Key: "$group", Value: bson.M{"_id": "$_id.bucket", "count": bson.M{"$sum": 1}}}},
+			{{Key: "$project", Value: bson.M{"_id": 0, "bucket": "$_id", "count": 1}}},
+			{{Key: "$sort", Value: bson.M{"bucket": 1}}},
+		},
+	)
+}
+
+// CountOperation counts the operation logs of each operation and status in each time bucket
+func (s *StatsDaoImpl) CountOperation(
+	ctx context.Context, startTime, endTime time.Time, bucket string,
+) ([]entity.OperationCountModel, error) {
+	return aggregateStats[entity.OperationCountModel](
+		ctx, s, "CountOperation", config.OperationLogCollectionName,
+		seriesKey("operation", startTime, endTime, bucket),
+		mongo.Pipeline{
+			{{Key: "$match", Value: bson.M{"created_at": bson.M{"$gte": startTime, "$lt": endTime}}}},
+			{{Key: "$group", Value: bson.M{
+				"_id": bson.M{
+					"bucket": s.dateTrunc(bucket), "operation": "$operation", "status": "$status",
+				},
+				"count": bson.M{"$sum": 1},
+			}}},
+			{{Key: "$project", Value: bson.M{
+				"_id": 0, "bucket": "$_id.bucket", "operation": "$_id.operation", "status": "$_id.status", "count": 1,
+			}}},
+			{{Key: "$sort", Value: bson.D{{Key: "bucket", Value: 1}, {Key: "operation", Value: 1}, {Key: "status", Value: 1}}}},
+		},
+	)
+}
+
+func (s *StatsDaoImpl) CountNoticeByType(ctx context.Context) ([]entity.CountModel, error) {
+	return aggregateStats[entity.CountModel](
+		ctx, s, "CountNoticeByType", config.NoticeCollectionName, "notice:type",
+		countByPipeline(bson.M{}, "$notice_type"),
+	)
+}
+
+func (s *StatsDaoImpl) CountDocumentationByCategory(ctx context.Context) ([]entity.CountModel, error) {
+	return aggregateStats[entity.CountModel](
+		ctx, s, "CountDocumentationByCategory", config.DocumentationCollectionName, "documentation:category",
+		countByPipeline(bson.M{}, "$category"),
+	)
+}
+
+// dateTrunc returns the expression truncating created_at to the start of its time bucket. Weeks start on Monday.
+func (s *StatsDaoImpl) dateTrunc(bucket string) bson.M {
+	return bson.M{
+		"$dateTrunc": bson.M{
+			"date": "$created_at", "unit": bucket, "timezone": s.core.Config.StatsConfig.Timezone,
+			"startOfWeek": "monday",
+		},
+	}
+}
+
+// countByPipeline counts the documents matching match grouped by field, largest groups first
+func countByPipeline(match bson.M, field string) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{"_id": bson.M{"$ifNull": bson.A{field, ""}}, "count": bson.M{"$sum": 1}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+	}
+}
+
+// seriesKey returns the cache key of a time series
+func seriesKey(name string, startTime, endTime time.Time, bucket string) string {
+	return fmt.Sprintf("%s:%s:%d:%d", name, bucket, startTime.Unix(), endTime.Unix())
+}
+
+// aggregateStats runs the pipeline on the collection unless its result is cached under key
+func aggregateStats[T any](
+	ctx context.Context, s *StatsDaoImpl, method, collection, key string, pipeline mongo.Pipeline,
+) ([]T, error) {
+	key = fmt.Sprintf("%s:%s", config.StatsCachePrefix, key)
+	var result []T
+	cache, err := s.cache.Get(ctx, key)
+	if errors.Is(err, dao.CacheNil{}) {
+		s.core.Logger.Info(fmt.Sprintf("StatsDaoImpl.%s: cache miss", method), zap.String("key", key))
+	} else if err != nil {
+		s.core.Logger.Error(fmt.Sprintf("StatsDaoImpl.%s: failed to get cache", method), zap.Error(err), zap.String("key", key))
+	} else if err = json.Unmarshal([]byte(*cache), &result); err != nil {
+		s.core.Logger.Error(
+			fmt.Sprintf("StatsDaoImpl.%s: failed to unmarshal cache", method), zap.Error(err), zap.String("key", key),
+		)
+	} else {
+		s.core.Logger.Info(fmt.Sprintf("StatsDaoImpl.%s: cache hit", method), zap.String("key", key))
+		return result, nil
+	}
+
+	coll := s.core.Mongo.Client().Database(s.core.Mongo.DatabaseName).Collection(collection)
+	result = make([]T, 0)
+	if err = coll.Aggregate(ctx, pipeline).All(&result); err != nil {
+		s.core.Logger.Error(fmt.Sprintf("StatsDaoImpl.%s: failed to aggregate", method), zap.Error(err))
+		return nil, err
+	}
+	resultJSON, _ := json.Marshal(result)
+	if err := s.cache.Set(ctx, key, string(resultJSON), &s.core.Config.CacheConfig.StatsCacheTTL); err != nil {
+		s.core.Logger.Error(fmt.Sprintf("StatsDaoImpl.%s: failed to set cache", method), zap.Error(err), zap.String("key", key))
+	}
+	s.core.Logger.Info(fmt.Sprintf("StatsDaoImpl.%s: success", method), zap.Int("count", len(result)))
+	return result, nil
+}
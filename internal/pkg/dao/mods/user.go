@@ -35,7 +35,8 @@ type UserDao interface {
 	) (*int64, error)
 	InsertUser(ctx context.Context, username, email, password, role, organization string) (primitive.ObjectID, error)
 	UpdateUser(
-		ctx context.Context, userID primitive.ObjectID, username, email, password, role, organization *string,
+		ctx context.Context, userID primitive.ObjectID, version *int64, username, email, password, role,
+		organization *string,
 	) error
 	UpdateUserLastLogin(ctx context.Context, userID primitive.ObjectID) error
 	SoftDeleteUser(ctx context.Context, userID primitive.ObjectID) error
@@ -43,7 +44,7 @@ type UserDao interface {
 		ctx context.Context, organization, role *string,
 		createStartTime, createEndTime, updateStartTime, updateEndTime, lastLoginStartTime, lastLoginEndTime *time.Time,
 	) (*int64, error)
-	DeleteUser(ctx context.Context, userID primitive.ObjectID) error
+	DeleteUser(ctx context.Context, userID primitive.ObjectID, version *int64) error
 	DeleteUserList(
 		ctx context.Context, organization, role *string,
 		createStartTime, createEndTime, updateStartTime, updateEndTime, lastLoginStartTime, lastLoginEndTime *time.Time,
@@ -57,7 +58,7 @@ type UserDaoImpl struct {
 
 func NewUserDao(ctx context.Context, core *dao.Core, cache *dao.Cache) (UserDao, error) {
 	var _ UserDao = (*UserDaoImpl)(nil)
-	coll := core.Mongo.MongoClient.Database(core.Mongo.DatabaseName).Collection(config.UserCollectionName)
+	coll := core.Mongo.Client().Database(core.Mongo.DatabaseName).Collection(config.UserCollectionName)
 	if err := coll.CreateIndexes(
 		ctx, []options.IndexModel{
 			{
@@ -81,125 +82,50 @@ func NewUserDao(ctx context.Context, core *dao.Core, cache *dao.Cache) (UserDao,
 }
 
 func (u *UserDaoImpl) GetUserByID(ctx context.Context, userID primitive.ObjectID) (*entity.UserModel, error) {
-	var user entity.UserModel
-	key := fmt.Sprintf("%s:userID:%s", config.UserCachePrefix, userID.Hex())
-	cache, err := u.Cache.Get(ctx, key)
-	if errors.Is(err, dao.CacheNil{}) {
-		u.Core.Logger.Info("UserDaoImpl.GetUserByID: cache miss", zap.String("key", key))
-	} else if err != nil {
-		u.Core.Logger.Error("UserDaoImpl.GetUserByID: cache get failed", zap.Error(err), zap.String("key", key))
-	} else {
-		err := json.Unmarshal([]byte(*cache), &user)
-		if err != nil {
-			u.Core.Logger.Error(
-				"UserDaoImpl.GetUserByID: failed to unmarshal cache", zap.Error(err), zap.String("key", key),
-			)
-		} else {
-			u.Core.Logger.Info("UserDaoImpl.GetUserByID: cache hit", zap.String("key", key))
-			return &user, nil
-		}
-	}
-	coll := u.Core.Mongo.MongoClient.Database(u.Core.Mongo.DatabaseName).Collection(config.UserCollectionName)
-	if err := coll.Find(
-		ctx, bson.M{"_id": userID, "deleted": false},
-	).One(&user); err != nil {
-		u.Core.Logger.Error(
-			"UserDaoImpl.GetUserByID: failed to find user", zap.Error(err), zap.String("userID", userID.Hex()),
-		)
-		return nil, err
-	} else {
-		u.Core.Logger.Info("UserDaoImpl.GetUserByID: success", zap.String("userID", userID.Hex()))
-		userJSON, _ := json.Marshal(user)
-		if err := u.Cache.Set(ctx, key, string(userJSON), &u.Core.Config.CacheConfig.UserCacheTTL); err != nil {
-			u.Core.Logger.Error("UserDaoImpl.GetUserByID: cache set failed", zap.Error(err), zap.String("key", key))
-		} else {
-			u.Core.Logger.Info("UserDaoImpl.GetUserByID: cache set success", zap.String("key", key))
-		}
-		return &user, nil
-	}
+	key := u.Cache.Key(ctx, config.UserCachePrefix, "userID:%s", userID.Hex())
+	return dao.GetOrLoad(
+		ctx, u.Cache, u.Core.Logger, config.CacheEntityUser, key, u.Core.Config.CacheConfig.UserCacheTTL,
+		func(ctx context.Context) (*entity.UserModel, error) {
+			return u.findUser(ctx, "UserDaoImpl.GetUserByID", bson.M{"_id": userID}, zap.String("userID", userID.Hex()))
+		},
+	)
 }
 
 func (u *UserDaoImpl) GetUserByEmail(ctx context.Context, email string) (*entity.UserModel, error) {
-	var user entity.UserModel
-	key := fmt.Sprintf("%s:email:%s", config.UserCachePrefix, email)
-	cache, err := u.Cache.Get(ctx, key)
-	if errors.Is(err, dao.CacheNil{}) {
-		u.Core.Logger.Info("UserDaoImpl.GetUserByEmail: cache miss", zap.String("key", key))
-	} else if err != nil {
-		u.Core.Logger.Error("UserDaoImpl.GetUserByEmail: cache get failed", zap.Error(err), zap.String("key", key))
-	} else {
-		if err := json.Unmarshal([]byte(*cache), &user); err != nil {
-			u.Core.Logger.Error(
-				"UserDaoImpl.GetUserByEmail: failed to unmarshal cache", zap.Error(err), zap.String("key", key),
-			)
-		} else {
-			u.Core.Logger.Info("UserDaoImpl.GetUserByEmail: cache hit", zap.String("key", key))
-			return &user, nil
-		}
-	}
-	coll := u.Core.Mongo.MongoClient.Database(u.Core.Mongo.DatabaseName).Collection(config.UserCollectionName)
-	if err := coll.Find(
-		ctx, bson.M{"email": email, "deleted": false},
-	).One(&user); err != nil {
-		u.Core.Logger.Error(
-			"UserDaoImpl.GetUserByEmail: failed to find user", zap.Error(err), zap.String("email", email),
-		)
-		return nil, err
-	} else {
-		u.Core.Logger.Info(
-			"UserDaoImpl.GetUserByEmail: success",
-			zap.String("email", email),
-		)
-		userJSON, _ := json.Marshal(user)
-		if err := u.Cache.Set(ctx, key, string(userJSON), &u.Core.Config.CacheConfig.UserCacheTTL); err != nil {
-			u.Core.Logger.Error("UserDaoImpl.GetUserByEmail: cache set failed", zap.Error(err), zap.String("key", key))
-		} else {
-			u.Core.Logger.Info("UserDaoImpl.GetUserByEmail: cache set success", zap.String("key", key))
-		}
-		return &user, nil
-	}
+	key := u.Cache.Key(ctx, config.UserCachePrefix, "email:%s", email)
+	return dao.GetOrLoad(
+		ctx, u.Cache, u.Core.Logger, config.CacheEntityUser, key, u.Core.Config.CacheConfig.UserCacheTTL,
+		func(ctx context.Context) (*entity.UserModel, error) {
+			return u.findUser(ctx, "UserDaoImpl.GetUserByEmail", bson.M{"email": email}, zap.String("email", email))
+		},
+	)
 }
 
 func (u *UserDaoImpl) GetUserByUsername(ctx context.Context, username string) (*entity.UserModel, error) {
-	var user entity.UserModel
-	key := fmt.Sprintf("%s:username:%s", config.UserCachePrefix, username)
-	cache, err := u.Cache.Get(ctx, key)
-	if errors.Is(err, dao.CacheNil{}) {
-		u.Core.Logger.Info("UserDaoImpl.GetUserByUsername: cache miss", zap.String("key", key))
-	} else if err != nil {
-		u.Core.Logger.Error(
-			"UserDaoImpl.GetUserByUsername: cache get failed", zap.Error(err), zap.String("key", key),
-		)
-	} else {
-		if err := json.Unmarshal([]byte(*cache), &user); err != nil {
-			u.Core.Logger.Error(
-				"UserDaoImpl.GetUserByUsername: failed to unmarshal cache", zap.Error(err), zap.String("key", key),
+	key := u.Cache.Key(ctx, config.UserCachePrefix, "username:%s", username)
+	return dao.GetOrLoad(
+		ctx, u.Cache, u.Core.Logger, config.CacheEntityUser, key, u.Core.Config.CacheConfig.UserCacheTTL,
+		func(ctx context.Context) (*entity.UserModel, error) {
+			return u.findUser(
+				ctx, "UserDaoImpl.GetUserByUsername", bson.M{"username": username}, zap.String("username", username),
 			)
-		} else {
-			u.Core.Logger.Info("UserDaoImpl.GetUserByUsername: cache hit", zap.String("key", key))
-			return &user, nil
-		}
-	}
-	coll := u.Core.Mongo.MongoClient.Database(u.Core.Mongo.DatabaseName).Collection(config.UserCollectionName)
-	if err := coll.Find(
-		ctx, bson.M{"username": username, "deleted": false},
-	).One(&user); err != nil {
-		u.Core.Logger.Error(
-			"UserDaoImpl.GetUserByUsername: failed to find user", zap.Error(err), zap.String("username", username),
-		)
+		},
+	)
+}
+
+// findUser finds the user which is not deleted matching filter, logging as method with field.
+func (u *UserDaoImpl) findUser(
+	ctx context.Context, method string, filter bson.M, field zap.Field,
+) (*entity.UserModel, error) {
+	var user entity.UserModel
+	filter["deleted"] = false
+	coll := u.Core.Mongo.Client().Database(u.Core.Mongo.DatabaseName).Collection(config.UserCollectionName)
+	if err := coll.Find(ctx, filter).One(&user); err != nil {
+		u.Core.Logger.Error(method+": failed to find user", zap.Error(err), field)
 		return nil, err
-	} else {
-		u.Core.Logger.Info("UserDaoImpl.GetUserByUsername: success", zap.String("username", username))
-		userJSON, _ := json.Marshal(user)
-		if err := u.Cache.Set(ctx, key, string(userJSON), &u.Core.Config.CacheConfig.UserCacheTTL); err != nil {
-			u.Core.Logger.Error(
-				"UserDaoImpl.GetUserByUsername: cache set failed", zap.Error(err), zap.String("key", key),
-			)
-		} else {
-			u.Core.Logger.Info("UserDaoImpl.GetUserByUsername: cache set success", zap.String("key", key))
-		}
-		return &user, nil
 	}
+	u.Core.Logger.Info(method+": success", field)
+	return &user, nil
 }
 
 func (u *UserDaoImpl) GetUserList(
@@ -213,7 +139,7 @@ func (u *UserDaoImpl) GetUserList(
 		err      error
 	)
 	doc := bson.M{"deleted": false}
-	key := fmt.Sprintf("%s:offset:%d:limit:%d", config.UserCachePrefix, offset, limit) // for caching
+	key := u.Cache.Key(ctx, config.UserCachePrefix, "offset:%d:limit:%d", offset, limit) // for caching
 	if organization != nil {
 		doc["organization"] = *organization
 		key += fmt.Sprintf(":organization:%s", *organization)
@@ -280,13 +206,14 @@ func (u *UserDaoImpl) GetUserList(
 					CreatedAt:    user.CreatedAt,
 					UpdatedAt:    user.UpdatedAt,
 					DeletedAt:    user.DeletedAt,
+					Version:      user.Version,
 				},
 			)
 		}
 		return userList, &cache.Total, nil
 	}
 
-	coll := u.Core.Mongo.MongoClient.Database(u.Core.Mongo.DatabaseName).Collection(config.UserCollectionName)
+	coll := u.Core.Mongo.Client().Database(u.Core.Mongo.DatabaseName).Collection(config.UserCollectionName)
 	cursor := coll.Find(ctx, doc)
 	count, err := cursor.Count()
 	if err != nil {
@@ -329,6 +256,7 @@ func (u *UserDaoImpl) GetUserList(
 				CreatedAt:    user.CreatedAt,
 				UpdatedAt:    user.UpdatedAt,
 				DeletedAt:    user.DeletedAt,
+				Version:      user.Version,
 			},
 		)
 	}
@@ -354,7 +282,7 @@ func (u *UserDaoImpl) CountUser(
 	createStartTime, createEndTime, updateStartTime, updateEndTime, lastLoginStartTime, lastLoginEndTime *time.Time,
 ) (*int64, error) {
 	doc := bson.M{"deleted": false}
-	key := fmt.Sprintf("%s:count", config.UserCachePrefix)
+	key := u.Cache.Key(ctx, config.UserCachePrefix, "count")
 	if organization != nil {
 		doc["organization"] = *organization
 		key += fmt.Sprintf(":organization:%s", *organization)
@@ -392,7 +320,7 @@ func (u *UserDaoImpl) CountUser(
 			return &count, nil
 		}
 	}
-	coll := u.Core.Mongo.MongoClient.Database(u.Core.Mongo.DatabaseName).Collection(config.UserCollectionName)
+	coll := u.Core.Mongo.Client().Database(u.Core.Mongo.DatabaseName).Collection(config.UserCollectionName)
 	count, err := coll.Find(ctx, doc).Count()
 	if err != nil {
 		u.Core.Logger.Error(
@@ -412,7 +340,7 @@ func (u *UserDaoImpl) InsertUser(
 	ctx context.Context,
 	username, email, password, role, organization string,
 ) (primitive.ObjectID, error) {
-	coll := u.Core.Mongo.MongoClient.Database(u.Core.Mongo.DatabaseName).Collection(config.UserCollectionName)
+	coll := u.Core.Mongo.Client().Database(u.Core.Mongo.DatabaseName).Collection(config.UserCollectionName)
 	doc := bson.M{
 		"username":     username,
 		"email":        email,
@@ -424,6 +352,7 @@ func (u *UserDaoImpl) InsertUser(
 		"created_at":   time.Now(),
 		"updated_at":   time.Now(),
 		"deleted_at":   nil,
+		"version":      int64(1),
 	}
 	docJSON, _ := json.Marshal(doc)
 	result, err := coll.InsertOne(ctx, doc)
@@ -438,20 +367,21 @@ func (u *UserDaoImpl) InsertUser(
 			zap.String("userID", result.InsertedID.(primitive.ObjectID).Hex()),
 			zap.ByteString(config.UserCollectionName, docJSON),
 		)
-		prefix := config.UserCachePrefix
-		if err = u.Cache.Flush(ctx, &prefix); err != nil {
-			u.Core.Logger.Error("UserDaoImpl.InsertUser: failed to flush cache", zap.Error(err))
+		if err = u.Cache.Invalidate(ctx, config.UserCachePrefix); err != nil {
+			u.Core.Logger.Error("UserDaoImpl.InsertUser: failed to invalidate cache", zap.Error(err))
 		} else {
-			u.Core.Logger.Info("UserDaoImpl.InsertUser: cache flushed")
+			u.Core.Logger.Info("UserDaoImpl.InsertUser: cache invalidated")
 		}
 		return result.InsertedID.(primitive.ObjectID), nil
 	}
 }
 
+// UpdateUser updates the user, only if it still has the given version unless version is nil.
 func (u *UserDaoImpl) UpdateUser(
-	ctx context.Context, userID primitive.ObjectID, username, email, password, role, organization *string,
+	ctx context.Context, userID primitive.ObjectID, version *int64, username, email, password, role,
+	organization *string,
 ) error {
-	coll := u.Core.Mongo.MongoClient.Database(u.Core.Mongo.DatabaseName).Collection(config.UserCollectionName)
+	coll := u.Core.Mongo.Client().Database(u.Core.Mongo.DatabaseName).Collection(config.UserCollectionName)
 	doc := bson.M{"updated_at": time.Now()}
 	if username != nil {
 		doc["username"] = *username
@@ -469,7 +399,8 @@ func (u *UserDaoImpl) UpdateUser(
 		doc["organization"] = *organization
 	}
 	docJSON, _ := json.Marshal(doc)
-	if err := coll.UpdateId(ctx, userID, bson.M{"$set": doc}); err != nil {
+	err := coll.UpdateOne(ctx, versionFilter(userID, version), versionUpdate(doc))
+	if err = versionConflict(ctx, coll, userID, version, err); err != nil {
 		u.Core.Logger.Error(
 			"UserDaoImpl.UpdateUser: failed",
 			zap.Error(err), zap.String("userID", userID.Hex()), zap.ByteString(config.UserCollectionName, docJSON),
@@ -480,20 +411,19 @@ func (u *UserDaoImpl) UpdateUser(
 		"UserDaoImpl.UpdateUser: success",
 		zap.String("userID", userID.Hex()), zap.ByteString(config.UserCollectionName, docJSON),
 	)
-	prefix := config.UserCachePrefix
-	if err := u.Cache.Flush(ctx, &prefix); err != nil {
-		u.Core.Logger.Error("UserDaoImpl.UpdateUser: failed to flush cache", zap.Error(err))
+	if err := u.Cache.Invalidate(ctx, config.UserCachePrefix); err != nil {
+		u.Core.Logger.Error("UserDaoImpl.UpdateUser: failed to invalidate cache", zap.Error(err))
 	} else {
-		u.Core.Logger.Info("UserDaoImpl.UpdateUser: cache flushed")
+		u.Core.Logger.Info("UserDaoImpl.UpdateUser: cache invalidated")
 	}
 	return nil
 }
 
 func (u *UserDaoImpl) UpdateUserLastLogin(ctx context.Context, userID primitive.ObjectID) error {
-	coll := u.Core.Mongo.MongoClient.Database(u.Core.Mongo.DatabaseName).Collection(config.UserCollectionName)
+	coll := u.Core.Mongo.Client().Database(u.Core.Mongo.DatabaseName).Collection(config.UserCollectionName)
 	doc := bson.M{"last_login": time.Now()}
 	docJSON, _ := json.Marshal(doc)
-	if err := coll.UpdateId(ctx, userID, bson.M{"$set": doc}); err != nil {
+	if err := coll.UpdateId(ctx, userID, versionUpdate(doc)); err != nil {
 		u.Core.Logger.Error(
 			"UserDaoImpl.UpdateUserLastLogin: failed",
 			zap.Error(err), zap.String("userID", userID.Hex()), zap.ByteString(config.UserCollectionName, docJSON),
@@ -504,29 +434,27 @@ func (u *UserDaoImpl) UpdateUserLastLogin(ctx context.Context, userID primitive.
 		"UserDaoImpl.UpdateUserLastLogin: success",
 		zap.String("userID", userID.Hex()), zap.ByteString(config.UserCollectionName, docJSON),
 	)
-	prefix := config.UserCachePrefix
-	if err := u.Cache.Flush(ctx, &prefix); err != nil {
-		u.Core.Logger.Error("UserDaoImpl.UpdateUserLastLogin: failed to flush cache", zap.Error(err))
+	if err := u.Cache.Invalidate(ctx, config.UserCachePrefix); err != nil {
+		u.Core.Logger.Error("UserDaoImpl.UpdateUserLastLogin: failed to invalidate cache", zap.Error(err))
 	} else {
-		u.Core.Logger.Info("UserDaoImpl.UpdateUserLastLogin: cache flushed")
+		u.Core.Logger.Info("UserDaoImpl.UpdateUserLastLogin: cache invalidated")
 	}
 	return nil
 }
 
 func (u *UserDaoImpl) SoftDeleteUser(ctx context.Context, userID primitive.ObjectID) error {
-	coll := u.Core.Mongo.MongoClient.Database(u.Core.Mongo.DatabaseName).Collection(config.UserCollectionName)
+	coll := u.Core.Mongo.Client().Database(u.Core.Mongo.DatabaseName).Collection(config.UserCollectionName)
 	if err := coll.UpdateId(
-		ctx, userID, bson.M{"$set": bson.M{"deleted": true, "deleted_at": time.Now()}},
+		ctx, userID, versionUpdate(bson.M{"deleted": true, "deleted_at": time.Now()}),
 	); err != nil {
 		u.Core.Logger.Error("UserDaoImpl.DeleteUser", zap.Error(err), zap.String("userID", userID.Hex()))
 		return err
 	}
 	u.Core.Logger.Info("UserDaoImpl.DeleteUser", zap.String("userID", userID.Hex()))
-	prefix := config.UserCachePrefix
-	if err := u.Cache.Flush(ctx, &prefix); err != nil {
-		u.Core.Logger.Error("UserDaoImpl.SoftDeleteUser: failed to flush cache", zap.Error(err))
+	if err := u.Cache.Invalidate(ctx, config.UserCachePrefix); err != nil {
+		u.Core.Logger.Error("UserDaoImpl.SoftDeleteUser: failed to invalidate cache", zap.Error(err))
 	} else {
-		u.Core.Logger.Info("UserDaoImpl.SoftDeleteUser: cache flushed")
+		u.Core.Logger.Info("UserDaoImpl.SoftDeleteUser: cache invalidated")
 	}
 	return nil
 }
@@ -535,7 +463,7 @@ func (u *UserDaoImpl) SoftDeleteUserList(
 	ctx context.Context, organization, role *string,
 	createStartTime, createEndTime, updateStartTime, updateEndTime, lastLoginStartTime, lastLoginEndTime *time.Time,
 ) (*int64, error) {
-	coll := u.Core.Mongo.MongoClient.Database(u.Core.Mongo.DatabaseName).Collection(config.UserCollectionName)
+	coll := u.Core.Mongo.Client().Database(u.Core.Mongo.DatabaseName).Collection(config.UserCollectionName)
 	doc := bson.M{"deleted": false}
 	if organization != nil {
 		doc["organization"] = *organization
@@ -553,7 +481,7 @@ func (u *UserDaoImpl) SoftDeleteUserList(
 		doc["last_login"] = bson.M{"$gte": lastLoginStartTime, "$lte": lastLoginEndTime}
 	}
 	docJSON, _ := json.Marshal(doc)
-	result, err := coll.UpdateAll(ctx, doc, bson.M{"$set": bson.M{"deleted": true, "deleted_at": time.Now()}})
+	result, err := coll.UpdateAll(ctx, doc, versionUpdate(bson.M{"deleted": true, "deleted_at": time.Now()}))
 	if err != nil {
 		u.Core.Logger.Error(
 			"UserDaoImpl.DeleteUserList: failed", zap.Error(err), zap.ByteString(config.UserCollectionName, docJSON),
@@ -563,28 +491,28 @@ func (u *UserDaoImpl) SoftDeleteUserList(
 			"UserDaoImpl.DeleteUserList: success",
 			zap.Int64("count", result.ModifiedCount), zap.ByteString(config.UserCollectionName, docJSON),
 		)
-		prefix := config.UserCachePrefix
-		if err = u.Cache.Flush(ctx, &prefix); err != nil {
-			u.Core.Logger.Error("UserDaoImpl.SoftDeleteUserList: failed to flush cache", zap.Error(err))
+		if err = u.Cache.Invalidate(ctx, config.UserCachePrefix); err != nil {
+			u.Core.Logger.Error("UserDaoImpl.SoftDeleteUserList: failed to invalidate cache", zap.Error(err))
 		} else {
-			u.Core.Logger.Info("UserDaoImpl.SoftDeleteUserList: cache flushed")
+			u.Core.Logger.Info("UserDaoImpl.SoftDeleteUserList: cache invalidated")
 		}
 	}
 	return &result.ModifiedCount, err
 }
 
-func (u *UserDaoImpl) DeleteUser(ctx context.Context, userID primitive.ObjectID) error {
-	coll := u.Core.Mongo.MongoClient.Database(u.Core.Mongo.DatabaseName).Collection(config.UserCollectionName)
-	if err := coll.RemoveId(ctx, userID); err != nil {
+// DeleteUser deletes the user, only if it still has the given version unless version is nil.
+func (u *UserDaoImpl) DeleteUser(ctx context.Context, userID primitive.ObjectID, version *int64) error {
+	coll := u.Core.Mongo.Client().Database(u.Core.Mongo.DatabaseName).Collection(config.UserCollectionName)
+	err := coll.Remove(ctx, versionFilter(userID, version))
+	if err = versionConflict(ctx, coll, userID, version, err); err != nil {
 		u.Core.Logger.Error("UserDaoImpl.DeleteUser: failed", zap.Error(err), zap.String("userID", userID.Hex()))
 		return err
 	}
 	u.Core.Logger.Info("UserDaoImpl.DeleteUser: success", zap.String("userID", userID.Hex()))
-	prefix := config.UserCachePrefix
-	if err := u.Cache.Flush(ctx, &prefix); err != nil {
-		u.Core.Logger.Error("UserDaoImpl.DeleteUser: failed to flush cache", zap.Error(err))
+	if err := u.Cache.Invalidate(ctx, config.UserCachePrefix); err != nil {
+		u.Core.Logger.Error("UserDaoImpl.DeleteUser: failed to invalidate cache", zap.Error(err))
 	} else {
-		u.Core.Logger.Info("UserDaoImpl.DeleteUser: cache flushed")
+		u.Core.Logger.Info("UserDaoImpl.DeleteUser: cache invalidated")
 	}
 	return nil
 }
@@ -593,7 +521,7 @@ func (u *UserDaoImpl) DeleteUserList(
 	ctx context.Context, organization, role *string,
 	createStartTime, createEndTime, updateStartTime, updateEndTime, lastLoginStartTime, lastLoginEndTime *time.Time,
 ) (*int64, error) {
-	coll := u.Core.Mongo.MongoClient.Database(u.Core.Mongo.DatabaseName).Collection(config.UserCollectionName)
+	coll := u.Core.Mongo.Client().Database(u.Core.Mongo.DatabaseName).Collection(config.UserCollectionName)
 	doc := bson.M{}
 	if organization != nil {
 		doc["organization"] = *organization
@@ -621,11 +549,10 @@ func (u *UserDaoImpl) DeleteUserList(
 			"UserDaoImpl.DeleteUserList: success",
 			zap.Int64("count", result.DeletedCount), zap.ByteString(config.UserCollectionName, docJSON),
 		)
-		prefix := config.UserCachePrefix
-		if err = u.Cache.Flush(ctx, &prefix); err != nil {
-			u.Core.Logger.Error("UserDaoImpl.DeleteUserList: failed to flush cache", zap.Error(err))
+		if err = u.Cache.Invalidate(ctx, config.UserCachePrefix); err != nil {
+			u.Core.Logger.Error("UserDaoImpl.DeleteUserList: failed to invalidate cache", zap.Error(err))
 		} else {
-			u.Core.Logger.Info("UserDaoImpl.DeleteUserList: cache flushed")
+			u.Core.Logger.Info("UserDaoImpl.DeleteUserList: cache invalidated")
 		}
 	}
 	return &result.DeletedCount, err
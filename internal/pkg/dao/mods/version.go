@@ -0,0 +1,45 @@
+package mods
+
+import (
+	"context"
+	"errors"
+
+	"github.com/qiniu/qmgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrVersionConflict is returned by a conditional write when the entity exists but no longer has the expected version.
+var ErrVersionConflict = errors.New("version conflict")
+
+// versionFilter matches the entity with the given ID, and also the expected version unless version is nil. Entities
+// written before versioning have no version field and count as version 0.
+func versionFilter(id primitive.ObjectID, version *int64) bson.M {
+	filter := bson.M{"_id": id}
+	if version != nil {
+		if *version == 0 {
+			filter["version"] = bson.M{"$in": bson.A{int64(0), nil}}
+		} else {
+			filter["version"] = *version
+		}
+	}
+	return filter
+}
+
+// versionUpdate adds the version increment to a $set update.
+func versionUpdate(doc bson.M) bson.M {
+	return bson.M{"$set": doc, "$inc": bson.M{"version": int64(1)}}
+}
+
+// versionConflict tells a version conflict apart from a missing entity after a conditional write matched nothing.
+func versionConflict(
+	ctx context.Context, coll *qmgo.Collection, id primitive.ObjectID, version *int64, err error,
+) error {
+	if version == nil || !errors.Is(err, qmgo.ErrNoSuchDocuments) {
+		return err
+	}
+	if count, countErr := coll.Find(ctx, bson.M{"_id": id}).Count(); countErr == nil && count > 0 {
+		return ErrVersionConflict
+	}
+	return err
+}
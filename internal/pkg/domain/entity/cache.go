@@ -26,6 +26,7 @@ type UserCache struct {
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 	DeletedAt    time.Time `json:"deleted_at"`
+	Version      int64     `json:"version"`
 }
 
 type NoticeCacheList struct {
@@ -40,6 +41,7 @@ type NoticeCache struct {
 	NoticeType string    `json:"notice_type"`
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
+	Version    int64     `json:"version"`
 }
 
 type DocumentationCacheList struct {
@@ -49,10 +51,16 @@ type DocumentationCacheList struct {
 
 type DocumentationCache struct {
 	DocumentID string    `json:"document_id"`
+	ParentID   string    `json:"parent_id"`
+	Position   int64     `json:"position"`
+	Slug       string    `json:"slug"`
 	Title      string    `json:"title"`
 	Content    string    `json:"content"`
+	Category   string    `json:"category"`
+	Tags       []string  `json:"tags"`
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
+	Version    int64     `json:"version"`
 }
 type LoginLogCache struct {
 	UserIDHex string    `json:"user_id_hex"` // User ID in Hex
@@ -65,9 +73,10 @@ type OperationLogCache struct {
 	UserIDHex   string    `json:"user_id_hex"`   // User ID in Hex
 	IPAddress   string    `json:"ip_address"`    // IP Address
 	UserAgent   string    `json:"user_agent"`    // User Agent
-	Operation   string    `json:"operation"`     // Operation, 'CREATE' | 'UPDATE' | 'DELETE'
+	RequestID   string    `json:"request_id"`    // Request ID
+	Operation   string    `json:"operation"`     // Operation, 'CREATE' | 'UPDATE' | 'DELETE' | 'READ' | 'EXPORT'
 	EntityIDHex string    `json:"entity_id_hex"` // Entity ID in Hex
-	EntityType  string    `json:"entity_type"`   // Entity noticeType, 'USER' | 'DOCUMENTATION' | 'NOTICE'
+	EntityType  string    `json:"entity_type"`   // Entity noticeType, 'USER' | 'DOCUMENTATION' | 'NOTICE' | 'LOGIN_LOG' | 'OPERATION_LOG'
 	Description string    `json:"description"`   // Description of Operation
 	Status      string    `json:"status"`        // Status, 'SUCCESS' | 'FAILURE'
 	CreatedAt   time.Time `json:"created_at"`    // Created Time in ISO 8601
@@ -7,9 +7,15 @@ import (
 )
 
 type DocumentationModel struct {
-	DocumentID primitive.ObjectID `json:"document_id" bson:"_id"`       // Mongo ObjectId
-	Title      string             `json:"title" bson:"title"`           // Title of the document
-	Content    string             `json:"content" bson:"content"`       // Content of the document
-	CreatedAt  time.Time          `json:"created_at" bson:"created_at"` // Create Time
-	UpdatedAt  time.Time          `json:"updated_at" bson:"updated_at"` // Update Time
+	DocumentID primitive.ObjectID  `json:"document_id" bson:"_id"`       // Mongo ObjectId
+	ParentID   *primitive.ObjectID `json:"parent_id" bson:"parent_id"`   // Parent document, nil for a top-level document
+	Position   int64               `json:"position" bson:"position"`     // Order among the siblings, ascending
+	Slug       string              `json:"slug" bson:"slug"`             // Unique URL-safe identifier of the document
+	Title      string              `json:"title" bson:"title"`           // Title of the document
+	Content    string              `json:"content" bson:"content"`       // Content of the document
+	Category   string              `json:"category" bson:"category"`     // Category of the document
+	Tags       []string            `json:"tags" bson:"tags"`             // Tags of the document
+	CreatedAt  time.Time           `json:"created_at" bson:"created_at"` // Create Time
+	UpdatedAt  time.Time           `json:"updated_at" bson:"updated_at"` // Update Time
+	Version    int64               `json:"version" bson:"version"`       // Version, incremented by every write
 }
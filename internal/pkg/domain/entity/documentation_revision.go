@@ -0,0 +1,18 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type DocumentationRevisionModel struct {
+	RevisionID primitive.ObjectID `json:"revision_id" bson:"_id"`         // Mongo ObjectId
+	DocumentID primitive.ObjectID `json:"document_id" bson:"document_id"` // Documentation ID
+	Revision   int64              `json:"revision" bson:"revision"`       // Revision number, starts from 1
+	Title      string             `json:"title" bson:"title"`             // Title snapshot
+	Content    string             `json:"content" bson:"content"`         // Content snapshot
+	AuthorID   primitive.ObjectID `json:"author_id" bson:"author_id"`     // Author (User ID)
+	Summary    string             `json:"summary" bson:"summary"`         // Change summary
+	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`   // Created Time in ISO 8601
+}
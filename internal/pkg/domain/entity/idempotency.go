@@ -0,0 +1,11 @@
+package entity
+
+// IdempotencyRecord is cached under an idempotency key: the fingerprint of the request which first used the key and,
+// once that request completed, its response, which is replayed to the requests reusing the key.
+type IdempotencyRecord struct {
+	Fingerprint string              `json:"fingerprint"`       // SHA-256 of the method, URL and body of the request
+	Completed   bool                `json:"completed"`         // False while the request is being processed
+	Status      int                 `json:"status,omitempty"`  // HTTP status of the response
+	Headers     map[string][]string `json:"headers,omitempty"` // Headers of the response, by name
+	Body        []byte              `json:"body,omitempty"`    // Body of the response
+}
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"time"
+)
+
+// LogTailEvent is a log entry relayed to the admins tailing the logs. Operation events mirror a new operation log,
+// request events the request log of LoggingMiddleware.
+type LogTailEvent struct {
+	Type        string    `json:"type"`                  // Event type, 'operation' | 'request'
+	Time        time.Time `json:"time"`                  // Time of the entry in ISO 8601
+	RequestID   string    `json:"request_id,omitempty"`  // Request ID
+	UserID      string    `json:"user_id,omitempty"`     // User ID, empty for anonymous requests
+	IPAddress   string    `json:"ip_address,omitempty"`  // IP Address
+	Method      string    `json:"method,omitempty"`      // HTTP method
+	Path        string    `json:"path,omitempty"`        // Request path
+	Operation   string    `json:"operation,omitempty"`   // Operation of operation events
+	EntityType  string    `json:"entity_type,omitempty"` // Entity type of operation events
+	EntityID    string    `json:"entity_id,omitempty"`   // Entity ID of operation events
+	Description string    `json:"description,omitempty"` // Description of operation events
+	Status      string    `json:"status"`                // Status, 'SUCCESS' | 'FAILURE' (a 4xx or 5xx response)
+	StatusCode  int       `json:"status_code,omitempty"` // HTTP status of request and operation events
+	Latency     float64   `json:"latency_ms,omitempty"`  // Latency of request events in milliseconds
+}
@@ -14,4 +14,18 @@ type LoginLogModel struct {
 	IPAddress  string             `json:"ip_address" bson:"ip_address"` // IP Address
 	UserAgent  string             `json:"user_agent" bson:"user_agent"` // User Agent
 	CreatedAt  time.Time          `json:"created_at" bson:"created_at"` // Created Time in ISO 8601
+
+	// Analytics derived from the IP address and user agent when the login is logged
+	Browser        string     `json:"browser,omitempty" bson:"browser,omitempty"`                 // Browser name
+	BrowserVersion string     `json:"browser_version,omitempty" bson:"browser_version,omitempty"` // Browser version
+	OS             string     `json:"os,omitempty" bson:"os,omitempty"`                           // Operating system name
+	OSVersion      string     `json:"os_version,omitempty" bson:"os_version,omitempty"`           // Operating system version
+	Device         string     `json:"device,omitempty" bson:"device,omitempty"`                   // desktop, mobile, tablet, bot or other
+	CountryCode    string     `json:"country_code,omitempty" bson:"country_code,omitempty"`       // ISO 3166-1 alpha-2 country code
+	Country        string     `json:"country,omitempty" bson:"country,omitempty"`                 // Country name
+	City           string     `json:"city,omitempty" bson:"city,omitempty"`                       // City name
+	Latitude       *float64   `json:"latitude,omitempty" bson:"latitude,omitempty"`               // Approximate latitude
+	Longitude      *float64   `json:"longitude,omitempty" bson:"longitude,omitempty"`             // Approximate longitude
+	Flags          []string   `json:"flags,omitempty" bson:"flags,omitempty"`                     // Anomaly flags, e.g. NEW_COUNTRY
+	NotifiedAt     *time.Time `json:"notified_at,omitempty" bson:"notified_at,omitempty"`         // When the user was notified of the flags
 }
@@ -13,4 +13,5 @@ type NoticeModel struct {
 	NoticeType string             `json:"notice_type" bson:"notice_type"` // NoticeType, 'URGENT' | 'NORMAL'
 	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`   // Created Time in ISO 8601
 	UpdatedAt  time.Time          `json:"updated_at" bson:"updated_at"`   // Updated Time in ISO 8601
+	Version    int64              `json:"version" bson:"version"`         // Version, incremented by every write
 }
@@ -13,10 +13,31 @@ type OperationLogModel struct {
 	Email          string             `json:"email" bson:"email"`             // Email (for space-time trade-off)
 	IPAddress      string             `json:"ip_address" bson:"ip_address"`   // IP Address
 	UserAgent      string             `json:"user_agent" bson:"user_agent"`   // User Agent
-	Operation      string             `json:"operation" bson:"operation"`     // Operation, 'CREATE' | 'UPDATE' | 'DELETE'
+	RequestID      string             `json:"request_id" bson:"request_id"`   // Request ID of the operation
+	Operation      string             `json:"operation" bson:"operation"`     // Operation, 'CREATE' | 'UPDATE' | 'DELETE' | 'READ' | 'EXPORT'
 	EntityID       primitive.ObjectID `json:"entity_id" bson:"entity_id"`     // Entity ID
-	EntityType     string             `json:"entity_type" bson:"entity_type"` // Entity noticeType, 'USER' | 'DOCUMENTATION' | 'NOTICE'
+	EntityType     string             `json:"entity_type" bson:"entity_type"` // Entity noticeType, 'USER' | 'DOCUMENTATION' | 'NOTICE' | 'LOGIN_LOG' | 'OPERATION_LOG'
 	Description    string             `json:"description" bson:"description"` // Description of Operation
 	Status         string             `json:"status" bson:"status"`           // Status, 'SUCCESS' | 'FAILURE'
 	CreatedAt      time.Time          `json:"created_at" bson:"created_at"`   // Created Time in ISO 8601
+	Sequence       int64              `json:"sequence" bson:"sequence"`       // Position in the hash chain, from 1
+	PrevHash       string             `json:"prev_hash" bson:"prev_hash"`     // Hash of the previous record in the chain
+	Hash           string             `json:"hash" bson:"hash"`               // SHA-256 of this record and PrevHash
+}
+
+// OperationLogChainHeadModel is the single document tracking the last record of the operation log hash chain, so that
+// removing records from the end of the chain can be detected.
+type OperationLogChainHeadModel struct {
+	ChainHeadID string `json:"chain_head_id" bson:"_id"` // Fixed ID of the head document
+	Sequence    int64  `json:"sequence" bson:"sequence"` // Sequence of the last chained record
+	Hash        string `json:"hash" bson:"hash"`         // Hash of the last chained record
+}
+
+// OperationLogCheckpointModel is a signed snapshot of the operation log hash chain head.
+type OperationLogCheckpointModel struct {
+	CheckpointID primitive.ObjectID `json:"checkpoint_id" bson:"_id"`     // Mongo ObjectId
+	Sequence     int64              `json:"sequence" bson:"sequence"`     // Sequence of the chained record
+	Hash         string             `json:"hash" bson:"hash"`             // Hash of the chained record
+	Signature    string             `json:"signature" bson:"signature"`   // HMAC-SHA256 of Sequence, Hash and CreatedAt
+	CreatedAt    time.Time          `json:"created_at" bson:"created_at"` // Created Time in ISO 8601
 }
@@ -0,0 +1,13 @@
+package entity
+
+import (
+	"time"
+)
+
+// RateLimitOverride is stored under the ID of a user, and replaces the limit of the user rules for that user.
+type RateLimitOverride struct {
+	UserID    string    `json:"user_id" bson:"_id"`           // User ID in Hex
+	Max       int       `json:"max" bson:"max"`               // Requests per window of each user rule, ignored when exempt
+	Exempt    bool      `json:"exempt" bson:"exempt"`         // Whether the user rules do not limit the user at all
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"` // Updated Time in ISO 8601
+}
@@ -0,0 +1,13 @@
+package entity
+
+// DocumentationSearchResult is a documentation matched by a full-text search together with its relevance score.
+type DocumentationSearchResult struct {
+	DocumentationModel `bson:",inline"`
+	Score              float64 `json:"score" bson:"score"` // Mongo textScore
+}
+
+// NoticeSearchResult is a notice matched by a full-text search together with its relevance score.
+type NoticeSearchResult struct {
+	NoticeModel `bson:",inline"`
+	Score       float64 `json:"score" bson:"score"` // Mongo textScore
+}
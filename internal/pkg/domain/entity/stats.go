@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"time"
+)
+
+// CountModel is the number of documents in a group of a statistics aggregation.
+type CountModel struct {
+	Key   string `json:"key" bson:"_id"`     // Value the documents are grouped by
+	Count int64  `json:"count" bson:"count"` // Number of documents
+}
+
+// BucketCountModel is the number of documents in a time bucket of a statistics aggregation.
+type BucketCountModel struct {
+	Bucket time.Time `json:"bucket" bson:"bucket"` // Start of the time bucket
+	Count  int64     `json:"count" bson:"count"`   // Number of documents
+}
+
+// OperationCountModel is the number of operation logs of an operation and status in a time bucket.
+type OperationCountModel struct {
+	Bucket    time.Time `json:"bucket" bson:"bucket"`       // Start of the time bucket
+	Operation string    `json:"operation" bson:"operation"` // Operation type
+	Status    string    `json:"status" bson:"status"`       // Operation status
+	Count     int64     `json:"count" bson:"count"`         // Number of operation logs
+}
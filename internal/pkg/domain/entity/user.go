@@ -18,4 +18,5 @@ type UserModel struct {
 	CreatedAt    time.Time          `json:"created_at" bson:"created_at"`     // Created Time in ISO 8601
 	UpdatedAt    time.Time          `json:"updated_at" bson:"updated_at"`     // Updated Time in ISO 8601
 	DeletedAt    time.Time          `json:"deleted_at" bson:"deleted_at"`     // Deleted Time in ISO 8601
+	Version      int64              `json:"version" bson:"version"`           // Version, incremented by every write
 }
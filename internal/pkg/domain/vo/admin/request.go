@@ -58,20 +58,64 @@ type (
 	}
 
 	InsertDocumentationRequest struct {
-		Title   *string `json:"title" validate:"required,max=100,min=1"`
-		Content *string `json:"content" validate:"required,max=10000,min=1"`
+		Title    *string  `json:"title" validate:"required,max=100,min=1"`
+		Content  *string  `json:"content" validate:"required,max=10000,min=1"`
+		Slug     *string  `json:"slug" validate:"omitnil,max=100,slug"`
+		ParentID *string  `json:"parent_id" validate:"omitnil,mongodb"`
+		Position *int64   `json:"position" validate:"omitnil,numeric,min=0"`
+		Category *string  `json:"category" validate:"omitnil,max=50"`
+		Tags     []string `json:"tags" validate:"omitempty,max=20,dive,min=1,max=30"`
 	}
 
 	UpdateDocumentationRequest struct {
+		DocumentationID *string  `json:"documentation_id" validate:"required,mongodb"`
+		Title           *string  `json:"title" validate:"omitnil,max=100,min=1"`
+		Content         *string  `json:"content" validate:"omitnil,max=10000,min=1"`
+		Slug            *string  `json:"slug" validate:"omitnil,max=100,slug"`
+		Category        *string  `json:"category" validate:"omitnil,max=50"`
+		Tags            []string `json:"tags" validate:"omitempty,max=20,dive,min=1,max=30"`
+		Summary         *string  `json:"summary" validate:"omitnil,max=200"`
+	}
+
+	MoveDocumentationRequest struct {
 		DocumentationID *string `json:"documentation_id" validate:"required,mongodb"`
-		Title           *string `json:"title" validate:"omitnil,max=100,min=1"`
-		Content         *string `json:"content" validate:"omitnil,max=10000,min=1"`
+		ParentID        *string `json:"parent_id" validate:"omitnil,mongodb"`
+		Position        *int64  `json:"position" validate:"required,numeric,min=0"`
+	}
+
+	ReorderDocumentationRequest struct {
+		ParentID            *string  `json:"parent_id" validate:"omitnil,mongodb"`
+		DocumentationIDList []string `json:"documentation_id_list" validate:"required,min=1,dive,mongodb"`
 	}
 
 	DeleteDocumentationRequest struct {
 		DocumentationID *string `query:"documentationID" validate:"required,mongodb"`
 	}
 
+	GetDocumentationRevisionRequest struct {
+		DocumentationID *string `query:"documentationID" validate:"required,mongodb"`
+		Revision        *int64  `query:"revision" validate:"required,numeric,min=1"`
+	}
+
+	GetDocumentationRevisionListRequest struct {
+		DocumentationID *string `query:"documentationID" validate:"required,mongodb"`
+		Page            *int64  `query:"page" validate:"required,numeric,min=1"`
+		PageSize        *int64  `query:"pageSize" validate:"required,numeric,min=1,max=100"`
+		Desc            *bool   `query:"desc" validate:"required"`
+	}
+
+	DiffDocumentationRevisionRequest struct {
+		DocumentationID *string `query:"documentationID" validate:"required,mongodb"`
+		FromRevision    *int64  `query:"fromRevision" validate:"required,numeric,min=1"`
+		ToRevision      *int64  `query:"toRevision" validate:"required,numeric,min=1"`
+	}
+
+	RestoreDocumentationRevisionRequest struct {
+		DocumentationID *string `json:"documentation_id" validate:"required,mongodb"`
+		Revision        *int64  `json:"revision" validate:"required,numeric,min=1"`
+		Summary         *string `json:"summary" validate:"omitnil,max=200"`
+	}
+
 	GetLoginLogListRequest struct {
 		Page            *int64  `query:"page" validate:"required,numeric,min=1"`
 		PageSize        *int64  `query:"pageSize" validate:"required,numeric,min=1,max=100"`
@@ -92,4 +136,60 @@ type (
 		CreateStartTime *string `query:"createStartTime" validate:"omitnil,rfc3339,earlierThan=CreateEndTime"`
 		CreateEndTime   *string `query:"createEndTime" validate:"omitnil,rfc3339"`
 	}
+
+	ExportLoginLogListRequest struct {
+		Format          *string `query:"format" validate:"required,exportFormat"`
+		Desc            *bool   `query:"desc" validate:"required"`
+		Query           *string `query:"query" validate:"omitnil,max=100"`
+		CreateStartTime *string `query:"createStartTime" validate:"omitnil,rfc3339,earlierThan=CreateEndTime"`
+		CreateEndTime   *string `query:"createEndTime" validate:"omitnil,rfc3339"`
+	}
+
+	ExportOperationLogListRequest struct {
+		Format          *string `query:"format" validate:"required,exportFormat"`
+		Desc            *bool   `query:"desc" validate:"required"`
+		Query           *string `query:"query" validate:"omitnil,max=100"`
+		Operation       *string `query:"operation" validate:"omitnil,operationType"`
+		EntityType      *string `query:"entityType" validate:"omitnil,entityType"`
+		Status          *string `query:"status" validate:"omitnil,operationStatus"`
+		CreateStartTime *string `query:"createStartTime" validate:"omitnil,rfc3339,earlierThan=CreateEndTime"`
+		CreateEndTime   *string `query:"createEndTime" validate:"omitnil,rfc3339"`
+	}
+
+	TailLogsRequest struct {
+		Requests   *bool   `query:"requests"` // Also tail the request log, false by default
+		UserID     *string `query:"userID" validate:"omitnil,mongodb"`
+		EntityType *string `query:"entityType" validate:"omitnil,entityType"`
+		Status     *string `query:"status" validate:"omitnil,operationStatus"`
+		Path       *string `query:"path" validate:"omitnil,startswith=/,max=200"` // Prefix of the request path
+	}
+
+	ImportLogArchiveRequest struct {
+		Name *string `json:"name" validate:"required,max=200"`
+	}
+
+	GetStatsSeriesRequest struct {
+		Bucket    *string `query:"bucket" validate:"omitnil,statsBucket"` // hour, day, week or month, day by default
+		StartTime *string `query:"startTime" validate:"omitnil,rfc3339,earlierThan=EndTime"`
+		EndTime   *string `query:"endTime" validate:"omitnil,rfc3339"`
+	}
+
+	PurgeCacheRequest struct {
+		Entity *string `query:"entity" validate:"required_without=Prefix,excluded_with=Prefix,omitnil,cacheEntity"`
+		Prefix *string `query:"prefix" validate:"omitnil,startswith=dao:,max=200"` // A prefix of the DAO caches
+	}
+
+	WarmCacheRequest struct {
+		Entities []string `json:"entities" validate:"omitempty,dive,oneof=notice documentation"` // Every one by default
+	}
+
+	SetRateLimitOverrideRequest struct {
+		UserID *string `json:"user_id" validate:"required,mongodb"`
+		Max    *int    `json:"max" validate:"required_unless=Exempt true,omitnil,min=1"` // Requests per window of each user rule
+		Exempt *bool   `json:"exempt"`                                                   // Not limited by the user rules, false by default
+	}
+
+	DeleteRateLimitOverrideRequest struct {
+		UserID *string `query:"userID" validate:"required,mongodb"`
+	}
 )
@@ -10,6 +10,7 @@ type (
 		LastLogin    string `json:"last_login"`
 		CreatedAt    string `json:"created_at"`
 		UpdatedAt    string `json:"updated_at"`
+		Version      int64  `json:"version"`
 	}
 
 	GetUserListResponse struct {
@@ -17,6 +18,43 @@ type (
 		UserList []*GetUserResponse `json:"user_list"`
 	}
 
+	GetDocumentationRevisionResponse struct {
+		DocumentID string `json:"document_id"`
+		Revision   int64  `json:"revision"`
+		Title      string `json:"title"`
+		Content    string `json:"content"`
+		AuthorID   string `json:"author_id"`
+		Summary    string `json:"summary"`
+		CreatedAt  string `json:"created_at"`
+	}
+
+	DocumentationRevisionSummary struct {
+		Revision  int64  `json:"revision"`
+		Title     string `json:"title"`
+		AuthorID  string `json:"author_id"`
+		Summary   string `json:"summary"`
+		CreatedAt string `json:"created_at"`
+	}
+
+	GetDocumentationRevisionListResponse struct {
+		Total                            int64                           `json:"total"`
+		DocumentationRevisionSummaryList []*DocumentationRevisionSummary `json:"documentation_revision_summary_list"`
+	}
+
+	DiffDocumentationRevisionResponse struct {
+		DocumentID   string `json:"document_id"`
+		FromRevision int64  `json:"from_revision"`
+		ToRevision   int64  `json:"to_revision"`
+		FromTitle    string `json:"from_title"`
+		ToTitle      string `json:"to_title"`
+		Diff         string `json:"diff"` // Unified diff of the content
+	}
+
+	RestoreDocumentationRevisionResponse struct {
+		DocumentID string `json:"document_id"`
+		Revision   int64  `json:"revision"` // The newly created revision
+	}
+
 	GetLoginLogResponse struct {
 		LoginLogID string `json:"login_log_id"`
 		UserID     string `json:"user_id"`
@@ -25,6 +63,13 @@ type (
 		IPAddress  string `json:"ip_address"`
 		UserAgent  string `json:"user_agent"`
 		CreatedAt  string `json:"created_at"`
+		Browser    string `json:"browser"` // Browser and version parsed from the user agent
+		OS         string `json:"os"`      // Operating system and version parsed from the user agent
+		Device     string `json:"device"`  // desktop, mobile, tablet, bot or other
+		Country    string `json:"country"` // Located from the IP address when a GeoIP database is configured
+		City       string `json:"city"`
+		// Flags of suspicious logins: NEW_COUNTRY, NEW_DEVICE or IMPOSSIBLE_TRAVEL
+		Flags []string `json:"flags"`
 	}
 
 	GetLoginLogListResponse struct {
@@ -39,6 +84,7 @@ type (
 		Email          string `json:"email"`
 		IPAddress      string `json:"ip_address"`
 		UserAgent      string `json:"user_agent"`
+		RequestID      string `json:"request_id"`
 		Operation      string `json:"operation"`
 		EntityID       string `json:"entity_id"`
 		EntityType     string `json:"entity_type"`
@@ -52,6 +98,39 @@ type (
 		OperationLogList []*GetOperationLogResponse `json:"operation_log_list"`
 	}
 
+	OperationLogBrokenLink struct {
+		Sequence       int64  `json:"sequence"`
+		OperationLogID string `json:"operation_log_id"`
+		Reason         string `json:"reason"`
+	}
+
+	VerifyOperationLogChainResponse struct {
+		Valid        bool                    `json:"valid"`
+		Checked      int64                   `json:"checked"`
+		Checkpoints  int64                   `json:"checkpoints"`
+		LastSequence int64                   `json:"last_sequence"`
+		BrokenLink   *OperationLogBrokenLink `json:"broken_link"`
+	}
+
+	GetLogArchiveResponse struct {
+		Name       string `json:"name"`
+		Collection string `json:"collection"`
+		Size       int64  `json:"size"` // Compressed size in bytes
+		CreatedAt  string `json:"created_at"`
+	}
+
+	GetLogArchiveListResponse struct {
+		Total          int64                    `json:"total"`
+		LogArchiveList []*GetLogArchiveResponse `json:"log_archive_list"`
+	}
+
+	ImportLogArchiveResponse struct {
+		Name       string `json:"name"`
+		Collection string `json:"collection"` // Collection the logs were imported into
+		Total      int64  `json:"total"`      // Logs in the archive
+		Imported   int64  `json:"imported"`   // Logs inserted, the others are still present
+	}
+
 	GetErrorLogResponse struct {
 		ErrorLogID     string `json:"error_log_id"`
 		UserID         string `json:"user_id"`
@@ -71,4 +150,87 @@ type (
 		Total        int64                  `json:"total"`
 		ErrorLogList []*GetErrorLogResponse `json:"error_log_list"`
 	}
+
+	StatsCountResponse struct {
+		Key   string `json:"key"`
+		Count int64  `json:"count"`
+	}
+
+	StatsBucketCountResponse struct {
+		Bucket string `json:"bucket"` // Start of the time bucket in RFC3339
+		Count  int64  `json:"count"`
+	}
+
+	StatsOperationCountResponse struct {
+		Bucket    string `json:"bucket"` // Start of the time bucket in RFC3339
+		Operation string `json:"operation"`
+		Status    string `json:"status"`
+		Count     int64  `json:"count"`
+	}
+
+	GetUserStatsResponse struct {
+		Total          int64                 `json:"total"`
+		ByRole         []*StatsCountResponse `json:"by_role"`
+		ByOrganization []*StatsCountResponse `json:"by_organization"`
+	}
+
+	GetStatsSeriesResponse struct {
+		Bucket    string                      `json:"bucket"`
+		StartTime string                      `json:"start_time"`
+		EndTime   string                      `json:"end_time"`
+		Series    []*StatsBucketCountResponse `json:"series"` // Every bucket of the range, including empty ones
+	}
+
+	GetOperationStatsResponse struct {
+		Bucket    string                         `json:"bucket"`
+		StartTime string                         `json:"start_time"`
+		EndTime   string                         `json:"end_time"`
+		Series    []*StatsOperationCountResponse `json:"series"` // Only the non-empty buckets
+	}
+
+	GetContentStatsResponse struct {
+		NoticeTotal             int64                 `json:"notice_total"`
+		NoticeByType            []*StatsCountResponse `json:"notice_by_type"`
+		DocumentationTotal      int64                 `json:"documentation_total"`
+		DocumentationByCategory []*StatsCountResponse `json:"documentation_by_category"`
+	}
+
+	CachePrefixStatsResponse struct {
+		Prefix      string `json:"prefix"` // Under the namespace, or "other" for the keys under none of the known prefixes
+		Keys        int64  `json:"keys"`
+		MemoryBytes int64  `json:"memory_bytes"` // As estimated by the backend
+	}
+
+	GetCacheStatsResponse struct {
+		Backend      string                      `json:"backend"`
+		Namespace    string                      `json:"namespace"`
+		Prefixes     []*CachePrefixStatsResponse `json:"prefixes"`
+		LocalEntries int                         `json:"local_entries"` // Entries of the local cache of this instance
+	}
+
+	PurgeCacheResponse struct {
+		Prefix  string `json:"prefix"`
+		Deleted int64  `json:"deleted"`
+	}
+
+	CacheWarmResponse struct {
+		Entity string `json:"entity"`
+		Lists  int64  `json:"lists"` // Pages of the list loaded
+	}
+
+	WarmCacheResponse struct {
+		Warmed []*CacheWarmResponse `json:"warmed"`
+	}
+
+	RateLimitOverrideResponse struct {
+		UserID    string `json:"user_id"`
+		Max       int    `json:"max"`
+		Exempt    bool   `json:"exempt"`
+		UpdatedAt string `json:"updated_at"`
+	}
+
+	ListRateLimitOverridesResponse struct {
+		Total        int64                        `json:"total"`
+		OverrideList []*RateLimitOverrideResponse `json:"override_list"`
+	}
 )
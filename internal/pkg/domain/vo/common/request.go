@@ -17,6 +17,7 @@ type (
 
 	GetNoticeRequest struct {
 		NoticeID *string `query:"noticeID" validate:"required,mongodb"`
+		Format   *string `query:"format" validate:"omitnil,contentFormat"`
 	}
 
 	GetNoticeListRequest struct {
@@ -28,7 +29,9 @@ type (
 	}
 
 	GetDocumentationRequest struct {
-		DocumentationID *string `query:"documentationID" validate:"required"`
+		DocumentationID *string `query:"documentationID" validate:"required_without=Slug,omitnil,mongodb"`
+		Slug            *string `query:"slug" validate:"required_without=DocumentationID,omitnil,slug"`
+		Format          *string `query:"format" validate:"omitnil,contentFormat"`
 	}
 
 	GetDocumentationListRequest struct {
@@ -36,5 +39,14 @@ type (
 		PageSize        *int64  `query:"pageSize" validate:"required,numeric,min=1,max=100"`
 		UpdateStartTime *string `query:"updateStartTime" validate:"omitnil,rfc3339,earlierThan=UpdateEndTime"`
 		UpdateEndTime   *string `query:"updateEndTime" validate:"omitnil,rfc3339"`
+		Category        *string `query:"category" validate:"omitnil,max=50"`
+		Tag             *string `query:"tag" validate:"omitnil,max=30"`
+	}
+
+	SearchRequest struct {
+		Query    *string `query:"query" validate:"required,min=1,max=100"`
+		Scope    *string `query:"scope" validate:"omitnil,searchScope"`
+		Page     *int64  `query:"page" validate:"required,numeric,min=1"`
+		PageSize *int64  `query:"pageSize" validate:"required,numeric,min=1,max=100"`
 	}
 )
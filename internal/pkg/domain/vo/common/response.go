@@ -1,5 +1,9 @@
 package common
 
+import (
+	"fiber-admin/pkg/markdown"
+)
+
 type (
 	LoginResponse struct {
 		AccessToken  string  `json:"access_token"`
@@ -26,12 +30,15 @@ type (
 	}
 
 	GetNoticeResponse struct {
-		NoticeID   string `json:"notice_id"`
-		Title      string `json:"title"`
-		Content    string `json:"content"`
-		NoticeType string `json:"type"`
-		CreatedAt  string `json:"created_at"`
-		UpdatedAt  string `json:"updated_at"`
+		NoticeID   string              `json:"notice_id"`
+		Title      string              `json:"title"`
+		Content    string              `json:"content"`
+		Format     string              `json:"format"`
+		TOC        []*markdown.Heading `json:"toc,omitempty"`
+		NoticeType string              `json:"type"`
+		CreatedAt  string              `json:"created_at"`
+		UpdatedAt  string              `json:"updated_at"`
+		Version    int64               `json:"version"`
 	}
 
 	NoticeSummary struct {
@@ -47,17 +54,28 @@ type (
 	}
 
 	GetDocumentationResponse struct {
-		DocumentID string `json:"document_id"`
-		Title      string `json:"title"`
-		Content    string `json:"content"`
-		CreatedAt  string `json:"created_at"`
-		UpdatedAt  string `json:"updated_at"`
+		DocumentID string              `json:"document_id"`
+		ParentID   string              `json:"parent_id"`
+		Position   int64               `json:"position"`
+		Slug       string              `json:"slug"`
+		Title      string              `json:"title"`
+		Content    string              `json:"content"`
+		Format     string              `json:"format"`
+		TOC        []*markdown.Heading `json:"toc,omitempty"`
+		Category   string              `json:"category"`
+		Tags       []string            `json:"tags"`
+		CreatedAt  string              `json:"created_at"`
+		UpdatedAt  string              `json:"updated_at"`
+		Version    int64               `json:"version"`
 	}
 
 	DocumentationSummary struct {
-		DocumentID string `json:"document_id"`
-		Title      string `json:"title"`
-		CreatedAt  string `json:"created_at"`
+		DocumentID string   `json:"document_id"`
+		Slug       string   `json:"slug"`
+		Title      string   `json:"title"`
+		Category   string   `json:"category"`
+		Tags       []string `json:"tags"`
+		CreatedAt  string   `json:"created_at"`
 	}
 
 	GetDocumentationListResponse struct {
@@ -65,6 +83,21 @@ type (
 		DocumentationSummaryList []*DocumentationSummary `json:"documentation_summary_list"`
 	}
 
+	DocumentationTreeNode struct {
+		DocumentID string                   `json:"document_id"`
+		Slug       string                   `json:"slug"`
+		Title      string                   `json:"title"`
+		Category   string                   `json:"category"`
+		Tags       []string                 `json:"tags"`
+		Position   int64                    `json:"position"`
+		Children   []*DocumentationTreeNode `json:"children"`
+	}
+
+	GetDocumentationTreeResponse struct {
+		Total             int64                    `json:"total"`
+		DocumentationTree []*DocumentationTreeNode `json:"documentation_tree"`
+	}
+
 	GetProfileResponse struct {
 		UserID       string `json:"user_id"`
 		Username     string `json:"username"`
@@ -73,4 +106,19 @@ type (
 		Organization string `json:"organization"`
 		LastLogin    string `json:"last_login"`
 	}
+
+	SearchResult struct {
+		EntityType string  `json:"entity_type"`
+		EntityID   string  `json:"entity_id"`
+		Title      string  `json:"title"`
+		Snippet    string  `json:"snippet"`
+		Score      float64 `json:"score"`
+		CreatedAt  string  `json:"created_at"`
+		UpdatedAt  string  `json:"updated_at"`
+	}
+
+	SearchResponse struct {
+		Total            int64           `json:"total"`
+		SearchResultList []*SearchResult `json:"search_result_list"`
+	}
 )
@@ -1,24 +1,22 @@
 package middleware
 
 import (
-	"fmt"
-
 	"fiber-admin/internal/pkg/config"
 	ware "fiber-admin/internal/pkg/middleware/mods"
-	"fiber-admin/pkg/errors"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/limiter"
 	"github.com/gofiber/fiber/v2/middleware/requestid"
 )
 
 type Middleware struct {
-	AuthMiddleware        *ware.AuthMiddleware
-	LoggingMiddleware     *ware.LoggingMiddleware
-	PrometheusMiddleware  *ware.PrometheusMiddleware
-	ContextMiddleware     *ware.ContextMiddleware
-	IdempotencyMiddleware *ware.IdempotencyMiddleware
-	Config                *config.Config
+	AuthMiddleware         *ware.AuthMiddleware
+	LoggingMiddleware      *ware.LoggingMiddleware
+	PrometheusMiddleware   *ware.PrometheusMiddleware
+	ContextMiddleware      *ware.ContextMiddleware
+	IdempotencyMiddleware  *ware.IdempotencyMiddleware
+	OperationLogMiddleware *ware.OperationLogMiddleware
+	RateLimitMiddleware    *ware.RateLimitMiddleware
+	Config                 *config.Config
 }
 
 func (m *Middleware) Register(app *fiber.App) error {
@@ -52,19 +50,8 @@ func (m *Middleware) Register(app *fiber.App) error {
 		)
 	}
 
-	// Register limiter Middleware
-	app.Use(
-		limiter.New(
-			limiter.Config{
-				Max:               m.Config.MiddlewareConfig.LimiterConfig.Max,
-				Expiration:        m.Config.MiddlewareConfig.LimiterConfig.Expiration,
-				LimiterMiddleware: limiter.SlidingWindow{},
-				LimitReached: func(c *fiber.Ctx) error {
-					return errors.ServerBusy(fmt.Errorf("too many requests"))
-				},
-			},
-		),
-	)
+	// Register Rate Limit Middleware
+	m.RateLimitMiddleware.Register(app)
 
 	// Register Auth Middleware
 	// m.AuthMiddleware.Register(app)
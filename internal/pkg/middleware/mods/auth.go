@@ -3,6 +3,7 @@ package mods
 import (
 	e "errors"
 	"fmt"
+	"time"
 
 	"fiber-admin/internal/pkg/config"
 	"fiber-admin/internal/pkg/dao"
@@ -10,6 +11,7 @@ import (
 	auth "fiber-admin/pkg/jwt"
 	"fiber-admin/pkg/utils/check"
 	"fiber-admin/pkg/utils/crypt"
+	logging "fiber-admin/pkg/zap"
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt"
 )
@@ -17,10 +19,16 @@ import (
 type AuthMiddleware struct {
 	Jwt    *auth.Jwt
 	Cache  *dao.Cache
+	Zap    *logging.Zap
 	Config *config.Config
 }
 
 func (a *AuthMiddleware) AuthMiddleware() fiber.Handler {
+	// Tokens revoked before the cache namespace was configured are blacklisted outside of it, for the blacklist TTL.
+	var legacyUntil time.Time
+	if a.Config.CacheConfig.Namespace != "" {
+		legacyUntil = time.Now().Add(a.Config.CacheConfig.TokenBlacklistTTL)
+	}
 	return func(c *fiber.Ctx) error {
 		token := c.Get(fiber.HeaderAuthorization)
 		if token == "" {
@@ -31,7 +39,11 @@ func (a *AuthMiddleware) AuthMiddleware() fiber.Handler {
 		}
 		token = token[7:] // remove 'Bearer '
 		blacklistKey := fmt.Sprintf("%s:%s", config.TokenBlacklistCachePrefix, crypt.MD5(token))
-		if ok, err := a.Cache.Get(c.Context(), blacklistKey); err == nil && *ok == config.CacheTrue {
+		revoked, err := a.Cache.Get(c.Context(), blacklistKey)
+		if (err != nil || *revoked != config.CacheTrue) && time.Now().Before(legacyUntil) {
+			revoked, err = a.Cache.GetUnnamespaced(c.Context(), blacklistKey)
+		}
+		if err == nil && *revoked == config.CacheTrue {
 			return errors.TokenInvalid(fmt.Errorf("token has been revoked"))
 		}
 		sub, err := a.Jwt.VerifyAccessToken(token)
@@ -45,6 +57,9 @@ func (a *AuthMiddleware) AuthMiddleware() fiber.Handler {
 			return errors.TokenInvalid(fmt.Errorf("token invalid"))
 		}
 		c.Locals(config.UserIDKey, sub)
+		// The context middleware runs app-wide before this route-level middleware, so the user ID has to be put into
+		// the user context here for handlers and services to see it.
+		c.SetUserContext(a.Zap.SetUserIDInContext(c.UserContext(), sub))
 		return c.Next()
 	}
 }
@@ -4,28 +4,93 @@ import (
 	"fmt"
 
 	"fiber-admin/internal/pkg/config"
+	"fiber-admin/internal/pkg/domain/entity"
 	"fiber-admin/internal/pkg/service/common/mods"
 	"fiber-admin/pkg/errors"
+	"fiber-admin/pkg/utils/crypt"
 	"github.com/gofiber/fiber/v2"
 )
 
+// maxIdempotencyKeyLength bounds the keys clients can make the cache store.
+const maxIdempotencyKeyLength = 255
+
+// idempotentReplayedHeader marks a replayed response.
+const idempotentReplayedHeader = "Idempotent-Replayed"
+
+// unreplayedHeaders are the response headers which are not stored, as they describe the response sent rather than
+// the result of the request, or are set again for the replay.
+var unreplayedHeaders = map[string]struct{}{
+	fiber.HeaderContentLength:    {},
+	fiber.HeaderDate:             {},
+	fiber.HeaderConnection:       {},
+	fiber.HeaderServer:           {},
+	fiber.HeaderSetCookie:        {},
+	fiber.HeaderTransferEncoding: {},
+	fiber.HeaderXRequestID:       {},
+}
+
 type IdempotencyMiddleware struct {
 	IdempotencyService mods.IdempotencyService
 	Config             *config.Config
 }
 
+// IdempotencyMiddleware makes the route idempotent as the IETF Idempotency-Key draft describes. The first request
+// with a key is processed and its response stored for the TTL; the requests reusing the key get that response again,
+// with the Idempotent-Replayed header set. A request reusing the key while the first is in flight is answered with
+// 409, and one reusing it for a different request (method, URL or body) with 422. Keys are scoped to the user.
+//
+// Errors, 5xx responses and responses larger than the maximum size are not stored, and their key is released so
+// that the request can be retried.
 func (m *IdempotencyMiddleware) IdempotencyMiddleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		ctx := c.UserContext()
-		token := c.Get(m.Config.IdempotencyConfig.IdempotencyTokenHeader)
-		if token != "" {
-			err := m.IdempotencyService.CheckIdempotencyToken(ctx, token)
-			if err != nil {
-				return err
-			} else {
-				return c.Next()
+		key := c.Get(m.Config.IdempotencyConfig.IdempotencyTokenHeader)
+		if key == "" {
+			return errors.Idempotency(fmt.Errorf("idempotency key missed"))
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return errors.Idempotency(fmt.Errorf("idempotency key longer than %d characters", maxIdempotencyKeyLength))
+		}
+		scope, _ := c.Locals(config.UserIDKey).(string)
+		key = fmt.Sprintf("%s:%s", scope, key)
+		fingerprint := crypt.SHA256(fmt.Sprintf("%s %s\n%s", c.Method(), c.OriginalURL(), c.Body()))
+
+		record, err := m.IdempotencyService.BeginIdempotentRequest(ctx, key, fingerprint)
+		if err != nil {
+			return err
+		}
+		if record != nil {
+			for name, values := range record.Headers {
+				c.Response().Header.Del(name)
+				for _, value := range values {
+					c.Response().Header.Add(name, value)
+				}
 			}
+			c.Set(idempotentReplayedHeader, "true")
+			return c.Status(record.Status).Send(record.Body)
+		}
+
+		err = c.Next()
+		status, body := c.Response().StatusCode(), c.Response().Body()
+		if err != nil || status >= fiber.StatusInternalServerError ||
+			len(body) > m.Config.IdempotencyConfig.MaxResponseSize {
+			_ = m.IdempotencyService.ReleaseIdempotentRequest(ctx, key)
+			return err
+		}
+		record = &entity.IdempotencyRecord{
+			Fingerprint: fingerprint,
+			Status:      status,
+			Headers:     make(map[string][]string),
+			Body:        append([]byte(nil), body...),
 		}
-		return errors.Idempotency(fmt.Errorf("idempotency token missed"))
+		c.Response().Header.VisitAll(
+			func(name, value []byte) {
+				if _, ok := unreplayedHeaders[string(name)]; !ok {
+					record.Headers[string(name)] = append(record.Headers[string(name)], string(value))
+				}
+			},
+		)
+		_ = m.IdempotencyService.CompleteIdempotentRequest(ctx, key, record)
+		return nil
 	}
 }
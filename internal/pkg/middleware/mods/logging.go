@@ -1,23 +1,47 @@
 package mods
 
 import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"strings"
+	"time"
+
+	"fiber-admin/internal/pkg/config"
+	"fiber-admin/internal/pkg/config/mods/middleware"
+	"fiber-admin/internal/pkg/domain/entity"
+	sysservice "fiber-admin/internal/pkg/service/sys/mods"
+	apperrors "fiber-admin/pkg/errors"
+	"fiber-admin/pkg/redact"
 	logging "fiber-admin/pkg/zap"
 	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
 	"go.uber.org/zap"
 )
 
 type LoggingMiddleware struct {
-	Zap *logging.Zap
+	Zap            *logging.Zap
+	LogTailService sysservice.LogTailService
+	Config         *config.Config
 }
 
 func (l *LoggingMiddleware) Register(app *fiber.App) {
 	app.Use(l.loggingMiddleware())
 }
 
+// loggingMiddleware logs every request. Secrets are masked by the redaction rules of the logging config before they
+// are logged, bodies are truncated, and only a sample of the 2xx requests is logged.
 func (l *LoggingMiddleware) loggingMiddleware() fiber.Handler {
+	loggingConfig := middleware.LoggingConfig{SuccessSampleRate: 1}
+	if l.Config != nil {
+		loggingConfig = l.Config.MiddlewareConfig.LoggingConfig
+	}
+	redactor := redact.New(loggingConfig.RedactedFields, loggingConfig.RedactedHeaders)
 	return func(c *fiber.Ctx) error {
 		var (
 			// ctx                  = c.UserContext()
+			start                = time.Now()
 			sysLogger, reqLogger *zap.Logger
 			err                  error
 			sysCtx               = l.Zap.SetTagInContext(c.Context(), logging.SystemTag)
@@ -29,48 +53,144 @@ func (l *LoggingMiddleware) loggingMiddleware() fiber.Handler {
 		if err != nil {
 			sysLogger.Warn("Failed to execute request", zap.Error(err))
 		}
+		status := responseStatus(c, err)
+		l.publish(c, start, status)
 
-		if c.Response().StatusCode() >= fiber.StatusInternalServerError {
-			reqLogger.Error(
-				"Request",
-				zap.String("path", c.Path()),
-				zap.String("method", c.Method()),
-				zap.String("ip", c.IP()),
-				zap.String("userAgent", c.Get(fiber.HeaderUserAgent)),
-				zap.Any("query", c.Request().URI().QueryArgs()),
-				zap.Any("form", c.Request().PostArgs()),
-				zap.Any("body", c.Body()),
-				zap.Int("status", c.Response().StatusCode()),
-				zap.Any("response", c.Response().Body()),
-			)
-		} else if c.Response().StatusCode() >= fiber.StatusBadRequest {
-			reqLogger.Warn(
-				"Request",
-				zap.String("path", c.Path()),
-				zap.String("method", c.Method()),
-				zap.String("ip", c.IP()),
-				zap.String("userAgent", c.Get(fiber.HeaderUserAgent)),
-				zap.Any("query", c.Request().URI().QueryArgs()),
-				zap.Any("form", c.Request().PostArgs()),
-				zap.Any("body", c.Body()),
-				zap.Int("status", c.Response().StatusCode()),
-				zap.Any("response", c.Response().Body()),
-			)
+		if routeMatches(c, loggingConfig.ExcludedRoutes) {
+			return err
+		}
+		if status < fiber.StatusMultipleChoices && loggingConfig.SuccessSampleRate < 1 &&
+			rand.Float64() >= loggingConfig.SuccessSampleRate {
+			return err
+		}
+		var (
+			body     = c.Body()
+			response = responseBody(c)
+		)
+		if routeMatches(c, loggingConfig.RedactedBodyRoutes) {
+			body, response = []byte(redact.Mask), []byte(redact.Mask)
+		}
+		fields := []zap.Field{
+			zap.String("path", c.Path()),
+			zap.String("method", c.Method()),
+			zap.String("ip", c.IP()),
+			zap.String("userAgent", c.Get(fiber.HeaderUserAgent)),
+			zap.Any("headers", requestHeaders(c, redactor)),
+			zap.String("query", redactArgs(c.Request().URI().QueryArgs(), redactor)),
+			zap.String("form", redactArgs(c.Request().PostArgs(), redactor)),
+			zap.String("body", truncateBody(redactor.JSON(body), loggingConfig.MaxBodySize)),
+			zap.Int("status", status),
+			zap.String("response", truncateBody(redactor.JSON(response), loggingConfig.MaxBodySize)),
+		}
+		if status >= fiber.StatusInternalServerError {
+			reqLogger.Error("Request", fields...)
+		} else if status >= fiber.StatusBadRequest {
+			reqLogger.Warn("Request", fields...)
 		} else {
-			reqLogger.Info(
-				"Request",
-				zap.String("path", c.Path()),
-				zap.String("method", c.Method()),
-				zap.String("ip", c.IP()),
-				zap.String("userAgent", c.Get(fiber.HeaderUserAgent)),
-				zap.Any("query", c.Request().URI().QueryArgs()),
-				zap.Any("form", c.Request().PostArgs()),
-				zap.Any("body", c.Body()),
-				zap.Int("status", c.Response().StatusCode()),
-				zap.Any("response", c.Response().Body()),
-			)
+			reqLogger.Info("Request", fields...)
 		}
 
 		return err
 	}
 }
+
+// responseStatus returns the status of the response. The error handler has not run yet when the chain failed, so the
+// status it will respond with is derived from err.
+func responseStatus(c *fiber.Ctx, err error) int {
+	var (
+		appErr   *apperrors.AppError
+		fiberErr *fiber.Error
+	)
+	if errors.As(err, &appErr) {
+		return appErr.Status()
+	} else if errors.As(err, &fiberErr) {
+		return fiberErr.Code
+	} else if err != nil {
+		return fiber.StatusInternalServerError
+	}
+	return c.Response().StatusCode()
+}
+
+// requestHeaders returns the request headers with the redacted ones masked.
+func requestHeaders(c *fiber.Ctx, redactor *redact.Redactor) map[string]string {
+	headers := make(map[string]string)
+	c.Request().Header.VisitAll(
+		func(key, value []byte) {
+			name := string(key)
+			if redactor.Header(name) {
+				headers[name] = redact.Mask
+			} else {
+				headers[name] = string(value)
+			}
+		},
+	)
+	return headers
+}
+
+// redactArgs returns the query or form arguments, encoded, with the redacted ones masked.
+func redactArgs(args *fasthttp.Args, redactor *redact.Redactor) string {
+	values := url.Values{}
+	args.VisitAll(
+		func(key, value []byte) {
+			if redactor.Field(string(key)) {
+				values.Add(string(key), redact.Mask)
+			} else {
+				values.Add(string(key), string(value))
+			}
+		},
+	)
+	return values.Encode()
+}
+
+// truncateBody cuts the body to size bytes, noting how long it was. A size of 0 keeps the whole body.
+func truncateBody(body []byte, size int) string {
+	if size <= 0 || len(body) <= size {
+		return string(body)
+	}
+	return fmt.Sprintf("%s...(truncated, %d bytes)", body[:size], len(body))
+}
+
+// publish relays the request log to the admins tailing it. Excluded and unsampled requests are still relayed.
+func (l *LoggingMiddleware) publish(c *fiber.Ctx, start time.Time, status int) {
+	ctx := c.UserContext()
+	if l.LogTailService == nil || !l.LogTailService.Tailed(ctx, config.LogTailTypeRequest) {
+		return
+	}
+	event := &entity.LogTailEvent{
+		Type:       config.LogTailTypeRequest,
+		Time:       start,
+		IPAddress:  c.IP(),
+		Method:     c.Method(),
+		Path:       c.Path(),
+		Status:     config.OperationStatusSuccess,
+		StatusCode: status,
+		Latency:    float64(time.Since(start).Microseconds()) / 1000,
+	}
+	event.RequestID, _ = c.Locals(config.RequestIDKey).(string)
+	event.UserID, _ = c.Locals(config.UserIDKey).(string)
+	if status >= fiber.StatusBadRequest {
+		event.Status = config.OperationStatusFailure
+	}
+	l.LogTailService.Publish(ctx, event)
+}
+
+// responseBody returns the response body to log. Streamed bodies such as exports are not read, since that would load
+// the whole stream into memory before it is sent.
+func responseBody(c *fiber.Ctx) []byte {
+	if c.Response().IsBodyStream() {
+		return []byte("<stream>")
+	}
+	return c.Response().Body()
+}
+
+// routeMatches reports whether the route of the request is one of routes, written as the method and the full path.
+// Routes are compared case-insensitively and regardless of the spacing between the method and the path.
+func routeMatches(c *fiber.Ctx, routes []string) bool {
+	route := c.Method() + " " + c.Route().Path
+	for _, r := range routes {
+		if strings.EqualFold(strings.Join(strings.Fields(r), " "), route) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+package mods
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"fiber-admin/internal/pkg/config"
+	"fiber-admin/internal/pkg/domain/entity"
+	sysservice "fiber-admin/internal/pkg/service/sys/mods"
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OperationLog builds the handler that records an operation log entry for a route.
+type OperationLog func(operation, entityType string, entityIDFields ...string) fiber.Handler
+
+type OperationLogMiddleware struct {
+	LogsService    sysservice.LogsService
+	LogTailService sysservice.LogTailService
+	Config         *config.Config
+}
+
+// OperationLog records the operation of the route it is attached to once the rest of the chain has run. The entity ID
+// is taken from the OperationLogEntityIDKey local when the handler sets it (e.g. the ID of a created entity), and
+// otherwise from the first of entityIDFields found in the query or the JSON body. The error of the chain is logged as
+// the outcome and then returned unchanged, so it still reaches the error handler. A response with an error status is
+// a failure too, with or without an error.
+//
+// It goes before the role check of the route, so that attempts the role check denies are recorded as failures.
+//
+// READ and EXPORT operations mark routes giving access to sensitive resources. They are recorded with the query
+// parameters the resource was filtered with, unless access logging is disabled for the route.
+//
+// The entry is also published to the admins tailing the operation log.
+func (o *OperationLogMiddleware) OperationLog(operation, entityType string, entityIDFields ...string) fiber.Handler {
+	access := operation == config.OperationTypeRead || operation == config.OperationTypeExport
+	return func(c *fiber.Ctx) error {
+		if access && !o.accessLogged(c) {
+			return c.Next()
+		}
+		err := c.Next()
+
+		userID, ok := o.actor(c)
+		if !ok {
+			return err
+		}
+		var (
+			ctx         = c.UserContext()
+			entityID    = o.entityID(c, entityIDFields)
+			ipAddr      = c.IP()
+			userAgent   = c.Get(fiber.HeaderUserAgent)
+			requestID   = o.requestID(c)
+			description = fmt.Sprintf("%s %s", operation, strings.ToLower(entityType))
+			status      = config.OperationStatusSuccess
+		)
+		if entityID != nil {
+			description = fmt.Sprintf("%s: %s", description, entityID.Hex())
+		}
+		if access {
+			if filters := o.filters(c); filters != "" {
+				description = fmt.Sprintf("%s with filters %s", description, filters)
+			}
+		}
+		statusCode := responseStatus(c, err)
+		if err != nil {
+			description = fmt.Sprintf("%s failed (%d): %s", description, statusCode, err.Error())
+			status = config.OperationStatusFailure
+		} else if statusCode >= fiber.StatusBadRequest {
+			description = fmt.Sprintf("%s failed (%d)", description, statusCode)
+			status = config.OperationStatusFailure
+		}
+		_ = o.LogsService.CacheOperationLog(
+			ctx, &userID, entityID, &ipAddr, &userAgent, &requestID, &operation, &entityType, &description, &status,
+		)
+		if o.LogTailService != nil && o.LogTailService.Tailed(ctx, config.LogTailTypeOperation) {
+			event := &entity.LogTailEvent{
+				Type:        config.LogTailTypeOperation,
+				Time:        time.Now(),
+				RequestID:   requestID,
+				UserID:      userID.Hex(),
+				IPAddress:   ipAddr,
+				Method:      c.Method(),
+				Path:        c.Path(),
+				Operation:   operation,
+				EntityType:  entityType,
+				Description: description,
+				Status:      status,
+				StatusCode:  statusCode,
+			}
+			if entityID != nil {
+				event.EntityID = entityID.Hex()
+			}
+			o.LogTailService.Publish(ctx, event)
+		}
+		return err
+	}
+}
+
+// accessLogged reports whether the access to the current route is recorded.
+func (o *OperationLogMiddleware) accessLogged(c *fiber.Ctx) bool {
+	if o.Config == nil {
+		return true
+	}
+	if !o.Config.AuditConfig.AccessLog {
+		return false
+	}
+	return !routeMatches(c, o.Config.AuditConfig.AccessLogExcludedRoutes)
+}
+
+// filters returns the query parameters of the request, sorted by name.
+func (o *OperationLogMiddleware) filters(c *fiber.Ctx) string {
+	values := url.Values{}
+	c.Context().QueryArgs().VisitAll(
+		func(key, value []byte) {
+			values.Add(string(key), string(value))
+		},
+	)
+	return values.Encode()
+}
+
+func (o *OperationLogMiddleware) actor(c *fiber.Ctx) (primitive.ObjectID, bool) {
+	userIDHex, ok := c.Locals(config.UserIDKey).(string)
+	if !ok {
+		return primitive.NilObjectID, false
+	}
+	userID, err := primitive.ObjectIDFromHex(userIDHex)
+	if err != nil {
+		return primitive.NilObjectID, false
+	}
+	return userID, true
+}
+
+func (o *OperationLogMiddleware) requestID(c *fiber.Ctx) string {
+	requestID, _ := c.Locals(config.RequestIDKey).(string)
+	return requestID
+}
+
+func (o *OperationLogMiddleware) entityID(c *fiber.Ctx, fields []string) *primitive.ObjectID {
+	if entityIDHex, ok := c.Locals(config.OperationLogEntityIDKey).(string); ok {
+		if entityID, err := primitive.ObjectIDFromHex(entityIDHex); err == nil {
+			return &entityID
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	var body map[string]any
+	if strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEApplicationJSON) {
+		_ = json.Unmarshal(c.Body(), &body)
+	}
+	for _, field := range fields {
+		entityIDHex := c.Query(field)
+		if entityIDHex == "" {
+			entityIDHex, _ = body[field].(string)
+		}
+		if entityID, err := primitive.ObjectIDFromHex(entityIDHex); err == nil {
+			return &entityID
+		}
+	}
+	return nil
+}
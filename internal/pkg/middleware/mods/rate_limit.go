@@ -0,0 +1,185 @@
+package mods
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"fiber-admin/internal/pkg/config"
+	"fiber-admin/internal/pkg/config/mods/middleware"
+	"fiber-admin/internal/pkg/domain/entity"
+	sysservice "fiber-admin/internal/pkg/service/sys/mods"
+	"fiber-admin/pkg/errors"
+	auth "fiber-admin/pkg/jwt"
+	"fiber-admin/pkg/utils/check"
+	"fiber-admin/pkg/utils/crypt"
+	logging "fiber-admin/pkg/zap"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	rateLimitLimitHeader     = "RateLimit-Limit"
+	rateLimitRemainingHeader = "RateLimit-Remaining"
+	rateLimitResetHeader     = "RateLimit-Reset"
+)
+
+type RateLimitMiddleware struct {
+	RateLimitService sysservice.RateLimitService
+	Jwt              *auth.Jwt
+	Zap              *logging.Zap
+	Config           *config.Config
+	limiterConfig    atomic.Pointer[middleware.LimiterConfig]
+}
+
+func (r *RateLimitMiddleware) Register(app *fiber.App) {
+	r.SetLimiterConfig(r.Config.MiddlewareConfig.LimiterConfig)
+	app.Use(r.rateLimitMiddleware())
+}
+
+// SetLimiterConfig replaces the limits, which apply from the next request on.
+func (r *RateLimitMiddleware) SetLimiterConfig(limiterConfig middleware.LimiterConfig) {
+	r.limiterConfig.Store(&limiterConfig)
+}
+
+// rateLimitMiddleware counts each request against the rules of the longest path prefix matching it, or the default
+// rule. The RateLimit-* headers describe the most restrictive of them, and a request over a limit is answered with 429
+// and Retry-After. The override of a user replaces the limit of the user rules for that user.
+//
+// The limits fail open: a request which cannot be counted, e.g. because the cache is down, is let through.
+func (r *RateLimitMiddleware) rateLimitMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		sysCtx := r.Zap.SetTagInContext(c.Context(), logging.SystemTag)
+		sysLogger, _ := r.Zap.GetLogger(sysCtx)
+
+		var (
+			limiterConfig = r.limiterConfig.Load()
+			rules, prefix = matchRules(c.Path(), limiterConfig)
+			userID        = r.userID(c, rules)
+			override      *entity.RateLimitOverride
+			strictest     *sysservice.RateLimitResult
+		)
+		if userID != "" {
+			var err error
+			if override, err = r.RateLimitService.GetOverride(c.Context(), userID); err != nil {
+				sysLogger.Warn("Failed to get rate limit override", zap.Error(err))
+			}
+		}
+		for _, rule := range rules {
+			identity, id, limit := identify(c, limiterConfig, rule, userID, override)
+			if identity == "" {
+				continue // Exempt
+			}
+			key := fmt.Sprintf("%s:%s:%s:%s", identity, prefix, rule.Window, id)
+			result, err := r.RateLimitService.Limit(c.Context(), key, limit, rule.Window)
+			if err != nil {
+				sysLogger.Warn("Failed to count request against rate limit", zap.Error(err))
+				continue
+			}
+			if strictest == nil || stricter(result, strictest) {
+				strictest = result
+			}
+		}
+		if strictest == nil {
+			return c.Next()
+		}
+
+		reset := strconv.Itoa(int(math.Ceil(strictest.Reset.Seconds())))
+		c.Set(rateLimitLimitHeader, strconv.Itoa(strictest.Limit))
+		c.Set(rateLimitRemainingHeader, strconv.Itoa(strictest.Remaining))
+		c.Set(rateLimitResetHeader, reset)
+		if !strictest.Allowed {
+			c.Set(fiber.HeaderRetryAfter, reset)
+			return errors.TooManyRequests(fmt.Errorf("too many requests"))
+		}
+		return c.Next()
+	}
+}
+
+// matchRules returns the rules of the longest path prefix matching path, and that prefix, or the default rule. The path
+// prefixes match whole segments only, so that /api/v1/admin does not match /api/v1/administrators.
+func matchRules(path string, limiterConfig *middleware.LimiterConfig) ([]middleware.LimiterRule, string) {
+	var (
+		rules  []middleware.LimiterRule
+		prefix string
+	)
+	for _, rule := range limiterConfig.Rules {
+		rulePath := strings.TrimSuffix(rule.Path, "/")
+		if path != rulePath && !strings.HasPrefix(path, rulePath+"/") {
+			continue
+		}
+		switch {
+		case rules == nil || len(rulePath) > len(prefix):
+			rules, prefix = []middleware.LimiterRule{rule}, rulePath
+		case rulePath == prefix:
+			rules = append(rules, rule)
+		}
+	}
+	if rules == nil {
+		return []middleware.LimiterRule{
+			{Identity: config.RateLimitIdentityIP, Max: limiterConfig.Max, Window: limiterConfig.Expiration},
+		}, ""
+	}
+	return rules, prefix
+}
+
+// userID returns the user of a valid bearer token, when a rule limits by user. The token is not checked against the
+// blacklist, which the auth middleware of the route does.
+func (r *RateLimitMiddleware) userID(c *fiber.Ctx, rules []middleware.LimiterRule) string {
+	for _, rule := range rules {
+		if rule.Identity != config.RateLimitIdentityUser {
+			continue
+		}
+		token := c.Get(fiber.HeaderAuthorization)
+		if !check.IsBearerToken(token) {
+			return ""
+		}
+		sub, err := r.Jwt.VerifyAccessToken(token[7:]) // remove 'Bearer '
+		if err != nil {
+			return ""
+		}
+		return sub
+	}
+	return ""
+}
+
+// identify returns the identity the request is counted under for the rule, its ID and the limit. Requests without the
+// identity of the rule are counted by IP. An exempt user gets no identity.
+func identify(
+	c *fiber.Ctx, limiterConfig *middleware.LimiterConfig, rule middleware.LimiterRule, userID string,
+	override *entity.RateLimitOverride,
+) (string, string, int) {
+	switch rule.Identity {
+	case config.RateLimitIdentityUser:
+		if userID == "" {
+			break
+		}
+		if override == nil {
+			return config.RateLimitIdentityUser, userID, rule.Max
+		}
+		if override.Exempt {
+			return "", "", 0
+		}
+		return config.RateLimitIdentityUser, userID, override.Max
+	case config.RateLimitIdentityAPIKey:
+		if key := c.Get(limiterConfig.APIKeyHeader); key != "" {
+			return config.RateLimitIdentityAPIKey, crypt.SHA256(key), rule.Max
+		}
+	}
+	return config.RateLimitIdentityIP, c.IP(), rule.Max
+}
+
+// stricter reports whether a is more restrictive than b: a rejection over an allowance, the longer wait among
+// rejections and the fewer remaining requests among allowances.
+func stricter(a, b *sysservice.RateLimitResult) bool {
+	switch {
+	case a.Allowed != b.Allowed:
+		return !a.Allowed
+	case !a.Allowed:
+		return a.Reset > b.Reset
+	default:
+		return a.Remaining < b.Remaining
+	}
+}
@@ -1,6 +1,7 @@
 package router
 
 import (
+	wares "fiber-admin/internal/pkg/middleware/mods"
 	"fiber-admin/internal/pkg/router/v1"
 	"github.com/gofiber/contrib/casbin"
 	"github.com/gofiber/fiber/v2"
@@ -14,7 +15,8 @@ type Router struct {
 
 func (r *Router) RegisterRouter(
 	app *fiber.App, casbin *casbin.Middleware, idempotencyMiddleware, authMiddleware fiber.Handler,
+	operationLog wares.OperationLog,
 ) {
 	group := app.Group(prefix)
-	r.RouterV1.RegisterRouter(&group, casbin, idempotencyMiddleware, authMiddleware)
+	r.RouterV1.RegisterRouter(&group, casbin, idempotencyMiddleware, authMiddleware, operationLog)
 }
@@ -3,6 +3,7 @@ package mods
 import (
 	"fiber-admin/internal/pkg/api/v1/admin"
 	"fiber-admin/internal/pkg/config"
+	wares "fiber-admin/internal/pkg/middleware/mods"
 	"github.com/gofiber/contrib/casbin"
 	"github.com/gofiber/fiber/v2"
 )
@@ -14,24 +15,28 @@ type AdminRouter struct{}
 // RegisterAdminRouter registers the admin router.
 func (a *AdminRouter) RegisterAdminRouter(
 	app fiber.Router, api *admin.Admin, casbin *casbin.Middleware, idempotencyMiddleware, authMiddleware fiber.Handler,
+	operationLog wares.OperationLog,
 ) {
 	group := app.Group(adminPrefix)
 
 	group.Post(
 		"/notice",
 		authMiddleware,
+		operationLog(config.OperationTypeCreate, config.EntityTypeNotice),
 		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
 		api.NoticeApi.InsertNotice,
 	)
 	group.Put(
 		"/notice",
 		authMiddleware,
+		operationLog(config.OperationTypeUpdate, config.EntityTypeNotice, "notice_id"),
 		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
 		api.NoticeApi.UpdateNotice,
 	)
 	group.Delete(
 		"/notice",
 		authMiddleware,
+		operationLog(config.OperationTypeDelete, config.EntityTypeNotice, "noticeID"),
 		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
 		api.NoticeApi.DeleteNotice,
 	)
@@ -40,18 +45,21 @@ func (a *AdminRouter) RegisterAdminRouter(
 		"/user",
 		authMiddleware,
 		idempotencyMiddleware, // An example of using idempotency middleware. Actually not necessary.
+		operationLog(config.OperationTypeCreate, config.EntityTypeUser),
 		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
 		api.UserApi.InsertUser,
 	)
 	group.Get(
 		"/user",
 		authMiddleware,
+		operationLog(config.OperationTypeRead, config.EntityTypeUser, "userID"),
 		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
 		api.UserApi.GetUser,
 	)
 	group.Get(
 		"/user/list",
 		authMiddleware,
+		operationLog(config.OperationTypeRead, config.EntityTypeUser),
 		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
 		api.UserApi.GetUserList,
 	)
@@ -59,18 +67,21 @@ func (a *AdminRouter) RegisterAdminRouter(
 	group.Put(
 		"/user",
 		authMiddleware,
+		operationLog(config.OperationTypeUpdate, config.EntityTypeUser, "user_id"),
 		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
 		api.UserApi.UpdateUser,
 	)
 	group.Delete(
 		"/user",
 		authMiddleware,
+		operationLog(config.OperationTypeDelete, config.EntityTypeUser, "userID"),
 		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
 		api.UserApi.DeleteUser,
 	)
 	group.Put(
 		"/user/password",
 		authMiddleware,
+		operationLog(config.OperationTypeUpdate, config.EntityTypeUser, "user_id"),
 		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
 		api.UserApi.ChangeUserPassword,
 	)
@@ -78,32 +89,190 @@ func (a *AdminRouter) RegisterAdminRouter(
 	group.Post(
 		"/documentation",
 		authMiddleware,
+		operationLog(config.OperationTypeCreate, config.EntityTypeDocumentation),
 		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
 		api.DocumentationApi.InsertDocumentation,
 	)
 	group.Put(
 		"/documentation",
 		authMiddleware,
+		operationLog(config.OperationTypeUpdate, config.EntityTypeDocumentation, "documentation_id"),
 		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
 		api.DocumentationApi.UpdateDocumentation,
 	)
 	group.Delete(
 		"/documentation",
 		authMiddleware,
+		operationLog(config.OperationTypeDelete, config.EntityTypeDocumentation, "documentationID"),
 		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
 		api.DocumentationApi.DeleteDocumentation,
 	)
+	group.Post(
+		"/documentation/move",
+		authMiddleware,
+		operationLog(config.OperationTypeUpdate, config.EntityTypeDocumentation, "documentation_id"),
+		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
+		api.DocumentationApi.MoveDocumentation,
+	)
+	group.Post(
+		"/documentation/reorder",
+		authMiddleware,
+		operationLog(config.OperationTypeUpdate, config.EntityTypeDocumentation, "parent_id"),
+		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
+		api.DocumentationApi.ReorderDocumentation,
+	)
+	group.Get(
+		"/documentation/revision",
+		authMiddleware,
+		operationLog(config.OperationTypeRead, config.EntityTypeDocumentation, "documentationID"),
+		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
+		api.DocumentationApi.GetDocumentationRevision,
+	)
+	group.Get(
+		"/documentation/revision/list",
+		authMiddleware,
+		operationLog(config.OperationTypeRead, config.EntityTypeDocumentation, "documentationID"),
+		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
+		api.DocumentationApi.GetDocumentationRevisionList,
+	)
+	group.Get(
+		"/documentation/revision/diff",
+		authMiddleware,
+		operationLog(config.OperationTypeRead, config.EntityTypeDocumentation, "documentationID"),
+		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
+		api.DocumentationApi.DiffDocumentationRevision,
+	)
+	group.Post(
+		"/documentation/revision/restore",
+		authMiddleware,
+		operationLog(config.OperationTypeUpdate, config.EntityTypeDocumentation, "documentation_id"),
+		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
+		api.DocumentationApi.RestoreDocumentationRevision,
+	)
 
 	group.Get(
 		"/login-log/list",
 		authMiddleware,
+		operationLog(config.OperationTypeRead, config.EntityTypeLoginLog),
 		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
 		api.LogsApi.GetLoginLogList,
 	)
+	group.Get(
+		"/login-log/export",
+		authMiddleware,
+		operationLog(config.OperationTypeExport, config.EntityTypeLoginLog),
+		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
+		api.LogsApi.ExportLoginLogList,
+	)
 	group.Get(
 		"/operation-log/list",
 		authMiddleware,
+		operationLog(config.OperationTypeRead, config.EntityTypeOperationLog),
 		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
 		api.LogsApi.GetOperationLogList,
 	)
+	group.Get(
+		"/operation-log/export",
+		authMiddleware,
+		operationLog(config.OperationTypeExport, config.EntityTypeOperationLog),
+		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
+		api.LogsApi.ExportOperationLogList,
+	)
+	group.Get(
+		"/operation-log/verify",
+		authMiddleware,
+		operationLog(config.OperationTypeRead, config.EntityTypeOperationLog),
+		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
+		api.LogsApi.VerifyOperationLogChain,
+	)
+	group.Get(
+		"/log-tail",
+		authMiddleware,
+		operationLog(config.OperationTypeRead, config.EntityTypeOperationLog),
+		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
+		api.LogsApi.TailLogs,
+	)
+	group.Get(
+		"/log-archive/list",
+		authMiddleware,
+		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
+		api.LogsApi.GetLogArchiveList,
+	)
+	group.Post(
+		"/log-archive/import",
+		authMiddleware,
+		operationLog(config.OperationTypeCreate, config.EntityTypeOperationLog),
+		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
+		api.LogsApi.ImportLogArchive,
+	)
+	group.Get(
+		"/stats/user",
+		authMiddleware,
+		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
+		api.StatsApi.GetUserStats,
+	)
+	group.Get(
+		"/stats/user/new",
+		authMiddleware,
+		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
+		api.StatsApi.GetNewUserStats,
+	)
+	group.Get(
+		"/stats/user/active",
+		authMiddleware,
+		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
+		api.StatsApi.GetActiveUserStats,
+	)
+	group.Get(
+		"/stats/operation",
+		authMiddleware,
+		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
+		api.StatsApi.GetOperationStats,
+	)
+	group.Get(
+		"/stats/content",
+		authMiddleware,
+		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
+		api.StatsApi.GetContentStats,
+	)
+	group.Get(
+		"/cache/stats",
+		authMiddleware,
+		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
+		api.CacheApi.GetCacheStats,
+	)
+	group.Delete(
+		"/cache",
+		authMiddleware,
+		operationLog(config.OperationTypeDelete, config.EntityTypeCache),
+		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
+		api.CacheApi.PurgeCache,
+	)
+	group.Post(
+		"/cache/warm",
+		authMiddleware,
+		operationLog(config.OperationTypeUpdate, config.EntityTypeCache),
+		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
+		api.CacheApi.WarmCache,
+	)
+	group.Get(
+		"/rate-limit/override/list",
+		authMiddleware,
+		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
+		api.RateLimitApi.ListRateLimitOverrides,
+	)
+	group.Put(
+		"/rate-limit/override",
+		authMiddleware,
+		operationLog(config.OperationTypeUpdate, config.EntityTypeRateLimit, "user_id"),
+		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
+		api.RateLimitApi.SetRateLimitOverride,
+	)
+	group.Delete(
+		"/rate-limit/override",
+		authMiddleware,
+		operationLog(config.OperationTypeDelete, config.EntityTypeRateLimit, "userID"),
+		casbin.RequiresRoles([]string{config.UserRoleAdmin}),
+		api.RateLimitApi.DeleteRateLimitOverride,
+	)
 }
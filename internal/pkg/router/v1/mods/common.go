@@ -76,4 +76,15 @@ func (c *CommonRouter) RegisterCommonRouter(
 		authMiddleware,
 		api.DocumentationApi.GetDocumentationList,
 	)
+	documentationGroup.Get(
+		"/tree",
+		authMiddleware,
+		api.DocumentationApi.GetDocumentationTree,
+	)
+
+	app.Get(
+		"/search",
+		authMiddleware,
+		api.SearchApi.Search,
+	)
 }
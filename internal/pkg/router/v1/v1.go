@@ -2,6 +2,7 @@ package router
 
 import (
 	"fiber-admin/internal/pkg/api/v1"
+	wares "fiber-admin/internal/pkg/middleware/mods"
 	"fiber-admin/internal/pkg/router/v1/mods"
 	"github.com/gofiber/contrib/casbin"
 	"github.com/gofiber/fiber/v2"
@@ -17,14 +18,18 @@ type Router struct {
 
 func (a *Router) RegisterRouter(
 	router *fiber.Router, casbin *casbin.Middleware, idempotencyMiddleware, authMiddleware fiber.Handler,
+	operationLog wares.OperationLog,
 ) {
-	a.registerV1Router(router, casbin, idempotencyMiddleware, authMiddleware)
+	a.registerV1Router(router, casbin, idempotencyMiddleware, authMiddleware, operationLog)
 }
 
 func (a *Router) registerV1Router(
 	router *fiber.Router, casbin *casbin.Middleware, idempotencyMiddleware, authMiddleware fiber.Handler,
+	operationLog wares.OperationLog,
 ) {
 	v1Router := (*router).Group(v1Prefix)
-	a.AdminRouter.RegisterAdminRouter(v1Router, a.ApiV1.AdminApi, casbin, idempotencyMiddleware, authMiddleware)
+	a.AdminRouter.RegisterAdminRouter(
+		v1Router, a.ApiV1.AdminApi, casbin, idempotencyMiddleware, authMiddleware, operationLog,
+	)
 	a.CommonRouter.RegisterCommonRouter(v1Router, a.ApiV1.CommonApi, casbin, authMiddleware)
 }
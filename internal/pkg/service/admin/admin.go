@@ -5,8 +5,10 @@ import (
 )
 
 type Admin struct {
+	CacheService         mods.CacheService
 	DocumentationService mods.DocumentationService
 	LogsService          mods.LogsService
 	NoticeService        mods.NoticeService
+	StatsService         mods.StatsService
 	UserService          mods.UserService
 }
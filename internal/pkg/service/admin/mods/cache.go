@@ -0,0 +1,187 @@
+package mods
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"fiber-admin/internal/pkg/config"
+	"fiber-admin/internal/pkg/dao"
+	daos "fiber-admin/internal/pkg/dao/mods"
+	"fiber-admin/internal/pkg/domain/vo/admin"
+	"fiber-admin/internal/pkg/service"
+	"fiber-admin/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// cacheEntityPrefixes maps the entities to the prefix their DAO caches them under
+var cacheEntityPrefixes = map[string]string{
+	config.CacheEntityUser:          config.UserCachePrefix,
+	config.CacheEntityNotice:        config.NoticeCachePrefix,
+	config.CacheEntityDocumentation: config.DocumentationCachePrefix,
+	config.CacheEntityStats:         config.StatsCachePrefix,
+}
+
+// cacheStatsPrefixes are the prefixes GetCacheStats counts the keys under
+var cacheStatsPrefixes = []string{
+	config.UserCachePrefix,
+	config.NoticeCachePrefix,
+	config.DocumentationCachePrefix,
+	config.StatsCachePrefix,
+	config.DaoCachePrefix,
+	config.TokenBlacklistCachePrefix,
+	config.IdempotencyCachePrefix,
+	config.RateLimitOverrideCachePrefix,
+	config.RateLimitCachePrefix,
+	config.CacheGenerationPrefix,
+	config.LoginLogStreamKey,
+	config.LoginLogDeadLetterKey,
+	config.OperationLogStreamKey,
+	config.OperationLogDeadLetterKey,
+}
+
+type CacheService interface {
+	GetCacheStats(ctx context.Context) (*admin.GetCacheStatsResponse, error)
+	PurgeCache(ctx context.Context, entity, prefix *string) (*admin.PurgeCacheResponse, error)
+	WarmCache(ctx context.Context, entities []string) (*admin.WarmCacheResponse, error)
+}
+
+type CacheServiceImpl struct {
+	core             *service.Core
+	cache            *dao.Cache
+	noticeDao        daos.NoticeDao
+	documentationDao daos.DocumentationDao
+}
+
+func NewCacheService(
+	core *service.Core, cache *dao.Cache, noticeDao daos.NoticeDao, documentationDao daos.DocumentationDao,
+) CacheService {
+	return &CacheServiceImpl{
+		core:             core,
+		cache:            cache,
+		noticeDao:        noticeDao,
+		documentationDao: documentationDao,
+	}
+}
+
+func (c CacheServiceImpl) GetCacheStats(ctx context.Context) (*admin.GetCacheStatsResponse, error) {
+	stats, err := c.cache.Stats(ctx, cacheStatsPrefixes)
+	if err != nil {
+		c.core.Logger.Error("failed to get cache stats", zap.Error(err))
+		return nil, errors.OperationFailed(fmt.Errorf("failed to get cache stats"))
+	}
+	prefixes := make([]*admin.CachePrefixStatsResponse, 0, len(stats.Prefixes))
+	for _, prefix := range stats.Prefixes {
+		prefixes = append(
+			prefixes, &admin.CachePrefixStatsResponse{
+				Prefix:      prefix.Prefix,
+				Keys:        prefix.Keys,
+				MemoryBytes: prefix.Memory,
+			},
+		)
+	}
+	return &admin.GetCacheStatsResponse{
+		Backend:      c.core.Config.CacheConfig.Backend,
+		Namespace:    c.core.Config.CacheConfig.Namespace,
+		Prefixes:     prefixes,
+		LocalEntries: stats.LocalEntries,
+	}, nil
+}
+
+// PurgeCache deletes the cache of the entity, or the keys under a prefix of the DAO caches. The generation of an
+// entity cache is bumped first, so that every instance stops reading it at once, including from its local cache.
+// Only the DAO caches can be purged: the other keys, such as the token blacklist and the log streams, are not caches.
+func (c CacheServiceImpl) PurgeCache(ctx context.Context, entity, prefix *string) (*admin.PurgeCacheResponse, error) {
+	var purged string
+	switch {
+	case entity != nil:
+		entityPrefix, ok := cacheEntityPrefixes[*entity]
+		if !ok {
+			return nil, errors.InvalidRequest(fmt.Errorf("unknown cache entity %s", *entity))
+		}
+		if entityPrefix != config.StatsCachePrefix { // Stats keys have no generation
+			if err := c.cache.Invalidate(ctx, entityPrefix); err != nil {
+				c.core.Logger.Error("failed to invalidate cache", zap.Error(err), zap.String("prefix", entityPrefix))
+				return nil, errors.OperationFailed(fmt.Errorf("failed to purge cache"))
+			}
+		}
+		purged = entityPrefix
+	case prefix != nil && strings.HasPrefix(*prefix, config.DaoCachePrefix+":"):
+		purged = *prefix
+	default:
+		return nil, errors.InvalidRequest(fmt.Errorf("prefix should start with %s:", config.DaoCachePrefix))
+	}
+	deleted, err := c.cache.Flush(ctx, &purged)
+	if err != nil {
+		c.core.Logger.Error("failed to flush cache", zap.Error(err), zap.String("prefix", purged))
+		return nil, errors.OperationFailed(fmt.Errorf("failed to purge cache"))
+	}
+	c.core.Logger.Info("cache purged", zap.String("prefix", purged), zap.Int64("deleted", deleted))
+	return &admin.PurgeCacheResponse{Prefix: purged, Deleted: deleted}, nil
+}
+
+// WarmCache loads the first pages of the public notice and documentation lists into the cache, along with the
+// documentation outline, so that the first requests after a purge or a deployment do not all reach MongoDB. Lists
+// already cached are left as they are.
+func (c CacheServiceImpl) WarmCache(ctx context.Context, entities []string) (*admin.WarmCacheResponse, error) {
+	if len(entities) == 0 {
+		entities = []string{config.CacheEntityNotice, config.CacheEntityDocumentation}
+	}
+	resp := &admin.WarmCacheResponse{Warmed: make([]*admin.CacheWarmResponse, 0, len(entities))}
+	for _, entity := range entities {
+		var (
+			lists int64
+			err   error
+		)
+		switch entity {
+		case config.CacheEntityNotice:
+			lists, err = c.warmPages(
+				func(offset, limit int64) (int, *int64, error) {
+					notices, total, err := c.noticeDao.GetNoticeList(
+						ctx, offset, limit, false, nil, nil, nil, nil, nil,
+					)
+					return len(notices), total, err
+				},
+			)
+		case config.CacheEntityDocumentation:
+			lists, err = c.warmPages(
+				func(offset, limit int64) (int, *int64, error) {
+					documentations, total, err := c.documentationDao.GetDocumentationList(
+						ctx, offset, limit, false, nil, nil, nil, nil, nil, nil,
+					)
+					return len(documentations), total, err
+				},
+			)
+			if err == nil {
+				if _, err = c.documentationDao.GetDocumentationOutline(ctx); err == nil {
+					lists++
+				}
+			}
+		default:
+			return nil, errors.InvalidRequest(fmt.Errorf("cache of %s cannot be warmed", entity))
+		}
+		if err != nil {
+			return nil, errors.OperationFailed(fmt.Errorf("failed to warm %s cache", entity))
+		}
+		resp.Warmed = append(resp.Warmed, &admin.CacheWarmResponse{Entity: entity, Lists: lists})
+	}
+	return resp, nil
+}
+
+// warmPages loads the pages of the warm config through load, stopping after the last page of the list. It returns
+// the number of pages loaded.
+func (c CacheServiceImpl) warmPages(load func(offset, limit int64) (int, *int64, error)) (int64, error) {
+	warmConfig := c.core.Config.CacheConfig.WarmConfig
+	var pages int64
+	for ; pages < warmConfig.Pages; pages++ {
+		offset := pages * warmConfig.PageSize
+		count, total, err := load(offset, warmConfig.PageSize)
+		if err != nil {
+			return pages, err
+		}
+		if offset+int64(count) >= *total {
+			return pages + 1, nil
+		}
+	}
+	return pages, nil
+}
@@ -4,68 +4,549 @@ import (
 	"context"
 	e "errors"
 	"fmt"
+	"sort"
+	"time"
 
+	"fiber-admin/internal/pkg/config"
 	dao "fiber-admin/internal/pkg/dao/mods"
+	"fiber-admin/internal/pkg/domain/entity"
+	"fiber-admin/internal/pkg/domain/vo/admin"
 	"fiber-admin/internal/pkg/service"
 	"fiber-admin/pkg/errors"
+	"fiber-admin/pkg/utils/slug"
+	"github.com/pmezard/go-difflib/difflib"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultDocumentationSlug = "documentation"
+
+	initialRevisionSummary  = "Initial revision"
+	baselineRevisionSummary = "Baseline revision"
+	restoreRevisionSummary  = "Restore revision %d"
 )
 
 type DocumentationService interface {
-	InsertDocumentation(ctx context.Context, title, content *string) (string, error)
-	UpdateDocumentation(ctx context.Context, documentationID *primitive.ObjectID, title, content *string) error
-	DeleteDocumentation(ctx context.Context, documentationID *primitive.ObjectID) error
+	InsertDocumentation(
+		ctx context.Context, title, content, slug *string, parentID *primitive.ObjectID, position *int64,
+		category *string, tags []string,
+	) (string, error)
+	UpdateDocumentation(
+		ctx context.Context, documentationID *primitive.ObjectID, version *int64, title, content, slug, category *string,
+		tags []string, summary *string,
+	) error
+	DeleteDocumentation(ctx context.Context, documentationID *primitive.ObjectID, version *int64) error
+	MoveDocumentation(
+		ctx context.Context, documentationID, parentID *primitive.ObjectID, position *int64,
+	) error
+	ReorderDocumentation(
+		ctx context.Context, parentID *primitive.ObjectID, documentationIDList []primitive.ObjectID,
+	) error
+	GetDocumentationRevision(
+		ctx context.Context, documentationID *primitive.ObjectID, revision *int64,
+	) (*admin.GetDocumentationRevisionResponse, error)
+	GetDocumentationRevisionList(
+		ctx context.Context, documentationID *primitive.ObjectID, page, pageSize *int64, desc *bool,
+	) (*admin.GetDocumentationRevisionListResponse, error)
+	DiffDocumentationRevision(
+		ctx context.Context, documentationID *primitive.ObjectID, fromRevision, toRevision *int64,
+	) (*admin.DiffDocumentationRevisionResponse, error)
+	RestoreDocumentationRevision(
+		ctx context.Context, documentationID *primitive.ObjectID, version, revision *int64, summary *string,
+	) (*admin.RestoreDocumentationRevisionResponse, error)
 }
 
 type DocumentationServiceImpl struct {
-	core             *service.Core
-	documentationDao dao.DocumentationDao
+	core                     *service.Core
+	documentationDao         dao.DocumentationDao
+	documentationRevisionDao dao.DocumentationRevisionDao
 }
 
-func NewDocumentationService(core *service.Core, documentationDao dao.DocumentationDao) DocumentationService {
+func NewDocumentationService(
+	core *service.Core, documentationDao dao.DocumentationDao, documentationRevisionDao dao.DocumentationRevisionDao,
+) DocumentationService {
 	return &DocumentationServiceImpl{
-		core:             core,
-		documentationDao: documentationDao,
+		core:                     core,
+		documentationDao:         documentationDao,
+		documentationRevisionDao: documentationRevisionDao,
 	}
 }
 
-func (d DocumentationServiceImpl) InsertDocumentation(ctx context.Context, title, content *string) (string, error) {
-	documentationID, err := d.documentationDao.InsertDocumentation(ctx, *title, *content)
+func (d DocumentationServiceImpl) InsertDocumentation(
+	ctx context.Context, title, content, slug *string, parentID *primitive.ObjectID, position *int64,
+	category *string, tags []string,
+) (string, error) {
+	outline, err := d.getOutline(ctx)
+	if err != nil {
+		return "", err
+	}
+	if parentID != nil {
+		if _, ok := outline[*parentID]; !ok {
+			return "", errors.NotFound(fmt.Errorf("parent documentation (id: %s) not found", parentID.Hex()))
+		}
+	}
+	var documentationSlug string
+	if slug != nil {
+		documentationSlug = *slug
+	} else if documentationSlug, err = d.uniqueSlug(ctx, *title); err != nil {
+		return "", err
+	}
+	var documentationCategory string
+	if category != nil {
+		documentationCategory = *category
+	}
+	siblings := childrenOf(outline, parentID)
+	documentationID, err := d.documentationDao.InsertDocumentation(
+		ctx, *title, *content, documentationSlug, parentID, int64(len(siblings)), documentationCategory, tags,
+	)
 	if err != nil {
 		if mongo.IsDuplicateKeyError(err) {
-			return "", errors.DuplicateKeyError(fmt.Errorf("documentation with title %s already exists", *title))
+			return "", errors.DuplicateKeyError(
+				fmt.Errorf("documentation with slug %s already exists", documentationSlug),
+			)
 		} else {
 			return "", errors.OperationFailed(fmt.Errorf("failed to insert documentation")) // TODO: Consider index error, duplicate key error, etc.
 		}
 	}
+	if err = d.documentationRevisionDao.InsertDocumentationRevision(
+		ctx, documentationID, 1, d.authorID(ctx), *title, *content, initialRevisionSummary,
+	); err != nil {
+		// A documentation is never left without its first revision, which later revisions are diffed against
+		if err := d.documentationDao.DeleteDocumentation(ctx, documentationID, nil); err != nil {
+			d.core.Logger.Error(
+				"failed to roll back documentation without initial revision",
+				zap.Error(err), zap.String("documentationID", documentationID.Hex()),
+			)
+		}
+		return "", errors.OperationFailed(fmt.Errorf("failed to insert initial documentation revision"))
+	}
+	if position != nil && *position < int64(len(siblings)) {
+		if err = d.documentationDao.UpdateDocumentationPosition(
+			ctx, parentID, nil, insertAt(siblings, documentationID, *position),
+		); err != nil {
+			return "", errors.OperationFailed(
+				fmt.Errorf("failed to position documentation (id: %s)", documentationID.Hex()),
+			)
+		}
+	}
 	return documentationID.Hex(), nil
 }
 
+// UpdateDocumentation updates the documentation, only if it still has the given version unless version is nil.
 func (d DocumentationServiceImpl) UpdateDocumentation(
-	ctx context.Context, documentationID *primitive.ObjectID, title, content *string,
+	ctx context.Context, documentationID *primitive.ObjectID, version *int64, title, content, slug, category *string,
+	tags []string, summary *string,
 ) error {
-	err := d.documentationDao.UpdateDocumentation(ctx, *documentationID, title, content)
+	if err := d.ensureBaselineRevision(ctx, *documentationID); err != nil {
+		return err
+	}
+	documentation, err := d.documentationDao.UpdateDocumentation(
+		ctx, *documentationID, version, title, content, slug, category, tags,
+	)
 	if err != nil {
 		if mongo.IsDuplicateKeyError(err) {
-			return errors.DuplicateKeyError(fmt.Errorf("documentation with title %s already exists", *title))
+			return errors.DuplicateKeyError(fmt.Errorf("documentation with slug %s already exists", *slug))
+		} else if e.Is(err, dao.ErrVersionConflict) {
+			return errors.PreconditionFailed(
+				fmt.Errorf("documentation (id: %s) has been modified", documentationID.Hex()),
+			)
 		} else if e.Is(err, mongo.ErrNoDocuments) {
 			return errors.NotFound(fmt.Errorf("documentation (id: %s) not found", documentationID.Hex()))
 		} else {
 			return errors.OperationFailed(fmt.Errorf("failed to update documentation (id: %s)", documentationID.Hex()))
 		}
 	}
+	var changeSummary string
+	if summary != nil {
+		changeSummary = *summary
+	}
+	if _, err = d.insertRevision(ctx, documentation, changeSummary); err != nil {
+		return err
+	}
 	return nil
 }
 
-func (d DocumentationServiceImpl) DeleteDocumentation(ctx context.Context, documentationID *primitive.ObjectID) error {
-	err := d.documentationDao.DeleteDocumentation(ctx, *documentationID)
+// DeleteDocumentation deletes the documentation, only if it still has the given version unless version is nil.
+func (d DocumentationServiceImpl) DeleteDocumentation(
+	ctx context.Context, documentationID *primitive.ObjectID, version *int64,
+) error {
+	outline, err := d.getOutline(ctx)
 	if err != nil {
-		if e.Is(err, mongo.ErrNoDocuments) {
+		return err
+	}
+	if len(childrenOf(outline, documentationID)) > 0 {
+		return errors.InvalidRequest(
+			fmt.Errorf("documentation (id: %s) has child documentation, move or delete them first", documentationID.Hex()),
+		)
+	}
+	err = d.documentationDao.DeleteDocumentation(ctx, *documentationID, version)
+	if err != nil {
+		if e.Is(err, dao.ErrVersionConflict) {
+			return errors.PreconditionFailed(
+				fmt.Errorf("documentation (id: %s) has been modified", documentationID.Hex()),
+			)
+		} else if e.Is(err, mongo.ErrNoDocuments) {
 			return errors.NotFound(fmt.Errorf("documentation (id: %s) not found", documentationID.Hex()))
 		} else {
 			return errors.OperationFailed(fmt.Errorf("failed to delete documentation (id: %s)", documentationID.Hex()))
 		}
 	}
+	if _, err = d.documentationRevisionDao.DeleteDocumentationRevisionList(ctx, documentationID); err != nil {
+		d.core.Logger.Error(
+			"failed to delete documentation revisions",
+			zap.Error(err), zap.String("documentationID", documentationID.Hex()),
+		)
+	}
+	return nil
+}
+
+// MoveDocumentation moves a documentation under parentID (top level when nil) at the given position among its new
+// siblings. Moving a documentation under itself or one of its descendants is rejected.
+func (d DocumentationServiceImpl) MoveDocumentation(
+	ctx context.Context, documentationID, parentID *primitive.ObjectID, position *int64,
+) error {
+	outline, err := d.getOutline(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := outline[*documentationID]; !ok {
+		return errors.NotFound(fmt.Errorf("documentation (id: %s) not found", documentationID.Hex()))
+	}
+	for ancestorID := parentID; ancestorID != nil; {
+		if *ancestorID == *documentationID {
+			return errors.InvalidRequest(
+				fmt.Errorf("cannot move documentation (id: %s) under itself or its descendant", documentationID.Hex()),
+			)
+		}
+		ancestor, ok := outline[*ancestorID]
+		if !ok {
+			return errors.NotFound(fmt.Errorf("parent documentation (id: %s) not found", ancestorID.Hex()))
+		}
+		ancestorID = ancestor.ParentID
+	}
+	var siblings []primitive.ObjectID
+	for _, siblingID := range childrenOf(outline, parentID) {
+		if siblingID != *documentationID {
+			siblings = append(siblings, siblingID)
+		}
+	}
+	if err = d.documentationDao.UpdateDocumentationPosition(
+		ctx, parentID, documentationID, insertAt(siblings, *documentationID, *position),
+	); err != nil {
+		return errors.OperationFailed(fmt.Errorf("failed to move documentation (id: %s)", documentationID.Hex()))
+	}
+	return nil
+}
+
+// ReorderDocumentation sets the order of the children of parentID (top level when nil). The list must contain every
+// child exactly once.
+func (d DocumentationServiceImpl) ReorderDocumentation(
+	ctx context.Context, parentID *primitive.ObjectID, documentationIDList []primitive.ObjectID,
+) error {
+	outline, err := d.getOutline(ctx)
+	if err != nil {
+		return err
+	}
+	if parentID != nil {
+		if _, ok := outline[*parentID]; !ok {
+			return errors.NotFound(fmt.Errorf("parent documentation (id: %s) not found", parentID.Hex()))
+		}
+	}
+	children := make(map[primitive.ObjectID]bool)
+	for _, childID := range childrenOf(outline, parentID) {
+		children[childID] = true
+	}
+	if len(documentationIDList) != len(children) {
+		return errors.InvalidRequest(fmt.Errorf("documentation list must contain every child exactly once"))
+	}
+	for _, documentationID := range documentationIDList {
+		if !children[documentationID] {
+			return errors.InvalidRequest(fmt.Errorf("documentation list must contain every child exactly once"))
+		}
+		delete(children, documentationID)
+	}
+	if err = d.documentationDao.UpdateDocumentationPosition(ctx, parentID, nil, documentationIDList); err != nil {
+		return errors.OperationFailed(fmt.Errorf("failed to reorder documentation"))
+	}
+	return nil
+}
+
+func (d DocumentationServiceImpl) GetDocumentationRevision(
+	ctx context.Context, documentationID *primitive.ObjectID, revision *int64,
+) (*admin.GetDocumentationRevisionResponse, error) {
+	documentationRevision, err := d.getRevision(ctx, *documentationID, *revision)
+	if err != nil {
+		return nil, err
+	}
+	return &admin.GetDocumentationRevisionResponse{
+		DocumentID: documentationRevision.DocumentID.Hex(),
+		Revision:   documentationRevision.Revision,
+		Title:      documentationRevision.Title,
+		Content:    documentationRevision.Content,
+		AuthorID:   documentationRevision.AuthorID.Hex(),
+		Summary:    documentationRevision.Summary,
+		CreatedAt:  documentationRevision.CreatedAt.Format(time.RFC3339),
+	}, nil
+}
+
+func (d DocumentationServiceImpl) GetDocumentationRevisionList(
+	ctx context.Context, documentationID *primitive.ObjectID, page, pageSize *int64, desc *bool,
+) (*admin.GetDocumentationRevisionListResponse, error) {
+	offset := (*page - 1) * *pageSize
+	documentationRevisions, count, err := d.documentationRevisionDao.GetDocumentationRevisionList(
+		ctx, *documentationID, offset, *pageSize, *desc,
+	)
+	if err != nil {
+		return nil, errors.OperationFailed(
+			fmt.Errorf("failed to get revision list of documentation (id: %s)", documentationID.Hex()),
+		)
+	}
+	resp := make([]*admin.DocumentationRevisionSummary, 0, len(documentationRevisions))
+	for _, documentationRevision := range documentationRevisions {
+		resp = append(
+			resp, &admin.DocumentationRevisionSummary{
+				Revision:  documentationRevision.Revision,
+				Title:     documentationRevision.Title,
+				AuthorID:  documentationRevision.AuthorID.Hex(),
+				Summary:   documentationRevision.Summary,
+				CreatedAt: documentationRevision.CreatedAt.Format(time.RFC3339),
+			},
+		)
+	}
+	return &admin.GetDocumentationRevisionListResponse{
+		Total:                            *count,
+		DocumentationRevisionSummaryList: resp,
+	}, nil
+}
+
+func (d DocumentationServiceImpl) DiffDocumentationRevision(
+	ctx context.Context, documentationID *primitive.ObjectID, fromRevision, toRevision *int64,
+) (*admin.DiffDocumentationRevisionResponse, error) {
+	from, err := d.getRevision(ctx, *documentationID, *fromRevision)
+	if err != nil {
+		return nil, err
+	}
+	to, err := d.getRevision(ctx, *documentationID, *toRevision)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := difflib.GetUnifiedDiffString(
+		difflib.UnifiedDiff{
+			A:        difflib.SplitLines(from.Content),
+			B:        difflib.SplitLines(to.Content),
+			FromFile: fmt.Sprintf("revision %d", from.Revision),
+			ToFile:   fmt.Sprintf("revision %d", to.Revision),
+			Context:  3,
+		},
+	)
+	if err != nil {
+		d.core.Logger.Error(
+			"failed to diff documentation revisions", zap.Error(err),
+			zap.String("documentationID", documentationID.Hex()),
+		)
+		return nil, errors.ServiceError(fmt.Errorf("failed to diff documentation revisions"))
+	}
+	return &admin.DiffDocumentationRevisionResponse{
+		DocumentID:   documentationID.Hex(),
+		FromRevision: from.Revision,
+		ToRevision:   to.Revision,
+		FromTitle:    from.Title,
+		ToTitle:      to.Title,
+		Diff:         diff,
+	}, nil
+}
+
+// RestoreDocumentationRevision restores the revision, only if the documentation still has the given version unless
+// version is nil.
+func (d DocumentationServiceImpl) RestoreDocumentationRevision(
+	ctx context.Context, documentationID *primitive.ObjectID, version, revision *int64, summary *string,
+) (*admin.RestoreDocumentationRevisionResponse, error) {
+	documentationRevision, err := d.getRevision(ctx, *documentationID, *revision)
+	if err != nil {
+		return nil, err
+	}
+	documentation, err := d.documentationDao.UpdateDocumentation(
+		ctx, *documentationID, version, &documentationRevision.Title, &documentationRevision.Content, nil, nil, nil,
+	)
+	if err != nil {
+		if e.Is(err, dao.ErrVersionConflict) {
+			return nil, errors.PreconditionFailed(
+				fmt.Errorf("documentation (id: %s) has been modified", documentationID.Hex()),
+			)
+		} else if e.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.NotFound(fmt.Errorf("documentation (id: %s) not found", documentationID.Hex()))
+		} else {
+			return nil, errors.OperationFailed(
+				fmt.Errorf("failed to restore documentation (id: %s)", documentationID.Hex()),
+			)
+		}
+	}
+	changeSummary := fmt.Sprintf(restoreRevisionSummary, *revision)
+	if summary != nil {
+		changeSummary = *summary
+	}
+	newRevision, err := d.insertRevision(ctx, documentation, changeSummary)
+	if err != nil {
+		return nil, err
+	}
+	return &admin.RestoreDocumentationRevisionResponse{
+		DocumentID: documentationID.Hex(),
+		Revision:   newRevision,
+	}, nil
+}
+
+// getOutline returns every documentation, without content, keyed by ID.
+func (d DocumentationServiceImpl) getOutline(
+	ctx context.Context,
+) (map[primitive.ObjectID]entity.DocumentationModel, error) {
+	documentationList, err := d.documentationDao.GetDocumentationOutline(ctx)
+	if err != nil {
+		return nil, errors.OperationFailed(fmt.Errorf("failed to get documentation tree"))
+	}
+	outline := make(map[primitive.ObjectID]entity.DocumentationModel, len(documentationList))
+	for _, documentation := range documentationList {
+		outline[documentation.DocumentID] = documentation
+	}
+	return outline, nil
+}
+
+// uniqueSlug derives a slug from title, suffixing it with a counter until no documentation uses it.
+func (d DocumentationServiceImpl) uniqueSlug(ctx context.Context, title string) (string, error) {
+	base := slug.Make(title)
+	if base == "" {
+		base = defaultDocumentationSlug
+	}
+	candidate := base
+	for i := 2; ; i++ {
+		_, err := d.documentationDao.GetDocumentationBySlug(ctx, candidate)
+		if e.Is(err, mongo.ErrNoDocuments) {
+			return candidate, nil
+		} else if err != nil {
+			return "", errors.OperationFailed(fmt.Errorf("failed to generate documentation slug"))
+		}
+		candidate = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// getRevision returns the given revision of a documentation, mapping dao errors to app errors.
+func (d DocumentationServiceImpl) getRevision(
+	ctx context.Context, documentationID primitive.ObjectID, revision int64,
+) (*entity.DocumentationRevisionModel, error) {
+	documentationRevision, err := d.documentationRevisionDao.GetDocumentationRevision(ctx, documentationID, revision)
+	if err != nil {
+		if e.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.NotFound(
+				fmt.Errorf("revision %d of documentation (id: %s) not found", revision, documentationID.Hex()),
+			)
+		}
+		return nil, errors.OperationFailed(
+			fmt.Errorf("failed to get revision %d of documentation (id: %s)", revision, documentationID.Hex()),
+		)
+	}
+	return documentationRevision, nil
+}
+
+// insertRevision snapshots a documentation, as returned by the update which wrote it, as the revision of its version.
+// Taking both from the update keeps concurrent updates from snapshotting each other's content or racing for a number.
+func (d DocumentationServiceImpl) insertRevision(
+	ctx context.Context, documentation *entity.DocumentationModel, summary string,
+) (int64, error) {
+	err := d.documentationRevisionDao.InsertDocumentationRevision(
+		ctx, documentation.DocumentID, documentation.Version, d.authorID(ctx), documentation.Title,
+		documentation.Content, summary,
+	)
+	if err != nil {
+		return 0, errors.OperationFailed(
+			fmt.Errorf("failed to insert revision of documentation (id: %s)", documentation.DocumentID.Hex()),
+		)
+	}
+	return documentation.Version, nil
+}
+
+// ensureBaselineRevision snapshots documentation created before revisions existed, so the first update does not
+// discard its original content. A concurrent update may have taken the revision already, which is as good.
+func (d DocumentationServiceImpl) ensureBaselineRevision(ctx context.Context, documentationID primitive.ObjectID) error {
+	_, err := d.documentationRevisionDao.GetLatestDocumentationRevision(ctx, documentationID)
+	if err == nil {
+		return nil
+	}
+	if !e.Is(err, mongo.ErrNoDocuments) {
+		return errors.OperationFailed(
+			fmt.Errorf("failed to get revision of documentation (id: %s)", documentationID.Hex()),
+		)
+	}
+	documentation, err := d.documentationDao.GetDocumentationByID(ctx, documentationID)
+	if err != nil {
+		if e.Is(err, mongo.ErrNoDocuments) {
+			return errors.NotFound(fmt.Errorf("documentation (id: %s) not found", documentationID.Hex()))
+		}
+		return errors.OperationFailed(fmt.Errorf("failed to get documentation (id: %s)", documentationID.Hex()))
+	}
+	if err = d.documentationRevisionDao.InsertDocumentationRevision(
+		ctx, documentationID, documentation.Version, primitive.NilObjectID, documentation.Title, documentation.Content,
+		baselineRevisionSummary,
+	); err != nil && !mongo.IsDuplicateKeyError(err) {
+		return errors.OperationFailed(
+			fmt.Errorf("failed to insert baseline revision of documentation (id: %s)", documentationID.Hex()),
+		)
+	}
 	return nil
 }
+
+// authorID returns the ID of the current user, or NilObjectID when the context carries none (e.g. system calls).
+func (d DocumentationServiceImpl) authorID(ctx context.Context) primitive.ObjectID {
+	userIDHex, ok := ctx.Value(config.UserIDKey).(string)
+	if !ok {
+		return primitive.NilObjectID
+	}
+	userID, err := primitive.ObjectIDFromHex(userIDHex)
+	if err != nil {
+		return primitive.NilObjectID
+	}
+	return userID
+}
+
+// childrenOf returns the IDs of the children of parentID (top level when nil) ordered by position.
+func childrenOf(
+	outline map[primitive.ObjectID]entity.DocumentationModel, parentID *primitive.ObjectID,
+) []primitive.ObjectID {
+	var children []entity.DocumentationModel
+	for _, documentation := range outline {
+		if sameParent(documentation.ParentID, parentID) {
+			children = append(children, documentation)
+		}
+	}
+	sort.Slice(
+		children, func(i, j int) bool {
+			if children[i].Position != children[j].Position {
+				return children[i].Position < children[j].Position
+			}
+			return children[i].DocumentID.Hex() < children[j].DocumentID.Hex()
+		},
+	)
+	childIDs := make([]primitive.ObjectID, 0, len(children))
+	for _, child := range children {
+		childIDs = append(childIDs, child.DocumentID)
+	}
+	return childIDs
+}
+
+func sameParent(a, b *primitive.ObjectID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// insertAt returns a copy of list with id inserted at position, clamped to the end of the list.
+func insertAt(list []primitive.ObjectID, id primitive.ObjectID, position int64) []primitive.ObjectID {
+	if position > int64(len(list)) {
+		position = int64(len(list))
+	}
+	result := make([]primitive.ObjectID, 0, len(list)+1)
+	result = append(result, list[:position]...)
+	result = append(result, id)
+	return append(result, list[position:]...)
+}
@@ -2,13 +2,22 @@ package mods
 
 import (
 	"context"
+	"encoding/csv"
+	e "errors"
 	"fmt"
+	"io"
+	"strings"
 	"time"
 
+	"fiber-admin/internal/pkg/config"
 	dao "fiber-admin/internal/pkg/dao/mods"
+	"fiber-admin/internal/pkg/domain/entity"
 	"fiber-admin/internal/pkg/domain/vo/admin"
 	"fiber-admin/internal/pkg/service"
+	"fiber-admin/pkg/archive"
 	"fiber-admin/pkg/errors"
+	"github.com/goccy/go-json"
+	"go.uber.org/zap"
 )
 
 type LogsService interface {
@@ -20,28 +29,41 @@ type LogsService interface {
 		ctx context.Context, page, pageSize *int64, desc *bool, query, operation, entityType, status *string,
 		createStartTime, createEndTime *time.Time,
 	) (*admin.GetOperationLogListResponse, error)
+	VerifyOperationLogChain(ctx context.Context) (*admin.VerifyOperationLogChainResponse, error)
+	ExportLoginLogList(
+		ctx context.Context, w io.Writer, format *string, desc *bool, query *string,
+		createStartTime, createEndTime *time.Time,
+	) error
+	ExportOperationLogList(
+		ctx context.Context, w io.Writer, format *string, desc *bool, query, operation, entityType, status *string,
+		createStartTime, createEndTime *time.Time,
+	) error
+	GetLogArchiveList(ctx context.Context) (*admin.GetLogArchiveListResponse, error)
+	ImportLogArchive(ctx context.Context, name *string) (*admin.ImportLogArchiveResponse, error)
 }
 
 type LogsServiceImpl struct {
 	core            *service.Core
 	loginLogDao     dao.LoginLogDao
 	operationLogDao dao.OperationLogDao
+	archiveStore    archive.Store
 }
 
 func NewLogsService(
 	core *service.Core, loginLogDao dao.LoginLogDao, operationLogDao dao.OperationLogDao,
+	archiveStore archive.Store,
 ) LogsService {
 	return &LogsServiceImpl{
 		core:            core,
 		loginLogDao:     loginLogDao,
 		operationLogDao: operationLogDao,
+		archiveStore:    archiveStore,
 	}
 }
 
 func (l LogsServiceImpl) GetLoginLogList(
 	ctx context.Context, page, pageSize *int64, desc *bool, query *string, createStartTime, createEndTime *time.Time,
 ) (*admin.GetLoginLogListResponse, error) {
-	l.loginLogDao.SyncLoginLog(ctx) // Sync log before querying
 	offset := (*page - 1) * *pageSize
 	loginLogs, total, err := l.loginLogDao.GetLoginLogList(
 		ctx, offset, *pageSize, *desc, createStartTime, createEndTime, nil, nil, nil, query,
@@ -51,17 +73,7 @@ func (l LogsServiceImpl) GetLoginLogList(
 	}
 	loginLogList := make([]*admin.GetLoginLogResponse, 0, len(loginLogs))
 	for _, loginLog := range loginLogs {
-		loginLogList = append(
-			loginLogList, &admin.GetLoginLogResponse{
-				LoginLogID: loginLog.LoginLogID.Hex(),
-				UserID:     loginLog.UserID.Hex(),
-				Username:   loginLog.Username,
-				Email:      loginLog.Email,
-				IPAddress:  loginLog.IPAddress,
-				UserAgent:  loginLog.UserAgent,
-				CreatedAt:  loginLog.CreatedAt.Format(time.RFC3339),
-			},
-		)
+		loginLogList = append(loginLogList, toGetLoginLogResponse(&loginLog))
 	}
 	return &admin.GetLoginLogListResponse{
 		Total:        *total,
@@ -73,7 +85,6 @@ func (l LogsServiceImpl) GetOperationLogList(
 	ctx context.Context, page, pageSize *int64, desc *bool, query, operation, entityType, status *string,
 	createStartTime, createEndTime *time.Time,
 ) (*admin.GetOperationLogListResponse, error) {
-	l.operationLogDao.SyncOperationLog(ctx) // Sync log before querying
 	offset := (*page - 1) * *pageSize
 	operationLogs, total, err := l.operationLogDao.GetOperationLogList(
 		ctx, offset, *pageSize, *desc, createStartTime, createEndTime, nil, nil,
@@ -84,25 +95,367 @@ func (l LogsServiceImpl) GetOperationLogList(
 	}
 	operationLogList := make([]*admin.GetOperationLogResponse, 0, len(operationLogs))
 	for _, operationLog := range operationLogs {
-		operationLogList = append(
-			operationLogList, &admin.GetOperationLogResponse{
-				OperationLogID: operationLog.OperationLogID.Hex(),
-				UserID:         operationLog.UserID.Hex(),
-				Username:       operationLog.Username,
-				Email:          operationLog.Email,
-				IPAddress:      operationLog.IPAddress,
-				UserAgent:      operationLog.UserAgent,
-				Operation:      operationLog.Operation,
-				EntityID:       operationLog.EntityID.Hex(),
-				EntityType:     operationLog.EntityType,
-				Description:    operationLog.Description,
-				Status:         operationLog.Status,
-				CreatedAt:      operationLog.CreatedAt.Format(time.RFC3339),
-			},
-		)
+		operationLogList = append(operationLogList, toGetOperationLogResponse(&operationLog))
 	}
 	return &admin.GetOperationLogListResponse{
 		Total:            *total,
 		OperationLogList: operationLogList,
 	}, nil
 }
+
+func toGetLoginLogResponse(loginLog *entity.LoginLogModel) *admin.GetLoginLogResponse {
+	return &admin.GetLoginLogResponse{
+		LoginLogID: loginLog.LoginLogID.Hex(),
+		UserID:     loginLog.UserID.Hex(),
+		Username:   loginLog.Username,
+		Email:      loginLog.Email,
+		IPAddress:  loginLog.IPAddress,
+		UserAgent:  loginLog.UserAgent,
+		CreatedAt:  loginLog.CreatedAt.Format(time.RFC3339),
+		Browser:    strings.TrimSpace(loginLog.Browser + " " + loginLog.BrowserVersion),
+		OS:         strings.TrimSpace(loginLog.OS + " " + loginLog.OSVersion),
+		Device:     loginLog.Device,
+		Country:    loginLog.Country,
+		City:       loginLog.City,
+		Flags:      append([]string{}, loginLog.Flags...), // Empty rather than null
+	}
+}
+
+func toGetOperationLogResponse(operationLog *entity.OperationLogModel) *admin.GetOperationLogResponse {
+	return &admin.GetOperationLogResponse{
+		OperationLogID: operationLog.OperationLogID.Hex(),
+		UserID:         operationLog.UserID.Hex(),
+		Username:       operationLog.Username,
+		Email:          operationLog.Email,
+		IPAddress:      operationLog.IPAddress,
+		UserAgent:      operationLog.UserAgent,
+		RequestID:      operationLog.RequestID,
+		Operation:      operationLog.Operation,
+		EntityID:       operationLog.EntityID.Hex(),
+		EntityType:     operationLog.EntityType,
+		Description:    operationLog.Description,
+		Status:         operationLog.Status,
+		CreatedAt:      operationLog.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// ExportLoginLogList writes the login logs matching the filters of GetLoginLogList to w as CSV or NDJSON, reading
+// them from a cursor so that any number of them can be exported.
+func (l LogsServiceImpl) ExportLoginLogList(
+	ctx context.Context, w io.Writer, format *string, desc *bool, query *string,
+	createStartTime, createEndTime *time.Time,
+) error {
+	exporter, err := newLogExporter(
+		w, *format,
+		[]string{
+			"login_log_id", "user_id", "username", "email", "ip_address", "user_agent", "created_at", "browser", "os",
+			"device", "country", "city", "flags",
+		},
+	)
+	if err == nil {
+		err = l.loginLogDao.IterateLoginLogList(
+			ctx, *desc, createStartTime, createEndTime, nil, nil, nil, query,
+			func(loginLog *entity.LoginLogModel) error {
+				resp := toGetLoginLogResponse(loginLog)
+				return exporter.write(
+					resp, []string{
+						resp.LoginLogID, resp.UserID, resp.Username, resp.Email, resp.IPAddress, resp.UserAgent,
+						resp.CreatedAt, resp.Browser, resp.OS, resp.Device, resp.Country, resp.City,
+						strings.Join(resp.Flags, ";"),
+					},
+				)
+			},
+		)
+	}
+	if err == nil {
+		err = exporter.flush()
+	}
+	if err != nil {
+		l.core.Logger.Error("failed to export login logs", zap.Error(err))
+		return errors.OperationFailed(fmt.Errorf("failed to export login log list"))
+	}
+	return nil
+}
+
+// ExportOperationLogList writes the operation logs matching the filters of GetOperationLogList to w as CSV or NDJSON,
+// reading them from a cursor so that any number of them can be exported.
+func (l LogsServiceImpl) ExportOperationLogList(
+	ctx context.Context, w io.Writer, format *string, desc *bool, query, operation, entityType, status *string,
+	createStartTime, createEndTime *time.Time,
+) error {
+	exporter, err := newLogExporter(
+		w, *format,
+		[]string{
+			"operation_log_id", "user_id", "username", "email", "ip_address", "user_agent", "request_id",
+			"operation", "entity_id", "entity_type", "description", "status", "created_at",
+		},
+	)
+	if err == nil {
+		err = l.operationLogDao.IterateOperationLogList(
+			ctx, *desc, createStartTime, createEndTime, nil, nil, nil, operation, entityType, status, query,
+			func(operationLog *entity.OperationLogModel) error {
+				resp := toGetOperationLogResponse(operationLog)
+				return exporter.write(
+					resp, []string{
+						resp.OperationLogID, resp.UserID, resp.Username, resp.Email, resp.IPAddress, resp.UserAgent,
+						resp.RequestID, resp.Operation, resp.EntityID, resp.EntityType, resp.Description,
+						resp.Status, resp.CreatedAt,
+					},
+				)
+			},
+		)
+	}
+	if err == nil {
+		err = exporter.flush()
+	}
+	if err != nil {
+		l.core.Logger.Error("failed to export operation logs", zap.Error(err))
+		return errors.OperationFailed(fmt.Errorf("failed to export operation log list"))
+	}
+	return nil
+}
+
+// logExporter writes exported logs as CSV rows or as JSON lines.
+type logExporter struct {
+	csv     *csv.Writer
+	encoder *json.Encoder
+}
+
+func newLogExporter(w io.Writer, format string, header []string) (*logExporter, error) {
+	if format == config.ExportFormatNDJSON {
+		return &logExporter{encoder: json.NewEncoder(w)}, nil
+	}
+	exporter := &logExporter{csv: csv.NewWriter(w)}
+	return exporter, exporter.csv.Write(header)
+}
+
+// write writes record as a JSON line, or row as a CSV row.
+func (x *logExporter) write(record any, row []string) error {
+	if x.encoder != nil {
+		return x.encoder.Encode(record)
+	}
+	for i, cell := range row {
+		// Keep spreadsheets from evaluating user-controlled cells such as the user agent as formulas.
+		if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+			row[i] = "'" + cell
+		}
+	}
+	return x.csv.Write(row)
+}
+
+func (x *logExporter) flush() error {
+	if x.csv == nil {
+		return nil
+	}
+	x.csv.Flush()
+	return x.csv.Error()
+}
+
+// errChainBroken stops walking the operation log chain at the first broken link.
+var errChainBroken = e.New("operation log chain broken")
+
+// VerifyOperationLogChain walks the operation log hash chain in sequence order and reports the first broken link: a
+// missing record, a record whose content no longer matches its hash, a record that differs from a signed checkpoint,
+// or a forged checkpoint.
+func (l LogsServiceImpl) VerifyOperationLogChain(ctx context.Context) (*admin.VerifyOperationLogChainResponse, error) {
+	secret := l.core.Config.AuditConfig.CheckpointSecret
+	if secret == "" {
+		return nil, errors.ServiceError(dao.ErrCheckpointSecretMissing)
+	}
+	head, err := l.operationLogDao.GetOperationLogChainHead(ctx)
+	if err != nil {
+		return nil, errors.OperationFailed(fmt.Errorf("failed to get operation log chain head"))
+	}
+	checkpointList, err := l.operationLogDao.GetOperationLogCheckpointList(ctx)
+	if err != nil {
+		return nil, errors.OperationFailed(fmt.Errorf("failed to get operation log checkpoint list"))
+	}
+
+	resp := &admin.VerifyOperationLogChainResponse{Valid: true, Checkpoints: int64(len(checkpointList))}
+	breakAt := func(sequence int64, operationLogID, reason string) {
+		resp.Valid = false
+		resp.BrokenLink = &admin.OperationLogBrokenLink{
+			Sequence: sequence, OperationLogID: operationLogID, Reason: reason,
+		}
+	}
+	checkpoints := make(map[int64]*entity.OperationLogCheckpointModel, len(checkpointList))
+	for i := range checkpointList {
+		checkpoint := &checkpointList[i]
+		if dao.OperationLogCheckpointSignature(secret, checkpoint) != checkpoint.Signature {
+			breakAt(checkpoint.Sequence, "", "checkpoint signature is invalid")
+			return resp, nil
+		}
+		checkpoints[checkpoint.Sequence] = checkpoint
+	}
+
+	var prevHash string
+	err = l.operationLogDao.IterateOperationLogChain(
+		ctx, func(operationLog *entity.OperationLogModel) error {
+			var (
+				sequence = operationLog.Sequence
+				reason   string
+			)
+			switch {
+			case resp.LastSequence == 0 && sequence > 1:
+				// Earlier records may only be gone when a checkpoint vouches for the record the chain now starts at.
+				if anchor, ok := checkpoints[sequence-1]; !ok || anchor.Hash != operationLog.PrevHash {
+					reason = fmt.Sprintf("records before sequence %d are missing", sequence)
+				}
+			case resp.LastSequence != 0 && sequence != resp.LastSequence+1:
+				sequence = resp.LastSequence + 1
+				reason = "record is missing"
+			case operationLog.PrevHash != prevHash:
+				reason = "previous hash does not match the previous record"
+			}
+			if reason == "" && dao.OperationLogHash(operationLog) != operationLog.Hash {
+				reason = "record does not match its hash"
+			}
+			if checkpoint, ok := checkpoints[sequence]; reason == "" && ok && checkpoint.Hash != operationLog.Hash {
+				reason = "record does not match the signed checkpoint"
+			}
+			if reason != "" {
+				breakAt(sequence, operationLog.OperationLogID.Hex(), reason)
+				return errChainBroken
+			}
+			resp.Checked++
+			resp.LastSequence = sequence
+			prevHash = operationLog.Hash
+			return nil
+		},
+	)
+	if err != nil && !e.Is(err, errChainBroken) {
+		return nil, errors.OperationFailed(fmt.Errorf("failed to walk operation log chain"))
+	}
+	if resp.Valid {
+		// Records removed from the end of the chain leave the head or the last checkpoint behind them.
+		lastSequence := head.Sequence
+		var lastCheckpoint *entity.OperationLogCheckpointModel
+		if len(checkpointList) > 0 {
+			lastCheckpoint = &checkpointList[len(checkpointList)-1]
+			if lastCheckpoint.Sequence > lastSequence {
+				lastSequence = lastCheckpoint.Sequence
+			}
+		}
+		switch {
+		case resp.Checked == 0 && lastCheckpoint != nil &&
+			lastCheckpoint.Sequence == head.Sequence && lastCheckpoint.Hash == head.Hash:
+			// Every record was pruned, which is only intact when a signed checkpoint vouches for the head.
+			resp.LastSequence = head.Sequence
+		case lastSequence > resp.LastSequence:
+			breakAt(resp.LastSequence+1, "", fmt.Sprintf("records up to sequence %d are missing", lastSequence))
+		}
+	}
+	return resp, nil
+}
+
+func (l LogsServiceImpl) GetLogArchiveList(ctx context.Context) (*admin.GetLogArchiveListResponse, error) {
+	infos, err := l.archiveStore.List(ctx)
+	if err != nil {
+		l.core.Logger.Error("failed to list log archives", zap.Error(err))
+		return nil, errors.OperationFailed(fmt.Errorf("failed to get log archive list"))
+	}
+	logArchiveList := make([]*admin.GetLogArchiveResponse, 0, len(infos))
+	for _, info := range infos {
+		logArchiveList = append(
+			logArchiveList, &admin.GetLogArchiveResponse{
+				Name:       info.Name,
+				Collection: archive.Prefix(info.Name),
+				Size:       info.Size,
+				CreatedAt:  info.CreatedAt.Format(time.RFC3339),
+			},
+		)
+	}
+	return &admin.GetLogArchiveListResponse{
+		Total:          int64(len(logArchiveList)),
+		LogArchiveList: logArchiveList,
+	}, nil
+}
+
+// ImportLogArchive inserts the logs of an archive back for investigation. Logs still present are skipped. Login logs
+// return to their collection, where the next retention run archives them again as they are past their retention.
+// Operation logs go to a collection of their own, as reinserting them into the chain would break its verification.
+func (l LogsServiceImpl) ImportLogArchive(ctx context.Context, name *string) (*admin.ImportLogArchiveResponse, error) {
+	collection := archive.Prefix(*name)
+	if collection != config.LoginLogCollectionName && collection != config.OperationLogCollectionName {
+		return nil, errors.InvalidRequest(fmt.Errorf("invalid log archive name %s", *name))
+	}
+	file, err := l.archiveStore.Open(ctx, *name)
+	if e.Is(err, archive.ErrInvalidName) {
+		return nil, errors.InvalidRequest(fmt.Errorf("invalid log archive name %s", *name))
+	}
+	if e.Is(err, archive.ErrNotFound) {
+		return nil, errors.NotFound(fmt.Errorf("log archive %s not found", *name))
+	}
+	if err != nil {
+		l.core.Logger.Error("failed to open log archive", zap.Error(err), zap.String("name", *name))
+		return nil, errors.OperationFailed(fmt.Errorf("failed to open log archive %s", *name))
+	}
+	reader, err := archive.NewReader(file)
+	if err != nil {
+		l.core.Logger.Error("failed to read log archive", zap.Error(err), zap.String("name", *name))
+		return nil, errors.OperationFailed(fmt.Errorf("failed to read log archive %s", *name))
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	resp := &admin.ImportLogArchiveResponse{Name: *name, Collection: collection}
+	if collection == config.OperationLogCollectionName {
+		resp.Collection = config.OperationLogImportCollectionName
+	}
+	batchSize := int(l.core.Config.RetentionConfig.ArchiveBatchSize)
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+	if collection == config.LoginLogCollectionName {
+		err = importLogArchive(
+			ctx, reader, batchSize, resp, l.loginLogDao.ImportLoginLogList,
+		)
+	} else {
+		err = importLogArchive(
+			ctx, reader, batchSize, resp, l.operationLogDao.ImportOperationLogList,
+		)
+	}
+	if err != nil {
+		l.core.Logger.Error(
+			"failed to import log archive",
+			zap.Error(err), zap.String("name", *name), zap.Int64("imported", resp.Imported),
+		)
+		return nil, errors.OperationFailed(fmt.Errorf("failed to import log archive %s", *name))
+	}
+	return resp, nil
+}
+
+// importLogArchive reads the logs of an archive in batches and inserts them with insert.
+func importLogArchive[T any](
+	ctx context.Context, reader *archive.Reader, batchSize int, resp *admin.ImportLogArchiveResponse,
+	insert func(ctx context.Context, logs []T) (*int64, error),
+) error {
+	batch := make([]T, 0, batchSize)
+	flush := func() error {
+		count, err := insert(ctx, batch)
+		if err != nil {
+			return err
+		}
+		resp.Imported += *count
+		batch = batch[:0]
+		return nil
+	}
+	for {
+		var log T
+		err := reader.Read(&log)
+		if e.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		resp.Total++
+		if batch = append(batch, log); len(batch) == batchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return flush()
+}
@@ -14,8 +14,10 @@ import (
 
 type NoticeService interface {
 	InsertNotice(ctx context.Context, title, content, noticeType *string) (string, error)
-	UpdateNotice(ctx context.Context, noticeID *primitive.ObjectID, title, content, noticeType *string) error
-	DeleteNotice(ctx context.Context, noticeID *primitive.ObjectID) error
+	UpdateNotice(
+		ctx context.Context, noticeID *primitive.ObjectID, version *int64, title, content, noticeType *string,
+	) error
+	DeleteNotice(ctx context.Context, noticeID *primitive.ObjectID, version *int64) error
 }
 
 type NoticeServiceImpl struct {
@@ -42,13 +44,16 @@ func (n NoticeServiceImpl) InsertNotice(ctx context.Context, title, content, not
 	return noticeID.Hex(), nil
 }
 
+// UpdateNotice updates the notice, only if it still has the given version unless version is nil.
 func (n NoticeServiceImpl) UpdateNotice(
-	ctx context.Context, noticeID *primitive.ObjectID, title, content, noticeType *string,
+	ctx context.Context, noticeID *primitive.ObjectID, version *int64, title, content, noticeType *string,
 ) error {
-	err := n.noticeDao.UpdateNotice(ctx, *noticeID, title, content, noticeType)
+	err := n.noticeDao.UpdateNotice(ctx, *noticeID, version, title, content, noticeType)
 	if err != nil {
 		if mongo.IsDuplicateKeyError(err) {
 			return errors.DuplicateKeyError(fmt.Errorf("notice with title %s already exists", *title))
+		} else if e.Is(err, dao.ErrVersionConflict) {
+			return errors.PreconditionFailed(fmt.Errorf("notice (id: %s) has been modified", noticeID.Hex()))
 		} else if e.Is(err, mongo.ErrNoDocuments) {
 			return errors.NotFound(fmt.Errorf("notice (id: %s) not found", noticeID.Hex()))
 		} else {
@@ -58,9 +63,13 @@ func (n NoticeServiceImpl) UpdateNotice(
 	return nil
 }
 
-func (n NoticeServiceImpl) DeleteNotice(ctx context.Context, noticeID *primitive.ObjectID) error {
-	err := n.noticeDao.DeleteNotice(ctx, *noticeID)
+// DeleteNotice deletes the notice, only if it still has the given version unless version is nil.
+func (n NoticeServiceImpl) DeleteNotice(ctx context.Context, noticeID *primitive.ObjectID, version *int64) error {
+	err := n.noticeDao.DeleteNotice(ctx, *noticeID, version)
 	if err != nil {
+		if e.Is(err, dao.ErrVersionConflict) {
+			return errors.PreconditionFailed(fmt.Errorf("notice (id: %s) has been modified", noticeID.Hex()))
+		}
 		return errors.OperationFailed(fmt.Errorf("failed to delete notice (id: %s)", noticeID.Hex()))
 	}
 	return nil
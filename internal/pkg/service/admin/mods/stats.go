@@ -0,0 +1,241 @@
+package mods
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"fiber-admin/internal/pkg/config"
+	dao "fiber-admin/internal/pkg/dao/mods"
+	"fiber-admin/internal/pkg/domain/entity"
+	"fiber-admin/internal/pkg/domain/vo/admin"
+	"fiber-admin/internal/pkg/service"
+	"fiber-admin/pkg/errors"
+	"go.uber.org/zap"
+)
+
+type StatsService interface {
+	GetUserStats(ctx context.Context) (*admin.GetUserStatsResponse, error)
+	GetNewUserStats(
+		ctx context.Context, bucket *string, startTime, endTime *time.Time,
+	) (*admin.GetStatsSeriesResponse, error)
+	GetActiveUserStats(
+		ctx context.Context, bucket *string, startTime, endTime *time.Time,
+	) (*admin.GetStatsSeriesResponse, error)
+	GetOperationStats(
+		ctx context.Context, bucket *string, startTime, endTime *time.Time,
+	) (*admin.GetOperationStatsResponse, error)
+	GetContentStats(ctx context.Context) (*admin.GetContentStatsResponse, error)
+}
+
+type StatsServiceImpl struct {
+	core     *service.Core
+	statsDao dao.StatsDao
+}
+
+func NewStatsService(core *service.Core, statsDao dao.StatsDao) StatsService {
+	return &StatsServiceImpl{
+		core:     core,
+		statsDao: statsDao,
+	}
+}
+
+func (s StatsServiceImpl) GetUserStats(ctx context.Context) (*admin.GetUserStatsResponse, error) {
+	byRole, err := s.statsDao.CountUserByRole(ctx)
+	if err != nil {
+		return nil, errors.OperationFailed(fmt.Errorf("failed to count users by role"))
+	}
+	byOrganization, err := s.statsDao.CountUserByOrganization(ctx)
+	if err != nil {
+		return nil, errors.OperationFailed(fmt.Errorf("failed to count users by organization"))
+	}
+	total, byRoleResponse := toStatsCountResponse(byRole)
+	_, byOrganizationResponse := toStatsCountResponse(byOrganization)
+	return &admin.GetUserStatsResponse{
+		Total:          total,
+		ByRole:         byRoleResponse,
+		ByOrganization: byOrganizationResponse,
+	}, nil
+}
+
+func (s StatsServiceImpl) GetNewUserStats(
+	ctx context.Context, bucket *string, startTime, endTime *time.Time,
+) (*admin.GetStatsSeriesResponse, error) {
+	statsRange, err := s.resolveRange(bucket, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.statsDao.CountNewUser(ctx, statsRange.start, statsRange.end, statsRange.bucket)
+	if err != nil {
+		return nil, errors.OperationFailed(fmt.Errorf("failed to count new users"))
+	}
+	return statsRange.series(counts), nil
+}
+
+func (s StatsServiceImpl) GetActiveUserStats(
+	ctx context.Context, bucket *string, startTime, endTime *time.Time,
+) (*admin.GetStatsSeriesResponse, error) {
+	statsRange, err := s.resolveRange(bucket, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.statsDao.CountActiveUser(ctx, statsRange.start, statsRange.end, statsRange.bucket)
+	if err != nil {
+		return nil, errors.OperationFailed(fmt.Errorf("failed to count active users"))
+	}
+	return statsRange.series(counts), nil
+}
+
+func (s StatsServiceImpl) GetOperationStats(
+	ctx context.Context, bucket *string, startTime, endTime *time.Time,
+) (*admin.GetOperationStatsResponse, error) {
+	statsRange, err := s.resolveRange(bucket, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.statsDao.CountOperation(ctx, statsRange.start, statsRange.end, statsRange.bucket)
+	if err != nil {
+		return nil, errors.OperationFailed(fmt.Errorf("failed to count operations"))
+	}
+	series := make([]*admin.StatsOperationCountResponse, 0, len(counts))
+	for _, count := range counts {
+		series = append(
+			series, &admin.StatsOperationCountResponse{
+				Bucket:    count.Bucket.In(statsRange.location).Format(time.RFC3339),
+				Operation: count.Operation,
+				Status:    count.Status,
+				Count:     count.Count,
+			},
+		)
+	}
+	return &admin.GetOperationStatsResponse{
+		Bucket:    statsRange.bucket,
+		StartTime: statsRange.start.Format(time.RFC3339),
+		EndTime:   statsRange.end.Format(time.RFC3339),
+		Series:    series,
+	}, nil
+}
+
+func (s StatsServiceImpl) GetContentStats(ctx context.Context) (*admin.GetContentStatsResponse, error) {
+	noticeByType, err := s.statsDao.CountNoticeByType(ctx)
+	if err != nil {
+		return nil, errors.OperationFailed(fmt.Errorf("failed to count notices"))
+	}
+	documentationByCategory, err := s.statsDao.CountDocumentationByCategory(ctx)
+	if err != nil {
+		return nil, errors.OperationFailed(fmt.Errorf("failed to count documentations"))
+	}
+	noticeTotal, noticeByTypeResponse := toStatsCountResponse(noticeByType)
+	documentationTotal, documentationByCategoryResponse := toStatsCountResponse(documentationByCategory)
+	return &admin.GetContentStatsResponse{
+		NoticeTotal:             noticeTotal,
+		NoticeByType:            noticeByTypeResponse,
+		DocumentationTotal:      documentationTotal,
+		DocumentationByCategory: documentationByCategoryResponse,
+	}, nil
+}
+
+// statsRange is the time range of a series, split into buckets.
+type statsRange struct {
+	bucket   string
+	start    time.Time
+	end      time.Time
+	location *time.Location
+	buckets  []time.Time // Start of every bucket overlapping the range
+}
+
+// resolveRange fills in the defaults of a series request. The end defaults to now, rounded up to the stats cache TTL
+// so that dashboards polling the default range share a cache entry.
+func (s StatsServiceImpl) resolveRange(bucket *string, startTime, endTime *time.Time) (*statsRange, error) {
+	location, err := time.LoadLocation(s.core.Config.StatsConfig.Timezone)
+	if err != nil {
+		s.core.Logger.Error("failed to load stats timezone", zap.Error(err))
+		return nil, errors.ServiceError(fmt.Errorf("invalid stats timezone"))
+	}
+	r := &statsRange{bucket: config.StatsBucketDay, location: location}
+	if bucket != nil {
+		r.bucket = *bucket
+	}
+	if endTime != nil {
+		r.end = *endTime
+	} else if ttl := s.core.Config.CacheConfig.StatsCacheTTL; ttl > 0 {
+		r.end = time.Now().Truncate(ttl).Add(ttl)
+	} else {
+		r.end = time.Now()
+	}
+	if startTime != nil {
+		r.start = *startTime
+	} else {
+		r.start = r.end.Add(-s.core.Config.StatsConfig.DefaultRange)
+	}
+	r.start, r.end = r.start.In(location), r.end.In(location)
+
+	for b := truncateToBucket(r.start, r.bucket); b.Before(r.end); b = nextBucket(b, r.bucket) {
+		if len(r.buckets) == s.core.Config.StatsConfig.MaxBuckets {
+			return nil, errors.InvalidRequest(
+				fmt.Errorf("time range has more than %d %s buckets", s.core.Config.StatsConfig.MaxBuckets, r.bucket),
+			)
+		}
+		r.buckets = append(r.buckets, b)
+	}
+	return r, nil
+}
+
+// series returns every bucket of the range with its count, zero when the aggregation has none.
+func (r *statsRange) series(counts []entity.BucketCountModel) *admin.GetStatsSeriesResponse {
+	byBucket := make(map[int64]int64, len(counts))
+	for _, count := range counts {
+		byBucket[count.Bucket.Unix()] = count.Count
+	}
+	series := make([]*admin.StatsBucketCountResponse, 0, len(r.buckets))
+	for _, b := range r.buckets {
+		series = append(
+			series, &admin.StatsBucketCountResponse{Bucket: b.Format(time.RFC3339), Count: byBucket[b.Unix()]},
+		)
+	}
+	return &admin.GetStatsSeriesResponse{
+		Bucket:    r.bucket,
+		StartTime: r.start.Format(time.RFC3339),
+		EndTime:   r.end.Format(time.RFC3339),
+		Series:    series,
+	}
+}
+
+// truncateToBucket returns the start of the bucket t is in, the way $dateTrunc computes it in t's location.
+func truncateToBucket(t time.Time, bucket string) time.Time {
+	year, month, day := t.Date()
+	switch bucket {
+	case config.StatsBucketHour:
+		return time.Date(year, month, day, t.Hour(), 0, 0, 0, t.Location())
+	case config.StatsBucketWeek:
+		monday := day - (int(t.Weekday())+6)%7
+		return time.Date(year, month, monday, 0, 0, 0, 0, t.Location())
+	case config.StatsBucketMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	}
+}
+
+func nextBucket(t time.Time, bucket string) time.Time {
+	switch bucket {
+	case config.StatsBucketHour:
+		return t.Add(time.Hour)
+	case config.StatsBucketWeek:
+		return t.AddDate(0, 0, 7)
+	case config.StatsBucketMonth:
+		return t.AddDate(0, 1, 0)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+func toStatsCountResponse(counts []entity.CountModel) (int64, []*admin.StatsCountResponse) {
+	var total int64
+	response := make([]*admin.StatsCountResponse, 0, len(counts))
+	for _, count := range counts {
+		total += count.Count
+		response = append(response, &admin.StatsCountResponse{Key: count.Key, Count: count.Count})
+	}
+	return total, response
+}
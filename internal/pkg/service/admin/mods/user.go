@@ -25,8 +25,10 @@ type UserService interface {
 		ctx context.Context, page, pageSize *int64, desc *bool, role *string,
 		lastLoginBefore, lastLoginAfter, createdBefore, createdAfter *time.Time, query *string,
 	) (*admin.GetUserListResponse, error)
-	UpdateUser(ctx context.Context, userID *primitive.ObjectID, username, email, organization *string) error
-	DeleteUser(ctx context.Context, userID *primitive.ObjectID) error
+	UpdateUser(
+		ctx context.Context, userID *primitive.ObjectID, version *int64, username, email, organization *string,
+	) error
+	DeleteUser(ctx context.Context, userID *primitive.ObjectID, version *int64) error
 	ChangeUserPassword(ctx context.Context, userID *primitive.ObjectID, newPassword *string) error
 }
 
@@ -92,6 +94,7 @@ func (u UserServiceImpl) GetUser(ctx context.Context, userID *primitive.ObjectID
 		LastLogin:    user.LastLogin.Format(time.RFC3339),
 		CreatedAt:    user.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:    user.UpdatedAt.Format(time.RFC3339),
+		Version:      user.Version,
 	}, nil
 }
 
@@ -121,6 +124,7 @@ func (u UserServiceImpl) GetUserList(
 				LastLogin:    user.LastLogin.Format(time.RFC3339),
 				CreatedAt:    user.CreatedAt.Format(time.RFC3339),
 				UpdatedAt:    user.UpdatedAt.Format(time.RFC3339),
+				Version:      user.Version,
 			},
 		)
 	}
@@ -130,15 +134,17 @@ func (u UserServiceImpl) GetUserList(
 	}, nil
 }
 
-// UpdateUser updates a user's information.
+// UpdateUser updates a user's information, only if the user still has the given version unless version is nil.
 // Returns nil if successful.
 func (u UserServiceImpl) UpdateUser(
-	ctx context.Context, userID *primitive.ObjectID, username, email, organization *string,
+	ctx context.Context, userID *primitive.ObjectID, version *int64, username, email, organization *string,
 ) error {
-	err := u.userDao.UpdateUser(ctx, *userID, username, email, nil, nil, organization)
+	err := u.userDao.UpdateUser(ctx, *userID, version, username, email, nil, nil, organization)
 	if err != nil {
 		if mongo.IsDuplicateKeyError(err) {
 			return errors.DuplicateKeyError(fmt.Errorf("user with email %s already exists", *email))
+		} else if e.Is(err, dao.ErrVersionConflict) {
+			return errors.PreconditionFailed(fmt.Errorf("user (id: %s) has been modified", userID.Hex()))
 		} else if e.Is(err, mongo.ErrNoDocuments) {
 			return errors.NotFound(fmt.Errorf("user (id: %s) not found", userID.Hex()))
 		} else {
@@ -148,12 +154,14 @@ func (u UserServiceImpl) UpdateUser(
 	return nil
 }
 
-// DeleteUser deletes a user by user ID.
+// DeleteUser deletes a user by user ID, only if the user still has the given version unless version is nil.
 // Returns nil if successful.
-func (u UserServiceImpl) DeleteUser(ctx context.Context, userID *primitive.ObjectID) error {
-	err := u.userDao.DeleteUser(ctx, *userID)
+func (u UserServiceImpl) DeleteUser(ctx context.Context, userID *primitive.ObjectID, version *int64) error {
+	err := u.userDao.DeleteUser(ctx, *userID, version)
 	if err != nil {
-		if e.Is(err, mongo.ErrNoDocuments) {
+		if e.Is(err, dao.ErrVersionConflict) {
+			return errors.PreconditionFailed(fmt.Errorf("user (id: %s) has been modified", userID.Hex()))
+		} else if e.Is(err, mongo.ErrNoDocuments) {
 			return errors.NotFound(fmt.Errorf("user (id: %s) not found", userID.Hex()))
 		} else {
 			return errors.OperationFailed(fmt.Errorf("failed to delete user (id: %s)", userID.Hex()))
@@ -172,7 +180,7 @@ func (u UserServiceImpl) ChangeUserPassword(
 		u.core.Logger.Error("failed to hash password", zap.Error(err))
 		return errors.ServiceError(fmt.Errorf("failed to hash password"))
 	}
-	err = u.userDao.UpdateUser(ctx, *userID, nil, nil, &newPasswordHash, nil, nil)
+	err = u.userDao.UpdateUser(ctx, *userID, nil, nil, nil, &newPasswordHash, nil, nil)
 	if err != nil {
 		if e.Is(err, mongo.ErrNoDocuments) {
 			return errors.NotFound(fmt.Errorf("user (id: %s) not found", userID.Hex()))
@@ -9,4 +9,5 @@ type Common struct {
 	DocumentationService mods.DocumentationService
 	NoticeService        mods.NoticeService
 	ProfileService       mods.ProfileService
+	SearchService        mods.SearchService
 }
@@ -184,7 +184,7 @@ func (a authServiceImpl) ChangePassword(ctx context.Context, oldPassword, newPas
 		a.core.Logger.Error("failed to hash password", zap.Error(err))
 		return errors.ServiceError(fmt.Errorf("failed to hash password"))
 	}
-	if err = a.userDao.UpdateUser(ctx, userID, nil, nil, &hashedPassword, nil, nil); err != nil {
+	if err = a.userDao.UpdateUser(ctx, userID, nil, nil, nil, &hashedPassword, nil, nil); err != nil {
 		if mongo.IsDuplicateKeyError(err) {
 			return errors.DuplicateKeyError(
 				fmt.Errorf(
@@ -6,63 +6,111 @@ import (
 	"fmt"
 	"time"
 
-	dao "fiber-admin/internal/pkg/dao/mods"
+	"fiber-admin/internal/pkg/config"
+	"fiber-admin/internal/pkg/dao"
+	daos "fiber-admin/internal/pkg/dao/mods"
+	"fiber-admin/internal/pkg/domain/entity"
 	"fiber-admin/internal/pkg/domain/vo/common"
 	"fiber-admin/internal/pkg/service"
 	"fiber-admin/pkg/errors"
+	"fiber-admin/pkg/markdown"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type DocumentationService interface {
-	GetDocumentation(ctx context.Context, documentationID *primitive.ObjectID) (*common.GetDocumentationResponse, error)
+	GetDocumentation(
+		ctx context.Context, documentationID *primitive.ObjectID, slug, format *string,
+	) (*common.GetDocumentationResponse, error)
 	GetDocumentationList(
-		ctx context.Context, page, pageSize *int64, updateStartTime, updateEndTime *time.Time,
+		ctx context.Context, page, pageSize *int64, updateStartTime, updateEndTime *time.Time, category, tag *string,
 	) (*common.GetDocumentationListResponse, error)
+	GetDocumentationTree(ctx context.Context) (*common.GetDocumentationTreeResponse, error)
 }
 
 type documentationServiceImpl struct {
 	core             *service.Core
-	documentationDao dao.DocumentationDao
+	cache            *dao.Cache
+	markdown         *markdown.Markdown
+	documentationDao daos.DocumentationDao
 }
 
-func NewDocumentationService(core *service.Core, documentationDao dao.DocumentationDao) DocumentationService {
+func NewDocumentationService(
+	core *service.Core, documentationDao daos.DocumentationDao, cache *dao.Cache, markdown *markdown.Markdown,
+) DocumentationService {
 	return &documentationServiceImpl{
 		core:             core,
+		cache:            cache,
+		markdown:         markdown,
 		documentationDao: documentationDao,
 	}
 }
 
+// GetDocumentation returns the documentation by ID, or by slug when no ID is given. With format html the Markdown
+// content is rendered to sanitized HTML and a table of contents is attached.
 func (d documentationServiceImpl) GetDocumentation(
-	ctx context.Context, documentationID *primitive.ObjectID,
+	ctx context.Context, documentationID *primitive.ObjectID, slug, format *string,
 ) (*common.GetDocumentationResponse, error) {
-	documentation, err := d.documentationDao.GetDocumentationByID(ctx, *documentationID)
+	var (
+		documentation *entity.DocumentationModel
+		identifier    string
+		err           error
+	)
+	if documentationID != nil {
+		identifier = fmt.Sprintf("id: %s", documentationID.Hex())
+		documentation, err = d.documentationDao.GetDocumentationByID(ctx, *documentationID)
+	} else {
+		identifier = fmt.Sprintf("slug: %s", *slug)
+		documentation, err = d.documentationDao.GetDocumentationBySlug(ctx, *slug)
+	}
 	if err != nil {
 		if e.Is(err, mongo.ErrNoDocuments) {
-			return nil, errors.NotFound(fmt.Errorf("documentation (id: %s) not found", documentationID.Hex()))
+			return nil, errors.NotFound(fmt.Errorf("documentation (%s) not found", identifier))
 		} else {
-			return nil, errors.OperationFailed(
-				fmt.Errorf(
-					"failed to get documentation (id: %s)", documentationID.Hex(),
-				),
-			)
+			return nil, errors.OperationFailed(fmt.Errorf("failed to get documentation (%s)", identifier))
 		}
 	}
-	return &common.GetDocumentationResponse{
+	var parentID string
+	if documentation.ParentID != nil {
+		parentID = documentation.ParentID.Hex()
+	}
+	resp := &common.GetDocumentationResponse{
 		DocumentID: documentation.DocumentID.Hex(),
+		ParentID:   parentID,
+		Position:   documentation.Position,
+		Slug:       documentation.Slug,
 		Title:      documentation.Title,
 		Content:    documentation.Content,
+		Format:     config.ContentFormatMarkdown,
+		Category:   documentation.Category,
+		Tags:       documentation.Tags,
 		CreatedAt:  documentation.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:  documentation.UpdatedAt.Format(time.RFC3339),
-	}, nil
+		Version:    documentation.Version,
+	}
+	if format != nil && *format == config.ContentFormatHTML {
+		key := d.cache.Key(
+			ctx, config.DocumentationCachePrefix, "documentID:%s:html:%d", documentation.DocumentID.Hex(),
+			documentation.Version,
+		)
+		result, err := renderMarkdown(
+			ctx, d.core, d.cache, d.markdown, key, documentation.Content,
+			d.core.Config.CacheConfig.DocumentationCacheTTL,
+		)
+		if err != nil {
+			return nil, errors.OperationFailed(fmt.Errorf("failed to render documentation (%s)", identifier))
+		}
+		resp.Content, resp.Format, resp.TOC = result.HTML, config.ContentFormatHTML, result.TOC
+	}
+	return resp, nil
 }
 
 func (d documentationServiceImpl) GetDocumentationList(
-	ctx context.Context, page, pageSize *int64, updateBefore, updateAfter *time.Time,
+	ctx context.Context, page, pageSize *int64, updateBefore, updateAfter *time.Time, category, tag *string,
 ) (*common.GetDocumentationListResponse, error) {
 	offset := (*page - 1) * *pageSize
 	documentations, count, err := d.documentationDao.GetDocumentationList(
-		ctx, offset, *pageSize, false, nil, nil, updateBefore, updateAfter,
+		ctx, offset, *pageSize, false, nil, nil, updateBefore, updateAfter, category, tag,
 	)
 	if err != nil {
 		return nil, errors.OperationFailed(fmt.Errorf("failed to get documentation list"))
@@ -72,7 +120,10 @@ func (d documentationServiceImpl) GetDocumentationList(
 		resp = append(
 			resp, &common.DocumentationSummary{
 				DocumentID: documentation.DocumentID.Hex(),
+				Slug:       documentation.Slug,
 				Title:      documentation.Title,
+				Category:   documentation.Category,
+				Tags:       documentation.Tags,
 				CreatedAt:  documentation.CreatedAt.Format(time.RFC3339),
 			},
 		)
@@ -82,3 +133,42 @@ func (d documentationServiceImpl) GetDocumentationList(
 		DocumentationSummaryList: resp,
 	}, nil
 }
+
+// GetDocumentationTree returns every documentation nested under its parent and ordered by position. Documentation
+// whose parent no longer exists is listed at the top level.
+func (d documentationServiceImpl) GetDocumentationTree(
+	ctx context.Context,
+) (*common.GetDocumentationTreeResponse, error) {
+	documentations, err := d.documentationDao.GetDocumentationOutline(ctx)
+	if err != nil {
+		return nil, errors.OperationFailed(fmt.Errorf("failed to get documentation tree"))
+	}
+	nodes := make(map[primitive.ObjectID]*common.DocumentationTreeNode, len(documentations))
+	for _, documentation := range documentations {
+		nodes[documentation.DocumentID] = &common.DocumentationTreeNode{
+			DocumentID: documentation.DocumentID.Hex(),
+			Slug:       documentation.Slug,
+			Title:      documentation.Title,
+			Category:   documentation.Category,
+			Tags:       documentation.Tags,
+			Position:   documentation.Position,
+			Children:   make([]*common.DocumentationTreeNode, 0),
+		}
+	}
+	tree := make([]*common.DocumentationTreeNode, 0)
+	// The outline is sorted by position, so appending in order keeps every level sorted.
+	for _, documentation := range documentations {
+		node := nodes[documentation.DocumentID]
+		if documentation.ParentID != nil {
+			if parent, ok := nodes[*documentation.ParentID]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		tree = append(tree, node)
+	}
+	return &common.GetDocumentationTreeResponse{
+		Total:             int64(len(documentations)),
+		DocumentationTree: tree,
+	}, nil
+}
@@ -2,19 +2,28 @@ package mods
 
 import (
 	"context"
+	e "errors"
 	"fmt"
 
 	"fiber-admin/internal/pkg/config"
 	"fiber-admin/internal/pkg/dao"
+	"fiber-admin/internal/pkg/domain/entity"
 	"fiber-admin/internal/pkg/service"
 	"fiber-admin/pkg/errors"
 	"fiber-admin/pkg/utils/common"
+	"github.com/goccy/go-json"
 	"go.uber.org/zap"
 )
 
 type IdempotencyService interface {
 	GenerateIdempotencyToken(ctx context.Context) (string, error)
-	CheckIdempotencyToken(ctx context.Context, token string) error
+	// BeginIdempotentRequest claims key for the request with the fingerprint. It returns the stored record when a
+	// request with the same fingerprint completed under key already, so that its response is replayed.
+	BeginIdempotentRequest(ctx context.Context, key, fingerprint string) (*entity.IdempotencyRecord, error)
+	// CompleteIdempotentRequest stores the response of the request which claimed key.
+	CompleteIdempotentRequest(ctx context.Context, key string, record *entity.IdempotencyRecord) error
+	// ReleaseIdempotentRequest gives key up without storing a response, so that the request can be retried.
+	ReleaseIdempotentRequest(ctx context.Context, key string) error
 }
 
 type idempotencyServiceImpl struct {
@@ -29,33 +38,81 @@ func NewIdempotencyService(core *service.Core, cache *dao.Cache) IdempotencyServ
 	}
 }
 
-func (s *idempotencyServiceImpl) GenerateIdempotencyToken(ctx context.Context) (string, error) {
+// GenerateIdempotencyToken generates a key for the clients which cannot generate unique ones. Keys are not issued
+// in advance: any unique key works.
+func (s *idempotencyServiceImpl) GenerateIdempotencyToken(_ context.Context) (string, error) {
 	token, err := common.GenerateUUID4()
 	if err != nil {
 		s.core.Logger.Error("failed to generate idempotency token", zap.Error(err))
 		return "", errors.ServiceError(fmt.Errorf("failed to generate idempotency token"))
 	}
-	key := fmt.Sprintf("%s:%s", config.IdempotencyCachePrefix, token)
-	if err = s.cache.Set(ctx, key, config.CacheTrue, &s.core.Config.IdempotencyConfig.TTL); err != nil {
-		s.core.Logger.Error("failed to set idempotency token", zap.Error(err))
-		return "", errors.ServiceError(fmt.Errorf("failed to set idempotency token"))
-	}
 	return token, nil
 }
 
-func (s *idempotencyServiceImpl) CheckIdempotencyToken(ctx context.Context, token string) error {
-	key := fmt.Sprintf("%s:%s", config.IdempotencyCachePrefix, token)
-	result, err := s.cache.Get(ctx, key)
+// BeginIdempotentRequest claims key with a record which is not completed, for the lock timeout so that a crashed
+// request does not hold it forever. A key claimed already is answered according to the record under it: a different
+// fingerprint is rejected, a request still in flight is a conflict, and a completed one is replayed.
+func (s *idempotencyServiceImpl) BeginIdempotentRequest(
+	ctx context.Context, key, fingerprint string,
+) (*entity.IdempotencyRecord, error) {
+	cacheKey := idempotencyKey(key)
+	recordJSON, _ := json.Marshal(&entity.IdempotencyRecord{Fingerprint: fingerprint})
+	claimed, err := s.cache.SetNX(ctx, cacheKey, string(recordJSON), &s.core.Config.IdempotencyConfig.LockTimeout)
 	if err != nil {
-		s.core.Logger.Error("failed to get idempotency token", zap.Error(err))
-		return errors.Idempotency(fmt.Errorf("failed to get idempotency token"))
+		s.core.Logger.Error("failed to claim idempotency key", zap.Error(err), zap.String("key", cacheKey))
+		return nil, errors.ServiceError(fmt.Errorf("failed to claim idempotency key"))
+	}
+	if claimed {
+		return nil, nil
+	}
+
+	cached, err := s.cache.Get(ctx, cacheKey)
+	if e.Is(err, dao.CacheNil{}) {
+		// Released or expired since, by a request which was in flight a moment ago
+		return nil, errors.IdempotencyConflict(fmt.Errorf("a request with the same idempotency key is in progress"))
+	} else if err != nil {
+		s.core.Logger.Error("failed to get idempotency record", zap.Error(err), zap.String("key", cacheKey))
+		return nil, errors.ServiceError(fmt.Errorf("failed to get idempotency record"))
 	}
-	if err = s.cache.Delete(ctx, key); err != nil {
-		s.core.Logger.Error("failed to delete idempotency token", zap.Error(err))
-		return errors.Idempotency(fmt.Errorf("failed to delete idempotency token"))
+	record := new(entity.IdempotencyRecord)
+	if err = json.Unmarshal([]byte(*cached), record); err != nil {
+		s.core.Logger.Error("failed to unmarshal idempotency record", zap.Error(err), zap.String("key", cacheKey))
+		return nil, errors.ServiceError(fmt.Errorf("failed to get idempotency record"))
 	}
-	if result == nil {
-		return errors.Idempotency(fmt.Errorf("idempotency token not found"))
+	switch {
+	case record.Fingerprint != fingerprint:
+		return nil, errors.IdempotencyMismatch(fmt.Errorf("the idempotency key was used for a different request"))
+	case !record.Completed:
+		return nil, errors.IdempotencyConflict(fmt.Errorf("a request with the same idempotency key is in progress"))
+	}
+	return record, nil
+}
+
+func (s *idempotencyServiceImpl) CompleteIdempotentRequest(
+	ctx context.Context, key string, record *entity.IdempotencyRecord,
+) error {
+	cacheKey := idempotencyKey(key)
+	record.Completed = true
+	recordJSON, err := json.Marshal(record)
+	if err == nil {
+		err = s.cache.Set(ctx, cacheKey, string(recordJSON), &s.core.Config.IdempotencyConfig.TTL)
+	}
+	if err != nil {
+		s.core.Logger.Error("failed to store idempotency record", zap.Error(err), zap.String("key", cacheKey))
+		return errors.ServiceError(fmt.Errorf("failed to store idempotency record"))
 	}
 	return nil
 }
+
+func (s *idempotencyServiceImpl) ReleaseIdempotentRequest(ctx context.Context, key string) error {
+	cacheKey := idempotencyKey(key)
+	if err := s.cache.Delete(ctx, cacheKey); err != nil {
+		s.core.Logger.Error("failed to release idempotency key", zap.Error(err), zap.String("key", cacheKey))
+		return errors.ServiceError(fmt.Errorf("failed to release idempotency key"))
+	}
+	return nil
+}
+
+func idempotencyKey(key string) string {
+	return fmt.Sprintf("%s:%s", config.IdempotencyCachePrefix, key)
+}
@@ -0,0 +1,42 @@
+package mods
+
+import (
+	"context"
+	e "errors"
+	"time"
+
+	"fiber-admin/internal/pkg/dao"
+	"fiber-admin/internal/pkg/service"
+	"fiber-admin/pkg/markdown"
+	"github.com/goccy/go-json"
+	"go.uber.org/zap"
+)
+
+// renderMarkdown renders content to sanitized HTML through the cache. Callers key the result with Cache.Key under the
+// entity's cache prefix, so it is invalidated together with the entity, and include the version in the key, so an
+// edit is never answered with a stale rendering.
+func renderMarkdown(
+	ctx context.Context, core *service.Core, cache *dao.Cache, md *markdown.Markdown, key, content string,
+	ttl time.Duration,
+) (*markdown.Result, error) {
+	cached, err := cache.Get(ctx, key)
+	if err == nil {
+		var result markdown.Result
+		if err = json.Unmarshal([]byte(*cached), &result); err == nil {
+			return &result, nil
+		}
+		core.Logger.Error("failed to unmarshal rendered markdown", zap.Error(err), zap.String("key", key))
+	} else if !e.Is(err, dao.CacheNil{}) {
+		core.Logger.Error("failed to get rendered markdown", zap.Error(err), zap.String("key", key))
+	}
+
+	result, err := md.Render(content)
+	if err != nil {
+		return nil, err
+	}
+	resultJSON, _ := json.Marshal(result)
+	if err = cache.Set(ctx, key, string(resultJSON), &ttl); err != nil {
+		core.Logger.Error("failed to cache rendered markdown", zap.Error(err), zap.String("key", key))
+	}
+	return result, nil
+}
@@ -6,16 +6,19 @@ import (
 	"fmt"
 	"time"
 
-	dao "fiber-admin/internal/pkg/dao/mods"
+	"fiber-admin/internal/pkg/config"
+	"fiber-admin/internal/pkg/dao"
+	daos "fiber-admin/internal/pkg/dao/mods"
 	"fiber-admin/internal/pkg/domain/vo/common"
 	"fiber-admin/internal/pkg/service"
 	"fiber-admin/pkg/errors"
+	"fiber-admin/pkg/markdown"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type NoticeService interface {
-	GetNotice(ctx context.Context, noticeID *primitive.ObjectID) (*common.GetNoticeResponse, error)
+	GetNotice(ctx context.Context, noticeID *primitive.ObjectID, format *string) (*common.GetNoticeResponse, error)
 	GetNoticeList(
 		ctx context.Context, page, pageSize *int64, noticeType *string, updateBefore, updateAfter *time.Time,
 	) (*common.GetNoticeListResponse, error)
@@ -23,17 +26,25 @@ type NoticeService interface {
 
 type noticeServiceImpl struct {
 	core      *service.Core
-	noticeDao dao.NoticeDao
+	cache     *dao.Cache
+	markdown  *markdown.Markdown
+	noticeDao daos.NoticeDao
 }
 
-func NewNoticeService(core *service.Core, noticeDao dao.NoticeDao) NoticeService {
+func NewNoticeService(
+	core *service.Core, noticeDao daos.NoticeDao, cache *dao.Cache, markdown *markdown.Markdown,
+) NoticeService {
 	return &noticeServiceImpl{
 		core:      core,
+		cache:     cache,
+		markdown:  markdown,
 		noticeDao: noticeDao,
 	}
 }
 
-func (n noticeServiceImpl) GetNotice(ctx context.Context, noticeID *primitive.ObjectID) (
+// GetNotice returns the notice by ID. With format html the Markdown content is rendered to sanitized HTML and a table
+// of contents is attached.
+func (n noticeServiceImpl) GetNotice(ctx context.Context, noticeID *primitive.ObjectID, format *string) (
 	*common.GetNoticeResponse, error,
 ) {
 	notice, err := n.noticeDao.GetNoticeByID(ctx, *noticeID)
@@ -44,14 +55,27 @@ func (n noticeServiceImpl) GetNotice(ctx context.Context, noticeID *primitive.Ob
 			return nil, errors.OperationFailed(fmt.Errorf("failed to get notice (id: %s)", noticeID.Hex()))
 		}
 	}
-	return &common.GetNoticeResponse{
+	resp := &common.GetNoticeResponse{
 		NoticeID:   notice.NoticeID.Hex(),
 		Title:      notice.Title,
 		Content:    notice.Content,
+		Format:     config.ContentFormatMarkdown,
 		NoticeType: notice.NoticeType,
 		CreatedAt:  notice.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:  notice.UpdatedAt.Format(time.RFC3339),
-	}, nil
+		Version:    notice.Version,
+	}
+	if format != nil && *format == config.ContentFormatHTML {
+		key := n.cache.Key(ctx, config.NoticeCachePrefix, "noticeID:%s:html:%d", noticeID.Hex(), notice.Version)
+		result, err := renderMarkdown(
+			ctx, n.core, n.cache, n.markdown, key, notice.Content, n.core.Config.CacheConfig.NoticeCacheTTL,
+		)
+		if err != nil {
+			return nil, errors.OperationFailed(fmt.Errorf("failed to render notice (id: %s)", noticeID.Hex()))
+		}
+		resp.Content, resp.Format, resp.TOC = result.HTML, config.ContentFormatHTML, result.TOC
+	}
+	return resp, nil
 }
 
 func (n noticeServiceImpl) GetNoticeList(
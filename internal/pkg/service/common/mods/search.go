@@ -0,0 +1,121 @@
+package mods
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"fiber-admin/internal/pkg/config"
+	dao "fiber-admin/internal/pkg/dao/mods"
+	"fiber-admin/internal/pkg/domain/vo/common"
+	"fiber-admin/internal/pkg/service"
+	"fiber-admin/pkg/errors"
+	"fiber-admin/pkg/utils/highlight"
+)
+
+type SearchService interface {
+	Search(ctx context.Context, query string, scope *string, page, pageSize *int64) (*common.SearchResponse, error)
+}
+
+type searchServiceImpl struct {
+	core             *service.Core
+	documentationDao dao.DocumentationDao
+	noticeDao        dao.NoticeDao
+}
+
+func NewSearchService(
+	core *service.Core, documentationDao dao.DocumentationDao, noticeDao dao.NoticeDao,
+) SearchService {
+	return &searchServiceImpl{
+		core:             core,
+		documentationDao: documentationDao,
+		noticeDao:        noticeDao,
+	}
+}
+
+// Search ranks documentation and notices matching query by relevance. Searching every scope merges both collections
+// by score, so each collection is asked for the first offset+limit matches and the merged list is paged afterwards.
+func (s searchServiceImpl) Search(
+	ctx context.Context, query string, scope *string, page, pageSize *int64,
+) (*common.SearchResponse, error) {
+	searchScope := config.SearchScopeAll
+	if scope != nil {
+		searchScope = *scope
+	}
+	offset, limit := (*page-1)**pageSize, *pageSize
+	if searchScope == config.SearchScopeAll {
+		offset, limit = 0, offset+limit
+	}
+
+	var (
+		total   int64
+		results []*common.SearchResult
+		terms   = highlight.Terms(query)
+	)
+	if searchScope == config.SearchScopeAll || searchScope == config.EntityTypeDocumentation {
+		documentationList, count, err := s.documentationDao.SearchDocumentation(ctx, query, offset, limit)
+		if err != nil {
+			return nil, errors.OperationFailed(fmt.Errorf("failed to search documentation"))
+		}
+		total += *count
+		for _, documentation := range documentationList {
+			results = append(
+				results, s.result(
+					config.EntityTypeDocumentation, documentation.DocumentID.Hex(), documentation.Title,
+					documentation.Content, documentation.Score, documentation.CreatedAt, documentation.UpdatedAt, terms,
+				),
+			)
+		}
+	}
+	if searchScope == config.SearchScopeAll || searchScope == config.EntityTypeNotice {
+		noticeList, count, err := s.noticeDao.SearchNotice(ctx, query, offset, limit)
+		if err != nil {
+			return nil, errors.OperationFailed(fmt.Errorf("failed to search notice"))
+		}
+		total += *count
+		for _, notice := range noticeList {
+			results = append(
+				results, s.result(
+					config.EntityTypeNotice, notice.NoticeID.Hex(), notice.Title, notice.Content, notice.Score,
+					notice.CreatedAt, notice.UpdatedAt, terms,
+				),
+			)
+		}
+	}
+
+	if searchScope == config.SearchScopeAll {
+		sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+		start, end := (*page-1)**pageSize, *page**pageSize
+		if start > int64(len(results)) {
+			start = int64(len(results))
+		}
+		if end > int64(len(results)) {
+			end = int64(len(results))
+		}
+		results = results[start:end]
+	}
+	if results == nil {
+		results = make([]*common.SearchResult, 0)
+	}
+	return &common.SearchResponse{
+		Total:            total,
+		SearchResultList: results,
+	}, nil
+}
+
+// result builds a search result with the title highlighted and a highlighted snippet of the content.
+func (s searchServiceImpl) result(
+	entityType, entityID, title, content string, score float64, createdAt, updatedAt time.Time, terms []string,
+) *common.SearchResult {
+	cfg := s.core.Config.SearchConfig
+	return &common.SearchResult{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Title:      highlight.Highlight(title, terms, cfg.HighlightPreTag, cfg.HighlightPostTag),
+		Snippet:    highlight.Snippet(content, terms, cfg.SnippetLength, cfg.HighlightPreTag, cfg.HighlightPostTag),
+		Score:      score,
+		CreatedAt:  createdAt.Format(time.RFC3339),
+		UpdatedAt:  updatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,163 @@
+package mods
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"fiber-admin/internal/pkg/config"
+	"fiber-admin/internal/pkg/dao"
+	"fiber-admin/internal/pkg/domain/entity"
+	"fiber-admin/internal/pkg/service"
+	"fiber-admin/pkg/errors"
+	"github.com/goccy/go-json"
+	"go.uber.org/zap"
+)
+
+// LogTailService relays new log entries to the admins tailing them. Events go through Redis pub/sub, so an admin
+// connected to any instance sees the entries of every instance. Events are published only while someone is tailing
+// them, so that the request log costs nothing when nobody is watching.
+type LogTailService interface {
+	// Tailed reports whether anyone is tailing the events of the type. The answer is cached briefly.
+	Tailed(ctx context.Context, eventType string) bool
+	Publish(ctx context.Context, event *entity.LogTailEvent)
+	// Tail subscribes to the events of the types matching the filter. The events are sent on the returned channel,
+	// which is closed once the returned function ends the subscription.
+	Tail(
+		ctx context.Context, eventTypes []string, filter *LogTailFilter,
+	) (<-chan *entity.LogTailEvent, func(), error)
+}
+
+// LogTailFilter selects the tailed events. Nil fields match every event.
+type LogTailFilter struct {
+	UserID     *string
+	EntityType *string // Request events have no entity type, so they never match one
+	Status     *string
+	Path       *string // Prefix of the request path
+}
+
+// Match reports whether the event passes the filter.
+func (f *LogTailFilter) Match(event *entity.LogTailEvent) bool {
+	if f == nil {
+		return true
+	}
+	if f.UserID != nil && *f.UserID != event.UserID {
+		return false
+	}
+	if f.EntityType != nil && *f.EntityType != event.EntityType {
+		return false
+	}
+	if f.Status != nil && *f.Status != event.Status {
+		return false
+	}
+	if f.Path != nil && !strings.HasPrefix(event.Path, *f.Path) {
+		return false
+	}
+	return true
+}
+
+// subscriberCheck caches whether anyone is tailing a type of events
+type subscriberCheck struct {
+	checkedAt atomic.Int64 // Unix time of the last check in nanoseconds
+	tailed    atomic.Bool
+}
+
+type logTailServiceImpl struct {
+	core   *service.Core
+	cache  *dao.Cache
+	checks map[string]*subscriberCheck
+}
+
+func NewLogTailService(core *service.Core, cache *dao.Cache) LogTailService {
+	return &logTailServiceImpl{
+		core:  core,
+		cache: cache,
+		checks: map[string]*subscriberCheck{
+			config.LogTailTypeOperation: new(subscriberCheck),
+			config.LogTailTypeRequest:   new(subscriberCheck),
+		},
+	}
+}
+
+func logTailChannel(eventType string) string {
+	return fmt.Sprintf("%s:%s", config.LogTailChannelPrefix, eventType)
+}
+
+func (l logTailServiceImpl) Tailed(ctx context.Context, eventType string) bool {
+	check, ok := l.checks[eventType]
+	if !ok || (eventType == config.LogTailTypeRequest && !l.core.Config.LogTailConfig.RequestLog) {
+		return false
+	}
+	// Only one request per interval asks Redis, the others use the last answer
+	now := time.Now().UnixNano()
+	checkedAt := check.checkedAt.Load()
+	if now-checkedAt < int64(l.core.Config.LogTailConfig.SubscriberCheck) ||
+		!check.checkedAt.CompareAndSwap(checkedAt, now) {
+		return check.tailed.Load()
+	}
+	count, err := l.cache.SubscriberCount(ctx, logTailChannel(eventType))
+	if err != nil {
+		l.core.Logger.Error("failed to count log tail subscribers", zap.Error(err), zap.String("type", eventType))
+		return check.tailed.Load()
+	}
+	check.tailed.Store(count > 0)
+	return count > 0
+}
+
+func (l logTailServiceImpl) Publish(ctx context.Context, event *entity.LogTailEvent) {
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		l.core.Logger.Error("failed to marshal log tail event", zap.Error(err))
+		return
+	}
+	if err := l.cache.Publish(ctx, logTailChannel(event.Type), string(eventJSON)); err != nil {
+		l.core.Logger.Error("failed to publish log tail event", zap.Error(err), zap.String("type", event.Type))
+	}
+}
+
+func (l logTailServiceImpl) Tail(
+	ctx context.Context, eventTypes []string, filter *LogTailFilter,
+) (<-chan *entity.LogTailEvent, func(), error) {
+	channels := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		if eventType == config.LogTailTypeRequest && !l.core.Config.LogTailConfig.RequestLog {
+			return nil, nil, errors.InvalidRequest(fmt.Errorf("request log tailing is disabled"))
+		}
+		channels = append(channels, logTailChannel(eventType))
+	}
+	// The subscription is active once Subscribe returns, so the events published once Tail returns are not missed
+	subscription, err := l.cache.Subscribe(ctx, channels...)
+	if err != nil {
+		l.core.Logger.Error("failed to subscribe to log tail", zap.Error(err))
+		return nil, nil, errors.ServiceError(fmt.Errorf("failed to tail logs"))
+	}
+	// Publish from this instance right away instead of waiting for the next check
+	for _, eventType := range eventTypes {
+		if check, ok := l.checks[eventType]; ok {
+			check.checkedAt.Store(0)
+		}
+	}
+
+	events := make(chan *entity.LogTailEvent, l.core.Config.LogTailConfig.BufferSize)
+	go func() {
+		defer close(events)
+		for message := range subscription.Channel() {
+			event := new(entity.LogTailEvent)
+			if err := json.Unmarshal([]byte(message.Payload), event); err != nil {
+				l.core.Logger.Error("failed to unmarshal log tail event", zap.Error(err))
+				continue
+			}
+			if !filter.Match(event) {
+				continue
+			}
+			select {
+			case events <- event:
+			default:
+				// The client cannot keep up. Its events are dropped rather than holding up the subscription.
+			}
+		}
+	}()
+	return events, func() { _ = subscription.Close() }, nil
+}
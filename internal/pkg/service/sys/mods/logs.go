@@ -3,11 +3,18 @@ package mods
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"fiber-admin/internal/pkg/config"
+	configmods "fiber-admin/internal/pkg/config/mods"
 	"fiber-admin/internal/pkg/dao/mods"
+	"fiber-admin/internal/pkg/domain/entity"
 	"fiber-admin/internal/pkg/service"
+	"fiber-admin/pkg/archive"
 	"fiber-admin/pkg/errors"
+	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
 )
 
 type LogsService interface {
@@ -17,12 +24,14 @@ type LogsService interface {
 	CacheLoginLog(ctx context.Context, userID *primitive.ObjectID, ipAddress, userAgent *string) error
 	InsertOperationLog(
 		ctx context.Context, userID, entityID *primitive.ObjectID,
-		ipAddress, userAgent, operation, entityType, description, status *string,
+		ipAddress, userAgent, requestID, operation, entityType, description, status *string,
 	) error
 	CacheOperationLog(
-		ctx context.Context, userID, entityID *primitive.ObjectID, ipAddress, userAgent *string,
+		ctx context.Context, userID, entityID *primitive.ObjectID, ipAddress, userAgent, requestID *string,
 		operation, entityType, description, status *string,
 	) error
+	ArchiveExpiredLogs(ctx context.Context) error
+	NotifySuspiciousLogins(ctx context.Context) error
 }
 
 type logsServiceImpl struct {
@@ -30,15 +39,18 @@ type logsServiceImpl struct {
 	loginLogDao     mods.LoginLogDao
 	operationLogDao mods.OperationLogDao
 	userDao         mods.UserDao
+	archiveStore    archive.Store
 }
 
 func NewLogsService(
 	core *service.Core, loginLogDao mods.LoginLogDao, operationLogDao mods.OperationLogDao,
+	archiveStore archive.Store,
 ) LogsService {
 	return &logsServiceImpl{
 		core:            core,
 		loginLogDao:     loginLogDao,
 		operationLogDao: operationLogDao,
+		archiveStore:    archiveStore,
 	}
 }
 
@@ -64,10 +76,10 @@ func (l logsServiceImpl) CacheLoginLog(
 
 func (l logsServiceImpl) InsertOperationLog(
 	ctx context.Context, userID, entityID *primitive.ObjectID,
-	ipAddress, userAgent, operation, entityType, description, status *string,
+	ipAddress, userAgent, requestID, operation, entityType, description, status *string,
 ) error {
 	_, err := l.operationLogDao.InsertOperationLog(
-		ctx, *userID, *entityID, *ipAddress, *userAgent, *operation, *entityType, *description,
+		ctx, *userID, *entityID, *ipAddress, *userAgent, *requestID, *operation, *entityType, *description,
 		*status,
 	)
 	if err != nil {
@@ -77,18 +89,207 @@ func (l logsServiceImpl) InsertOperationLog(
 }
 
 func (l logsServiceImpl) CacheOperationLog(
-	ctx context.Context, userID, entityID *primitive.ObjectID, ipAddress, userAgent *string,
+	ctx context.Context, userID, entityID *primitive.ObjectID, ipAddress, userAgent, requestID *string,
 	operation, entityType, description, status *string,
 ) error {
-	var _entityID primitive.ObjectID
-	if entityID == nil {
-		_entityID = primitive.NilObjectID
+	_entityID := primitive.NilObjectID
+	if entityID != nil {
+		_entityID = *entityID
 	}
 	err := l.operationLogDao.CacheOperationLog(
-		ctx, *userID, _entityID, *ipAddress, *userAgent, *operation, *entityType, *description, *status,
+		ctx, *userID, _entityID, *ipAddress, *userAgent, *requestID, *operation, *entityType, *description, *status,
 	)
 	if err != nil {
 		return errors.OperationFailed(fmt.Errorf("failed to cache operation log"))
 	}
 	return nil
 }
+
+// ArchiveExpiredLogs removes the logs older than the retention of their collection, writing them to an archive first
+// unless archiving is disabled for the collection.
+func (l logsServiceImpl) ArchiveExpiredLogs(ctx context.Context) error {
+	retention := l.core.Config.RetentionConfig
+	err := l.archiveExpiredLogs(
+		ctx, config.LoginLogCollectionName, retention.LoginLog,
+		func(ctx context.Context, endTime time.Time, fn func(id primitive.ObjectID, record any) error) error {
+			return l.loginLogDao.IterateExpiredLoginLogList(
+				ctx, endTime, func(loginLog *entity.LoginLogModel) error {
+					return fn(loginLog.LoginLogID, loginLog)
+				},
+			)
+		},
+		l.loginLogDao.DeleteLoginLogByIDList,
+	)
+	if err != nil {
+		return err
+	}
+	return l.archiveExpiredLogs(
+		ctx, config.OperationLogCollectionName, retention.OperationLog,
+		func(ctx context.Context, endTime time.Time, fn func(id primitive.ObjectID, record any) error) error {
+			return l.operationLogDao.IterateExpiredOperationLogList(
+				ctx, endTime, func(operationLog *entity.OperationLogModel) error {
+					return fn(operationLog.OperationLogID, operationLog)
+				},
+			)
+		},
+		l.operationLogDao.DeleteOperationLogByIDList,
+	)
+}
+
+// archiveExpiredLogs archives and deletes the expired logs of a collection. Logs are only deleted once the archive
+// holding them is completely written.
+func (l logsServiceImpl) archiveExpiredLogs(
+	ctx context.Context, collection string, retention configmods.LogRetentionConfig,
+	iterate func(ctx context.Context, endTime time.Time, fn func(id primitive.ObjectID, record any) error) error,
+	deleteList func(ctx context.Context, ids []primitive.ObjectID) (*int64, error),
+) error {
+	if retention.MaxAge <= 0 {
+		return nil
+	}
+	now := time.Now()
+	endTime := now.Add(-retention.MaxAge)
+
+	var writer *archive.Writer
+	name := archive.Name(collection, now)
+	if retention.Archive {
+		file, err := l.archiveStore.Create(ctx, name)
+		if err != nil {
+			l.core.Logger.Error(
+				"failed to create archive", zap.Error(err), zap.String("name", name),
+			)
+			return errors.OperationFailed(fmt.Errorf("failed to create archive %s", name))
+		}
+		writer = archive.NewWriter(file)
+	}
+	var ids []primitive.ObjectID
+	err := iterate(
+		ctx, endTime, func(id primitive.ObjectID, record any) error {
+			ids = append(ids, id)
+			if writer == nil {
+				return nil
+			}
+			return writer.Write(record)
+		},
+	)
+	if writer != nil {
+		if err == nil && len(ids) > 0 {
+			err = writer.Close()
+		} else {
+			_ = writer.Abort() // Nothing to keep
+		}
+	}
+	if err != nil {
+		l.core.Logger.Error(
+			"failed to archive expired logs",
+			zap.Error(err), zap.String("collection", collection), zap.String("name", name),
+		)
+		return errors.OperationFailed(fmt.Errorf("failed to archive %s", collection))
+	}
+
+	batchSize := int(l.core.Config.RetentionConfig.ArchiveBatchSize)
+	if batchSize <= 0 {
+		batchSize = len(ids)
+	}
+	var deleted int64
+	for start := 0; start < len(ids); start += batchSize {
+		end := min(start+batchSize, len(ids))
+		count, err := deleteList(ctx, ids[start:end])
+		if err != nil {
+			return errors.OperationFailed(fmt.Errorf("failed to delete archived %s", collection))
+		}
+		deleted += *count
+	}
+	if len(ids) > 0 {
+		l.core.Logger.Info(
+			"archived expired logs",
+			zap.String("collection", collection), zap.Bool("archived", writer != nil), zap.String("name", name),
+			zap.Int64("deleted", deleted), zap.Time("endTime", endTime),
+		)
+	}
+	return nil
+}
+
+// suspiciousLoginNotificationBatch bounds the flagged logins notified in one run.
+const suspiciousLoginNotificationBatch = 100
+
+// suspiciousLoginNotification is the body posted to the notification webhook for a flagged login.
+type suspiciousLoginNotification struct {
+	LoginLogID string   `json:"login_log_id"`
+	UserID     string   `json:"user_id"`
+	Username   string   `json:"username"`
+	Email      string   `json:"email"`
+	IPAddress  string   `json:"ip_address"`
+	UserAgent  string   `json:"user_agent"`
+	Browser    string   `json:"browser"`
+	OS         string   `json:"os"`
+	Device     string   `json:"device"`
+	Country    string   `json:"country"`
+	City       string   `json:"city"`
+	Flags      []string `json:"flags"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+// NotifySuspiciousLogins posts the flagged logins the users have not been notified of to the notification webhook.
+// Logins are marked as notified once the webhook accepts them, so failed ones are retried by the next run.
+func (l logsServiceImpl) NotifySuspiciousLogins(ctx context.Context) error {
+	analyticsConfig := l.core.Config.LoginAnalyticsConfig
+	if analyticsConfig.NotifyWebhook == "" {
+		return nil
+	}
+	loginLogs, err := l.loginLogDao.GetUnnotifiedLoginLogList(
+		ctx, time.Now().Add(-analyticsConfig.NotifyMaxAge), suspiciousLoginNotificationBatch,
+	)
+	if err != nil {
+		return errors.OperationFailed(fmt.Errorf("failed to get suspicious logins"))
+	}
+	notified := make([]primitive.ObjectID, 0, len(loginLogs))
+	for _, loginLog := range loginLogs {
+		if err = l.notifySuspiciousLogin(&loginLog); err != nil {
+			l.core.Logger.Error(
+				"failed to notify suspicious login",
+				zap.Error(err), zap.String("loginLogID", loginLog.LoginLogID.Hex()),
+			)
+			break
+		}
+		notified = append(notified, loginLog.LoginLogID)
+	}
+	if len(notified) > 0 {
+		if err := l.loginLogDao.SetLoginLogNotified(ctx, notified); err != nil {
+			return errors.OperationFailed(fmt.Errorf("failed to mark suspicious logins as notified"))
+		}
+	}
+	if err != nil {
+		return errors.ServiceError(fmt.Errorf("failed to notify suspicious logins"))
+	}
+	return nil
+}
+
+func (l logsServiceImpl) notifySuspiciousLogin(loginLog *entity.LoginLogModel) error {
+	agent := fiber.Post(l.core.Config.LoginAnalyticsConfig.NotifyWebhook).
+		Timeout(l.core.Config.LoginAnalyticsConfig.NotifyTimeout).
+		JSON(
+			suspiciousLoginNotification{
+				LoginLogID: loginLog.LoginLogID.Hex(),
+				UserID:     loginLog.UserID.Hex(),
+				Username:   loginLog.Username,
+				Email:      loginLog.Email,
+				IPAddress:  loginLog.IPAddress,
+				UserAgent:  loginLog.UserAgent,
+				Browser:    loginLog.Browser,
+				OS:         loginLog.OS,
+				Device:     loginLog.Device,
+				Country:    loginLog.Country,
+				City:       loginLog.City,
+				Flags:      loginLog.Flags,
+				CreatedAt:  loginLog.CreatedAt.Format(time.RFC3339),
+			},
+		)
+	status, _, errs := agent.Bytes()
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	if status < fiber.StatusOK || status >= fiber.StatusMultipleChoices {
+		return fmt.Errorf("webhook responded with status %d", status)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package mods
+
+import (
+	"context"
+	e "errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"fiber-admin/internal/pkg/config"
+	"fiber-admin/internal/pkg/dao"
+	"fiber-admin/internal/pkg/dao/mods"
+	"fiber-admin/internal/pkg/domain/entity"
+	"fiber-admin/internal/pkg/service"
+	"fiber-admin/pkg/errors"
+	"fiber-admin/pkg/lru"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// rateLimitOverrideCacheSize bounds the overrides an instance caches, misses included
+const rateLimitOverrideCacheSize = 10000
+
+// RateLimitService counts requests in the cache, so that the limits hold across instances. With the memory cache
+// backend each instance, and each prefork child, counts its own requests.
+type RateLimitService interface {
+	// Limit counts a request of the key against a limit of requests per window.
+	Limit(ctx context.Context, key string, limit int, window time.Duration) (*RateLimitResult, error)
+	// GetOverride returns the override of the user, or nil. Overrides are cached for the override cache TTL, until
+	// they are changed.
+	GetOverride(ctx context.Context, userID string) (*entity.RateLimitOverride, error)
+	ListOverrides(ctx context.Context) ([]entity.RateLimitOverride, error)
+	SetOverride(ctx context.Context, override *entity.RateLimitOverride) error
+	DeleteOverride(ctx context.Context, userID string) error
+}
+
+// RateLimitResult is the state of a limit once a request was counted.
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	Reset     time.Duration // Until the current window ends
+}
+
+type rateLimitServiceImpl struct {
+	core                 *service.Core
+	cache                *dao.Cache
+	rateLimitOverrideDao mods.RateLimitOverrideDao
+	overrides            *lru.Cache[string, *entity.RateLimitOverride] // By cache key
+}
+
+func NewRateLimitService(
+	core *service.Core, cache *dao.Cache, rateLimitOverrideDao mods.RateLimitOverrideDao,
+) RateLimitService {
+	return &rateLimitServiceImpl{
+		core:                 core,
+		cache:                cache,
+		rateLimitOverrideDao: rateLimitOverrideDao,
+		overrides:            lru.New[string, *entity.RateLimitOverride](rateLimitOverrideCacheSize),
+	}
+}
+
+// Limit approximates a sliding window with the counters of the current and previous fixed windows: the previous count
+// is weighted by the part of the previous window the sliding window still covers. Rejected requests are counted too,
+// so that a client has to slow down to get through.
+func (r rateLimitServiceImpl) Limit(
+	ctx context.Context, key string, limit int, window time.Duration,
+) (*RateLimitResult, error) {
+	now := time.Now().UnixNano()
+	index, elapsed := now/int64(window), time.Duration(now%int64(window))
+	current, err := r.cache.IncrExpire(
+		ctx, fmt.Sprintf("%s:%s:%d", config.RateLimitCachePrefix, key, index), 2*window,
+	)
+	if err != nil {
+		r.core.Logger.Error("failed to count request", zap.Error(err), zap.String("key", key))
+		return nil, errors.ServiceError(fmt.Errorf("failed to count request"))
+	}
+	var previous int64
+	cached, err := r.cache.Get(ctx, fmt.Sprintf("%s:%s:%d", config.RateLimitCachePrefix, key, index-1))
+	if err == nil {
+		previous, _ = strconv.ParseInt(*cached, 10, 64)
+	} else if !e.Is(err, dao.CacheNil{}) {
+		r.core.Logger.Error("failed to get previous request count", zap.Error(err), zap.String("key", key))
+		return nil, errors.ServiceError(fmt.Errorf("failed to count request"))
+	}
+
+	count := int(math.Ceil(float64(previous)*(1-float64(elapsed)/float64(window)) + float64(current)))
+	result := &RateLimitResult{
+		Allowed: count <= limit,
+		Limit:   limit,
+		Reset:   window - elapsed,
+	}
+	if count < limit {
+		result.Remaining = limit - count
+	}
+	return result, nil
+}
+
+func (r rateLimitServiceImpl) GetOverride(ctx context.Context, userID string) (*entity.RateLimitOverride, error) {
+	// Overrides are cached under the current generation, so that an instance sees a change made on another one at once
+	key := r.cache.Key(ctx, config.RateLimitOverrideCachePrefix, "userID:%s", userID)
+	if override, ok := r.overrides.Get(key); ok {
+		return override, nil
+	}
+	override, err := r.rateLimitOverrideDao.GetRateLimitOverride(ctx, userID)
+	if e.Is(err, mongo.ErrNoDocuments) {
+		override = nil
+	} else if err != nil {
+		r.core.Logger.Error("failed to get rate limit override", zap.Error(err), zap.String("userID", userID))
+		return nil, errors.ServiceError(fmt.Errorf("failed to get rate limit override"))
+	}
+	r.overrides.Set(key, override, r.core.Config.MiddlewareConfig.LimiterConfig.OverrideCacheTTL)
+	return override, nil
+}
+
+func (r rateLimitServiceImpl) ListOverrides(ctx context.Context) ([]entity.RateLimitOverride, error) {
+	overrides, err := r.rateLimitOverrideDao.GetRateLimitOverrideList(ctx)
+	if err != nil {
+		return nil, errors.OperationFailed(fmt.Errorf("failed to list rate limit overrides"))
+	}
+	return overrides, nil
+}
+
+// SetOverride stores the override, which every instance sees at once. An override which is not exempt needs a max.
+func (r rateLimitServiceImpl) SetOverride(ctx context.Context, override *entity.RateLimitOverride) error {
+	if !override.Exempt && override.Max <= 0 {
+		return errors.InvalidRequest(fmt.Errorf("max must be greater than 0 unless exempt"))
+	}
+	override.UpdatedAt = time.Now()
+	if err := r.rateLimitOverrideDao.UpsertRateLimitOverride(ctx, override); err != nil {
+		return errors.OperationFailed(fmt.Errorf("failed to set rate limit override"))
+	}
+	return nil
+}
+
+func (r rateLimitServiceImpl) DeleteOverride(ctx context.Context, userID string) error {
+	if err := r.rateLimitOverrideDao.DeleteRateLimitOverride(ctx, userID); err != nil {
+		return errors.OperationFailed(fmt.Errorf("failed to delete rate limit override"))
+	}
+	return nil
+}
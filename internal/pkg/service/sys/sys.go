@@ -5,5 +5,7 @@ import (
 )
 
 type Sys struct {
-	LogsService mods.LogsService
+	LogsService      mods.LogsService
+	LogTailService   mods.LogTailService
+	RateLimitService mods.RateLimitService
 }
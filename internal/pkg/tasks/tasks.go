@@ -5,8 +5,10 @@ import (
 
 	"fiber-admin/internal/pkg/config"
 	"fiber-admin/internal/pkg/dao/mods"
+	sysservice "fiber-admin/internal/pkg/service/sys/mods"
 	"fiber-admin/pkg/cron"
 	"fiber-admin/pkg/jwt"
+	"fiber-admin/pkg/prometheus"
 	logging "fiber-admin/pkg/zap"
 	"go.uber.org/zap"
 )
@@ -16,13 +18,15 @@ type Tasks struct {
 	config          *config.Config
 	loginLogDao     mods.LoginLogDao
 	operationLogDao mods.OperationLogDao
+	logsService     sysservice.LogsService
 	jwt             *jwt.Jwt
+	prometheus      *prometheus.Prometheus
 	logger          *zap.Logger
 }
 
 func New(
 	ctx context.Context, config *config.Config, loginLogDao mods.LoginLogDao, operationLogDao mods.OperationLogDao,
-	jwt *jwt.Jwt, zap *logging.Zap,
+	logsService sysservice.LogsService, jwt *jwt.Jwt, prometheus *prometheus.Prometheus, zap *logging.Zap,
 ) (*Tasks, error) {
 	ctx = zap.SetTagInContext(ctx, logging.CronTag)
 	logger, err := zap.GetLogger(ctx)
@@ -34,7 +38,9 @@ func New(
 		config:          config,
 		loginLogDao:     loginLogDao,
 		operationLogDao: operationLogDao,
+		logsService:     logsService,
 		jwt:             jwt,
+		prometheus:      prometheus,
 		logger:          logger,
 	}, nil
 }
@@ -43,6 +49,17 @@ func (t *Tasks) syncLogs() {
 	t.logger.Info("Syncing logs from cache to database")
 	t.loginLogDao.SyncLoginLog(t.cron.Context())
 	t.operationLogDao.SyncOperationLog(t.cron.Context())
+
+	if stats, err := t.loginLogDao.GetLoginLogQueueStats(t.cron.Context()); err != nil {
+		t.logger.Error("Failed to get login log queue stats", zap.Error(err))
+	} else {
+		t.prometheus.ObserveLogQueue("login", stats.Backlog, stats.Pending, stats.DeadLetters, stats.Lag)
+	}
+	if stats, err := t.operationLogDao.GetOperationLogQueueStats(t.cron.Context()); err != nil {
+		t.logger.Error("Failed to get operation log queue stats", zap.Error(err))
+	} else {
+		t.prometheus.ObserveLogQueue("operation", stats.Backlog, stats.Pending, stats.DeadLetters, stats.Lag)
+	}
 }
 
 func (t *Tasks) updateKey() {
@@ -52,6 +69,27 @@ func (t *Tasks) updateKey() {
 	}
 }
 
+func (t *Tasks) checkpointOperationLog() {
+	t.logger.Info("Signing operation log checkpoint")
+	if _, err := t.operationLogDao.InsertOperationLogCheckpoint(t.cron.Context()); err != nil {
+		t.logger.Error("Failed to sign operation log checkpoint", zap.Error(err))
+	}
+}
+
+func (t *Tasks) archiveLogs() {
+	t.logger.Info("Archiving expired logs")
+	if err := t.logsService.ArchiveExpiredLogs(t.cron.Context()); err != nil {
+		t.logger.Error("Failed to archive expired logs", zap.Error(err))
+	}
+}
+
+func (t *Tasks) notifySuspiciousLogins() {
+	t.logger.Info("Notifying users of suspicious logins")
+	if err := t.logsService.NotifySuspiciousLogins(t.cron.Context()); err != nil {
+		t.logger.Error("Failed to notify users of suspicious logins", zap.Error(err))
+	}
+}
+
 func (t *Tasks) Start() error {
 	syncLogsID, err := t.cron.AddFunc(t.config.TasksConfig.SyncLogsSpec, t.syncLogs)
 	if err != nil {
@@ -64,6 +102,26 @@ func (t *Tasks) Start() error {
 		return err
 	}
 	t.logger.Info("Added update key task", zap.Int("id", int(updateKeyID)))
+	checkpointID, err := t.cron.AddFunc(t.config.TasksConfig.CheckpointSpec, t.checkpointOperationLog)
+	if err != nil {
+		t.logger.Error("Failed to add operation log checkpoint task", zap.Error(err))
+		return err
+	}
+	t.logger.Info("Added operation log checkpoint task", zap.Int("id", int(checkpointID)))
+	retentionID, err := t.cron.AddFunc(t.config.TasksConfig.RetentionSpec, t.archiveLogs)
+	if err != nil {
+		t.logger.Error("Failed to add log retention task", zap.Error(err))
+		return err
+	}
+	t.logger.Info("Added log retention task", zap.Int("id", int(retentionID)))
+	if t.config.LoginAnalyticsConfig.NotifyWebhook != "" {
+		notifyLoginID, err := t.cron.AddFunc(t.config.TasksConfig.NotifyLoginSpec, t.notifySuspiciousLogins)
+		if err != nil {
+			t.logger.Error("Failed to add suspicious login notification task", zap.Error(err))
+			return err
+		}
+		t.logger.Info("Added suspicious login notification task", zap.Int("id", int(notifyLoginID)))
+	}
 	t.logger.Info("Starting tasks")
 	t.cron.Start()
 	return nil
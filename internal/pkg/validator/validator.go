@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"fiber-admin/internal/pkg/config"
+	"fiber-admin/pkg/utils/slug"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -54,7 +55,8 @@ func userRole(fl validator.FieldLevel) bool {
 
 func operationType(fl validator.FieldLevel) bool {
 	switch fl.Field().String() {
-	case config.OperationTypeCreate, config.OperationTypeUpdate, config.OperationTypeDelete:
+	case config.OperationTypeCreate, config.OperationTypeUpdate, config.OperationTypeDelete,
+		config.OperationTypeRead, config.OperationTypeExport:
 		return true
 	default:
 		return false
@@ -63,7 +65,8 @@ func operationType(fl validator.FieldLevel) bool {
 
 func entityType(fl validator.FieldLevel) bool {
 	switch fl.Field().String() {
-	case config.EntityTypeDocumentation, config.EntityTypeNotice, config.EntityTypeUser:
+	case config.EntityTypeDocumentation, config.EntityTypeNotice, config.EntityTypeUser, config.EntityTypeLoginLog,
+		config.EntityTypeOperationLog, config.EntityTypeCache, config.EntityTypeRateLimit:
 		return true
 	default:
 		return false
@@ -79,6 +82,55 @@ func operationStatus(fl validator.FieldLevel) bool {
 	}
 }
 
+func searchScope(fl validator.FieldLevel) bool {
+	switch fl.Field().String() {
+	case config.SearchScopeAll, config.EntityTypeDocumentation, config.EntityTypeNotice:
+		return true
+	default:
+		return false
+	}
+}
+
+func contentFormat(fl validator.FieldLevel) bool {
+	switch fl.Field().String() {
+	case config.ContentFormatMarkdown, config.ContentFormatHTML:
+		return true
+	default:
+		return false
+	}
+}
+
+func exportFormat(fl validator.FieldLevel) bool {
+	switch fl.Field().String() {
+	case config.ExportFormatCSV, config.ExportFormatNDJSON:
+		return true
+	default:
+		return false
+	}
+}
+
+func statsBucket(fl validator.FieldLevel) bool {
+	switch fl.Field().String() {
+	case config.StatsBucketHour, config.StatsBucketDay, config.StatsBucketWeek, config.StatsBucketMonth:
+		return true
+	default:
+		return false
+	}
+}
+
+func cacheEntity(fl validator.FieldLevel) bool {
+	switch fl.Field().String() {
+	case config.CacheEntityUser, config.CacheEntityNotice, config.CacheEntityDocumentation, config.CacheEntityStats:
+		return true
+	default:
+		return false
+	}
+}
+
+func slugFormat(fl validator.FieldLevel) bool {
+	return slug.Valid(fl.Field().String())
+}
+
 func NewValidator() (*validator.Validate, error) {
 	var err error
 	once.Do(
@@ -105,6 +157,24 @@ func NewValidator() (*validator.Validate, error) {
 			if err = validate.RegisterValidation("operationStatus", operationStatus); err != nil {
 				return
 			}
+			if err = validate.RegisterValidation("searchScope", searchScope); err != nil {
+				return
+			}
+			if err = validate.RegisterValidation("slug", slugFormat); err != nil {
+				return
+			}
+			if err = validate.RegisterValidation("contentFormat", contentFormat); err != nil {
+				return
+			}
+			if err = validate.RegisterValidation("exportFormat", exportFormat); err != nil {
+				return
+			}
+			if err = validate.RegisterValidation("statsBucket", statsBucket); err != nil {
+				return
+			}
+			if err = validate.RegisterValidation("cacheEntity", cacheEntity); err != nil {
+				return
+			}
 			validateInstance = validate
 		},
 	)
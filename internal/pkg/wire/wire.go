@@ -45,10 +45,14 @@ var (
 		wire.Struct(new(commonapis.DocumentationApi), "*"),
 		wire.Struct(new(commonapis.NoticeApi), "*"),
 		wire.Struct(new(commonapis.IdempotencyApi), "*"),
+		wire.Struct(new(commonapis.SearchApi), "*"),
 		wire.Struct(new(adminapis.UserApi), "*"),
 		wire.Struct(new(adminapis.DocumentationApi), "*"),
 		wire.Struct(new(adminapis.NoticeApi), "*"),
 		wire.Struct(new(adminapis.LogsApi), "*"),
+		wire.Struct(new(adminapis.StatsApi), "*"),
+		wire.Struct(new(adminapis.CacheApi), "*"),
+		wire.Struct(new(adminapis.RateLimitApi), "*"),
 		wire.Struct(new(commonapi.Common), "*"),
 		wire.Struct(new(adminapi.Admin), "*"),
 		wire.Struct(new(api.Api), "*"),
@@ -67,12 +71,17 @@ var (
 		adminservices.NewNoticeService,
 		adminservices.NewDocumentationService,
 		adminservices.NewLogsService,
+		adminservices.NewStatsService,
+		adminservices.NewCacheService,
 		commonservices.NewAuthService,
 		commonservices.NewProfileService,
 		commonservices.NewDocumentationService,
 		commonservices.NewNoticeService,
 		commonservices.NewIdempotencyService,
+		commonservices.NewSearchService,
 		sysservices.NewLogsService,
+		sysservices.NewLogTailService,
+		sysservices.NewRateLimitService,
 	)
 
 	DaoProviderSet = wire.NewSet(
@@ -83,6 +92,9 @@ var (
 		daos.NewLoginLogDao,
 		daos.NewOperationLogDao,
 		daos.NewDocumentationDao,
+		daos.NewDocumentationRevisionDao,
+		daos.NewStatsDao,
+		daos.NewRateLimitOverrideDao,
 	)
 
 	MiddlewareProviderSet = wire.NewSet(
@@ -91,6 +103,8 @@ var (
 		wire.Struct(new(wares.AuthMiddleware), "*"),
 		wire.Struct(new(wares.ContextMiddleware), "*"),
 		wire.Struct(new(wares.IdempotencyMiddleware), "*"),
+		wire.Struct(new(wares.OperationLogMiddleware), "*"),
+		wire.Struct(new(wares.RateLimitMiddleware), "*"),
 		wire.Struct(new(middleware.Middleware), "*"),
 	)
 
@@ -104,10 +118,13 @@ func InitializeApp(ctx context.Context) (*app.App, error) {
 	wire.Build(
 		config.New,
 		InitializeMongo,
-		InitializeRedis,
+		InitializeCacheBackend,
 		InitializeZap,
 		InitializeJwt,
 		InitializePrometheus,
+		InitializeMarkdown,
+		InitializeArchiveStore,
+		InitializeGeoIP,
 		InitializeCasbinEnforcer,
 		DaoProviderSet,
 		ServiceProviderSet,
@@ -120,3 +137,24 @@ func InitializeApp(ctx context.Context) (*app.App, error) {
 	)
 	return new(app.App), nil
 }
+
+// InitializeLogsService initialize the admin logs service for commands run outside the server
+func InitializeLogsService(ctx context.Context) (adminservices.LogsService, error) {
+	wire.Build(
+		config.New,
+		InitializeMongo,
+		InitializeCacheBackend,
+		InitializeZap,
+		InitializeArchiveStore,
+		InitializeGeoIP,
+		InitializePrometheus,
+		dao.NewCore,
+		dao.NewCache,
+		daos.NewUserDao,
+		daos.NewLoginLogDao,
+		daos.NewOperationLogDao,
+		service.NewCore,
+		adminservices.NewLogsService,
+	)
+	return nil, nil
+}
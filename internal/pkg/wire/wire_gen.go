@@ -55,11 +55,12 @@ func InitializeApp(ctx context.Context) (*app.App, error) {
 	if err != nil {
 		return nil, err
 	}
-	redis, err := InitializeRedis(ctx, configConfig)
+	cacheBackend, err := InitializeCacheBackend(ctx, configConfig)
 	if err != nil {
 		return nil, err
 	}
-	cache := dao.NewCache(redis, configConfig)
+	prometheus := InitializePrometheus(configConfig)
+	cache := dao.NewCache(cacheBackend, configConfig, prometheus)
 	userDao, err := mods.NewUserDao(ctx, daoCore, cache)
 	if err != nil {
 		return nil, err
@@ -69,7 +70,11 @@ func InitializeApp(ctx context.Context) (*app.App, error) {
 		return nil, err
 	}
 	userService := mods2.NewUserService(core, userDao, enforcer)
-	loginLogDao, err := mods.NewLoginLogDao(ctx, daoCore, cache, userDao)
+	reader, err := InitializeGeoIP(configConfig)
+	if err != nil {
+		return nil, err
+	}
+	loginLogDao, err := mods.NewLoginLogDao(ctx, daoCore, cache, userDao, reader)
 	if err != nil {
 		return nil, err
 	}
@@ -77,14 +82,18 @@ func InitializeApp(ctx context.Context) (*app.App, error) {
 	if err != nil {
 		return nil, err
 	}
-	logsService := mods3.NewLogsService(core, loginLogDao, operationLogDao)
+	store, err := InitializeArchiveStore(configConfig)
+	if err != nil {
+		return nil, err
+	}
+	logsService := mods3.NewLogsService(core, loginLogDao, operationLogDao, store)
+	logTailService := mods3.NewLogTailService(core, cache)
 	validate, err := validator.NewValidator()
 	if err != nil {
 		return nil, err
 	}
 	userApi := &mods4.UserApi{
 		UserService: userService,
-		LogsService: logsService,
 		Validator:   validate,
 	}
 	noticeDao, err := mods.NewNoticeDao(ctx, daoCore, cache)
@@ -94,29 +103,53 @@ func InitializeApp(ctx context.Context) (*app.App, error) {
 	noticeService := mods2.NewNoticeService(core, noticeDao)
 	noticeApi := &mods4.NoticeApi{
 		NoticeService: noticeService,
-		LogsService:   logsService,
 		Validator:     validate,
 	}
 	documentationDao, err := mods.NewDocumentationDao(ctx, daoCore, cache)
 	if err != nil {
 		return nil, err
 	}
-	documentationService := mods2.NewDocumentationService(core, documentationDao)
+	documentationRevisionDao, err := mods.NewDocumentationRevisionDao(ctx, daoCore)
+	if err != nil {
+		return nil, err
+	}
+	documentationService := mods2.NewDocumentationService(core, documentationDao, documentationRevisionDao)
 	documentationApi := &mods4.DocumentationApi{
 		DocumentationService: documentationService,
-		LogsService:          logsService,
 		Validator:            validate,
 	}
-	modsLogsService := mods2.NewLogsService(core, loginLogDao, operationLogDao)
+	modsLogsService := mods2.NewLogsService(core, loginLogDao, operationLogDao, store)
 	logsApi := &mods4.LogsApi{
-		LogsService: modsLogsService,
-		Validator:   validate,
+		LogsService:    modsLogsService,
+		LogTailService: logTailService,
+		Validator:      validate,
+		Config:         configConfig,
+	}
+	statsDao := mods.NewStatsDao(daoCore, cache)
+	statsService := mods2.NewStatsService(core, statsDao)
+	statsApi := &mods4.StatsApi{
+		StatsService: statsService,
+		Validator:    validate,
+	}
+	cacheService := mods2.NewCacheService(core, cache, noticeDao, documentationDao)
+	cacheApi := &mods4.CacheApi{
+		CacheService: cacheService,
+		Validator:    validate,
+	}
+	rateLimitOverrideDao := mods.NewRateLimitOverrideDao(daoCore, cache)
+	rateLimitService := mods3.NewRateLimitService(core, cache, rateLimitOverrideDao)
+	rateLimitApi := &mods4.RateLimitApi{
+		RateLimitService: rateLimitService,
+		Validator:        validate,
 	}
 	adminAdmin := &admin.Admin{
 		UserApi:          userApi,
 		NoticeApi:        noticeApi,
 		DocumentationApi: documentationApi,
 		LogsApi:          logsApi,
+		StatsApi:         statsApi,
+		CacheApi:         cacheApi,
+		RateLimitApi:     rateLimitApi,
 	}
 	jwt, err := InitializeJwt(configConfig)
 	if err != nil {
@@ -132,12 +165,13 @@ func InitializeApp(ctx context.Context) (*app.App, error) {
 	profileApi := &mods6.ProfileApi{
 		ProfileService: profileService,
 	}
-	modsDocumentationService := mods5.NewDocumentationService(core, documentationDao)
+	markdown := InitializeMarkdown(configConfig)
+	modsDocumentationService := mods5.NewDocumentationService(core, documentationDao, cache, markdown)
 	modsDocumentationApi := &mods6.DocumentationApi{
 		DocumentationService: modsDocumentationService,
 		Validator:            validate,
 	}
-	modsNoticeService := mods5.NewNoticeService(core, noticeDao)
+	modsNoticeService := mods5.NewNoticeService(core, noticeDao, cache, markdown)
 	modsNoticeApi := &mods6.NoticeApi{
 		NoticeService: modsNoticeService,
 		Validator:     validate,
@@ -146,12 +180,18 @@ func InitializeApp(ctx context.Context) (*app.App, error) {
 	idempotencyApi := &mods6.IdempotencyApi{
 		IdempotencyService: idempotencyService,
 	}
+	searchService := mods5.NewSearchService(core, documentationDao, noticeDao)
+	searchApi := &mods6.SearchApi{
+		SearchService: searchService,
+		Validator:     validate,
+	}
 	commonCommon := &common.Common{
 		AuthApi:          authApi,
 		ProfileApi:       profileApi,
 		DocumentationApi: modsDocumentationApi,
 		NoticeApi:        modsNoticeApi,
 		IdempotencyApi:   idempotencyApi,
+		SearchApi:        searchApi,
 	}
 	apiApi := &api.Api{
 		AdminApi:  adminAdmin,
@@ -172,12 +212,14 @@ func InitializeApp(ctx context.Context) (*app.App, error) {
 	authMiddleware := &mods8.AuthMiddleware{
 		Jwt:    jwt,
 		Cache:  cache,
+		Zap:    zap,
 		Config: configConfig,
 	}
 	loggingMiddleware := &mods8.LoggingMiddleware{
-		Zap: zap,
+		Zap:            zap,
+		LogTailService: logTailService,
+		Config:         configConfig,
 	}
-	prometheus := InitializePrometheus(configConfig)
 	prometheusMiddleware := &mods8.PrometheusMiddleware{
 		Prometheus: prometheus,
 		Zap:        zap,
@@ -189,39 +231,101 @@ func InitializeApp(ctx context.Context) (*app.App, error) {
 		IdempotencyService: idempotencyService,
 		Config:             configConfig,
 	}
-	middlewareMiddleware := &middleware.Middleware{
-		AuthMiddleware:        authMiddleware,
-		LoggingMiddleware:     loggingMiddleware,
-		PrometheusMiddleware:  prometheusMiddleware,
-		ContextMiddleware:     contextMiddleware,
-		IdempotencyMiddleware: idempotencyMiddleware,
-		Config:                configConfig,
+	operationLogMiddleware := &mods8.OperationLogMiddleware{
+		LogsService:    logsService,
+		LogTailService: logTailService,
+		Config:         configConfig,
 	}
-	tasksTasks, err := tasks.New(ctx, configConfig, loginLogDao, operationLogDao, jwt, zap)
+	rateLimitMiddleware := &mods8.RateLimitMiddleware{
+		RateLimitService: rateLimitService,
+		Jwt:              jwt,
+		Zap:              zap,
+		Config:           configConfig,
+	}
+	middlewareMiddleware := &middleware.Middleware{
+		AuthMiddleware:         authMiddleware,
+		LoggingMiddleware:      loggingMiddleware,
+		PrometheusMiddleware:   prometheusMiddleware,
+		ContextMiddleware:      contextMiddleware,
+		IdempotencyMiddleware:  idempotencyMiddleware,
+		OperationLogMiddleware: operationLogMiddleware,
+		RateLimitMiddleware:    rateLimitMiddleware,
+		Config:                 configConfig,
+	}
+	tasksTasks, err := tasks.New(ctx, configConfig, loginLogDao, operationLogDao, logsService, jwt, prometheus, zap)
 	if err != nil {
 		return nil, err
 	}
-	appApp, err := app.New(ctx, zap, configConfig, router3, middlewareMiddleware, tasksTasks, mongo, redis)
+	appApp, err := app.New(ctx, zap, configConfig, router3, middlewareMiddleware, tasksTasks, mongo, cacheBackend, jwt, prometheus)
 	if err != nil {
 		return nil, err
 	}
 	return appApp, nil
 }
 
+// InitializeLogsService initialize the admin logs service for commands run outside the server
+func InitializeLogsService(ctx context.Context) (mods2.LogsService, error) {
+	configConfig := config.New()
+	zap, err := InitializeZap(configConfig)
+	if err != nil {
+		return nil, err
+	}
+	core, err := service.NewCore(ctx, configConfig, zap)
+	if err != nil {
+		return nil, err
+	}
+	mongo, err := InitializeMongo(ctx, configConfig)
+	if err != nil {
+		return nil, err
+	}
+	daoCore, err := dao.NewCore(ctx, mongo, zap, configConfig)
+	if err != nil {
+		return nil, err
+	}
+	cacheBackend, err := InitializeCacheBackend(ctx, configConfig)
+	if err != nil {
+		return nil, err
+	}
+	prometheus := InitializePrometheus(configConfig)
+	cache := dao.NewCache(cacheBackend, configConfig, prometheus)
+	userDao, err := mods.NewUserDao(ctx, daoCore, cache)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := InitializeGeoIP(configConfig)
+	if err != nil {
+		return nil, err
+	}
+	loginLogDao, err := mods.NewLoginLogDao(ctx, daoCore, cache, userDao, reader)
+	if err != nil {
+		return nil, err
+	}
+	operationLogDao, err := mods.NewOperationLogDao(ctx, daoCore, cache, userDao)
+	if err != nil {
+		return nil, err
+	}
+	store, err := InitializeArchiveStore(configConfig)
+	if err != nil {
+		return nil, err
+	}
+	logsService := mods2.NewLogsService(core, loginLogDao, operationLogDao, store)
+	return logsService, nil
+}
+
 // wire.go:
 
 var (
 	RouterProviderSet = wire.NewSet(wire.Struct(new(mods7.AdminRouter), "*"), wire.Struct(new(mods7.CommonRouter), "*"), wire.Struct(new(router.Router), "*"), wire.Struct(new(router2.Router), "*"))
 
-	ApiProviderSet = wire.NewSet(wire.Struct(new(mods6.AuthApi), "*"), wire.Struct(new(mods6.ProfileApi), "*"), wire.Struct(new(mods6.DocumentationApi), "*"), wire.Struct(new(mods6.NoticeApi), "*"), wire.Struct(new(mods6.IdempotencyApi), "*"), wire.Struct(new(mods4.UserApi), "*"), wire.Struct(new(mods4.DocumentationApi), "*"), wire.Struct(new(mods4.NoticeApi), "*"), wire.Struct(new(mods4.LogsApi), "*"), wire.Struct(new(common.Common), "*"), wire.Struct(new(admin.Admin), "*"), wire.Struct(new(api.Api), "*"))
+	ApiProviderSet = wire.NewSet(wire.Struct(new(mods6.AuthApi), "*"), wire.Struct(new(mods6.ProfileApi), "*"), wire.Struct(new(mods6.DocumentationApi), "*"), wire.Struct(new(mods6.NoticeApi), "*"), wire.Struct(new(mods6.IdempotencyApi), "*"), wire.Struct(new(mods6.SearchApi), "*"), wire.Struct(new(mods4.UserApi), "*"), wire.Struct(new(mods4.DocumentationApi), "*"), wire.Struct(new(mods4.NoticeApi), "*"), wire.Struct(new(mods4.LogsApi), "*"), wire.Struct(new(mods4.StatsApi), "*"), wire.Struct(new(mods4.CacheApi), "*"), wire.Struct(new(mods4.RateLimitApi), "*"), wire.Struct(new(common.Common), "*"), wire.Struct(new(admin.Admin), "*"), wire.Struct(new(api.Api), "*"))
 
 	ValidatorProviderSet = wire.NewSet(validator.NewValidator)
 
-	ServiceProviderSet = wire.NewSet(service.NewCore, wire.Struct(new(admin2.Admin), "*"), wire.Struct(new(common2.Common), "*"), wire.Struct(new(sys.Sys), "*"), mods2.NewUserService, mods2.NewNoticeService, mods2.NewDocumentationService, mods2.NewLogsService, mods5.NewAuthService, mods5.NewProfileService, mods5.NewDocumentationService, mods5.NewNoticeService, mods5.NewIdempotencyService, mods3.NewLogsService)
+	ServiceProviderSet = wire.NewSet(service.NewCore, wire.Struct(new(admin2.Admin), "*"), wire.Struct(new(common2.Common), "*"), wire.Struct(new(sys.Sys), "*"), mods2.NewUserService, mods2.NewNoticeService, mods2.NewDocumentationService, mods2.NewLogsService, mods2.NewStatsService, mods2.NewCacheService, mods5.NewAuthService, mods5.NewProfileService, mods5.NewDocumentationService, mods5.NewNoticeService, mods5.NewIdempotencyService, mods5.NewSearchService, mods3.NewLogsService, mods3.NewLogTailService, mods3.NewRateLimitService)
 
-	DaoProviderSet = wire.NewSet(dao.NewCore, dao.NewCache, mods.NewUserDao, mods.NewNoticeDao, mods.NewLoginLogDao, mods.NewOperationLogDao, mods.NewDocumentationDao)
+	DaoProviderSet = wire.NewSet(dao.NewCore, dao.NewCache, mods.NewUserDao, mods.NewNoticeDao, mods.NewLoginLogDao, mods.NewOperationLogDao, mods.NewDocumentationDao, mods.NewDocumentationRevisionDao, mods.NewStatsDao, mods.NewRateLimitOverrideDao)
 
-	MiddlewareProviderSet = wire.NewSet(wire.Struct(new(mods8.LoggingMiddleware), "*"), wire.Struct(new(mods8.PrometheusMiddleware), "*"), wire.Struct(new(mods8.AuthMiddleware), "*"), wire.Struct(new(mods8.ContextMiddleware), "*"), wire.Struct(new(mods8.IdempotencyMiddleware), "*"), wire.Struct(new(middleware.Middleware), "*"))
+	MiddlewareProviderSet = wire.NewSet(wire.Struct(new(mods8.LoggingMiddleware), "*"), wire.Struct(new(mods8.PrometheusMiddleware), "*"), wire.Struct(new(mods8.AuthMiddleware), "*"), wire.Struct(new(mods8.ContextMiddleware), "*"), wire.Struct(new(mods8.IdempotencyMiddleware), "*"), wire.Struct(new(mods8.OperationLogMiddleware), "*"), wire.Struct(new(mods8.RateLimitMiddleware), "*"), wire.Struct(new(middleware.Middleware), "*"))
 
 	SchedulerProviderSet = wire.NewSet(tasks.New)
 )
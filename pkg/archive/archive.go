@@ -0,0 +1,223 @@
+package archive
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// Extension is the file extension of archives, which hold one JSON record per line compressed with gzip.
+const Extension = ".ndjson.gz"
+
+// nameTimeLayout is the layout of the creation time in archive names.
+const nameTimeLayout = "20060102T150405Z"
+
+var (
+	ErrInvalidName = errors.New("invalid archive name")
+	ErrNotFound    = errors.New("archive not found")
+)
+
+// Info describes a stored archive.
+type Info struct {
+	Name      string
+	Size      int64
+	CreatedAt time.Time
+}
+
+// Name returns the name of an archive of the given kind created at t.
+func Name(prefix string, t time.Time) string {
+	return prefix + "-" + t.UTC().Format(nameTimeLayout) + Extension
+}
+
+// Prefix returns the kind of archive a name returned by Name was given.
+func Prefix(name string) string {
+	i := strings.LastIndex(name, "-")
+	if i < 0 || !strings.HasSuffix(name, Extension) {
+		return ""
+	}
+	return name[:i]
+}
+
+// Store keeps archives by name. Local disk is the only implementation so far; an object store can be plugged in by
+// implementing the same interface.
+type Store interface {
+	Create(ctx context.Context, name string) (File, error)
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+	List(ctx context.Context) ([]Info, error)
+	Remove(ctx context.Context, name string) error
+}
+
+// File is an archive being written. It only becomes visible once closed, and is discarded by Abort.
+type File interface {
+	io.WriteCloser
+	Abort() error
+}
+
+// LocalStore keeps archives as files in a directory.
+type LocalStore struct {
+	Dir string
+}
+
+func NewLocalStore(dir string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	return &LocalStore{Dir: dir}, nil
+}
+
+// localFile renames the temporary file to the archive name once it is completely written.
+type localFile struct {
+	*os.File
+	path string
+}
+
+func (f *localFile) Close() error {
+	if err := f.File.Close(); err != nil {
+		_ = os.Remove(f.File.Name())
+		return err
+	}
+	return os.Rename(f.File.Name(), f.path)
+}
+
+func (f *localFile) Abort() error {
+	_ = f.File.Close()
+	return os.Remove(f.File.Name())
+}
+
+func (s *LocalStore) Create(_ context.Context, name string) (File, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.CreateTemp(s.Dir, "."+name+".*")
+	if err != nil {
+		return nil, err
+	}
+	return &localFile{File: file, path: path}, nil
+}
+
+func (s *LocalStore) Open(_ context.Context, name string) (io.ReadCloser, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+// List returns the archives, newest first.
+func (s *LocalStore) List(_ context.Context) ([]Info, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]Info, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), Extension) || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue // Removed while listing
+		}
+		infos = append(infos, Info{Name: entry.Name(), Size: info.Size(), CreatedAt: info.ModTime()})
+	}
+	sort.Slice(
+		infos, func(i, j int) bool {
+			return infos[i].CreatedAt.After(infos[j].CreatedAt)
+		},
+	)
+	return infos, nil
+}
+
+func (s *LocalStore) Remove(_ context.Context, name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path returns the file of the archive, rejecting names that would leave the directory.
+func (s *LocalStore) path(name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, Extension) {
+		return "", ErrInvalidName
+	}
+	return filepath.Join(s.Dir, name), nil
+}
+
+// Writer writes records to an archive.
+type Writer struct {
+	w       File
+	gz      *gzip.Writer
+	encoder *json.Encoder
+	Count   int64 // Number of records written
+}
+
+func NewWriter(w File) *Writer {
+	gz := gzip.NewWriter(w)
+	return &Writer{w: w, gz: gz, encoder: json.NewEncoder(gz)}
+}
+
+func (w *Writer) Write(record any) error {
+	if err := w.encoder.Encode(record); err != nil {
+		return err
+	}
+	w.Count++
+	return nil
+}
+
+// Close flushes the archive and closes the underlying writer.
+func (w *Writer) Close() error {
+	if err := w.gz.Close(); err != nil {
+		_ = w.w.Close()
+		return err
+	}
+	return w.w.Close()
+}
+
+// Abort discards the archive.
+func (w *Writer) Abort() error {
+	_ = w.gz.Close()
+	return w.w.Abort()
+}
+
+// Reader reads records from an archive.
+type Reader struct {
+	r       io.ReadCloser
+	gz      *gzip.Reader
+	decoder *json.Decoder
+}
+
+func NewReader(r io.ReadCloser) (*Reader, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		_ = r.Close()
+		return nil, err
+	}
+	return &Reader{r: r, gz: gz, decoder: json.NewDecoder(gz)}, nil
+}
+
+// Read decodes the next record into record, returning io.EOF after the last one.
+func (r *Reader) Read(record any) error {
+	return r.decoder.Decode(record)
+}
+
+func (r *Reader) Close() error {
+	_ = r.gz.Close()
+	return r.r.Close()
+}
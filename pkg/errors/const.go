@@ -11,15 +11,19 @@ const ( // Business error code
 	CodeTokenMissed    = 1005
 	CodePermissionDeny = 1006
 
-	CodeInvalidRequest = 2001
-	CodeIdempotency    = 2002
+	CodeInvalidRequest      = 2001
+	CodeIdempotency         = 2002
+	CodePreconditionFailed  = 2003
+	CodeIdempotencyConflict = 2004
+	CodeIdempotencyMismatch = 2005
 
 	CodeNotFound        = 3001
 	CodeOperationFailed = 3002
 	CodeDuplicateKey    = 3003
 
-	CodeServerBusy   = 4001
-	CodeServiceError = 4002
+	CodeServerBusy      = 4001
+	CodeServiceError    = 4002
+	CodeTooManyRequests = 4003
 
 	CodeUnknownError = 9999
 )
@@ -36,6 +36,22 @@ func Idempotency(err error) *AppError {
 	return NewAppError(CodeIdempotency, fiber.StatusBadRequest, "Idempotency check failed", err)
 }
 
+// IdempotencyConflict is returned for a request reusing the idempotency key of a request still being processed.
+func IdempotencyConflict(err error) *AppError {
+	return NewAppError(CodeIdempotencyConflict, fiber.StatusConflict, "Idempotency conflict", err)
+}
+
+// IdempotencyMismatch is returned for a request reusing the idempotency key of a different request.
+func IdempotencyMismatch(err error) *AppError {
+	return NewAppError(
+		CodeIdempotencyMismatch, fiber.StatusUnprocessableEntity, "Idempotency key reused with a different request", err,
+	)
+}
+
+func PreconditionFailed(err error) *AppError {
+	return NewAppError(CodePreconditionFailed, fiber.StatusPreconditionFailed, "Precondition failed", err)
+}
+
 func NotFound(err error) *AppError {
 	return NewAppError(CodeNotFound, fiber.StatusNotFound, "Not found", err)
 }
@@ -55,3 +71,7 @@ func ServerBusy(err error) *AppError {
 func ServiceError(err error) *AppError {
 	return NewAppError(CodeServiceError, fiber.StatusInternalServerError, "Service error", err)
 }
+
+func TooManyRequests(err error) *AppError {
+	return NewAppError(CodeTooManyRequests, fiber.StatusTooManyRequests, "Too many requests", err)
+}
@@ -0,0 +1,383 @@
+// Package geoip looks up the location of IP addresses in a MaxMind DB file, such as GeoLite2-City. Only the parts of
+// the format needed to read City and Country databases are implemented.
+package geoip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"os"
+)
+
+// metadataMarker precedes the metadata section at the end of the file.
+var metadataMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// dataSectionSeparator is the size of the zero bytes between the search tree and the data section.
+const dataSectionSeparator = 16
+
+// maxDecodeDepth bounds how deep maps, arrays and pointers nest, so that a corrupt or crafted file pointing back at
+// itself fails to decode instead of exhausting the stack. Location records nest a few levels deep.
+const maxDecodeDepth = 32
+
+var ErrInvalidDatabase = errors.New("invalid MaxMind DB file")
+
+// Location is the location of an IP address. Fields missing from the database are left empty.
+type Location struct {
+	CountryCode string   // ISO 3166-1 alpha-2 country code
+	Country     string   // English country name
+	City        string   // English city name
+	Latitude    *float64 // Approximate latitude
+	Longitude   *float64 // Approximate longitude
+}
+
+// Reader reads a MaxMind DB file loaded in memory. It is safe for concurrent use.
+type Reader struct {
+	buf        []byte
+	data       []byte
+	nodeCount  uint
+	recordSize uint
+	ipVersion  uint
+	ipv4Start  uint
+}
+
+// Open loads the database file at path.
+func Open(path string) (*Reader, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return New(buf)
+}
+
+// New reads a database from its content.
+func New(buf []byte) (*Reader, error) {
+	i := bytes.LastIndex(buf, metadataMarker)
+	if i < 0 {
+		return nil, fmt.Errorf("%w: metadata not found", ErrInvalidDatabase)
+	}
+	metadataStart := i + len(metadataMarker)
+	metadata, _, err := (&decoder{data: buf[metadataStart:]}).decode(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDatabase, err)
+	}
+	fields, ok := metadata.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: metadata is not a map", ErrInvalidDatabase)
+	}
+	r := &Reader{
+		buf:        buf,
+		nodeCount:  uint(toUint(fields["node_count"])),
+		recordSize: uint(toUint(fields["record_size"])),
+		ipVersion:  uint(toUint(fields["ip_version"])),
+	}
+	if r.recordSize != 24 && r.recordSize != 28 && r.recordSize != 32 {
+		return nil, fmt.Errorf("%w: unsupported record size %d", ErrInvalidDatabase, r.recordSize)
+	}
+	treeSize := r.nodeCount * r.recordSize / 4
+	if treeSize+dataSectionSeparator > uint(i) {
+		return nil, fmt.Errorf("%w: search tree exceeds the file", ErrInvalidDatabase)
+	}
+	r.data = buf[treeSize+dataSectionSeparator : i]
+
+	// IPv4 addresses are stored under ::/96 in IPv6 databases.
+	if r.ipVersion == 6 {
+		node := uint(0)
+		for j := 0; j < 96 && node < r.nodeCount; j++ {
+			if node, err = r.record(node, 0); err != nil {
+				return nil, err
+			}
+		}
+		r.ipv4Start = node
+	}
+	return r, nil
+}
+
+// Lookup returns the location of ip, or nil when the database does not know it.
+func (r *Reader) Lookup(ip net.IP) (*Location, error) {
+	record, err := r.lookup(ip)
+	if err != nil || record == nil {
+		return nil, err
+	}
+	fields, _ := record.(map[string]any)
+	location := &Location{
+		CountryCode: toString(lookupPath(fields, "country", "iso_code")),
+		Country:     toString(lookupPath(fields, "country", "names", "en")),
+		City:        toString(lookupPath(fields, "city", "names", "en")),
+	}
+	latitude, okLatitude := lookupPath(fields, "location", "latitude").(float64)
+	longitude, okLongitude := lookupPath(fields, "location", "longitude").(float64)
+	if okLatitude && okLongitude {
+		location.Latitude, location.Longitude = &latitude, &longitude
+	}
+	return location, nil
+}
+
+// lookup walks the search tree along the bits of ip and decodes the record it ends at.
+func (r *Reader) lookup(ip net.IP) (any, error) {
+	node := uint(0)
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+		node = r.ipv4Start
+	} else if r.ipVersion == 4 {
+		return nil, nil // IPv6 addresses cannot be in an IPv4 database
+	} else {
+		ip = ip.To16()
+	}
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address")
+	}
+	var err error
+	for i := 0; i < len(ip)*8 && node < r.nodeCount; i++ {
+		bit := uint(ip[i/8]>>(7-uint(i%8))) & 1
+		if node, err = r.record(node, bit); err != nil {
+			return nil, err
+		}
+	}
+	if node == r.nodeCount {
+		return nil, nil
+	}
+	if node < r.nodeCount {
+		return nil, fmt.Errorf("%w: search tree is deeper than the address", ErrInvalidDatabase)
+	}
+	offset := node - r.nodeCount - dataSectionSeparator
+	value, _, err := (&decoder{data: r.data}).decode(offset, 0)
+	return value, err
+}
+
+// record returns the left (bit 0) or right (bit 1) record of a search tree node.
+func (r *Reader) record(node, bit uint) (uint, error) {
+	size := r.recordSize / 4 // Bytes per node
+	start := node * size
+	if start+size > uint(len(r.buf)) {
+		return 0, fmt.Errorf("%w: node %d out of range", ErrInvalidDatabase, node)
+	}
+	b := r.buf[start : start+size]
+	switch r.recordSize {
+	case 24:
+		if bit == 0 {
+			return uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2]), nil
+		}
+		return uint(b[3])<<16 | uint(b[4])<<8 | uint(b[5]), nil
+	case 28:
+		if bit == 0 {
+			return uint(b[3]&0xF0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2]), nil
+		}
+		return uint(b[3]&0x0F)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6]), nil
+	default:
+		if bit == 0 {
+			return uint(binary.BigEndian.Uint32(b[:4])), nil
+		}
+		return uint(binary.BigEndian.Uint32(b[4:])), nil
+	}
+}
+
+// Data section field types
+const (
+	typeExtended = iota
+	typePointer
+	typeString
+	typeDouble
+	typeBytes
+	typeUint16
+	typeUint32
+	typeMap
+	typeInt32
+	typeUint64
+	typeUint128
+	typeArray
+	typeContainer
+	typeEndMarker
+	typeBool
+	typeFloat
+)
+
+// decoder decodes the data section into maps, slices, strings, bools, float64, int64 and uint64 values.
+type decoder struct {
+	data []byte
+}
+
+// decode decodes the value at offset, nested depth levels deep, and returns it with the offset following it.
+func (d *decoder) decode(offset uint, depth int) (any, uint, error) {
+	if offset >= uint(len(d.data)) {
+		return nil, 0, fmt.Errorf("offset %d out of range", offset)
+	}
+	if depth > maxDecodeDepth {
+		return nil, 0, fmt.Errorf("value at %d nests deeper than %d levels", offset, maxDecodeDepth)
+	}
+	ctrl := d.data[offset]
+	offset++
+	typeNum := uint(ctrl >> 5)
+	if typeNum == typePointer {
+		pointer, next, err := d.pointer(ctrl, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		// A pointer may not point to another pointer
+		if pointer < uint(len(d.data)) && d.data[pointer]>>5 == typePointer {
+			return nil, 0, fmt.Errorf("pointer at %d points to a pointer", offset-1)
+		}
+		value, _, err := d.decode(pointer, depth+1)
+		return value, next, err
+	}
+	if typeNum == typeExtended {
+		if offset >= uint(len(d.data)) {
+			return nil, 0, fmt.Errorf("offset %d out of range", offset)
+		}
+		typeNum = 7 + uint(d.data[offset])
+		offset++
+	}
+	size, offset, err := d.size(ctrl, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	// Every key and value takes a byte at least, so a size the remaining bytes cannot hold is corrupt; checking it
+	// first keeps a crafted size from allocating a huge map or slice.
+	remaining := uint(len(d.data)) - offset
+	switch typeNum {
+	case typeMap:
+		if size > remaining/2 {
+			return nil, 0, fmt.Errorf("map at %d exceeds the data section", offset)
+		}
+		fields := make(map[string]any, size)
+		for i := uint(0); i < size; i++ {
+			key, next, err := d.decode(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			value, next, err := d.decode(next, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			fields[toString(key)] = value
+			offset = next
+		}
+		return fields, offset, nil
+	case typeArray:
+		if size > remaining {
+			return nil, 0, fmt.Errorf("array at %d exceeds the data section", offset)
+		}
+		values := make([]any, 0, size)
+		for i := uint(0); i < size; i++ {
+			value, next, err := d.decode(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			values = append(values, value)
+			offset = next
+		}
+		return values, offset, nil
+	case typeBool:
+		return size != 0, offset, nil
+	case typeContainer, typeEndMarker:
+		return nil, offset, nil
+	}
+
+	if offset+size > uint(len(d.data)) {
+		return nil, 0, fmt.Errorf("value at %d exceeds the data section", offset)
+	}
+	b := d.data[offset : offset+size]
+	offset += size
+	switch typeNum {
+	case typeString:
+		return string(b), offset, nil
+	case typeBytes:
+		return append([]byte(nil), b...), offset, nil
+	case typeDouble:
+		if size != 8 {
+			return nil, 0, fmt.Errorf("invalid double size %d", size)
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), offset, nil
+	case typeFloat:
+		if size != 4 {
+			return nil, 0, fmt.Errorf("invalid float size %d", size)
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), offset, nil
+	case typeInt32:
+		var v int32
+		for _, c := range b {
+			v = v<<8 | int32(c)
+		}
+		return int64(v), offset, nil
+	case typeUint16, typeUint32, typeUint64:
+		var v uint64
+		for _, c := range b {
+			v = v<<8 | uint64(c)
+		}
+		return v, offset, nil
+	case typeUint128:
+		return append([]byte(nil), b...), offset, nil // Not needed for locations, kept as raw bytes
+	}
+	return nil, 0, fmt.Errorf("unknown type %d", typeNum)
+}
+
+// size returns the payload size encoded by a control byte and the offset of the payload.
+func (d *decoder) size(ctrl byte, offset uint) (uint, uint, error) {
+	size := uint(ctrl & 0x1F)
+	if size < 29 {
+		return size, offset, nil
+	}
+	n := size - 28 // Number of bytes the size is extended with
+	if offset+n > uint(len(d.data)) {
+		return 0, 0, fmt.Errorf("size at %d exceeds the data section", offset)
+	}
+	var extra uint
+	for _, c := range d.data[offset : offset+n] {
+		extra = extra<<8 | uint(c)
+	}
+	switch size {
+	case 29:
+		size = 29 + extra
+	case 30:
+		size = 285 + extra
+	default:
+		size = 65821 + extra
+	}
+	return size, offset + n, nil
+}
+
+// pointer returns the offset a pointer points to and the offset following the pointer.
+func (d *decoder) pointer(ctrl byte, offset uint) (uint, uint, error) {
+	n := uint((ctrl>>3)&0x3) + 1
+	if offset+n > uint(len(d.data)) {
+		return 0, 0, fmt.Errorf("pointer at %d exceeds the data section", offset)
+	}
+	var pointer uint
+	if n < 4 {
+		pointer = uint(ctrl & 0x7)
+	}
+	for _, c := range d.data[offset : offset+n] {
+		pointer = pointer<<8 | uint(c)
+	}
+	switch n {
+	case 2:
+		pointer += 2048
+	case 3:
+		pointer += 526336
+	}
+	return pointer, offset + n, nil
+}
+
+func lookupPath(fields map[string]any, path ...string) any {
+	var value any = fields
+	for _, key := range path {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = m[key]
+	}
+	return value
+}
+
+func toString(value any) string {
+	s, _ := value.(string)
+	return s
+}
+
+func toUint(value any) uint64 {
+	v, _ := value.(uint64)
+	return v
+}
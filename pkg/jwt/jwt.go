@@ -6,6 +6,7 @@ import (
 	"crypto/rand"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang-jwt/jwt"
@@ -21,7 +22,14 @@ var (
 	once        sync.Once
 )
 
+// Jwt signs and verifies tokens. Its key and durations can be changed while it is in use: each token is signed or
+// verified with a single state.
 type Jwt struct {
+	state atomic.Pointer[state]
+	mu    sync.Mutex // Serializes the changes of the state
+}
+
+type state struct {
 	privateKey      *ecdsa.PrivateKey
 	publicKey       *ecdsa.PublicKey
 	tokenDuration   time.Duration
@@ -36,14 +44,16 @@ func New(
 	var err error
 	once.Do(
 		func() {
-			j := &Jwt{
+			s := &state{
 				privateKey:      privateKey,
 				publicKey:       &privateKey.PublicKey,
 				tokenDuration:   tokenDuration,
 				refreshDuration: refreshDuration,
 				refreshBuffer:   refreshBuffer,
 			}
-			if err = j.checkJWT(); err == nil {
+			if err = s.check(); err == nil {
+				j := new(Jwt)
+				j.state.Store(s)
 				jwtInstance = j
 			}
 		},
@@ -51,21 +61,16 @@ func New(
 	return jwtInstance, err
 }
 
-func Update(privateKey *ecdsa.PrivateKey, tokenDuration, refreshDuration, refreshBuffer time.Duration) error {
-	var err error
-	if privateKey == nil {
-		privateKey = jwtInstance.privateKey
-	}
-	jwtInstance = &Jwt{
-		privateKey:      privateKey,
-		publicKey:       &privateKey.PublicKey,
-		tokenDuration:   tokenDuration,
-		refreshDuration: refreshDuration,
-		refreshBuffer:   refreshBuffer,
-	}
-	if err = jwtInstance.checkJWT(); err != nil {
+// SetDurations changes the durations of the tokens signed from now on, unless they are invalid.
+func (j *Jwt) SetDurations(tokenDuration, refreshDuration, refreshBuffer time.Duration) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	s := *j.state.Load()
+	s.tokenDuration, s.refreshDuration, s.refreshBuffer = tokenDuration, refreshDuration, refreshBuffer
+	if err := s.check(); err != nil {
 		return err
 	}
+	j.state.Store(&s)
 	return nil
 }
 
@@ -75,25 +80,29 @@ func (j *Jwt) UpdateKey() error {
 	if err != nil {
 		return err
 	}
-	j.privateKey = privateKey
-	j.publicKey = &privateKey.PublicKey
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	s := *j.state.Load()
+	s.privateKey = privateKey
+	s.publicKey = &privateKey.PublicKey
+	j.state.Store(&s)
 	return nil
 }
 
-func (j *Jwt) checkJWT() error {
-	if j.privateKey == nil {
+func (s *state) check() error {
+	if s.privateKey == nil {
 		return fmt.Errorf("private key is nil")
 	}
-	if j.tokenDuration == 0 {
+	if s.tokenDuration == 0 {
 		return fmt.Errorf("token duration is 0")
 	}
-	if j.refreshDuration == 0 {
+	if s.refreshDuration == 0 {
 		return fmt.Errorf("refresh duration is 0")
 	}
-	if j.refreshBuffer == 0 {
+	if s.refreshBuffer == 0 {
 		return fmt.Errorf("refresh buffer is 0")
 	}
-	if j.tokenDuration > j.refreshDuration || j.refreshDuration < j.refreshBuffer || j.tokenDuration < j.refreshBuffer {
+	if s.tokenDuration > s.refreshDuration || s.refreshDuration < s.refreshBuffer || s.tokenDuration < s.refreshBuffer {
 		return fmt.Errorf("invalid token, refresh or buffer duration")
 	}
 
@@ -104,17 +113,18 @@ func (j *Jwt) GenerateAccessToken(subject string) (string, error) {
 	if subject == "" {
 		return "", fmt.Errorf("subject is empty") // TODO: CHANGE ERROR TYPE
 	}
+	s := j.state.Load()
 	token := jwt.NewWithClaims(
 		jwt.SigningMethodES256, &jwt.StandardClaims{
 			Subject:   subject,
 			Audience:  AccessAudience,
 			IssuedAt:  time.Now().Unix(),
-			ExpiresAt: time.Now().Add(j.tokenDuration).Unix(),
+			ExpiresAt: time.Now().Add(s.tokenDuration).Unix(),
 			NotBefore: time.Now().Unix(),
 		},
 	)
 
-	tokenString, err := token.SignedString(j.privateKey)
+	tokenString, err := token.SignedString(s.privateKey)
 	if err != nil {
 		return "", err
 	}
@@ -126,17 +136,18 @@ func (j *Jwt) GenerateRefreshToken(subject string) (string, error) {
 	if subject == "" {
 		return "", fmt.Errorf("subject is empty") // TODO: CHANGE ERROR TYPE
 	}
+	s := j.state.Load()
 	token := jwt.NewWithClaims(
 		jwt.SigningMethodES256, &jwt.StandardClaims{
 			Subject:   subject,
 			Audience:  RefreshAudience,
 			IssuedAt:  time.Now().Unix(),
-			ExpiresAt: time.Now().Add(j.refreshDuration).Unix(),
+			ExpiresAt: time.Now().Add(s.refreshDuration).Unix(),
 			NotBefore: time.Now().Unix(),
 		},
 	)
 
-	tokenString, err := token.SignedString(j.privateKey)
+	tokenString, err := token.SignedString(s.privateKey)
 	if err != nil {
 		return "", err
 	}
@@ -151,7 +162,7 @@ func (j *Jwt) RefreshToken(token string) (string, error) {
 	}
 
 	// Check if token is expired
-	if time.Unix(int64(claims["exp"].(float64)), 0).Sub(time.Now()) > j.refreshBuffer {
+	if time.Unix(int64(claims["exp"].(float64)), 0).Sub(time.Now()) > j.state.Load().refreshBuffer {
 		return "", fmt.Errorf(
 			"token is not expired yet: %v", time.Unix(int64(claims["exp"].(float64)), 0).Sub(time.Now()),
 		)
@@ -168,10 +179,11 @@ func (j *Jwt) RefreshToken(token string) (string, error) {
 
 func (j *Jwt) ExtractClaims(token string) (map[string]interface{}, error) {
 	claims := jwt.MapClaims{}
+	publicKey := j.state.Load().publicKey
 
 	t, err := jwt.ParseWithClaims(
 		token, claims, func(token *jwt.Token) (interface{}, error) {
-			return j.publicKey, nil
+			return publicKey, nil
 		},
 	)
 	if err != nil || !t.Valid {
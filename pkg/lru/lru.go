@@ -0,0 +1,99 @@
+// Package lru implements a bounded, concurrency-safe in-memory cache evicting the least recently used entries, whose
+// entries also expire after a TTL.
+package lru
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+// Cache holds up to size entries. The zero value is not usable, create caches with New.
+type Cache[K comparable, V any] struct {
+	mutex   sync.Mutex
+	size    int
+	order   *list.List // Most recently used first
+	entries map[K]*list.Element
+}
+
+// New returns a cache holding up to size entries.
+func New[K comparable, V any](size int) *Cache[K, V] {
+	return &Cache[K, V]{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[K]*list.Element),
+	}
+}
+
+// Get returns the value of key, if it is cached and has not expired.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	var zero V
+	element, ok := c.entries[key]
+	if !ok {
+		return zero, false
+	}
+	e := element.Value.(*entry[K, V])
+	if time.Now().After(e.expiresAt) {
+		c.remove(element)
+		return zero, false
+	}
+	c.order.MoveToFront(element)
+	return e.value, true
+}
+
+// Set caches value under key for ttl, evicting the least recently used entry when the cache is full.
+func (c *Cache[K, V]) Set(key K, value V, ttl time.Duration) {
+	if c.size <= 0 || ttl <= 0 {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	expiresAt := time.Now().Add(ttl)
+	if element, ok := c.entries[key]; ok {
+		e := element.Value.(*entry[K, V])
+		e.value, e.expiresAt = value, expiresAt
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&entry[K, V]{key: key, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+// Remove drops key from the cache.
+func (c *Cache[K, V]) Remove(key K) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+}
+
+// Purge drops every entry.
+func (c *Cache[K, V]) Purge() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.order.Init()
+	c.entries = make(map[K]*list.Element)
+}
+
+// Len returns the number of cached entries, including those which expired but were not dropped yet.
+func (c *Cache[K, V]) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.order.Len()
+}
+
+func (c *Cache[K, V]) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*entry[K, V]).key)
+}
@@ -0,0 +1,125 @@
+package markdown
+
+import (
+	"bytes"
+	"regexp"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+type Config struct {
+	HighlightStyle string
+	TOCMaxDepth    int
+}
+
+// Heading is an entry of the table of contents, nested under the closest preceding heading of a lower level.
+type Heading struct {
+	Level    int        `json:"level"`
+	ID       string     `json:"id"`
+	Title    string     `json:"title"`
+	Children []*Heading `json:"children"`
+}
+
+// Result is a rendered Markdown document.
+type Result struct {
+	HTML string     `json:"html"`
+	TOC  []*Heading `json:"toc"`
+}
+
+// Markdown renders GitHub Flavored Markdown to sanitized HTML. Raw HTML in the source is dropped by the renderer and
+// the output is passed through an allowlist sanitizer, so the result is safe to embed as is.
+type Markdown struct {
+	MarkdownConfig *Config
+	md             goldmark.Markdown
+	policy         *bluemonday.Policy
+}
+
+func New(highlightStyle string, tocMaxDepth int) *Markdown {
+	m := &Markdown{
+		MarkdownConfig: &Config{
+			HighlightStyle: highlightStyle,
+			TOCMaxDepth:    tocMaxDepth,
+		},
+	}
+	m.init()
+	return m
+}
+
+func (m *Markdown) init() {
+	m.md = goldmark.New(
+		goldmark.WithExtensions(
+			extension.NewTable(extension.WithTableCellAlignMethod(extension.TableCellAlignAttribute)),
+			extension.Strikethrough,
+			extension.Linkify,
+			extension.TaskList,
+			highlighting.NewHighlighting(
+				highlighting.WithStyle(m.MarkdownConfig.HighlightStyle),
+				// Inline styles keep highlighted code self-contained, clients need no chroma stylesheet.
+				highlighting.WithFormatOptions(chromahtml.WithClasses(false)),
+			),
+		),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	)
+
+	policy := bluemonday.UGCPolicy()
+	policy.AllowStyles("color", "background-color", "font-weight", "font-style", "text-decoration").
+		OnElements("pre", "span")
+	policy.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	policy.AllowAttrs("checked", "disabled").OnElements("input")
+	m.policy = policy
+}
+
+// Render renders source to sanitized HTML together with its table of contents.
+func (m *Markdown) Render(source string) (*Result, error) {
+	src := []byte(source)
+	doc := m.md.Parser().Parse(text.NewReader(src))
+
+	var buf bytes.Buffer
+	if err := m.md.Renderer().Render(&buf, src, doc); err != nil {
+		return nil, err
+	}
+	return &Result{
+		HTML: m.policy.Sanitize(buf.String()),
+		TOC:  m.toc(doc, src),
+	}, nil
+}
+
+// toc collects the headings up to TOCMaxDepth and nests each under the closest preceding heading of a lower level.
+func (m *Markdown) toc(doc ast.Node, src []byte) []*Heading {
+	toc := make([]*Heading, 0)
+	var stack []*Heading
+	for node := doc.FirstChild(); node != nil; node = node.NextSibling() {
+		heading, ok := node.(*ast.Heading)
+		if !ok || heading.Level > m.MarkdownConfig.TOCMaxDepth {
+			continue
+		}
+		entry := &Heading{
+			Level:    heading.Level,
+			Title:    string(heading.Text(src)),
+			Children: make([]*Heading, 0),
+		}
+		if id, ok := heading.AttributeString("id"); ok {
+			if idBytes, ok := id.([]byte); ok {
+				entry.ID = string(idBytes)
+			}
+		}
+		for len(stack) > 0 && stack[len(stack)-1].Level >= entry.Level {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			toc = append(toc, entry)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, entry)
+		}
+		stack = append(stack, entry)
+	}
+	return toc
+}
@@ -3,15 +3,25 @@ package mongo
 import (
 	"context"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/qiniu/qmgo"
 )
 
+const (
+	// minDrainTimeout is the least time a replaced client is kept open for
+	minDrainTimeout = 10 * time.Second
+	// maxDrainTimeout is the time a replaced client is kept open for when operations have no socket timeout
+	maxDrainTimeout = time.Minute
+)
+
 type Mongo struct {
-	MongoClient  *qmgo.Client
+	mongoClient  atomic.Pointer[qmgo.Client]
 	DatabaseName string
 	qmgoConfig   *qmgo.Config
 	pingTimeout  int64
+	mu           sync.Mutex // Guards qmgoConfig and pingTimeout
 }
 
 var (
@@ -37,30 +47,61 @@ func New(ctx context.Context, config *qmgo.Config, PingTimeout int64, databaseNa
 	return mongoInstance, err
 }
 
-func Update(ctx context.Context, config *qmgo.Config, PingTimeout int64, databaseName string) error {
-	if err := mongoInstance.Close(ctx); err != nil {
+// Reconnect connects to MongoDB with the config, and replaces the client once the new one answers. The operations in
+// flight finish on the replaced client, which is closed when they have timed out. The client is left alone when the
+// new one cannot connect. The database stays the same.
+func (m *Mongo) Reconnect(ctx context.Context, config *qmgo.Config, pingTimeout int64) error {
+	client, err := connect(ctx, config, pingTimeout)
+	if err != nil {
 		return err
 	}
-	*mongoInstance = Mongo{
-		qmgoConfig:   config,
-		pingTimeout:  PingTimeout,
-		DatabaseName: databaseName,
+	m.mu.Lock()
+	replaced := m.mongoClient.Swap(client)
+	drain := drainTimeout(m.qmgoConfig)
+	m.qmgoConfig, m.pingTimeout = config, pingTimeout
+	m.mu.Unlock()
+	if replaced != nil {
+		time.AfterFunc(drain, func() { _ = replaced.Close(context.Background()) })
+	}
+	return nil
+}
+
+// drainTimeout is how long the operations in flight on a client may still take.
+func drainTimeout(config *qmgo.Config) time.Duration {
+	if config.SocketTimeoutMS == nil || *config.SocketTimeoutMS <= 0 {
+		return maxDrainTimeout
 	}
-	return mongoInstance.init(ctx)
+	return max(time.Duration(*config.SocketTimeoutMS)*time.Millisecond, minDrainTimeout)
 }
 
 func (m *Mongo) init(ctx context.Context) error {
-	client, err := qmgo.NewClient(ctx, m.qmgoConfig)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	client, err := connect(ctx, m.qmgoConfig, m.pingTimeout)
 	if err != nil {
 		return err
 	}
-	if err = client.Ping(m.pingTimeout); err != nil {
-		return err
-	}
-	m.MongoClient = client
+	m.mongoClient.Store(client)
 	return nil
 }
 
+func connect(ctx context.Context, config *qmgo.Config, pingTimeout int64) (*qmgo.Client, error) {
+	client, err := qmgo.NewClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	if err = client.Ping(pingTimeout); err != nil {
+		_ = client.Close(ctx)
+		return nil, err
+	}
+	return client, nil
+}
+
+// Client returns the current client, which Reconnect may replace at any time. Callers should not keep it.
+func (m *Mongo) Client() *qmgo.Client {
+	return m.mongoClient.Load()
+}
+
 func (m *Mongo) Close(ctx context.Context) error {
-	return m.MongoClient.Close(ctx)
+	return m.mongoClient.Load().Close(ctx)
 }
@@ -1,6 +1,7 @@
 package prometheus
 
 import (
+	"errors"
 	"strconv"
 	"time"
 
@@ -18,6 +19,13 @@ type Prometheus struct {
 	PrometheusConfig *Config
 	reqCount         *prometheus.CounterVec
 	reqDuration      *prometheus.HistogramVec
+	queueBacklog     *prometheus.GaugeVec
+	queuePending     *prometheus.GaugeVec
+	queueDeadLetters *prometheus.GaugeVec
+	queueLag         *prometheus.GaugeVec
+	cacheRequests    *prometheus.CounterVec
+	configReloads    *prometheus.CounterVec
+	configReloadTime *prometheus.GaugeVec
 }
 
 func New(namespace string, subsystem string, metricPath string) *Prometheus {
@@ -51,6 +59,82 @@ func (p *Prometheus) init() {
 			Help:      "Duration of HTTP requests",
 		}, []string{"method", "handler"},
 	)
+
+	queue := []string{"queue"}
+	p.queueBacklog = p.registerGauge("log_queue_backlog", "Number of queued logs not stored yet", queue)
+	p.queuePending = p.registerGauge("log_queue_pending", "Number of queued logs read but not stored yet", queue)
+	p.queueDeadLetters = p.registerGauge(
+		"log_queue_dead_letters", "Number of queued logs that could not be stored", queue,
+	)
+	p.queueLag = p.registerGauge("log_queue_lag_seconds", "Age of the oldest queued log not stored yet", queue)
+	p.cacheRequests = p.registerCounter(
+		"cache_requests_total", "Number of cache-aside reads by result", []string{"entity", "result"},
+	)
+	p.configReloads = p.registerCounter(
+		"config_reloads_total", "Number of config reloads by result", []string{"result"},
+	)
+	p.configReloadTime = p.registerGauge(
+		"config_last_reload_timestamp_seconds", "Time of the last config reload by result", []string{"result"},
+	)
+}
+
+// registerGauge creates a gauge and registers it with the default registry served on the metric path, reusing the
+// gauge registered by an earlier instance.
+func (p *Prometheus) registerGauge(name, help string, labels []string) *prometheus.GaugeVec {
+	gauge := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      name,
+			Namespace: p.PrometheusConfig.Namespace,
+			Subsystem: p.PrometheusConfig.Subsystem,
+			Help:      help,
+		}, labels,
+	)
+	if err := prometheus.Register(gauge); err != nil {
+		var registered prometheus.AlreadyRegisteredError
+		if errors.As(err, &registered) {
+			return registered.ExistingCollector.(*prometheus.GaugeVec)
+		}
+	}
+	return gauge
+}
+
+// registerCounter creates a counter and registers it with the default registry served on the metric path, reusing the
+// counter registered by an earlier instance.
+func (p *Prometheus) registerCounter(name, help string, labels []string) *prometheus.CounterVec {
+	counter := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:      name,
+			Namespace: p.PrometheusConfig.Namespace,
+			Subsystem: p.PrometheusConfig.Subsystem,
+			Help:      help,
+		}, labels,
+	)
+	if err := prometheus.Register(counter); err != nil {
+		var registered prometheus.AlreadyRegisteredError
+		if errors.As(err, &registered) {
+			return registered.ExistingCollector.(*prometheus.CounterVec)
+		}
+	}
+	return counter
+}
+
+// ObserveCache counts a cache-aside read of the entity, e.g. a hit or a miss.
+func (p *Prometheus) ObserveCache(entity, result string) {
+	p.cacheRequests.WithLabelValues(entity, result).Inc()
+}
+
+// ObserveLogQueue records the state of a log queue.
+func (p *Prometheus) ObserveLogQueue(queue string, backlog, pending, deadLetters int64, lag time.Duration) {
+	p.queueBacklog.WithLabelValues(queue).Set(float64(backlog))
+	p.queuePending.WithLabelValues(queue).Set(float64(pending))
+	p.queueDeadLetters.WithLabelValues(queue).Set(float64(deadLetters))
+	p.queueLag.WithLabelValues(queue).Set(lag.Seconds())
+}
+
+// ObserveConfigReload counts a config reload, e.g. applied or rolled back.
+func (p *Prometheus) ObserveConfigReload(result string) {
+	p.configReloads.WithLabelValues(result).Inc()
+	p.configReloadTime.WithLabelValues(result).SetToCurrentTime()
 }
 
 func (p *Prometheus) PrometheusFiberHandler() fiber.Handler {
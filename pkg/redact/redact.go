@@ -0,0 +1,152 @@
+// Package redact masks secrets in JSON documents, form values and headers before they are logged. Fields are matched
+// case-insensitively, either by name at any depth or by a dot-separated path from the root of the document.
+package redact
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// Mask replaces the redacted values.
+const Mask = "[REDACTED]"
+
+// Wildcard matches any key or array index in a path.
+const Wildcard = "*"
+
+// Redactor masks the values of the configured fields and headers.
+type Redactor struct {
+	names   map[string]struct{} // Fields matched at any depth
+	paths   [][]string          // Fields matched from the root
+	keys    [][]byte            // Quoted last segments of names and paths, to skip documents without any of them
+	headers map[string]struct{}
+}
+
+// New returns a Redactor masking fields, each a name such as "password" or a path such as "data.*.token", and headers.
+func New(fields, headers []string) *Redactor {
+	r := &Redactor{names: make(map[string]struct{}), headers: make(map[string]struct{})}
+	for _, field := range fields {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field == "" {
+			continue
+		}
+		segments := strings.Split(field, ".")
+		if len(segments) == 1 {
+			r.names[field] = struct{}{}
+		} else {
+			r.paths = append(r.paths, segments)
+		}
+		if last := segments[len(segments)-1]; last != Wildcard {
+			r.keys = append(r.keys, []byte(`"`+last+`"`))
+		} else {
+			r.keys = append(r.keys, nil) // Any key may match
+		}
+	}
+	for _, header := range headers {
+		if header = strings.TrimSpace(header); header != "" {
+			r.headers[strings.ToLower(header)] = struct{}{}
+		}
+	}
+	return r
+}
+
+// Header reports whether the header is redacted.
+func (r *Redactor) Header(name string) bool {
+	_, ok := r.headers[strings.ToLower(name)]
+	return ok
+}
+
+// Field reports whether the top-level field, e.g. a form value or query parameter, is redacted.
+func (r *Redactor) Field(name string) bool {
+	return r.redacted([]string{strings.ToLower(name)})
+}
+
+// JSON returns body with the redacted fields masked. Bodies which are not JSON documents are returned unchanged.
+// Documents are re-encoded with their keys sorted when any field is masked.
+func (r *Redactor) JSON(body []byte) []byte {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') || !r.mayContain(trimmed) {
+		return body
+	}
+	decoder := json.NewDecoder(bytes.NewReader(trimmed))
+	decoder.UseNumber() // Keep numbers as they were written
+	var document any
+	if err := decoder.Decode(&document); err != nil {
+		return body
+	}
+	if !r.walk(document, nil) {
+		return body
+	}
+	redacted, err := json.Marshal(document)
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+// mayContain reports whether the body may contain a redacted field, without decoding it.
+func (r *Redactor) mayContain(body []byte) bool {
+	if len(r.keys) == 0 {
+		return false
+	}
+	lower := bytes.ToLower(body)
+	for _, key := range r.keys {
+		if key == nil || bytes.Contains(lower, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// walk masks the redacted fields under value, found at path, and reports whether any was masked.
+func (r *Redactor) walk(value any, path []string) bool {
+	masked := false
+	switch value := value.(type) {
+	case map[string]any:
+		for key, child := range value {
+			childPath := append(append(make([]string, 0, len(path)+1), path...), strings.ToLower(key))
+			if r.redacted(childPath) {
+				value[key] = Mask
+				masked = true
+			} else if r.walk(child, childPath) {
+				masked = true
+			}
+		}
+	case []any:
+		for i, child := range value {
+			// Array elements are only matched by the wildcard
+			childPath := append(append(make([]string, 0, len(path)+1), path...), "")
+			if r.redacted(childPath) {
+				value[i] = Mask
+				masked = true
+			} else if r.walk(child, childPath) {
+				masked = true
+			}
+		}
+	}
+	return masked
+}
+
+// redacted reports whether the field at the lowercase path is redacted.
+func (r *Redactor) redacted(path []string) bool {
+	if _, ok := r.names[path[len(path)-1]]; ok {
+		return true
+	}
+	for _, rule := range r.paths {
+		if len(rule) != len(path) {
+			continue
+		}
+		matched := true
+		for i, segment := range rule {
+			if segment != Wildcard && segment != path[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
@@ -2,13 +2,18 @@ package redis
 
 import (
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 	"golang.org/x/net/context"
 )
 
+// minDrainTimeout is the least time a replaced client is kept open for
+const minDrainTimeout = 10 * time.Second
+
 type Redis struct {
-	RedisClient *redis.Client
+	redisClient atomic.Pointer[redis.Client]
 	redisConfig *Config
 	mu          sync.Mutex
 }
@@ -40,57 +45,66 @@ func New(ctx context.Context, options *redis.Options) (*Redis, error) {
 	return redisInstance, err
 }
 
-func Update(ctx context.Context, options *redis.Options) error {
-	var err error
-	if err := redisInstance.RedisClient.Close(); err != nil {
+// Reconnect connects to Redis with the options, and replaces the client once the new one answers. The commands in
+// flight finish on the replaced client, which is closed when they have timed out. The client is left alone when the
+// new one cannot connect.
+func (r *Redis) Reconnect(ctx context.Context, options *redis.Options) error {
+	client := redis.NewClient(options)
+	if _, err := client.Ping(ctx).Result(); err != nil {
+		_ = client.Close()
 		return err
 	}
-	*redisInstance = Redis{
-		redisConfig: &Config{
-			redisOptions: options,
-		},
-	}
-	if err = redisInstance.Init(ctx); err != nil {
-		return err
+
+	r.mu.Lock()
+	r.redisConfig = &Config{redisOptions: options}
+	replaced := r.redisClient.Swap(client)
+	r.mu.Unlock()
+	if replaced != nil {
+		time.AfterFunc(drainTimeout(replaced.Options()), func() { _ = replaced.Close() })
 	}
 	return nil
 }
 
+// drainTimeout is how long the commands in flight on a client may still take: they wait for a connection of the
+// pool, then write and read within their timeouts.
+func drainTimeout(options *redis.Options) time.Duration {
+	return max(options.PoolTimeout+options.ReadTimeout+options.WriteTimeout, minDrainTimeout)
+}
+
 func (r *Redis) Init(ctx context.Context) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-
-	if r.RedisClient != nil {
+	if r.redisClient.Load() != nil {
 		return nil
 	}
 	client := redis.NewClient(r.redisConfig.redisOptions)
 	if _, err := client.Ping(ctx).Result(); err != nil {
 		return err
 	}
-	r.RedisClient = client
+	r.redisClient.Store(client)
 	return nil
 }
 
-func (r *Redis) GetClient() (client *redis.Client, err error) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+// Client returns the current client, which Reconnect may replace at any time. Callers should not keep it.
+func (r *Redis) Client() *redis.Client {
+	return r.redisClient.Load()
+}
 
-	if r.RedisClient == nil {
+func (r *Redis) GetClient() (client *redis.Client, err error) {
+	if client = r.redisClient.Load(); client == nil {
 		if err = r.Init(context.Background()); err != nil {
 			return nil, err
 		}
 	}
-	return r.RedisClient, nil
+	return r.redisClient.Load(), nil
 }
 
 func (r *Redis) Close() error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-
-	if r.RedisClient == nil {
+	client := r.redisClient.Swap(nil)
+	if client == nil {
 		return nil
 	}
-	err := r.RedisClient.Close()
-	r.RedisClient = nil
-	return err
+	return client.Close()
 }
@@ -0,0 +1,158 @@
+// Package useragent extracts the browser, operating system and device type from User-Agent headers. It recognizes
+// the common browsers and platforms by their tokens rather than keeping a full database of agents.
+package useragent
+
+import (
+	"strings"
+)
+
+// Device types
+const (
+	DeviceDesktop = "desktop"
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceBot     = "bot"
+	DeviceOther   = "other"
+)
+
+// Agent is a parsed User-Agent. Unrecognized parts are left empty.
+type Agent struct {
+	Browser        string
+	BrowserVersion string
+	OS             string
+	OSVersion      string
+	Device         string
+}
+
+// browserTokens maps the tokens identifying browsers to their names, most specific first since most browsers also
+// claim to be Chrome, Safari or Mozilla.
+var browserTokens = []struct {
+	token string
+	name  string
+}{
+	{"Edg/", "Edge"},
+	{"EdgA/", "Edge"},
+	{"EdgiOS/", "Edge"},
+	{"Edge/", "Edge"},
+	{"OPR/", "Opera"},
+	{"Opera/", "Opera"},
+	{"SamsungBrowser/", "Samsung Internet"},
+	{"YaBrowser/", "Yandex"},
+	{"Vivaldi/", "Vivaldi"},
+	{"CriOS/", "Chrome"},
+	{"Chrome/", "Chrome"},
+	{"FxiOS/", "Firefox"},
+	{"Firefox/", "Firefox"},
+	{"MSIE ", "Internet Explorer"},
+	{"Trident/", "Internet Explorer"},
+	{"curl/", "curl"},
+	{"Wget/", "Wget"},
+	{"PostmanRuntime/", "Postman"},
+	{"Go-http-client/", "Go"},
+	{"python-requests/", "Python Requests"},
+	{"okhttp/", "OkHttp"},
+}
+
+var botTokens = []string{"bot", "spider", "crawl", "slurp", "headless"}
+
+// windowsVersions maps Windows NT versions to their marketing names.
+var windowsVersions = map[string]string{
+	"10.0": "10",
+	"6.3":  "8.1",
+	"6.2":  "8",
+	"6.1":  "7",
+	"6.0":  "Vista",
+	"5.1":  "XP",
+}
+
+// Parse parses a User-Agent header.
+func Parse(ua string) Agent {
+	var agent Agent
+	agent.Browser, agent.BrowserVersion = parseBrowser(ua)
+	agent.OS, agent.OSVersion = parseOS(ua)
+	agent.Device = parseDevice(ua, agent)
+	return agent
+}
+
+// Key identifies the device regardless of browser and system updates.
+func (a Agent) Key() string {
+	return a.Browser + "|" + a.OS + "|" + a.Device
+}
+
+func parseBrowser(ua string) (string, string) {
+	for _, browser := range browserTokens {
+		if i := strings.Index(ua, browser.token); i >= 0 {
+			version := readVersion(ua[i+len(browser.token):])
+			if browser.token == "Trident/" {
+				if j := strings.Index(ua, "rv:"); j >= 0 {
+					version = readVersion(ua[j+3:])
+				}
+			}
+			return browser.name, version
+		}
+	}
+	if strings.Contains(ua, "Safari/") {
+		if i := strings.Index(ua, "Version/"); i >= 0 {
+			return "Safari", readVersion(ua[i+len("Version/"):])
+		}
+		return "Safari", ""
+	}
+	return "", ""
+}
+
+func parseOS(ua string) (string, string) {
+	switch {
+	case strings.Contains(ua, "Windows NT "):
+		version := readVersion(ua[strings.Index(ua, "Windows NT ")+len("Windows NT "):])
+		if name, ok := windowsVersions[version]; ok {
+			version = name
+		}
+		return "Windows", version
+	case strings.Contains(ua, "iPhone") || strings.Contains(ua, "iPad") || strings.Contains(ua, "iPod"):
+		if i := strings.Index(ua, " OS "); i >= 0 {
+			return "iOS", readVersion(strings.ReplaceAll(ua[i+4:], "_", "."))
+		}
+		return "iOS", ""
+	case strings.Contains(ua, "Android"):
+		if i := strings.Index(ua, "Android "); i >= 0 {
+			return "Android", readVersion(ua[i+len("Android "):])
+		}
+		return "Android", ""
+	case strings.Contains(ua, "Mac OS X"):
+		i := strings.Index(ua, "Mac OS X")
+		return "macOS", readVersion(strings.ReplaceAll(strings.TrimLeft(ua[i+len("Mac OS X"):], " "), "_", "."))
+	case strings.Contains(ua, "CrOS"):
+		return "ChromeOS", ""
+	case strings.Contains(ua, "Linux"):
+		return "Linux", ""
+	}
+	return "", ""
+}
+
+func parseDevice(ua string, agent Agent) string {
+	lower := strings.ToLower(ua)
+	for _, token := range botTokens {
+		if strings.Contains(lower, token) {
+			return DeviceBot
+		}
+	}
+	switch {
+	case strings.Contains(ua, "iPad") || strings.Contains(ua, "Tablet") ||
+		(agent.OS == "Android" && !strings.Contains(ua, "Mobile")):
+		return DeviceTablet
+	case strings.Contains(ua, "Mobi") || strings.Contains(ua, "iPhone") || strings.Contains(ua, "iPod"):
+		return DeviceMobile
+	case agent.OS != "":
+		return DeviceDesktop
+	}
+	return DeviceOther
+}
+
+// readVersion returns the version number at the start of s.
+func readVersion(s string) string {
+	end := 0
+	for end < len(s) && (s[end] == '.' || s[end] >= '0' && s[end] <= '9') {
+		end++
+	}
+	return strings.TrimRight(s[:end], ".")
+}
@@ -33,7 +33,7 @@ func IsValidLogLevel(level string) bool {
 }
 
 func IsBearerToken(token string) bool {
-	if len(token) > 7 && token[:6] == "Bearer" || token[:6] == "bearer" {
+	if len(token) > 7 && (token[:6] == "Bearer" || token[:6] == "bearer") {
 		return true
 	}
 	return false
@@ -0,0 +1,61 @@
+package etag
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Format returns the strong entity tag of an entity version, e.g. "3".
+func Format(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// FormatRepresentation returns the strong entity tag of a representation of an entity version other than the one
+// Format tags, e.g. "3-html". Representations of the same version must not share a strong entity tag, and the tag
+// cannot be used in If-Match.
+func FormatRepresentation(version int64, representation string) string {
+	return strconv.Quote(strconv.FormatInt(version, 10) + "-" + representation)
+}
+
+// ParseIfMatch parses an If-Match header into the version a write is conditional on. An empty header or "*" puts no
+// condition on the version and yields nil. Anything other than a single strong entity tag issued by Format can never
+// match, so it is reported as an error and the write must fail its precondition.
+func ParseIfMatch(header string) (*int64, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+	if strings.HasPrefix(header, "W/") {
+		return nil, fmt.Errorf("weak entity tag %s cannot be used in If-Match", header)
+	}
+	unquoted, err := strconv.Unquote(header)
+	if err != nil {
+		return nil, fmt.Errorf("invalid entity tag %s", header)
+	}
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version < 0 {
+		return nil, fmt.Errorf("unknown entity tag %s", header)
+	}
+	return &version, nil
+}
+
+// NoneMatch reports whether an If-None-Match header still allows sending the representation tagged with tag, i.e.
+// whether none of the listed entity tags matches it. Entity tags are compared weakly, as RFC 9110 requires for
+// If-None-Match.
+func NoneMatch(header, tag string) bool {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return true
+	}
+	if header == "*" {
+		return false
+	}
+	tag = strings.TrimPrefix(tag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == tag {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,143 @@
+package highlight
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const ellipsis = "…"
+
+type span struct {
+	start, end int // rune offsets, end exclusive
+}
+
+// Terms splits a full-text search query into the plain terms to highlight. Negated terms (prefixed with '-') are
+// dropped and phrase quotes are stripped.
+func Terms(query string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	for _, field := range strings.Fields(query) {
+		if strings.HasPrefix(field, "-") {
+			continue
+		}
+		term := strings.Trim(field, `"`)
+		if term == "" || seen[strings.ToLower(term)] {
+			continue
+		}
+		seen[strings.ToLower(term)] = true
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// Highlight HTML-escapes text and wraps every case-insensitive occurrence of terms in preTag and postTag.
+func Highlight(text string, terms []string, preTag, postTag string) string {
+	runes := []rune(text)
+	return render(runes, match(runes, terms), 0, len(runes), preTag, postTag)
+}
+
+// Snippet returns an HTML-escaped excerpt of at most length runes centred on the first occurrence of terms, with
+// every occurrence inside the excerpt wrapped in preTag and postTag. Text without any occurrence yields its head.
+func Snippet(text string, terms []string, length int, preTag, postTag string) string {
+	runes := []rune(text)
+	if length <= 0 || length > len(runes) {
+		length = len(runes)
+	}
+	spans := match(runes, terms)
+
+	start := 0
+	if len(spans) > 0 {
+		start = spans[0].start - length/4 // keep a little leading context
+	}
+	if start+length > len(runes) {
+		start = len(runes) - length
+	}
+	if start < 0 {
+		start = 0
+	}
+	end := start + length
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString(ellipsis)
+	}
+	b.WriteString(render(runes, spans, start, end, preTag, postTag))
+	if end < len(runes) {
+		b.WriteString(ellipsis)
+	}
+	return b.String()
+}
+
+// match returns the merged, sorted spans of runes matching any of the terms case-insensitively.
+func match(runes []rune, terms []string) []span {
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+	var spans []span
+	for _, term := range terms {
+		needle := []rune(strings.ToLower(term))
+		if len(needle) == 0 {
+			continue
+		}
+		for i := 0; i+len(needle) <= len(lower); i++ {
+			if equal(lower[i:i+len(needle)], needle) {
+				spans = append(spans, span{i, i + len(needle)})
+			}
+		}
+	}
+	if len(spans) == 0 {
+		return nil
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	merged := spans[:1]
+	for _, s := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if s.start <= last.end {
+			if s.end > last.end {
+				last.end = s.end
+			}
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+// render escapes runes[start:end], wrapping the parts covered by spans in the given tags.
+func render(runes []rune, spans []span, start, end int, preTag, postTag string) string {
+	var b strings.Builder
+	pos := start
+	for _, s := range spans {
+		if s.end <= start || s.start >= end {
+			continue
+		}
+		if s.start > pos {
+			b.WriteString(html.EscapeString(string(runes[pos:s.start])))
+			pos = s.start
+		}
+		stop := s.end
+		if stop > end {
+			stop = end
+		}
+		b.WriteString(preTag)
+		b.WriteString(html.EscapeString(string(runes[pos:stop])))
+		b.WriteString(postTag)
+		pos = stop
+	}
+	if pos < end {
+		b.WriteString(html.EscapeString(string(runes[pos:end])))
+	}
+	return b.String()
+}
+
+func equal(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,33 @@
+package slug
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var pattern = regexp.MustCompile(`^[\p{Ll}\p{Lm}\p{Lo}\p{Nd}]+(-[\p{Ll}\p{Lm}\p{Lo}\p{Nd}]+)*$`)
+
+// Make derives a slug from s: letters are lower-cased, digits kept and every other run of characters collapses into a
+// single hyphen. The result is empty when s holds no letter or digit.
+func Make(s string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			hyphen = false
+		} else {
+			hyphen = true
+		}
+	}
+	return b.String()
+}
+
+// Valid reports whether s is a well-formed slug, i.e. lower-case letters and digits separated by single hyphens.
+func Valid(s string) bool {
+	return pattern.MatchString(s)
+}
@@ -35,17 +35,13 @@ func New(config *zap.Config, options ...zap.Option) (*Zap, error) {
 	return zapInstance, nil
 }
 
-func Update(config *zap.Config, options ...zap.Option) error {
-	z := &Zap{
-		config: &Config{
-			zapConfig:  config,
-			zapOptions: options,
-		},
-	}
-	if err := z.Init(); err != nil {
+// SetLevel changes the level of the logger, and of every logger derived from it, in place.
+func (z *Zap) SetLevel(level string) error {
+	l, err := zapcore.ParseLevel(level)
+	if err != nil {
 		return err
 	}
-	zapInstance = z
+	z.config.zapConfig.Level.SetLevel(l)
 	return nil
 }
 
@@ -63,7 +59,6 @@ func (z *Zap) Init() error {
 		return err
 	}
 
-	z.config.zapOptions = append(z.config.zapOptions)
 	z.Logger = logger.WithOptions(z.config.zapOptions...)
 
 	return nil
@@ -33,8 +33,14 @@ func Teardown() error {
 	_, _ = injector.UserDao.DeleteUserList(injector.Ctx, nil, nil, nil, nil, nil, nil, nil, nil)
 	_, _ = injector.NoticeDao.DeleteNoticeList(injector.Ctx, nil, nil, nil, nil, nil)
 	_, _ = injector.DocumentationDao.DeleteDocumentationList(injector.Ctx, nil, nil, nil, nil)
+	_, _ = injector.DocumentationRevisionDao.DeleteDocumentationRevisionList(injector.Ctx, nil)
 	_, _ = injector.LoginLogDao.DeleteLoginLogList(injector.Ctx, nil, nil, nil, nil, nil)
 	_, _ = injector.OperationLogDao.DeleteOperationLogList(injector.Ctx, nil, nil, nil, nil, nil, nil, nil, nil)
+	database := injector.Mongo.Client().Database(injector.Mongo.DatabaseName)
+	_ = database.Collection(config.OperationLogChainCollectionName).DropCollection(injector.Ctx)
+	_ = database.Collection(config.OperationLogCheckpointCollectionName).DropCollection(injector.Ctx)
+	_ = database.Collection(config.OperationLogImportCollectionName).DropCollection(injector.Ctx)
+	_ = database.Collection(config.RateLimitOverrideCollectionName).DropCollection(injector.Ctx)
 	var (
 		username    = "Admin"
 		password, _ = crypt.Hash("Admin@123")
@@ -49,7 +55,7 @@ func Teardown() error {
 	if err != nil {
 		return err
 	}
-	err = injector.Cache.Flush(injector.Ctx, nil)
+	_, err = injector.Cache.Flush(injector.Ctx, nil)
 	if err != nil {
 		return err
 	}
@@ -57,7 +63,7 @@ func Teardown() error {
 	if err != nil {
 		return err
 	}
-	err = injector.Redis.Close()
+	err = injector.CacheBackend.Close()
 	if err != nil {
 		return err
 	}
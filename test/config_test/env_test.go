@@ -0,0 +1,97 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fiber-admin/internal/pkg/config"
+	"fiber-admin/internal/pkg/config/mods/middleware"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// readConfig reads the dev config file with the flags bound to the port and the log level.
+func readConfig(t *testing.T, args ...string) (*config.Config, error) {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String("port", "", "")
+	flags.String("log-level", "", "")
+	assert.NoError(t, flags.Parse(args))
+
+	v := viper.New()
+	v.SetConfigFile("../../configs/dev/server.dev.yml")
+	assert.NoError(t, v.ReadInConfig())
+	assert.NoError(t, v.BindPFlag("base.app_port", flags.Lookup("port")))
+	assert.NoError(t, v.BindPFlag("zap.zap_level", flags.Lookup("log-level")))
+	cfg := config.Default()
+	err := config.Unmarshal(v, cfg)
+	return cfg, err
+}
+
+func TestEnvName(t *testing.T) {
+	assert.Equal(t, "FIBER_ADMIN_MONGO_MONGO_URI", config.EnvName("mongo.mongo_uri"))
+	assert.Equal(t, "FIBER_ADMIN_CACHE_REDIS_REDIS_PASSWORD", config.EnvName("cache.redis.redis_password"))
+}
+
+func TestEnvPrecedence(t *testing.T) {
+	// Default, then file
+	cfg, err := readConfig(t)
+	assert.NoError(t, err)
+	assert.Equal(t, config.Default().LogTailConfig, cfg.LogTailConfig)
+	fileConfig := *cfg
+
+	// Env over file
+	t.Setenv("FIBER_ADMIN_BASE_APP_PORT", "4000")
+	t.Setenv("FIBER_ADMIN_ZAP_ZAP_LEVEL", "error")
+	t.Setenv("FIBER_ADMIN_LOG_TAIL_LOG_TAIL_HEARTBEAT", "30s")
+	t.Setenv("FIBER_ADMIN_BASE_ENABLE_CORS", "false")
+	t.Setenv("FIBER_ADMIN_FIBER_TRUSTED_PROXIES", "10.0.0.1,10.1.0.0/16")
+	t.Setenv("FIBER_ADMIN_MIDDLEWARE_LIMITER_RULES", `[{path: /api/v1/admin, identity: user, max: 5, window: 2m}]`)
+	cfg, err = readConfig(t)
+	assert.NoError(t, err)
+	assert.Equal(t, "4000", cfg.BaseConfig.AppPort)
+	assert.Equal(t, "error", cfg.ZapConfig.Level)
+	assert.Equal(t, 30*time.Second, cfg.LogTailConfig.Heartbeat)
+	assert.False(t, cfg.BaseConfig.EnableCors)
+	assert.Equal(t, []string{"10.0.0.1", "10.1.0.0/16"}, cfg.FiberConfig.TrustedProxies)
+	assert.Equal(
+		t, []middleware.LimiterRule{{Path: "/api/v1/admin", Identity: "user", Max: 5, Window: 2 * time.Minute}},
+		cfg.MiddlewareConfig.LimiterConfig.Rules,
+	)
+	assert.Equal(t, fileConfig.MongoConfig, cfg.MongoConfig)
+
+	// Flag over env
+	cfg, err = readConfig(t, "--port", "5000")
+	assert.NoError(t, err)
+	assert.Equal(t, "5000", cfg.BaseConfig.AppPort)
+	assert.Equal(t, "error", cfg.ZapConfig.Level)
+}
+
+func TestEnvSecretFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "redis_password")
+	assert.NoError(t, os.WriteFile(file, []byte("s3cret\n"), 0o600))
+	t.Setenv("FIBER_ADMIN_CACHE_REDIS_REDIS_PASSWORD_FILE", file)
+
+	cfg, err := readConfig(t)
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cret", cfg.CacheConfig.RedisConfig.Password)
+
+	// The secret is read again, e.g. after it is rotated
+	assert.NoError(t, os.WriteFile(file, []byte("rotated"), 0o600))
+	cfg, err = readConfig(t)
+	assert.NoError(t, err)
+	assert.Equal(t, "rotated", cfg.CacheConfig.RedisConfig.Password)
+
+	t.Setenv("FIBER_ADMIN_CACHE_REDIS_REDIS_PASSWORD", "root")
+	_, err = readConfig(t)
+	assert.ErrorContains(t, err, "both FIBER_ADMIN_CACHE_REDIS_REDIS_PASSWORD and")
+
+	// Only secrets are read from files
+	t.Setenv("FIBER_ADMIN_BASE_APP_PORT_FILE", file)
+	os.Unsetenv("FIBER_ADMIN_CACHE_REDIS_REDIS_PASSWORD")
+	cfg, err = readConfig(t)
+	assert.NoError(t, err)
+	assert.Equal(t, "8000", cfg.BaseConfig.AppPort)
+}
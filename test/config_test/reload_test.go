@@ -0,0 +1,83 @@
+package config_test
+
+import (
+	"context"
+	e "errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"fiber-admin/internal/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReload(t *testing.T) {
+	ctx := context.Background()
+	current := newConfig()
+	config.Set(current)
+
+	var (
+		jwtApplied []time.Duration
+		zapApplied []string
+		zapFailure error
+	)
+	config.Subscribe(
+		"jwt", []string{"jwt"}, func(ctx context.Context, old, new *config.Config) error {
+			jwtApplied = append(jwtApplied, new.JWTConfig.TokenDuration)
+			return nil
+		},
+	)
+	config.Subscribe(
+		"zap", []string{"zap.zap_level"}, func(ctx context.Context, old, new *config.Config) error {
+			if zapFailure != nil {
+				return zapFailure
+			}
+			zapApplied = append(zapApplied, new.ZapConfig.Level)
+			return nil
+		},
+	)
+
+	// Invalid
+	cfg := newConfig()
+	cfg.JWTConfig.TokenDuration = 0
+	_, err := config.Reload(ctx, cfg)
+	var errs config.ValidationErrors
+	assert.True(t, e.As(err, &errs))
+	assert.Same(t, current, config.Applied())
+
+	// Unchanged
+	result, err := config.Reload(ctx, newConfig())
+	assert.NoError(t, err)
+	assert.Empty(t, result.Changed)
+	assert.Same(t, current, config.Applied())
+
+	// Applied, the port on restart
+	cfg = newConfig()
+	cfg.JWTConfig.TokenDuration = time.Hour
+	cfg.ZapConfig.Level = "debug"
+	cfg.BaseConfig.AppPort = "4000"
+	result, err = config.Reload(ctx, cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"base.app_port", "jwt.jwt_token_duration", "zap.zap_level"}, result.Changed)
+	assert.Equal(t, []string{"jwt", "zap"}, result.Applied)
+	assert.Equal(t, []string{"base.app_port"}, result.Restart)
+	assert.Equal(t, []time.Duration{time.Hour}, jwtApplied)
+	assert.Equal(t, []string{"debug"}, zapApplied)
+	assert.Same(t, cfg, config.Applied())
+	current = cfg
+
+	// Rolled back
+	zapFailure = fmt.Errorf("no such level")
+	cfg = newConfig()
+	cfg.JWTConfig.TokenDuration = 90 * time.Minute
+	cfg.ZapConfig.Level = "warn"
+	_, err = config.Reload(ctx, cfg)
+	var reloadErr *config.ReloadError
+	assert.True(t, e.As(err, &reloadErr))
+	assert.Equal(t, "zap", reloadErr.Subscription)
+	assert.Empty(t, reloadErr.Rollback)
+	assert.ErrorIs(t, err, zapFailure)
+	assert.Equal(t, []time.Duration{time.Hour, 90 * time.Minute, time.Hour}, jwtApplied)
+	assert.Equal(t, []string{"debug"}, zapApplied)
+	assert.Same(t, current, config.Applied())
+}
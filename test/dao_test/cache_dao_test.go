@@ -0,0 +1,290 @@
+package dao_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"fiber-admin/internal/pkg/config"
+	"fiber-admin/internal/pkg/dao"
+	"fiber-admin/test/wire"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type cachedValue struct {
+	Name string `json:"name"`
+}
+
+func TestGetOrLoad(t *testing.T) {
+	var (
+		injector = wire.GetInjector()
+		cache    = injector.Cache
+		logger   = injector.Zap.Logger
+		ctx      = injector.Ctx
+		key      = "test:get_or_load:" + primitive.NewObjectID().Hex()
+		ttl      = time.Minute
+		loads    atomic.Int32
+		wg       sync.WaitGroup
+	)
+	load := func(ctx context.Context) (*cachedValue, error) {
+		loads.Add(1)
+		time.Sleep(100 * time.Millisecond)
+		return &cachedValue{Name: "value"}, nil
+	}
+	defer func() { _ = cache.Delete(ctx, key) }()
+
+	// Concurrent misses share one load
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := dao.GetOrLoad(ctx, cache, logger, "test", key, ttl, load)
+			assert.NoError(t, err)
+			assert.Equal(t, "value", value.Name)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), loads.Load())
+
+	value, err := dao.GetOrLoad(ctx, cache, logger, "test", key, ttl, load)
+	assert.NoError(t, err)
+	assert.Equal(t, "value", value.Name)
+	assert.Equal(t, int32(1), loads.Load())
+
+	// The TTL is jittered around ttl
+	remaining, err := cache.Backend.TTL(ctx, key)
+	assert.NoError(t, err)
+	jitter := injector.Config.CacheConfig.TTLJitter
+	assert.LessOrEqual(t, remaining, time.Duration(float64(ttl)*(1+jitter)))
+	assert.GreaterOrEqual(t, remaining, time.Duration(float64(ttl)*(1-jitter))-time.Second)
+}
+
+func TestGetOrLoadNotFound(t *testing.T) {
+	var (
+		injector = wire.GetInjector()
+		cache    = injector.Cache
+		logger   = injector.Zap.Logger
+		ctx      = injector.Ctx
+		key      = "test:get_or_load:" + primitive.NewObjectID().Hex()
+		loads    atomic.Int32
+	)
+	load := func(ctx context.Context) (*cachedValue, error) {
+		loads.Add(1)
+		return nil, mongo.ErrNoDocuments
+	}
+	defer func() { _ = cache.Delete(ctx, key) }()
+
+	_, err := dao.GetOrLoad(ctx, cache, logger, "test", key, time.Minute, load)
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+	// The missing document is cached
+	_, err = dao.GetOrLoad(ctx, cache, logger, "test", key, time.Minute, load)
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+	assert.Equal(t, int32(1), loads.Load())
+
+	remaining, err := cache.Backend.TTL(ctx, key)
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, remaining, injector.Config.CacheConfig.NegativeCacheTTL)
+}
+
+func TestLocalCache(t *testing.T) {
+	var (
+		injector = wire.GetInjector()
+		logger   = injector.Zap.Logger
+		ctx      = injector.Ctx
+		prefix   = "test:local:" + primitive.NewObjectID().Hex()
+		loads    atomic.Int32
+	)
+	localConfig := *injector.Config
+	localConfig.CacheConfig.LocalConfig.Enabled = true
+	localConfig.CacheConfig.LocalConfig.Entities = []string{"test"}
+	// Two instances sharing Redis
+	first := dao.NewCache(injector.CacheBackend, &localConfig, nil)
+	second := dao.NewCache(injector.CacheBackend, &localConfig, nil)
+	load := func(ctx context.Context) (*cachedValue, error) {
+		loads.Add(1)
+		return &cachedValue{Name: "value"}, nil
+	}
+	time.Sleep(100 * time.Millisecond) // Let the instances subscribe to invalidations
+
+	key := first.Key(ctx, prefix, "id:%d", 1)
+	defer func() { _ = first.Delete(ctx, key) }()
+	_, err := dao.GetOrLoad(ctx, first, logger, "test", key, time.Minute, load)
+	assert.NoError(t, err)
+	// Served locally once Redis no longer has the key
+	assert.NoError(t, first.Delete(ctx, key))
+	value, err := dao.GetOrLoad(ctx, first, logger, "test", key, time.Minute, load)
+	assert.NoError(t, err)
+	assert.Equal(t, "value", value.Name)
+	assert.Equal(t, int32(1), loads.Load())
+
+	// An invalidation on one instance reaches the other
+	assert.NoError(t, second.Invalidate(ctx, prefix))
+	assert.Eventually(
+		t, func() bool { return first.Key(ctx, prefix, "id:%d", 1) != key }, time.Second, 10*time.Millisecond,
+	)
+}
+
+func TestCacheBackend(t *testing.T) {
+	var (
+		injector = wire.GetInjector()
+		ctx      = injector.Ctx
+	)
+	backends := map[string]dao.CacheBackend{
+		"redis":  injector.CacheBackend,
+		"memory": dao.NewMemoryCacheBackend(),
+	}
+	for name, backend := range backends {
+		t.Run(
+			name, func(t *testing.T) {
+				prefix := "test:backend:" + primitive.NewObjectID().Hex()
+				defer func() { _, _ = backend.DeletePrefix(ctx, prefix) }()
+
+				_, err := backend.Get(ctx, prefix+":missing")
+				assert.ErrorIs(t, err, dao.CacheNil{})
+				assert.NoError(t, backend.Set(ctx, prefix+":key", "value", time.Minute))
+				value, err := backend.Get(ctx, prefix+":key")
+				assert.NoError(t, err)
+				assert.Equal(t, "value", value)
+				ttl, err := backend.TTL(ctx, prefix+":key")
+				assert.NoError(t, err)
+				assert.InDelta(t, time.Minute, ttl, float64(time.Second))
+				assert.NoError(t, backend.Set(ctx, prefix+":expiring", "value", 50*time.Millisecond))
+				time.Sleep(100 * time.Millisecond)
+				_, err = backend.Get(ctx, prefix+":expiring")
+				assert.ErrorIs(t, err, dao.CacheNil{})
+
+				set, err := backend.SetNX(ctx, prefix+":key", "other", time.Minute)
+				assert.NoError(t, err)
+				assert.False(t, set)
+				set, err = backend.SetNX(ctx, prefix+":new", "value", time.Minute)
+				assert.NoError(t, err)
+				assert.True(t, set)
+
+				count, err := backend.Incr(ctx, prefix+":counter")
+				assert.NoError(t, err)
+				assert.Equal(t, int64(1), count)
+				count, err = backend.Incr(ctx, prefix+":counter")
+				assert.NoError(t, err)
+				assert.Equal(t, int64(2), count)
+
+				count, err = backend.IncrExpire(ctx, prefix+":window", time.Minute)
+				assert.NoError(t, err)
+				assert.Equal(t, int64(1), count)
+				count, err = backend.IncrExpire(ctx, prefix+":window", time.Hour)
+				assert.NoError(t, err)
+				assert.Equal(t, int64(2), count)
+				ttl, err = backend.TTL(ctx, prefix+":window") // Set when created only
+				assert.NoError(t, err)
+				assert.InDelta(t, time.Minute, ttl, float64(time.Second))
+
+				assert.NoError(t, backend.RightPush(ctx, prefix+":list", "b"))
+				assert.NoError(t, backend.RightPush(ctx, prefix+":list", "c"))
+				assert.NoError(t, backend.LeftPush(ctx, prefix+":list", "a"))
+				length, err := backend.Len(ctx, prefix+":list")
+				assert.NoError(t, err)
+				assert.Equal(t, int64(3), length)
+				for _, want := range []string{"a", "b", "c"} {
+					value, err = backend.LeftPop(ctx, prefix+":list")
+					assert.NoError(t, err)
+					assert.Equal(t, want, value)
+				}
+				_, err = backend.LeftPop(ctx, prefix+":list")
+				assert.ErrorIs(t, err, dao.CacheNil{})
+
+				assert.NoError(t, backend.Delete(ctx, prefix+":key"))
+				_, err = backend.Get(ctx, prefix+":key")
+				assert.ErrorIs(t, err, dao.CacheNil{})
+				// Scan takes the prefix literally
+				assert.NoError(t, backend.Set(ctx, prefix+":glob*", "value", time.Minute))
+				assert.NoError(t, backend.Set(ctx, prefix+":globbed", "value", time.Minute))
+				var scanned []string
+				assert.NoError(
+					t, backend.Scan(
+						ctx, prefix+":glob*", func(keys []string) error {
+							scanned = append(scanned, keys...)
+							return nil
+						},
+					),
+				)
+				assert.Equal(t, []string{prefix + ":glob*"}, scanned)
+				memory, err := backend.MemoryUsage(ctx, prefix+":glob*", prefix+":missing")
+				assert.NoError(t, err)
+				assert.Positive(t, memory)
+				deleted, err := backend.DeletePrefix(ctx, prefix)
+				assert.NoError(t, err)
+				assert.Equal(t, int64(5), deleted)
+				_, err = backend.Get(ctx, prefix+":counter")
+				assert.ErrorIs(t, err, dao.CacheNil{})
+
+				channel := prefix + ":channel"
+				subscription, err := backend.Subscribe(ctx, channel)
+				assert.NoError(t, err)
+				subscribers, err := backend.SubscriberCount(ctx, channel)
+				assert.NoError(t, err)
+				assert.Equal(t, int64(1), subscribers)
+				assert.NoError(t, backend.Publish(ctx, channel, "message"))
+				select {
+				case message := <-subscription.Channel():
+					assert.Equal(t, channel, message.Channel)
+					assert.Equal(t, "message", message.Payload)
+				case <-time.After(time.Second):
+					t.Error("message not received")
+				}
+				assert.NoError(t, subscription.Close())
+				assert.Eventually(
+					t, func() bool {
+						_, open := <-subscription.Channel()
+						return !open
+					}, time.Second, 10*time.Millisecond,
+				)
+			},
+		)
+	}
+}
+
+func TestCacheNamespace(t *testing.T) {
+	var (
+		injector = wire.GetInjector()
+		ctx      = injector.Ctx
+		backend  = dao.NewMemoryCacheBackend()
+	)
+	defer func() { _ = backend.Close() }()
+	namespaced := func(namespace string) *dao.Cache {
+		namespacedConfig := *injector.Config
+		namespacedConfig.CacheConfig.Namespace = namespace
+		return dao.NewCache(backend, &namespacedConfig, nil)
+	}
+	first, second, unnamespaced := namespaced("first"), namespaced("second"), namespaced("")
+
+	assert.NoError(t, first.Set(ctx, "dao:notice:key", "value", nil))
+	assert.NoError(t, second.Set(ctx, "dao:notice:key", "value", nil))
+	assert.NoError(t, second.Set(ctx, "token:blacklist:key", "value", nil))
+	value, err := backend.Get(ctx, "first:dao:notice:key")
+	assert.NoError(t, err)
+	assert.Equal(t, "value", value)
+
+	stats, err := second.Stats(ctx, []string{config.DaoCachePrefix, config.NoticeCachePrefix})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{config.DaoCachePrefix, config.NoticeCachePrefix, dao.CacheOtherPrefix}, []string{
+		stats.Prefixes[0].Prefix, stats.Prefixes[1].Prefix, stats.Prefixes[2].Prefix,
+	})
+	assert.Equal(t, int64(0), stats.Prefixes[0].Keys) // Counted towards the longest prefix only
+	assert.Equal(t, int64(1), stats.Prefixes[1].Keys)
+	assert.Equal(t, int64(1), stats.Prefixes[2].Keys)
+	assert.Positive(t, stats.Prefixes[1].Memory)
+
+	// Flushing a namespace leaves the others alone
+	deleted, err := second.Flush(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+	_, err = first.Get(ctx, "dao:notice:key")
+	assert.NoError(t, err)
+	_, err = unnamespaced.Flush(ctx, nil)
+	assert.Error(t, err)
+	_, err = first.Get(ctx, "dao:notice:key")
+	assert.NoError(t, err)
+}
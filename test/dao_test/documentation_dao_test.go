@@ -19,10 +19,13 @@ func TestInsertDocumentation(t *testing.T) {
 		ctx              = injector.Ctx
 		title            = "Title"
 		content          = "Content"
+		slug             = "title"
+		category         = "Guide"
+		tags             = []string{"getting-started"}
 		err              error
 	)
 
-	documentID, err = documentationDao.InsertDocumentation(ctx, title, content)
+	documentID, err = documentationDao.InsertDocumentation(ctx, title, content, slug, nil, 0, category, tags)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, documentID)
 
@@ -31,6 +34,28 @@ func TestInsertDocumentation(t *testing.T) {
 	assert.NotNil(t, documentation)
 	assert.Equal(t, title, documentation.Title)
 	assert.Equal(t, content, documentation.Content)
+	assert.Equal(t, slug, documentation.Slug)
+	assert.Equal(t, category, documentation.Category)
+	assert.Equal(t, tags, documentation.Tags)
+	assert.Nil(t, documentation.ParentID)
+
+	_, err = documentationDao.InsertDocumentation(ctx, title, content, slug, nil, 1, category, tags)
+	assert.Error(t, err)
+}
+
+func TestGetDocumentationBySlug(t *testing.T) {
+	// t.Skip("Skip TestGetDocumentationBySlug")
+	var (
+		injector         = wire.GetInjector()
+		documentationDao = injector.DocumentationDao
+		ctx              = injector.Ctx
+	)
+	documentation, err := documentationDao.GetDocumentationBySlug(ctx, "title")
+	assert.NoError(t, err)
+	assert.Equal(t, documentID, documentation.DocumentID)
+
+	_, err = documentationDao.GetDocumentationBySlug(ctx, "nonexistent-slug")
+	assert.Error(t, err)
 }
 
 func TestGetDocumentation(t *testing.T) {
@@ -64,7 +89,7 @@ func TestGetDocumentationList(t *testing.T) {
 		err              error
 	)
 	documentationList, count, err := documentationDao.GetDocumentationList(
-		ctx, 0, 10, false, nil, nil, nil, nil,
+		ctx, 0, 10, false, nil, nil, nil, nil, nil, nil,
 	)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, count)
@@ -74,7 +99,7 @@ func TestGetDocumentationList(t *testing.T) {
 	t.Logf("=====================================")
 
 	documentationList, count, err = documentationDao.GetDocumentationList(
-		ctx, 0, 10, false, &createStartTime, &createEndTime, nil, nil,
+		ctx, 0, 10, false, &createStartTime, &createEndTime, nil, nil, nil, nil,
 	)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, count)
@@ -85,7 +110,7 @@ func TestGetDocumentationList(t *testing.T) {
 	t.Logf("=====================================")
 
 	documentationList, count, err = documentationDao.GetDocumentationList(
-		ctx, 0, 10, false, nil, nil, &updateStartTime, &updateEndTime,
+		ctx, 0, 10, false, nil, nil, &updateStartTime, &updateEndTime, nil, nil,
 	)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, count)
@@ -96,7 +121,7 @@ func TestGetDocumentationList(t *testing.T) {
 	t.Logf("=====================================")
 
 	documentationList, count, err = documentationDao.GetDocumentationList(
-		ctx, 0, 10, false, &createStartTime, &createEndTime, &updateStartTime, &updateEndTime,
+		ctx, 0, 10, false, &createStartTime, &createEndTime, &updateStartTime, &updateEndTime, nil, nil,
 	)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, count)
@@ -109,6 +134,68 @@ func TestGetDocumentationList(t *testing.T) {
 	t.Logf("=====================================")
 }
 
+func TestSearchDocumentation(t *testing.T) {
+	// t.Skip("Skip TestSearchDocumentation")
+	var (
+		injector         = wire.GetInjector()
+		ctx              = injector.Ctx
+		documentationDao = injector.DocumentationDao
+	)
+
+	documentationList, count, err := documentationDao.SearchDocumentation(ctx, "title", 0, 10)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, count)
+	assert.NotEmpty(t, documentationList)
+	assert.Equal(t, documentID, documentationList[0].DocumentID)
+	assert.Greater(t, documentationList[0].Score, float64(0))
+
+	documentationList, count, err = documentationDao.SearchDocumentation(ctx, "nonexistentterm", 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), *count)
+	assert.Empty(t, documentationList)
+}
+
+func TestUpdateDocumentationPosition(t *testing.T) {
+	// t.Skip("Skip TestUpdateDocumentationPosition")
+	var (
+		injector         = wire.GetInjector()
+		documentationDao = injector.DocumentationDao
+		ctx              = injector.Ctx
+	)
+	childID, err := documentationDao.InsertDocumentation(ctx, "Child", "Content", "child", nil, 1, "", nil)
+	assert.NoError(t, err)
+
+	siblingID, err := documentationDao.InsertDocumentation(
+		ctx, "Sibling", "Content", "sibling", &documentID, 0, "", nil,
+	)
+	assert.NoError(t, err)
+
+	err = documentationDao.UpdateDocumentationPosition(
+		ctx, &documentID, &childID, []primitive.ObjectID{childID, siblingID},
+	)
+	assert.NoError(t, err)
+
+	outline, err := documentationDao.GetDocumentationOutline(ctx)
+	assert.NoError(t, err)
+	for _, documentation := range outline {
+		assert.Empty(t, documentation.Content)
+		if documentation.DocumentID == childID {
+			assert.Equal(t, documentID, *documentation.ParentID)
+			assert.Equal(t, int64(0), documentation.Position)
+			assert.Equal(t, int64(2), documentation.Version)
+		}
+		if documentation.DocumentID == siblingID {
+			assert.Equal(t, int64(1), documentation.Position)
+			assert.Equal(t, int64(1), documentation.Version) // Renumbering is not an edit
+		}
+	}
+
+	err = documentationDao.DeleteDocumentation(ctx, childID, nil)
+	assert.NoError(t, err)
+	err = documentationDao.DeleteDocumentation(ctx, siblingID, nil)
+	assert.NoError(t, err)
+}
+
 func TestUpdateDocumentation(t *testing.T) {
 	// t.Skip("Skip TestUpdateDocumentation")
 	var (
@@ -120,14 +207,17 @@ func TestUpdateDocumentation(t *testing.T) {
 		err              error
 	)
 
-	err = documentationDao.UpdateDocumentation(ctx, documentID, &title, &content)
+	updated, err := documentationDao.UpdateDocumentation(ctx, documentID, nil, &title, &content, nil, nil, nil)
 	assert.NoError(t, err)
+	assert.Equal(t, title, updated.Title)
+	assert.Equal(t, content, updated.Content)
 
 	documentation, err := documentationDao.GetDocumentationByID(ctx, documentID)
 	assert.NoError(t, err)
 	assert.NotNil(t, documentation)
 	assert.Equal(t, title, documentation.Title)
 	assert.Equal(t, content, documentation.Content)
+	assert.Equal(t, documentation.Version, updated.Version)
 }
 
 func TestDeleteDocumentation(t *testing.T) {
@@ -138,7 +228,7 @@ func TestDeleteDocumentation(t *testing.T) {
 		ctx              = injector.Ctx
 		err              error
 	)
-	err = documentationDao.DeleteDocumentation(ctx, documentID)
+	err = documentationDao.DeleteDocumentation(ctx, documentID, nil)
 	assert.NoError(t, err)
 
 	documentation, err := documentationDao.GetDocumentationByID(ctx, documentID)
@@ -160,7 +250,7 @@ func TestDeleteDocumentationList(t *testing.T) {
 	)
 
 	documentationList, count, err := documentationDao.GetDocumentationList(
-		ctx, 0, 10, false, &createStartTime, &createEndTime, &updateStartTime, &updateEndTime,
+		ctx, 0, 10, false, &createStartTime, &createEndTime, &updateStartTime, &updateEndTime, nil, nil,
 	)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, count)
@@ -178,7 +268,7 @@ func TestDeleteDocumentationList(t *testing.T) {
 	t.Logf("=====================================")
 
 	documentationList, count, err = documentationDao.GetDocumentationList(
-		ctx, 0, 10, false, &createStartTime, &createEndTime, &updateStartTime, &updateEndTime,
+		ctx, 0, 10, false, &createStartTime, &createEndTime, &updateStartTime, &updateEndTime, nil, nil,
 	)
 	assert.NoError(t, err)
 	assert.Empty(t, documentationList)
@@ -0,0 +1,89 @@
+package dao_test
+
+import (
+	"testing"
+
+	"fiber-admin/test/wire"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var revisionDocumentID = primitive.NewObjectID()
+
+func TestInsertDocumentationRevision(t *testing.T) {
+	var (
+		injector                 = wire.GetInjector()
+		documentationRevisionDao = injector.DocumentationRevisionDao
+		ctx                      = injector.Ctx
+		authorID                 = primitive.NewObjectID()
+		title                    = "Title"
+		content                  = "Content"
+		summary                  = "Summary"
+	)
+
+	err := documentationRevisionDao.InsertDocumentationRevision(
+		ctx, revisionDocumentID, 1, authorID, title, content, summary,
+	)
+	assert.NoError(t, err)
+
+	err = documentationRevisionDao.InsertDocumentationRevision(
+		ctx, revisionDocumentID, 2, authorID, title, "New Content", summary,
+	)
+	assert.NoError(t, err)
+
+	// A revision is taken once
+	err = documentationRevisionDao.InsertDocumentationRevision(
+		ctx, revisionDocumentID, 2, authorID, title, "Other Content", summary,
+	)
+	assert.True(t, mongo.IsDuplicateKeyError(err))
+
+	documentationRevision, err := documentationRevisionDao.GetDocumentationRevision(ctx, revisionDocumentID, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, content, documentationRevision.Content)
+	assert.Equal(t, authorID, documentationRevision.AuthorID)
+}
+
+func TestGetLatestDocumentationRevision(t *testing.T) {
+	var (
+		injector                 = wire.GetInjector()
+		documentationRevisionDao = injector.DocumentationRevisionDao
+		ctx                      = injector.Ctx
+	)
+	documentationRevision, err := documentationRevisionDao.GetLatestDocumentationRevision(ctx, revisionDocumentID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), documentationRevision.Revision)
+	assert.Equal(t, "New Content", documentationRevision.Content)
+
+	_, err = documentationRevisionDao.GetLatestDocumentationRevision(ctx, primitive.NewObjectID())
+	assert.Error(t, err)
+}
+
+func TestGetDocumentationRevisionList(t *testing.T) {
+	var (
+		injector                 = wire.GetInjector()
+		documentationRevisionDao = injector.DocumentationRevisionDao
+		ctx                      = injector.Ctx
+	)
+	documentationRevisionList, count, err := documentationRevisionDao.GetDocumentationRevisionList(
+		ctx, revisionDocumentID, 0, 10, true,
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), *count)
+	assert.Equal(t, int64(2), documentationRevisionList[0].Revision)
+	t.Logf("Documentation Revision List: %v", documentationRevisionList)
+}
+
+func TestDeleteDocumentationRevisionList(t *testing.T) {
+	var (
+		injector                 = wire.GetInjector()
+		documentationRevisionDao = injector.DocumentationRevisionDao
+		ctx                      = injector.Ctx
+	)
+	count, err := documentationRevisionDao.DeleteDocumentationRevisionList(ctx, &revisionDocumentID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), *count)
+
+	_, err = documentationRevisionDao.GetDocumentationRevision(ctx, revisionDocumentID, 1)
+	assert.Error(t, err)
+}
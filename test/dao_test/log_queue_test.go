@@ -0,0 +1,74 @@
+package dao_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"fiber-admin/internal/pkg/config/mods"
+	"fiber-admin/internal/pkg/dao"
+	"fiber-admin/test/wire"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestLogQueueDeadLetter(t *testing.T) {
+	var (
+		injector   = wire.GetInjector()
+		ctx        = injector.Ctx
+		conf       = *injector.Config
+		stream     = "test:log_queue:" + primitive.NewObjectID().Hex()
+		deadLetter = stream + ":dead_letter"
+		claimIdle  = 10 * time.Millisecond
+		deliveries int
+	)
+	conf.CacheConfig.LocalConfig.Enabled = false
+	conf.LogQueueConfig = mods.LogQueueConfig{BatchSize: 10, MaxDeliveries: 2, ClaimIdle: claimIdle}
+	cache := dao.NewCache(injector.CacheBackend, &conf, nil)
+	queue := dao.NewLogQueue(cache, stream, deadLetter, stream+":legacy")
+	defer func() {
+		_ = cache.Delete(ctx, stream)
+		_ = cache.Delete(ctx, deadLetter)
+	}()
+
+	// Every insert fails as the store would, with a write error which is not a duplicate key
+	insert := func(ctx context.Context, entries []dao.LogQueueEntry) map[string]error {
+		deliveries++
+		return dao.InsertManyFailures(
+			entries, mongo.BulkWriteException{
+				WriteErrors: []mongo.BulkWriteError{
+					{WriteError: mongo.WriteError{Index: 0, Code: 121, Message: "Document failed validation"}},
+				},
+			},
+		)
+	}
+	assert.NoError(t, queue.Push(ctx, `{"ip_address": "127.0.0.1"}`))
+
+	// The failed entry stays pending
+	assert.NoError(t, queue.Consume(ctx, insert))
+	stats, err := queue.Stats(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), stats.Backlog)
+	assert.Equal(t, int64(1), stats.Pending)
+	assert.Zero(t, stats.DeadLetters)
+
+	// Once idle, it is reclaimed and retried
+	time.Sleep(2 * claimIdle)
+	assert.NoError(t, queue.Consume(ctx, insert))
+	assert.Equal(t, 2, deliveries)
+	stats, err = queue.Stats(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), stats.Pending)
+	assert.Zero(t, stats.DeadLetters)
+
+	// Delivered MaxDeliveries times, it is dead-lettered instead of being retried again
+	time.Sleep(2 * claimIdle)
+	assert.NoError(t, queue.Consume(ctx, insert))
+	assert.Equal(t, 2, deliveries)
+	stats, err = queue.Stats(ctx)
+	assert.NoError(t, err)
+	assert.Zero(t, stats.Backlog)
+	assert.Zero(t, stats.Pending)
+	assert.Equal(t, int64(1), stats.DeadLetters)
+}
@@ -51,14 +51,12 @@ func TestCacheLoginLog(t *testing.T) {
 
 	err = loginLogDao.CacheLoginLog(ctx, userID, ipAddress, userAgent)
 	assert.NoError(t, err)
-	pop, err := wire.GetInjector().Cache.LeftPop(ctx, "log:login")
+	stats, err := loginLogDao.GetLoginLogQueueStats(ctx)
 	assert.NoError(t, err)
-	assert.NotNil(t, pop)
-	assert.NotEmpty(t, *pop)
-	t.Logf("Login log: %s", *pop)
+	assert.NotNil(t, stats)
+	assert.NotZero(t, stats.Backlog)
+	t.Logf("Login log queue: %+v", *stats)
 	t.Logf("=====================================")
-	err = loginLogDao.CacheLoginLog(ctx, userID, ipAddress, userAgent)
-	assert.NoError(t, err)
 }
 
 func TestSyncLoginLog(t *testing.T) {
@@ -72,6 +70,10 @@ func TestSyncLoginLog(t *testing.T) {
 	)
 	// t.Skip("Skip TestSyncLoginLog")
 	loginLogDao.SyncLoginLog(ctx)
+	stats, err := loginLogDao.GetLoginLogQueueStats(ctx)
+	assert.NoError(t, err)
+	assert.Zero(t, stats.Backlog)
+	assert.Zero(t, stats.Pending)
 	loginLogList, count, err := loginLogDao.GetLoginLogList(
 		ctx, 0, 10, false, nil, nil, nil, &ipAddress, &userAgent, nil,
 	)
@@ -1,9 +1,12 @@
 package dao_test
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
+	"fiber-admin/internal/pkg/dao"
+	"fiber-admin/internal/pkg/dao/mods"
 	"fiber-admin/test/wire"
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -124,6 +127,76 @@ func TestGetNoticeList(t *testing.T) {
 	t.Logf("=====================================")
 }
 
+func TestGetNoticeListInvalidation(t *testing.T) {
+	var (
+		injector  = wire.GetInjector()
+		noticeDao = injector.NoticeDao
+		ctx       = injector.Ctx
+	)
+
+	_, before, err := noticeDao.GetNoticeList(ctx, 0, 10, true, nil, nil, nil, nil, nil)
+	assert.NoError(t, err)
+	// The page is cached now, and must not be served once a notice is inserted
+	insertedID, err := noticeDao.InsertNotice(ctx, "Title", "Content", "NORMAL")
+	assert.NoError(t, err)
+	noticeList, after, err := noticeDao.GetNoticeList(ctx, 0, 10, true, nil, nil, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, *before+1, *after)
+	assert.Equal(t, insertedID, noticeList[0].NoticeID)
+
+	assert.NoError(t, noticeDao.DeleteNotice(ctx, insertedID, nil))
+	_, after, err = noticeDao.GetNoticeList(ctx, 0, 10, true, nil, nil, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, *before, *after)
+}
+
+func TestCacheFlush(t *testing.T) {
+	var (
+		injector = wire.GetInjector()
+		cache    = injector.Cache
+		ctx      = injector.Ctx
+		prefix   = "test:flush:" + primitive.NewObjectID().Hex()
+	)
+	// More keys than a SCAN batch
+	for i := 0; i < 1200; i++ {
+		assert.NoError(t, cache.Set(ctx, fmt.Sprintf("%s:%d", prefix, i), "1", nil))
+	}
+	assert.NoError(t, cache.Set(ctx, "test:other", "1", nil))
+
+	scoped := prefix + ":"
+	deleted, err := cache.Flush(ctx, &scoped)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1200), deleted)
+	_, err = cache.Get(ctx, prefix+":0")
+	assert.ErrorIs(t, err, dao.CacheNil{})
+	_, err = cache.Get(ctx, prefix+":1199")
+	assert.ErrorIs(t, err, dao.CacheNil{})
+	_, err = cache.Get(ctx, "test:other")
+	assert.NoError(t, err)
+	assert.NoError(t, cache.Delete(ctx, "test:other"))
+}
+
+func TestSearchNotice(t *testing.T) {
+	// t.Skip("Skip TestSearchNotice")
+	var (
+		injector  = wire.GetInjector()
+		ctx       = injector.Ctx
+		noticeDao = injector.NoticeDao
+	)
+
+	noticeList, count, err := noticeDao.SearchNotice(ctx, "title", 0, 10)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, count)
+	assert.NotEmpty(t, noticeList)
+	assert.Equal(t, noticeID, noticeList[0].NoticeID)
+	assert.Greater(t, noticeList[0].Score, float64(0))
+
+	noticeList, count, err = noticeDao.SearchNotice(ctx, "nonexistentterm", 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), *count)
+	assert.Empty(t, noticeList)
+}
+
 func TestUpdateNotice(t *testing.T) {
 	// t.Skip("Skip TestUpdateNotice")
 	var (
@@ -135,15 +208,26 @@ func TestUpdateNotice(t *testing.T) {
 		noticeType = "NORMAL"
 	)
 
-	err := noticeDao.UpdateNotice(ctx, noticeID, &title, &content, &noticeType)
+	notice, err := noticeDao.GetNoticeByID(ctx, noticeID)
 	assert.NoError(t, err)
+	version := notice.Version
 
-	notice, err := noticeDao.GetNoticeByID(ctx, noticeID)
+	err = noticeDao.UpdateNotice(ctx, noticeID, &version, &title, &content, &noticeType)
+	assert.NoError(t, err)
+
+	notice, err = noticeDao.GetNoticeByID(ctx, noticeID)
 	assert.NoError(t, err)
 	assert.NotNil(t, notice)
 	assert.Equal(t, title, notice.Title)
 	assert.Equal(t, content, notice.Content)
 	assert.Equal(t, noticeType, notice.NoticeType)
+	assert.Equal(t, version+1, notice.Version)
+
+	// The notice has moved past the version it was read at.
+	err = noticeDao.UpdateNotice(ctx, noticeID, &version, &title, &content, &noticeType)
+	assert.ErrorIs(t, err, mods.ErrVersionConflict)
+	err = noticeDao.DeleteNotice(ctx, noticeID, &version)
+	assert.ErrorIs(t, err, mods.ErrVersionConflict)
 }
 
 func TestDeleteNotice(t *testing.T) {
@@ -154,7 +238,7 @@ func TestDeleteNotice(t *testing.T) {
 		noticeDao = injector.NoticeDao
 		err       error
 	)
-	err = noticeDao.DeleteNotice(ctx, noticeID)
+	err = noticeDao.DeleteNotice(ctx, noticeID, nil)
 	assert.NoError(t, err)
 
 	notice, err := noticeDao.GetNoticeByID(ctx, noticeID)
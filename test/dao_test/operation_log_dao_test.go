@@ -22,12 +22,13 @@ func TestInsertOperationLog(t *testing.T) {
 		ipAddress       = "123.456.789.100"
 		operation       = "CREATE"
 		userAgent       = "Mozilla/5.0"
+		requestID       = "3f8c2a1e-6d0b-4f57-9a3e-1c2b7d9e4f60"
 		description     = "Create user"
 		status          = "SUCCESS"
 		err             error
 	)
 	operationLogID, err = operationLogDao.InsertOperationLog(
-		ctx, userID, entityID, ipAddress, userAgent, operation, entityType, description, status,
+		ctx, userID, entityID, ipAddress, userAgent, requestID, operation, entityType, description, status,
 	)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, operationLogID)
@@ -39,6 +40,7 @@ func TestInsertOperationLog(t *testing.T) {
 	assert.Equal(t, entityID, operationLog.EntityID)
 	assert.Equal(t, ipAddress, operationLog.IPAddress)
 	assert.Equal(t, userAgent, operationLog.UserAgent)
+	assert.Equal(t, requestID, operationLog.RequestID)
 	assert.Equal(t, operation, operationLog.Operation)
 	assert.Equal(t, entityType, operationLog.EntityType)
 	assert.Equal(t, description, operationLog.Description)
@@ -56,23 +58,20 @@ func TestCacheOperationLog(t *testing.T) {
 		ipAddress       = "cache 123.456.789.100"
 		operation       = "cache CREATE"
 		userAgent       = "cache Mozilla/5.0"
+		requestID       = "cache 7b1e9c40-2f6a-4d83-b5c1-0e9a8f3d2c75"
 		description     = "cache Create user"
 		status          = "cache SUCCESS"
 	)
 	err := operationLogDao.CacheOperationLog(
-		ctx, userID, entityID, ipAddress, userAgent, operation, entityType, description, status,
+		ctx, userID, entityID, ipAddress, userAgent, requestID, operation, entityType, description, status,
 	)
 	assert.NoError(t, err)
-	pop, err := wire.GetInjector().Cache.LeftPop(ctx, "log:operation")
+	stats, err := operationLogDao.GetOperationLogQueueStats(ctx)
 	assert.NoError(t, err)
-	assert.NotNil(t, pop)
-	assert.NotEmpty(t, *pop)
-	t.Logf("Operation log: %s", *pop)
+	assert.NotNil(t, stats)
+	assert.NotZero(t, stats.Backlog)
+	t.Logf("Operation log queue: %+v", *stats)
 	t.Logf("=====================================")
-	err = operationLogDao.CacheOperationLog(
-		ctx, userID, entityID, ipAddress, userAgent, operation, entityType, description, status,
-	)
-	assert.NoError(t, err)
 }
 
 func TestSyncOperationLog(t *testing.T) {
@@ -84,10 +83,15 @@ func TestSyncOperationLog(t *testing.T) {
 		ipAddress       = "cache 123.456.789.100"
 		operation       = "cache CREATE"
 		userAgent       = "cache Mozilla/5.0"
+		requestID       = "cache 7b1e9c40-2f6a-4d83-b5c1-0e9a8f3d2c75"
 		description     = "cache Create user"
 		status          = "cache SUCCESS"
 	)
 	operationLogDao.SyncOperationLog(ctx)
+	stats, err := operationLogDao.GetOperationLogQueueStats(ctx)
+	assert.NoError(t, err)
+	assert.Zero(t, stats.Backlog)
+	assert.Zero(t, stats.Pending)
 	operationLogList, count, err := operationLogDao.GetOperationLogList(
 		ctx, 0, 10, false, nil, nil, nil, nil, &ipAddress, &operation, &entityType, &status, nil,
 	)
@@ -96,6 +100,7 @@ func TestSyncOperationLog(t *testing.T) {
 	assert.NotEmpty(t, *count)
 	assert.NotEmpty(t, operationLogList)
 	assert.Equal(t, 1, len(operationLogList))
+	assert.Equal(t, requestID, operationLogList[0].RequestID)
 	t.Logf("User ID: %s", userID)
 	t.Logf("Entity type: %s", entityType)
 	t.Logf("IP address: %s", ipAddress)
@@ -0,0 +1,47 @@
+package dao_test
+
+import (
+	"testing"
+
+	"fiber-admin/internal/pkg/domain/entity"
+	"fiber-admin/test/wire"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestRateLimitOverride(t *testing.T) {
+	var (
+		injector             = wire.GetInjector()
+		rateLimitOverrideDao = injector.RateLimitOverrideDao
+		ctx                  = injector.Ctx
+		userID               = primitive.NewObjectID().Hex()
+	)
+
+	// A missing override is cached, and the upsert invalidates it
+	_, err := rateLimitOverrideDao.GetRateLimitOverride(ctx, userID)
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+	assert.NoError(t, rateLimitOverrideDao.UpsertRateLimitOverride(ctx, &entity.RateLimitOverride{UserID: userID, Max: 5}))
+	override, err := rateLimitOverrideDao.GetRateLimitOverride(ctx, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, override.Max)
+
+	assert.NoError(
+		t, rateLimitOverrideDao.UpsertRateLimitOverride(ctx, &entity.RateLimitOverride{UserID: userID, Exempt: true}),
+	)
+	override, err = rateLimitOverrideDao.GetRateLimitOverride(ctx, userID)
+	assert.NoError(t, err)
+	assert.True(t, override.Exempt)
+
+	overrideList, err := rateLimitOverrideDao.GetRateLimitOverrideList(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, overrideList, 1)
+	assert.Equal(t, userID, overrideList[0].UserID)
+	assert.True(t, overrideList[0].Exempt)
+
+	assert.NoError(t, rateLimitOverrideDao.DeleteRateLimitOverride(ctx, userID))
+	_, err = rateLimitOverrideDao.GetRateLimitOverride(ctx, userID)
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+	// Deleting a missing override is not an error
+	assert.NoError(t, rateLimitOverrideDao.DeleteRateLimitOverride(ctx, userID))
+}
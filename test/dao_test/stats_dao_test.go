@@ -0,0 +1,74 @@
+package dao_test
+
+import (
+	"testing"
+	"time"
+
+	"fiber-admin/internal/pkg/config"
+	"fiber-admin/test/wire"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountUser(t *testing.T) {
+	var (
+		injector = wire.GetInjector()
+		ctx      = injector.Ctx
+		statsDao = injector.StatsDao
+	)
+
+	byRole, err := statsDao.CountUserByRole(ctx)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, byRole)
+
+	byOrganization, err := statsDao.CountUserByOrganization(ctx)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, byOrganization)
+}
+
+func TestCountSeries(t *testing.T) {
+	var (
+		injector  = wire.GetInjector()
+		ctx       = injector.Ctx
+		statsDao  = injector.StatsDao
+		endTime   = time.Now().Add(time.Hour)
+		startTime = endTime.AddDate(0, 0, -7)
+	)
+
+	newUsers, err := statsDao.CountNewUser(ctx, startTime, endTime, config.StatsBucketDay)
+	assert.NoError(t, err)
+	for _, count := range newUsers {
+		assert.Positive(t, count.Count)
+	}
+
+	activeUsers, err := statsDao.CountActiveUser(ctx, startTime, endTime, config.StatsBucketHour)
+	assert.NoError(t, err)
+	for _, count := range activeUsers {
+		assert.Positive(t, count.Count)
+	}
+
+	operations, err := statsDao.CountOperation(ctx, startTime, endTime, config.StatsBucketWeek)
+	assert.NoError(t, err)
+	for _, count := range operations {
+		assert.NotEmpty(t, count.Operation)
+		assert.Positive(t, count.Count)
+	}
+
+	// A second call is served from the cache.
+	cached, err := statsDao.CountOperation(ctx, startTime, endTime, config.StatsBucketWeek)
+	assert.NoError(t, err)
+	assert.Equal(t, len(operations), len(cached))
+}
+
+func TestCountContent(t *testing.T) {
+	var (
+		injector = wire.GetInjector()
+		ctx      = injector.Ctx
+		statsDao = injector.StatsDao
+	)
+
+	_, err := statsDao.CountNoticeByType(ctx)
+	assert.NoError(t, err)
+
+	_, err = statsDao.CountDocumentationByCategory(ctx)
+	assert.NoError(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"fiber-admin/internal/pkg/config"
 	"fiber-admin/internal/pkg/domain/entity"
 	"fiber-admin/pkg/utils/crypt"
 	"fiber-admin/test/mock"
@@ -71,7 +72,8 @@ func TestGetUser(t *testing.T) {
 
 	_, _ = userDao.GetUserByID(ctx, userID)
 
-	userCache, err := wire.GetInjector().Cache.Get(ctx, "dao:user:userID:"+user.UserID.Hex())
+	cache := wire.GetInjector().Cache
+	userCache, err := cache.Get(ctx, cache.Key(ctx, config.UserCachePrefix, "userID:%s", user.UserID.Hex()))
 	assert.NoError(t, err)
 	assert.NotNil(t, userCache)
 	assert.NotEmpty(t, userCache)
@@ -263,7 +265,7 @@ func TestUpdateUser(t *testing.T) {
 		password, _ = crypt.Hash("User@123")
 		err         error
 	)
-	err = userDao.UpdateUser(ctx, userID, &username, &email, &password, &role, &org)
+	err = userDao.UpdateUser(ctx, userID, nil, &username, &email, &password, &role, &org)
 	assert.NoError(t, err)
 
 	user, err := userDao.GetUserByID(ctx, userID)
@@ -291,7 +293,7 @@ func TestDeleteUser(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, user)
 
-	err = userDao.DeleteUser(ctx, userID)
+	err = userDao.DeleteUser(ctx, userID, nil)
 	assert.NoError(t, err)
 
 	user, err = userDao.GetUserByID(ctx, userID)
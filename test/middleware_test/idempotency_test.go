@@ -0,0 +1,138 @@
+package middleware_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"fiber-admin/internal/pkg/config"
+	"fiber-admin/internal/pkg/dao"
+	"fiber-admin/internal/pkg/errors"
+	wares "fiber-admin/internal/pkg/middleware/mods"
+	"fiber-admin/internal/pkg/service"
+	"fiber-admin/internal/pkg/service/common/mods"
+	apperrors "fiber-admin/pkg/errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// newIdempotencyApp returns an app whose POST /notice is idempotent and counts how often its handler ran. The handler
+// of /notice/slow waits for release, and the one of /notice/failing fails.
+func newIdempotencyApp(calls *atomic.Int32, release chan struct{}) *fiber.App {
+	conf := &config.Config{}
+	conf.CacheConfig.Namespace = "test"
+	conf.IdempotencyConfig.IdempotencyTokenHeader = "Idempotency-Key"
+	conf.IdempotencyConfig.TTL = time.Minute
+	conf.IdempotencyConfig.LockTimeout = time.Minute
+	conf.IdempotencyConfig.MaxResponseSize = 1024
+	cache := dao.NewCache(dao.NewMemoryCacheBackend(), conf, nil)
+	middleware := &wares.IdempotencyMiddleware{
+		IdempotencyService: mods.NewIdempotencyService(&service.Core{Config: conf, Logger: zap.NewNop()}, cache),
+		Config:             conf,
+	}
+	app := fiber.New(fiber.Config{ErrorHandler: errors.ErrorHandler})
+	app.Use(
+		func(c *fiber.Ctx) error {
+			c.Locals(config.UserIDKey, c.Get("X-User"))
+			return c.Next()
+		},
+	)
+	app.Post(
+		"/notice", middleware.IdempotencyMiddleware(), func(c *fiber.Ctx) error {
+			c.Set(fiber.HeaderLocation, "/notice/1")
+			return c.Status(fiber.StatusCreated).JSON(fiber.Map{"call": calls.Add(1)})
+		},
+	)
+	app.Post(
+		"/notice/slow", middleware.IdempotencyMiddleware(), func(c *fiber.Ctx) error {
+			<-release
+			return c.SendStatus(fiber.StatusCreated)
+		},
+	)
+	app.Post(
+		"/notice/failing", middleware.IdempotencyMiddleware(), func(c *fiber.Ctx) error {
+			calls.Add(1)
+			return apperrors.ServiceError(fmt.Errorf("failed"))
+		},
+	)
+	return app
+}
+
+func newIdempotentRequest(path, key, user, body string) *http.Request {
+	req := httptest.NewRequest(fiber.MethodPost, path, strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	req.Header.Set("X-User", user)
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	return req
+}
+
+func TestIdempotencyReplay(t *testing.T) {
+	var calls atomic.Int32
+	app := newIdempotencyApp(&calls, nil)
+
+	resp, err := app.Test(newIdempotentRequest("/notice", "", "user", `{"title":"a"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	first, err := app.Test(newIdempotentRequest("/notice", "key", "user", `{"title":"a"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusCreated, first.StatusCode)
+	firstBody, _ := io.ReadAll(first.Body)
+
+	// A retry gets the stored response without running the handler again
+	replay, err := app.Test(newIdempotentRequest("/notice", "key", "user", `{"title":"a"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusCreated, replay.StatusCode)
+	assert.Equal(t, "true", replay.Header.Get("Idempotent-Replayed"))
+	assert.Equal(t, "/notice/1", replay.Header.Get(fiber.HeaderLocation))
+	replayBody, _ := io.ReadAll(replay.Body)
+	assert.Equal(t, firstBody, replayBody)
+	assert.Equal(t, int32(1), calls.Load())
+
+	// The same key with a different payload is rejected
+	resp, err = app.Test(newIdempotentRequest("/notice", "key", "user", `{"title":"b"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+
+	// Keys are scoped to the user
+	resp, err = app.Test(newIdempotentRequest("/notice", "key", "other", `{"title":"b"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestIdempotencyConflictAndRetry(t *testing.T) {
+	var (
+		calls   atomic.Int32
+		release = make(chan struct{})
+	)
+	app := newIdempotencyApp(&calls, release)
+
+	done := make(chan *http.Response)
+	go func() {
+		resp, _ := app.Test(newIdempotentRequest("/notice/slow", "slow", "user", ""), -1)
+		done <- resp
+	}()
+	time.Sleep(100 * time.Millisecond) // Let the first request claim the key
+	resp, err := app.Test(newIdempotentRequest("/notice/slow", "slow", "user", ""))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+	close(release)
+	assert.Equal(t, fiber.StatusCreated, (<-done).StatusCode)
+
+	// Failed requests release their key, so that they can be retried
+	for i := 0; i < 2; i++ {
+		resp, err = app.Test(newIdempotentRequest("/notice/failing", "failing", "user", ""))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	}
+	assert.Equal(t, int32(2), calls.Load())
+}
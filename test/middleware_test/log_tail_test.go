@@ -0,0 +1,146 @@
+package middleware_test
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"fiber-admin/internal/pkg/config"
+	"fiber-admin/internal/pkg/domain/entity"
+	"fiber-admin/internal/pkg/errors"
+	wares "fiber-admin/internal/pkg/middleware/mods"
+	sysservice "fiber-admin/internal/pkg/service/sys/mods"
+	apperrors "fiber-admin/pkg/errors"
+	logging "fiber-admin/pkg/zap"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// logTailServiceStub records the published events instead of relaying them.
+type logTailServiceStub struct {
+	tailed bool
+	events []*entity.LogTailEvent
+}
+
+func (l *logTailServiceStub) Tailed(context.Context, string) bool {
+	return l.tailed
+}
+
+func (l *logTailServiceStub) Publish(_ context.Context, event *entity.LogTailEvent) {
+	l.events = append(l.events, event)
+}
+
+func (l *logTailServiceStub) Tail(
+	context.Context, []string, *sysservice.LogTailFilter,
+) (<-chan *entity.LogTailEvent, func(), error) {
+	return nil, nil, nil
+}
+
+func TestOperationLogTail(t *testing.T) {
+	var (
+		stub     = new(logsServiceStub)
+		tailStub = &logTailServiceStub{tailed: true}
+		userID   = primitive.NewObjectID()
+		noticeID = primitive.NewObjectID()
+	)
+	middleware := &wares.OperationLogMiddleware{LogsService: stub, LogTailService: tailStub}
+	app := fiber.New(fiber.Config{ErrorHandler: errors.ErrorHandler})
+	app.Use(
+		func(c *fiber.Ctx) error {
+			c.Locals(config.RequestIDKey, "request-id")
+			c.Locals(config.UserIDKey, userID.Hex())
+			return c.Next()
+		},
+	)
+	app.Delete(
+		"/notice",
+		middleware.OperationLog(config.OperationTypeDelete, config.EntityTypeNotice, "notice_id"),
+		func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusOK)
+		},
+	)
+
+	_, err := app.Test(httptest.NewRequest(fiber.MethodDelete, "/notice?notice_id="+noticeID.Hex(), nil))
+	assert.NoError(t, err)
+	assert.Len(t, tailStub.events, 1)
+	event := tailStub.events[0]
+	assert.Equal(t, config.LogTailTypeOperation, event.Type)
+	assert.Equal(t, "request-id", event.RequestID)
+	assert.Equal(t, userID.Hex(), event.UserID)
+	assert.Equal(t, fiber.MethodDelete, event.Method)
+	assert.Equal(t, "/notice", event.Path)
+	assert.Equal(t, config.OperationTypeDelete, event.Operation)
+	assert.Equal(t, config.EntityTypeNotice, event.EntityType)
+	assert.Equal(t, noticeID.Hex(), event.EntityID)
+	assert.Equal(t, stub.logs[0].description, event.Description)
+	assert.Equal(t, config.OperationStatusSuccess, event.Status)
+
+	// Nothing is published while nobody is tailing
+	tailStub.tailed = false
+	_, err = app.Test(httptest.NewRequest(fiber.MethodDelete, "/notice?notice_id="+noticeID.Hex(), nil))
+	assert.NoError(t, err)
+	assert.Len(t, stub.logs, 2)
+	assert.Len(t, tailStub.events, 1)
+}
+
+func TestLoggingTail(t *testing.T) {
+	zapConfig := zap.NewProductionConfig()
+	zapConfig.OutputPaths = []string{}
+	z, err := logging.New(&zapConfig)
+	assert.NoError(t, err)
+	var (
+		tailStub = &logTailServiceStub{tailed: true}
+		userID   = primitive.NewObjectID()
+	)
+	middleware := &wares.LoggingMiddleware{Zap: z, LogTailService: tailStub}
+	app := fiber.New(fiber.Config{ErrorHandler: errors.ErrorHandler})
+	middleware.Register(app)
+	app.Get(
+		"/notice", func(c *fiber.Ctx) error {
+			c.Locals(config.UserIDKey, userID.Hex())
+			return apperrors.NotFound(fmt.Errorf("notice not found"))
+		},
+	)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/notice", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	assert.Len(t, tailStub.events, 1)
+	event := tailStub.events[0]
+	assert.Equal(t, config.LogTailTypeRequest, event.Type)
+	assert.Equal(t, userID.Hex(), event.UserID)
+	assert.Equal(t, fiber.MethodGet, event.Method)
+	assert.Equal(t, "/notice", event.Path)
+	// The status the error handler responds with, not the one set when the chain returned
+	assert.Equal(t, fiber.StatusNotFound, event.StatusCode)
+	assert.Equal(t, config.OperationStatusFailure, event.Status)
+}
+
+func TestLogTailFilter(t *testing.T) {
+	var (
+		userID     = primitive.NewObjectID().Hex()
+		entityType = config.EntityTypeNotice
+		status     = config.OperationStatusFailure
+		path       = "/api/v1/admin/"
+		event      = &entity.LogTailEvent{
+			Type: config.LogTailTypeOperation, UserID: userID, EntityType: entityType, Status: status,
+			Path: "/api/v1/admin/notice",
+		}
+	)
+	assert.True(t, (*sysservice.LogTailFilter)(nil).Match(event))
+	assert.True(t, (&sysservice.LogTailFilter{}).Match(event))
+	assert.True(
+		t, (&sysservice.LogTailFilter{UserID: &userID, EntityType: &entityType, Status: &status, Path: &path}).Match(event),
+	)
+
+	otherPath := "/api/v1/common/"
+	assert.False(t, (&sysservice.LogTailFilter{Path: &otherPath}).Match(event))
+	success := config.OperationStatusSuccess
+	assert.False(t, (&sysservice.LogTailFilter{Status: &success}).Match(event))
+	// Request events have no entity type
+	event.Type, event.EntityType = config.LogTailTypeRequest, ""
+	assert.False(t, (&sysservice.LogTailFilter{EntityType: &entityType}).Match(event))
+}
@@ -0,0 +1,118 @@
+package middleware_test
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"fiber-admin/internal/pkg/config"
+	"fiber-admin/internal/pkg/errors"
+	wares "fiber-admin/internal/pkg/middleware/mods"
+	apperrors "fiber-admin/pkg/errors"
+	"fiber-admin/pkg/redact"
+	logging "fiber-admin/pkg/zap"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// newLoggingApp returns an app logging its requests to the returned observer.
+func newLoggingApp(conf *config.Config) (*fiber.App, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	middleware := &wares.LoggingMiddleware{Zap: &logging.Zap{Logger: zap.New(core)}, Config: conf}
+	app := fiber.New(fiber.Config{ErrorHandler: errors.ErrorHandler})
+	middleware.Register(app)
+	app.Post(
+		"/auth/login", func(c *fiber.Ctx) error {
+			return c.JSON(fiber.Map{"code": 0, "data": fiber.Map{"access_token": "jwt", "user_id": "1"}})
+		},
+	)
+	app.Get(
+		"/notice", func(c *fiber.Ctx) error {
+			return c.SendString(strings.Repeat("a", 100))
+		},
+	)
+	app.Get(
+		"/notice/missing", func(c *fiber.Ctx) error {
+			return apperrors.NotFound(fmt.Errorf("notice not found"))
+		},
+	)
+	return app, logs
+}
+
+func newLoggingConfig() *config.Config {
+	conf := &config.Config{}
+	conf.MiddlewareConfig.LoggingConfig.RedactedFields = []string{"password", "access_token", "token"}
+	conf.MiddlewareConfig.LoggingConfig.RedactedHeaders = []string{"Authorization"}
+	conf.MiddlewareConfig.LoggingConfig.SuccessSampleRate = 1
+	return conf
+}
+
+func TestLoggingRedaction(t *testing.T) {
+	app, logs := newLoggingApp(newLoggingConfig())
+
+	req := httptest.NewRequest(
+		fiber.MethodPost, "/auth/login?token=abc&page=1", strings.NewReader(`{"email":"a@b.c","password":"hunter2"}`),
+	)
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer jwt")
+	_, err := app.Test(req)
+	assert.NoError(t, err)
+
+	entries := logs.FilterMessage("Request").All()
+	assert.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, `{"email":"a@b.c","password":"[REDACTED]"}`, fields["body"])
+	assert.Equal(t, `{"code":0,"data":{"access_token":"[REDACTED]","user_id":"1"}}`, fields["response"])
+	assert.Equal(t, "page=1&token=%5BREDACTED%5D", fields["query"])
+	assert.Equal(t, redact.Mask, fields["headers"].(map[string]string)[fiber.HeaderAuthorization])
+	for _, entry := range logs.All() {
+		for _, value := range entry.ContextMap() {
+			assert.NotContains(t, fmt.Sprint(value), "hunter2")
+		}
+	}
+}
+
+func TestLoggingRoutes(t *testing.T) {
+	conf := newLoggingConfig()
+	conf.MiddlewareConfig.LoggingConfig.RedactedBodyRoutes = []string{"post /auth/login"}
+	conf.MiddlewareConfig.LoggingConfig.ExcludedRoutes = []string{"GET   /notice"}
+	app, logs := newLoggingApp(conf)
+
+	_, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/auth/login", strings.NewReader(`hunter2`)))
+	assert.NoError(t, err)
+	_, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/notice", nil))
+	assert.NoError(t, err)
+
+	entries := logs.FilterMessage("Request").All()
+	assert.Len(t, entries, 1)
+	assert.Equal(t, redact.Mask, entries[0].ContextMap()["body"])
+	assert.Equal(t, redact.Mask, entries[0].ContextMap()["response"])
+}
+
+func TestLoggingTruncationAndSampling(t *testing.T) {
+	conf := newLoggingConfig()
+	conf.MiddlewareConfig.LoggingConfig.MaxBodySize = 10
+	app, logs := newLoggingApp(conf)
+
+	_, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/notice", nil))
+	assert.NoError(t, err)
+	entries := logs.FilterMessage("Request").TakeAll()
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "aaaaaaaaaa...(truncated, 100 bytes)", entries[0].ContextMap()["response"])
+
+	// Unsampled 2xx requests are dropped, failures are always logged
+	conf.MiddlewareConfig.LoggingConfig.SuccessSampleRate = 0
+	app, logs = newLoggingApp(conf)
+	_, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/notice", nil))
+	assert.NoError(t, err)
+	_, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/notice/missing", nil))
+	assert.NoError(t, err)
+	entries = logs.FilterMessage("Request").All()
+	assert.Len(t, entries, 1)
+	assert.Equal(t, zapcore.WarnLevel, entries[0].Level)
+	assert.Equal(t, int64(fiber.StatusNotFound), entries[0].ContextMap()["status"])
+}
@@ -0,0 +1,277 @@
+package middleware_test
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"fiber-admin/internal/pkg/config"
+	"fiber-admin/internal/pkg/errors"
+	wares "fiber-admin/internal/pkg/middleware/mods"
+	apperrors "fiber-admin/pkg/errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type operationLog struct {
+	userID, entityID                                      primitive.ObjectID
+	requestID, operation, entityType, description, status string
+}
+
+// logsServiceStub records the operation logs instead of caching them.
+type logsServiceStub struct {
+	logs []operationLog
+}
+
+func (l *logsServiceStub) InsertLoginLog(context.Context, *primitive.ObjectID, *string, *string) error {
+	return nil
+}
+
+func (l *logsServiceStub) CacheLoginLog(context.Context, *primitive.ObjectID, *string, *string) error {
+	return nil
+}
+
+func (l *logsServiceStub) InsertOperationLog(
+	ctx context.Context, userID, entityID *primitive.ObjectID,
+	ipAddress, userAgent, requestID, operation, entityType, description, status *string,
+) error {
+	return l.CacheOperationLog(
+		ctx, userID, entityID, ipAddress, userAgent, requestID, operation, entityType, description, status,
+	)
+}
+
+func (l *logsServiceStub) CacheOperationLog(
+	_ context.Context, userID, entityID *primitive.ObjectID, _, _, requestID *string,
+	operation, entityType, description, status *string,
+) error {
+	log := operationLog{
+		userID: *userID, requestID: *requestID, operation: *operation, entityType: *entityType,
+		description: *description, status: *status,
+	}
+	if entityID != nil {
+		log.entityID = *entityID
+	}
+	l.logs = append(l.logs, log)
+	return nil
+}
+
+func (l *logsServiceStub) ArchiveExpiredLogs(context.Context) error {
+	return nil
+}
+
+func (l *logsServiceStub) NotifySuspiciousLogins(context.Context) error {
+	return nil
+}
+
+func newApp(userID primitive.ObjectID, stub *logsServiceStub, handler fiber.Handler) *fiber.App {
+	middleware := &wares.OperationLogMiddleware{LogsService: stub}
+	app := fiber.New(fiber.Config{ErrorHandler: errors.ErrorHandler})
+	app.Use(
+		func(c *fiber.Ctx) error {
+			c.Locals(config.RequestIDKey, "request-id")
+			c.Locals(config.UserIDKey, userID.Hex())
+			return c.Next()
+		},
+	)
+	app.Post(
+		"/notice", middleware.OperationLog(config.OperationTypeCreate, config.EntityTypeNotice), handler,
+	)
+	app.Put(
+		"/notice", middleware.OperationLog(config.OperationTypeUpdate, config.EntityTypeNotice, "notice_id"), handler,
+	)
+	app.Delete(
+		"/notice", middleware.OperationLog(config.OperationTypeDelete, config.EntityTypeNotice, "noticeID"), handler,
+	)
+	app.Get(
+		"/user", middleware.OperationLog(config.OperationTypeRead, config.EntityTypeUser, "userID"), handler,
+	)
+	app.Get(
+		"/user/list", middleware.OperationLog(config.OperationTypeRead, config.EntityTypeUser), handler,
+	)
+	return app
+}
+
+func TestOperationLogEntityIDFromLocals(t *testing.T) {
+	var (
+		stub     = new(logsServiceStub)
+		userID   = primitive.NewObjectID()
+		noticeID = primitive.NewObjectID()
+	)
+	app := newApp(
+		userID, stub, func(c *fiber.Ctx) error {
+			c.Locals(config.OperationLogEntityIDKey, noticeID.Hex())
+			return c.SendStatus(fiber.StatusOK)
+		},
+	)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/notice", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Len(t, stub.logs, 1)
+	assert.Equal(t, userID, stub.logs[0].userID)
+	assert.Equal(t, noticeID, stub.logs[0].entityID)
+	assert.Equal(t, "request-id", stub.logs[0].requestID)
+	assert.Equal(t, config.OperationTypeCreate, stub.logs[0].operation)
+	assert.Equal(t, config.EntityTypeNotice, stub.logs[0].entityType)
+	assert.Equal(t, config.OperationStatusSuccess, stub.logs[0].status)
+}
+
+func TestOperationLogEntityIDFromRequest(t *testing.T) {
+	var (
+		stub     = new(logsServiceStub)
+		userID   = primitive.NewObjectID()
+		noticeID = primitive.NewObjectID()
+	)
+	app := newApp(
+		userID, stub, func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusOK)
+		},
+	)
+
+	req := httptest.NewRequest(fiber.MethodPut, "/notice", strings.NewReader(`{"notice_id":"`+noticeID.Hex()+`"}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	_, err := app.Test(req)
+	assert.NoError(t, err)
+
+	assert.Len(t, stub.logs, 1)
+	assert.Equal(t, noticeID, stub.logs[0].entityID)
+	assert.Equal(t, config.OperationStatusSuccess, stub.logs[0].status)
+}
+
+func TestOperationLogEntityIDFromDeleteQuery(t *testing.T) {
+	var (
+		stub     = new(logsServiceStub)
+		userID   = primitive.NewObjectID()
+		noticeID = primitive.NewObjectID()
+	)
+	app := newApp(
+		userID, stub, func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusOK)
+		},
+	)
+
+	// Delete requests send the ID as a query parameter, as DeleteNoticeRequest reads it
+	_, err := app.Test(httptest.NewRequest(fiber.MethodDelete, "/notice?noticeID="+noticeID.Hex(), nil))
+	assert.NoError(t, err)
+
+	assert.Len(t, stub.logs, 1)
+	assert.Equal(t, noticeID, stub.logs[0].entityID)
+	assert.Equal(t, config.OperationTypeDelete, stub.logs[0].operation)
+	assert.Equal(t, "DELETE notice: "+noticeID.Hex(), stub.logs[0].description)
+}
+
+func TestOperationLogFailure(t *testing.T) {
+	var (
+		stub   = new(logsServiceStub)
+		userID = primitive.NewObjectID()
+	)
+	app := newApp(
+		userID, stub, func(c *fiber.Ctx) error {
+			return apperrors.NotFound(fmt.Errorf("notice not found"))
+		},
+	)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodDelete, "/notice", nil))
+	assert.NoError(t, err)
+	// The error still reaches the error handler.
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	assert.Len(t, stub.logs, 1)
+	assert.Equal(t, primitive.NilObjectID, stub.logs[0].entityID)
+	assert.Equal(t, config.OperationStatusFailure, stub.logs[0].status)
+	assert.Contains(t, stub.logs[0].description, "notice not found")
+}
+
+// The operation log goes before the role check, so that denied attempts are recorded.
+func TestOperationLogDenied(t *testing.T) {
+	var (
+		stub   = new(logsServiceStub)
+		userID = primitive.NewObjectID()
+	)
+	// Denied as the role check of the app does, with an error
+	app := newApp(
+		userID, stub, func(c *fiber.Ctx) error {
+			return apperrors.PermissionDeny(fmt.Errorf("permission deny"))
+		},
+	)
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/notice", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+	assert.Len(t, stub.logs, 1)
+	assert.Equal(t, config.OperationStatusFailure, stub.logs[0].status)
+	assert.Contains(t, stub.logs[0].description, "CREATE notice failed (403)")
+
+	// Denied with a status only, as the default role check does
+	app = newApp(
+		userID, stub, func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusForbidden)
+		},
+	)
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodPost, "/notice", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+	assert.Len(t, stub.logs, 2)
+	assert.Equal(t, config.OperationStatusFailure, stub.logs[1].status)
+	assert.Equal(t, "CREATE notice failed (403)", stub.logs[1].description)
+}
+
+func TestOperationLogAccess(t *testing.T) {
+	var (
+		stub   = new(logsServiceStub)
+		userID = primitive.NewObjectID()
+	)
+	app := newApp(
+		userID, stub, func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusOK)
+		},
+	)
+
+	_, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/user/list?pageSize=10&page=1&query=a%26b", nil))
+	assert.NoError(t, err)
+	_, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/user?userID="+userID.Hex(), nil))
+	assert.NoError(t, err)
+
+	assert.Len(t, stub.logs, 2)
+	assert.Equal(t, config.OperationTypeRead, stub.logs[0].operation)
+	assert.Equal(t, config.EntityTypeUser, stub.logs[0].entityType)
+	// Filters are sorted and escaped so that they cannot be confused with each other.
+	assert.Equal(t, "READ user with filters page=1&pageSize=10&query=a%26b", stub.logs[0].description)
+	assert.Equal(t, userID, stub.logs[1].entityID)
+}
+
+func TestOperationLogAccessExcluded(t *testing.T) {
+	var (
+		stub   = new(logsServiceStub)
+		conf   = &config.Config{}
+		userID = primitive.NewObjectID()
+	)
+	conf.AuditConfig.AccessLog = true
+	conf.AuditConfig.AccessLogExcludedRoutes = []string{"get  /user/list"}
+	middleware := &wares.OperationLogMiddleware{LogsService: stub, Config: conf}
+	app := fiber.New()
+	app.Use(
+		func(c *fiber.Ctx) error {
+			c.Locals(config.UserIDKey, userID.Hex())
+			return c.Next()
+		},
+	)
+	handler := func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	}
+	app.Get("/user", middleware.OperationLog(config.OperationTypeRead, config.EntityTypeUser), handler)
+	app.Get("/user/list", middleware.OperationLog(config.OperationTypeRead, config.EntityTypeUser), handler)
+
+	_, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/user/list", nil))
+	assert.NoError(t, err)
+	assert.Empty(t, stub.logs)
+	_, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/user", nil))
+	assert.NoError(t, err)
+	assert.Len(t, stub.logs, 1)
+
+	conf.AuditConfig.AccessLog = false
+	_, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/user", nil))
+	assert.NoError(t, err)
+	assert.Len(t, stub.logs, 1)
+}
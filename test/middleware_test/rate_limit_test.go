@@ -0,0 +1,185 @@
+package middleware_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"fiber-admin/internal/pkg/config"
+	"fiber-admin/internal/pkg/config/mods/middleware"
+	"fiber-admin/internal/pkg/dao"
+	"fiber-admin/internal/pkg/domain/entity"
+	"fiber-admin/internal/pkg/errors"
+	wares "fiber-admin/internal/pkg/middleware/mods"
+	"fiber-admin/internal/pkg/service"
+	sysservice "fiber-admin/internal/pkg/service/sys/mods"
+	auth "fiber-admin/pkg/jwt"
+	logging "fiber-admin/pkg/zap"
+	"fiber-admin/test/mock"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// newRateLimitApp returns an app limiting every request to 2 per minute by IP, and the requests under /admin to 1
+// per minute by user and those under /keys to 1 per minute by API key.
+func newRateLimitApp(t *testing.T) (
+	*fiber.App, *wares.RateLimitMiddleware, sysservice.RateLimitService, *auth.Jwt,
+) {
+	conf := &config.Config{}
+	conf.CacheConfig.Namespace = "test"
+	conf.MiddlewareConfig.LimiterConfig = middleware.LimiterConfig{
+		Max:              2,
+		Expiration:       time.Minute,
+		APIKeyHeader:     "X-Api-Key",
+		OverrideCacheTTL: time.Minute,
+		Rules: []middleware.LimiterRule{
+			{Path: "/admin", Identity: config.RateLimitIdentityUser, Max: 1, Window: time.Minute},
+			{Path: "/keys/", Identity: config.RateLimitIdentityAPIKey, Max: 1, Window: time.Minute},
+		},
+	}
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	jwt, err := auth.New(privateKey, time.Hour, 2*time.Hour, time.Minute)
+	assert.NoError(t, err)
+
+	cache := dao.NewCache(dao.NewMemoryCacheBackend(), conf, nil)
+	rateLimitService := sysservice.NewRateLimitService(
+		&service.Core{Config: conf, Logger: zap.NewNop()}, cache, mock.NewRateLimitOverrideDaoMock(cache),
+	)
+	rateLimitMiddleware := &wares.RateLimitMiddleware{
+		RateLimitService: rateLimitService,
+		Jwt:              jwt,
+		Zap:              &logging.Zap{Logger: zap.NewNop()},
+		Config:           conf,
+	}
+	app := fiber.New(fiber.Config{ErrorHandler: errors.ErrorHandler})
+	rateLimitMiddleware.Register(app)
+	for _, path := range []string{"/notice", "/admin/notice", "/administrators", "/keys/list"} {
+		app.Get(
+			path, func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			},
+		)
+	}
+	return app, rateLimitMiddleware, rateLimitService, jwt
+}
+
+func testRateLimit(t *testing.T, app *fiber.App, req *http.Request, status int) *http.Response {
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, status, resp.StatusCode)
+	return resp
+}
+
+func newBearerRequest(t *testing.T, jwt *auth.Jwt, path, userID string) *http.Request {
+	token, err := jwt.GenerateAccessToken(userID)
+	assert.NoError(t, err)
+	req := httptest.NewRequest(fiber.MethodGet, path, nil)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+	return req
+}
+
+func TestRateLimitHeaders(t *testing.T) {
+	app, _, _, _ := newRateLimitApp(t)
+
+	resp := testRateLimit(t, app, httptest.NewRequest(fiber.MethodGet, "/notice", nil), fiber.StatusOK)
+	assert.Equal(t, "2", resp.Header.Get("RateLimit-Limit"))
+	assert.Equal(t, "1", resp.Header.Get("RateLimit-Remaining"))
+	reset, err := strconv.Atoi(resp.Header.Get("RateLimit-Reset"))
+	assert.NoError(t, err)
+	assert.True(t, reset > 0 && reset <= 60)
+	assert.Empty(t, resp.Header.Get(fiber.HeaderRetryAfter))
+
+	resp = testRateLimit(t, app, httptest.NewRequest(fiber.MethodGet, "/notice", nil), fiber.StatusOK)
+	assert.Equal(t, "0", resp.Header.Get("RateLimit-Remaining"))
+
+	resp = testRateLimit(t, app, httptest.NewRequest(fiber.MethodGet, "/notice", nil), fiber.StatusTooManyRequests)
+	assert.Equal(t, "0", resp.Header.Get("RateLimit-Remaining"))
+	assert.Equal(t, resp.Header.Get("RateLimit-Reset"), resp.Header.Get(fiber.HeaderRetryAfter))
+
+	// The rules of /admin do not apply to /administrators, which falls back to the default rule
+	testRateLimit(t, app, httptest.NewRequest(fiber.MethodGet, "/administrators", nil), fiber.StatusTooManyRequests)
+}
+
+func TestRateLimitIdentities(t *testing.T) {
+	app, _, _, jwt := newRateLimitApp(t)
+
+	// Each user has a limit of its own
+	testRateLimit(t, app, newBearerRequest(t, jwt, "/admin/notice", "user"), fiber.StatusOK)
+	testRateLimit(t, app, newBearerRequest(t, jwt, "/admin/notice", "user"), fiber.StatusTooManyRequests)
+	testRateLimit(t, app, newBearerRequest(t, jwt, "/admin/notice", "other"), fiber.StatusOK)
+
+	// Requests without a valid token are limited by IP
+	req := httptest.NewRequest(fiber.MethodGet, "/admin/notice", nil)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer invalid")
+	testRateLimit(t, app, req, fiber.StatusOK)
+	testRateLimit(t, app, httptest.NewRequest(fiber.MethodGet, "/admin/notice", nil), fiber.StatusTooManyRequests)
+
+	// Each API key has a limit of its own
+	for _, key := range []string{"a", "b"} {
+		req = httptest.NewRequest(fiber.MethodGet, "/keys/list", nil)
+		req.Header.Set("X-Api-Key", key)
+		testRateLimit(t, app, req, fiber.StatusOK)
+	}
+	req = httptest.NewRequest(fiber.MethodGet, "/keys/list", nil)
+	req.Header.Set("X-Api-Key", "a")
+	testRateLimit(t, app, req, fiber.StatusTooManyRequests)
+}
+
+func TestRateLimitOverride(t *testing.T) {
+	var (
+		ctx                           = context.Background()
+		app, _, rateLimitService, jwt = newRateLimitApp(t)
+	)
+	assert.Error(t, rateLimitService.SetOverride(ctx, &entity.RateLimitOverride{UserID: "user"}))
+	assert.NoError(t, rateLimitService.SetOverride(ctx, &entity.RateLimitOverride{UserID: "user", Max: 2}))
+	resp := testRateLimit(t, app, newBearerRequest(t, jwt, "/admin/notice", "user"), fiber.StatusOK)
+	assert.Equal(t, "2", resp.Header.Get("RateLimit-Limit"))
+	testRateLimit(t, app, newBearerRequest(t, jwt, "/admin/notice", "user"), fiber.StatusOK)
+	testRateLimit(t, app, newBearerRequest(t, jwt, "/admin/notice", "user"), fiber.StatusTooManyRequests)
+
+	assert.NoError(t, rateLimitService.SetOverride(ctx, &entity.RateLimitOverride{UserID: "user", Exempt: true}))
+	for i := 0; i < 3; i++ {
+		resp = testRateLimit(t, app, newBearerRequest(t, jwt, "/admin/notice", "user"), fiber.StatusOK)
+		assert.Empty(t, resp.Header.Get("RateLimit-Limit"))
+	}
+	overrides, err := rateLimitService.ListOverrides(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, overrides, 1)
+	assert.True(t, overrides[0].Exempt)
+
+	assert.NoError(t, rateLimitService.DeleteOverride(ctx, "user"))
+	testRateLimit(t, app, newBearerRequest(t, jwt, "/admin/notice", "user"), fiber.StatusTooManyRequests)
+	overrides, err = rateLimitService.ListOverrides(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, overrides)
+}
+
+func TestRateLimitSetLimiterConfig(t *testing.T) {
+	app, rateLimitMiddleware, _, _ := newRateLimitApp(t)
+
+	testRateLimit(t, app, httptest.NewRequest(fiber.MethodGet, "/notice", nil), fiber.StatusOK)
+
+	// The new limits apply from the next request on
+	rateLimitMiddleware.SetLimiterConfig(
+		middleware.LimiterConfig{
+			Max:        5,
+			Expiration: time.Hour,
+			Rules: []middleware.LimiterRule{
+				{Path: "/notice", Identity: config.RateLimitIdentityIP, Max: 1, Window: time.Hour},
+			},
+		},
+	)
+	resp := testRateLimit(t, app, httptest.NewRequest(fiber.MethodGet, "/notice", nil), fiber.StatusOK)
+	assert.Equal(t, "1", resp.Header.Get("RateLimit-Limit"))
+	testRateLimit(t, app, httptest.NewRequest(fiber.MethodGet, "/notice", nil), fiber.StatusTooManyRequests)
+	resp = testRateLimit(t, app, httptest.NewRequest(fiber.MethodGet, "/administrators", nil), fiber.StatusOK)
+	assert.Equal(t, "5", resp.Header.Get("RateLimit-Limit"))
+}
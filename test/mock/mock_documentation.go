@@ -3,6 +3,7 @@ package mock
 import (
 	"context"
 	"math/rand"
+	"strings"
 
 	"fiber-admin/internal/pkg/dao/mods"
 	"fiber-admin/internal/pkg/domain/entity"
@@ -50,7 +51,9 @@ func (m *DocumentationDaoMock) RandomDocumentationID() primitive.ObjectID {
 
 func (m *DocumentationDaoMock) GenerateDocumentationModel() *entity.DocumentationModel {
 	title, content := GenerateDocumentation()
-	documentationID, err := m.DocumentationDao.InsertDocumentation(context.Background(), title, content)
+	documentationID, err := m.DocumentationDao.InsertDocumentation(
+		context.Background(), title, content, strings.ToLower(title), nil, 0, "", nil,
+	)
 	if err != nil {
 		panic(err)
 	}
@@ -68,6 +71,6 @@ func GenerateDocumentation() (string, string) {
 
 func (m *DocumentationDaoMock) Delete() {
 	for _, documentationID := range m.DocumentationIDs {
-		_ = m.DocumentationDao.DeleteDocumentation(context.Background(), documentationID)
+		_ = m.DocumentationDao.DeleteDocumentation(context.Background(), documentationID, nil)
 	}
 }
@@ -66,7 +66,7 @@ func (m *NoticeDaoMock) GenerateNoticeModel() *entity.NoticeModel {
 
 func (m *NoticeDaoMock) Delete() {
 	for _, noticeID := range m.NoticeIDs {
-		_ = m.NoticeDao.DeleteNotice(context.Background(), noticeID)
+		_ = m.NoticeDao.DeleteNotice(context.Background(), noticeID, nil)
 	}
 }
 
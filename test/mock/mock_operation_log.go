@@ -50,7 +50,8 @@ func (m *OperationLogDaoMock) GenerateOperationLogWithUserID(userID primitive.Ob
 	ipAddress, userAgent, operation, description, status := GenerateOperationLog()
 	entityID, entityType := RandomEntity(m.NoticeMock, m.DocumentationMock, m.UserMock)
 	operationLogID, err := m.OperationLogDao.InsertOperationLog(
-		context.Background(), userID, entityID, ipAddress, userAgent, operation, entityType, description, status,
+		context.Background(), userID, entityID, ipAddress, userAgent, RandomString(16), operation, entityType,
+		description, status,
 	)
 	if err != nil {
 		panic(err)
@@ -68,7 +69,8 @@ func (m *OperationLogDaoMock) GenerateOperationLogWithIpAddress(ipAddress string
 	_, userAgent, operation, description, status := GenerateOperationLog()
 	entityID, entityType := RandomEntity(m.NoticeMock, m.DocumentationMock, m.UserMock)
 	operationLogID, err := m.OperationLogDao.InsertOperationLog(
-		context.Background(), userID, entityID, ipAddress, userAgent, operation, entityType, description, status,
+		context.Background(), userID, entityID, ipAddress, userAgent, RandomString(16), operation, entityType,
+		description, status,
 	)
 	if err != nil {
 		panic(err)
@@ -86,7 +88,8 @@ func (m *OperationLogDaoMock) GenerateOperationLogWithEntityID(entityID primitiv
 	ipAddress, userAgent, operation, description, status := GenerateOperationLog()
 	entityType := RandomEnum([]string{"NOTICE", "DOCUMENTATION", "USER"})
 	operationLogID, err := m.OperationLogDao.InsertOperationLog(
-		context.Background(), userID, entityID, ipAddress, userAgent, operation, entityType, description, status,
+		context.Background(), userID, entityID, ipAddress, userAgent, RandomString(16), operation, entityType,
+		description, status,
 	)
 	if err != nil {
 		panic(err)
@@ -120,7 +123,8 @@ func (m *OperationLogDaoMock) GenerateOperationLogModel() *entity.OperationLogMo
 	ipAddress, userAgent, operation, description, status := GenerateOperationLog()
 	entityID, entityType := RandomEntity(m.NoticeMock, m.DocumentationMock, m.UserMock)
 	operationLogID, err := m.OperationLogDao.InsertOperationLog(
-		context.Background(), userID, entityID, ipAddress, userAgent, operation, entityType, description, status,
+		context.Background(), userID, entityID, ipAddress, userAgent, RandomString(16), operation, entityType,
+		description, status,
 	)
 	if err != nil {
 		panic(err)
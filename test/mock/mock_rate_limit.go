@@ -0,0 +1,59 @@
+package mock
+
+import (
+	"context"
+	"sort"
+
+	"fiber-admin/internal/pkg/config"
+	"fiber-admin/internal/pkg/dao"
+	"fiber-admin/internal/pkg/domain/entity"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// RateLimitOverrideDaoMock keeps the overrides in memory, invalidating the cache on changes as the DAO does.
+type RateLimitOverrideDaoMock struct {
+	OverrideMap map[string]entity.RateLimitOverride
+	Cache       *dao.Cache
+}
+
+func NewRateLimitOverrideDaoMock(cache *dao.Cache) *RateLimitOverrideDaoMock {
+	return &RateLimitOverrideDaoMock{
+		OverrideMap: make(map[string]entity.RateLimitOverride),
+		Cache:       cache,
+	}
+}
+
+func (m *RateLimitOverrideDaoMock) GetRateLimitOverride(
+	_ context.Context, userID string,
+) (*entity.RateLimitOverride, error) {
+	override, ok := m.OverrideMap[userID]
+	if !ok {
+		return nil, mongo.ErrNoDocuments
+	}
+	return &override, nil
+}
+
+func (m *RateLimitOverrideDaoMock) GetRateLimitOverrideList(_ context.Context) ([]entity.RateLimitOverride, error) {
+	overrideList := make([]entity.RateLimitOverride, 0, len(m.OverrideMap))
+	for _, override := range m.OverrideMap {
+		overrideList = append(overrideList, override)
+	}
+	sort.Slice(
+		overrideList, func(i, j int) bool {
+			return overrideList[i].UserID < overrideList[j].UserID
+		},
+	)
+	return overrideList, nil
+}
+
+func (m *RateLimitOverrideDaoMock) UpsertRateLimitOverride(
+	ctx context.Context, override *entity.RateLimitOverride,
+) error {
+	m.OverrideMap[override.UserID] = *override
+	return m.Cache.Invalidate(ctx, config.RateLimitOverrideCachePrefix)
+}
+
+func (m *RateLimitOverrideDaoMock) DeleteRateLimitOverride(ctx context.Context, userID string) error {
+	delete(m.OverrideMap, userID)
+	return m.Cache.Invalidate(ctx, config.RateLimitOverrideCachePrefix)
+}
@@ -72,7 +72,7 @@ func (m *UserDaoMock) GenerateUserModel() *entity.UserModel {
 
 func (m *UserDaoMock) Delete() {
 	for _, userID := range m.UserIDs {
-		_ = m.UserDao.DeleteUser(context.Background(), userID)
+		_ = m.UserDao.DeleteUser(context.Background(), userID, nil)
 	}
 }
 
@@ -0,0 +1,50 @@
+package service_test
+
+import (
+	"testing"
+
+	"fiber-admin/internal/pkg/config"
+	"fiber-admin/internal/pkg/dao"
+	"fiber-admin/test/wire"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWarmAndPurgeCache(t *testing.T) {
+	var (
+		injector     = wire.GetInjector()
+		ctx          = injector.Ctx
+		cacheService = injector.AdminCacheService
+	)
+	warmed, err := cacheService.WarmCache(ctx, nil)
+	assert.NoError(t, err)
+	assert.Len(t, warmed.Warmed, 2)
+	for _, entity := range warmed.Warmed {
+		assert.Positive(t, entity.Lists)
+	}
+
+	stats, err := cacheService.GetCacheStats(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, injector.Config.CacheConfig.Namespace, stats.Namespace)
+	keys := make(map[string]int64)
+	for _, prefix := range stats.Prefixes {
+		keys[prefix.Prefix] = prefix.Keys
+	}
+	assert.Positive(t, keys[config.NoticeCachePrefix])
+	assert.Positive(t, keys[config.DocumentationCachePrefix])
+	assert.Contains(t, keys, dao.CacheOtherPrefix)
+
+	entity := config.CacheEntityNotice
+	purged, err := cacheService.PurgeCache(ctx, &entity, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, config.NoticeCachePrefix, purged.Prefix)
+	assert.Positive(t, purged.Deleted)
+
+	// Only the DAO caches can be purged by prefix
+	prefix := config.DocumentationCachePrefix
+	purged, err = cacheService.PurgeCache(ctx, nil, &prefix)
+	assert.NoError(t, err)
+	assert.Positive(t, purged.Deleted)
+	prefix = config.TokenBlacklistCachePrefix
+	_, err = cacheService.PurgeCache(ctx, nil, &prefix)
+	assert.Error(t, err)
+}
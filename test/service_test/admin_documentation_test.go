@@ -3,8 +3,12 @@ package service_test
 import (
 	"testing"
 
+	"fiber-admin/internal/pkg/domain/vo/common"
+	"fiber-admin/pkg/errors"
+	"fiber-admin/pkg/utils/slug"
 	"fiber-admin/test/mock"
 	"fiber-admin/test/wire"
+	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -17,7 +21,9 @@ func TestInsertDocumentation(t *testing.T) {
 		title                = mock.RandomString(10)
 		content              = mock.RandomString(10)
 	)
-	documentationIDHex, err := documentationService.InsertDocumentation(ctx, &title, &content)
+	documentationIDHex, err := documentationService.InsertDocumentation(
+		ctx, &title, &content, nil, nil, nil, nil, nil,
+	)
 	assert.NoError(t, err)
 	assert.NotNil(t, documentationIDHex)
 
@@ -42,8 +48,11 @@ func TestUpdateDocumentation(t *testing.T) {
 		documentationID      = injector.DocumentationDaoMock.RandomDocumentationID()
 		title                = mock.RandomString(10)
 		content              = mock.RandomString(10)
+		summary              = mock.RandomString(10)
+	)
+	err := documentationService.UpdateDocumentation(
+		ctx, &documentationID, nil, &title, &content, nil, nil, nil, &summary,
 	)
-	err := documentationService.UpdateDocumentation(ctx, &documentationID, &title, &content)
 	assert.NoError(t, err)
 
 	documentation, err := injector.DocumentationDao.GetDocumentationByID(ctx, documentationID)
@@ -55,6 +64,136 @@ func TestUpdateDocumentation(t *testing.T) {
 	t.Logf("Documentation Data: %+v", documentation)
 }
 
+func TestDocumentationRevision(t *testing.T) {
+	var (
+		injector             = wire.GetInjector()
+		ctx                  = injector.Ctx
+		documentationService = injector.AdminDocumentationService
+		title                = mock.RandomString(10)
+		content              = mock.RandomString(10)
+		newContent           = mock.RandomString(10)
+		summary              = mock.RandomString(10)
+		page                 = int64(1)
+		pageSize             = int64(10)
+		desc                 = true
+		fromRevision         = int64(1)
+		toRevision           = int64(2)
+	)
+	documentationIDHex, err := documentationService.InsertDocumentation(
+		ctx, &title, &content, nil, nil, nil, nil, nil,
+	)
+	assert.NoError(t, err)
+	documentationID, err := primitive.ObjectIDFromHex(documentationIDHex)
+	assert.NoError(t, err)
+
+	err = documentationService.UpdateDocumentation(
+		ctx, &documentationID, nil, nil, &newContent, nil, nil, nil, &summary,
+	)
+	assert.NoError(t, err)
+
+	revisionList, err := documentationService.GetDocumentationRevisionList(ctx, &documentationID, &page, &pageSize, &desc)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), revisionList.Total)
+	assert.Equal(t, summary, revisionList.DocumentationRevisionSummaryList[0].Summary)
+
+	diff, err := documentationService.DiffDocumentationRevision(ctx, &documentationID, &fromRevision, &toRevision)
+	assert.NoError(t, err)
+	assert.Contains(t, diff.Diff, "-"+content)
+	assert.Contains(t, diff.Diff, "+"+newContent)
+
+	// A restore based on a version which is no longer current is rejected
+	staleVersion := int64(1)
+	_, err = documentationService.RestoreDocumentationRevision(
+		ctx, &documentationID, &staleVersion, &fromRevision, nil,
+	)
+	var appErr *errors.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, fiber.StatusPreconditionFailed, appErr.Status())
+
+	currentVersion := int64(2)
+	restored, err := documentationService.RestoreDocumentationRevision(
+		ctx, &documentationID, &currentVersion, &fromRevision, nil,
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), restored.Revision)
+
+	documentation, err := injector.DocumentationDao.GetDocumentationByID(ctx, documentationID)
+	assert.NoError(t, err)
+	assert.Equal(t, content, documentation.Content)
+
+	t.Logf("Diff: %s", diff.Diff)
+}
+
+func TestDocumentationTree(t *testing.T) {
+	var (
+		injector             = wire.GetInjector()
+		ctx                  = injector.Ctx
+		documentationService = injector.AdminDocumentationService
+		parentTitle          = "Guide " + mock.RandomString(10)
+		childTitle           = "Install " + mock.RandomString(10)
+		content              = mock.RandomString(10)
+		category             = "guide"
+		tags                 = []string{"setup"}
+		first                = int64(0)
+	)
+	parentIDHex, err := documentationService.InsertDocumentation(
+		ctx, &parentTitle, &content, nil, nil, nil, &category, tags,
+	)
+	assert.NoError(t, err)
+	parentID, _ := primitive.ObjectIDFromHex(parentIDHex)
+	parent, err := injector.DocumentationDao.GetDocumentationByID(ctx, parentID)
+	assert.NoError(t, err)
+	assert.Equal(t, slug.Make(parentTitle), parent.Slug)
+
+	// A second documentation with the same title gets a suffixed slug.
+	siblingIDHex, err := documentationService.InsertDocumentation(
+		ctx, &parentTitle, &content, nil, nil, nil, nil, nil,
+	)
+	assert.NoError(t, err)
+	siblingID, _ := primitive.ObjectIDFromHex(siblingIDHex)
+	sibling, err := injector.DocumentationDao.GetDocumentationByID(ctx, siblingID)
+	assert.NoError(t, err)
+	assert.Equal(t, slug.Make(parentTitle)+"-2", sibling.Slug)
+
+	childIDHex, err := documentationService.InsertDocumentation(
+		ctx, &childTitle, &content, nil, &parentID, nil, nil, nil,
+	)
+	assert.NoError(t, err)
+	childID, _ := primitive.ObjectIDFromHex(childIDHex)
+
+	// Moving a documentation under its own descendant is a cycle.
+	err = documentationService.MoveDocumentation(ctx, &parentID, &childID, &first)
+	assert.Error(t, err)
+	err = documentationService.DeleteDocumentation(ctx, &parentID, nil)
+	assert.Error(t, err)
+
+	err = documentationService.MoveDocumentation(ctx, &siblingID, &parentID, &first)
+	assert.NoError(t, err)
+	tree, err := injector.CommonDocumentationService.GetDocumentationTree(ctx)
+	assert.NoError(t, err)
+	var parentNode *common.DocumentationTreeNode
+	for _, node := range tree.DocumentationTree {
+		if node.DocumentID == parentIDHex {
+			parentNode = node
+		}
+	}
+	assert.NotNil(t, parentNode)
+	assert.Len(t, parentNode.Children, 2)
+	assert.Equal(t, siblingIDHex, parentNode.Children[0].DocumentID)
+	assert.Equal(t, childIDHex, parentNode.Children[1].DocumentID)
+
+	err = documentationService.ReorderDocumentation(ctx, &parentID, []primitive.ObjectID{childID})
+	assert.Error(t, err)
+	err = documentationService.ReorderDocumentation(ctx, &parentID, []primitive.ObjectID{childID, siblingID})
+	assert.NoError(t, err)
+	resp, err := injector.CommonDocumentationService.GetDocumentation(ctx, nil, &sibling.Slug, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, parentIDHex, resp.ParentID)
+	assert.Equal(t, int64(1), resp.Position)
+
+	t.Logf("Documentation Tree: %+v", tree)
+}
+
 func TestDeleteDocumentation(t *testing.T) {
 	var (
 		injector             = wire.GetInjector()
@@ -62,7 +201,7 @@ func TestDeleteDocumentation(t *testing.T) {
 		documentationService = injector.AdminDocumentationService
 		documentationID      = injector.DocumentationDaoMock.RandomDocumentationID()
 	)
-	err := documentationService.DeleteDocumentation(ctx, &documentationID)
+	err := documentationService.DeleteDocumentation(ctx, &documentationID, nil)
 	assert.NoError(t, err)
 
 	documentation, err := injector.DocumentationDao.GetDocumentationByID(ctx, documentationID)
@@ -1,12 +1,17 @@
 package service_test
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 	"time"
 
+	"fiber-admin/internal/pkg/config"
+	"fiber-admin/internal/pkg/domain/entity"
 	"fiber-admin/test/mock"
 	"fiber-admin/test/wire"
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 func TestGetLoginLogList(t *testing.T) {
@@ -63,3 +68,138 @@ func TestGetOperationLogList(t *testing.T) {
 
 	t.Logf("Response Data: %+v", resp)
 }
+
+func TestExportLoginLogList(t *testing.T) {
+	var (
+		injector    = wire.GetInjector()
+		ctx         = injector.Ctx
+		logsService = injector.AdminLogsService
+		desc        = true
+	)
+	for _, format := range []string{config.ExportFormatCSV, config.ExportFormatNDJSON} {
+		var buf bytes.Buffer
+		err := logsService.ExportLoginLogList(ctx, &buf, &format, &desc, nil, nil, nil)
+		assert.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if format == config.ExportFormatCSV {
+			assert.True(t, strings.HasPrefix(lines[0], "login_log_id,"), "missing csv header")
+			lines = lines[1:]
+		}
+		assert.NotEmpty(t, lines)
+	}
+}
+
+func TestExportOperationLogList(t *testing.T) {
+	var (
+		injector    = wire.GetInjector()
+		ctx         = injector.Ctx
+		logsService = injector.AdminLogsService
+		desc        = false
+		status      = "SUCCESS"
+	)
+	for _, format := range []string{config.ExportFormatCSV, config.ExportFormatNDJSON} {
+		var buf bytes.Buffer
+		err := logsService.ExportOperationLogList(ctx, &buf, &format, &desc, nil, nil, nil, &status, nil, nil)
+		assert.NoError(t, err)
+		if format == config.ExportFormatCSV {
+			assert.True(t, strings.HasPrefix(buf.String(), "operation_log_id,"), "missing csv header")
+		}
+	}
+}
+
+func TestVerifyOperationLogChain(t *testing.T) {
+	var (
+		injector        = wire.GetInjector()
+		ctx             = injector.Ctx
+		logsService     = injector.AdminLogsService
+		operationLogDao = injector.OperationLogDao
+		collection      = injector.Mongo.Client().Database(injector.Mongo.DatabaseName).Collection(
+			config.OperationLogCollectionName,
+		)
+		auditConfig = injector.Config.AuditConfig
+	)
+	defer func() {
+		injector.Config.AuditConfig = auditConfig
+	}()
+	injector.Config.AuditConfig.CheckpointSecret = "test-checkpoint-secret"
+	checkpoint, err := operationLogDao.InsertOperationLogCheckpoint(ctx)
+	assert.NoError(t, err)
+	assert.NotNil(t, checkpoint)
+
+	resp, err := logsService.VerifyOperationLogChain(ctx)
+	assert.NoError(t, err)
+	assert.True(t, resp.Valid)
+	assert.Nil(t, resp.BrokenLink)
+	assert.Equal(t, checkpoint.Sequence, resp.LastSequence)
+	assert.NotZero(t, resp.Checked)
+
+	// Editing a record breaks the chain at that record.
+	var operationLog entity.OperationLogModel
+	assert.NoError(t, collection.Find(ctx, bson.M{"sequence": 1}).One(&operationLog))
+	assert.NoError(
+		t, collection.UpdateOne(ctx, bson.M{"_id": operationLog.OperationLogID}, bson.M{"$set": bson.M{"status": "X"}}),
+	)
+	resp, err = logsService.VerifyOperationLogChain(ctx)
+	assert.NoError(t, err)
+	assert.False(t, resp.Valid)
+	assert.Equal(t, int64(1), resp.BrokenLink.Sequence)
+	assert.Equal(t, operationLog.OperationLogID.Hex(), resp.BrokenLink.OperationLogID)
+	assert.NoError(
+		t, collection.UpdateOne(
+			ctx, bson.M{"_id": operationLog.OperationLogID}, bson.M{"$set": bson.M{"status": operationLog.Status}},
+		),
+	)
+
+	// Removing a record breaks the chain at the missing sequence.
+	assert.NoError(t, collection.Find(ctx, bson.M{"sequence": 2}).One(&operationLog))
+	assert.NoError(t, collection.RemoveId(ctx, operationLog.OperationLogID))
+	resp, err = logsService.VerifyOperationLogChain(ctx)
+	assert.NoError(t, err)
+	assert.False(t, resp.Valid)
+	assert.Equal(t, int64(2), resp.BrokenLink.Sequence)
+	_, err = collection.InsertOne(ctx, operationLog)
+	assert.NoError(t, err)
+
+	resp, err = logsService.VerifyOperationLogChain(ctx)
+	assert.NoError(t, err)
+	assert.True(t, resp.Valid)
+}
+
+func TestVerifyOperationLogChainPruned(t *testing.T) {
+	var (
+		injector        = wire.GetInjector()
+		ctx             = injector.Ctx
+		logsService     = injector.AdminLogsService
+		operationLogDao = injector.OperationLogDao
+		collection      = injector.Mongo.Client().Database(injector.Mongo.DatabaseName).Collection(
+			config.OperationLogCollectionName,
+		)
+		auditConfig = injector.Config.AuditConfig
+	)
+	defer func() {
+		injector.Config.AuditConfig = auditConfig
+	}()
+	injector.Config.AuditConfig.CheckpointSecret = "test-checkpoint-secret"
+	checkpoint, err := operationLogDao.InsertOperationLogCheckpoint(ctx)
+	assert.NoError(t, err)
+	assert.NotNil(t, checkpoint)
+
+	// Pruning every record up to the head checkpoint leaves an intact chain.
+	var operationLogList []entity.OperationLogModel
+	filter := bson.M{"sequence": bson.M{"$gt": 0, "$lte": checkpoint.Sequence}}
+	assert.NoError(t, collection.Find(ctx, filter).All(&operationLogList))
+	_, err = collection.RemoveAll(ctx, filter)
+	assert.NoError(t, err)
+	defer func() {
+		for _, operationLog := range operationLogList {
+			_, _ = collection.InsertOne(ctx, operationLog)
+		}
+	}()
+
+	resp, err := logsService.VerifyOperationLogChain(ctx)
+	assert.NoError(t, err)
+	assert.True(t, resp.Valid)
+	assert.Nil(t, resp.BrokenLink)
+	assert.Zero(t, resp.Checked)
+	assert.Equal(t, checkpoint.Sequence, resp.LastSequence)
+}
@@ -3,8 +3,10 @@ package service_test
 import (
 	"testing"
 
+	"fiber-admin/pkg/errors"
 	"fiber-admin/test/mock"
 	"fiber-admin/test/wire"
+	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -44,16 +46,26 @@ func TestUpdateNotice(t *testing.T) {
 		content       = mock.RandomString(10)
 		noticeType    = mock.RandomEnum([]string{"NORMAL", "URGENT"})
 	)
-	err := noticeService.UpdateNotice(ctx, &noticeID, &title, &content, &noticeType)
+	notice, err := injector.NoticeDao.GetNoticeByID(ctx, noticeID)
 	assert.NoError(t, err)
+	version := notice.Version
 
-	notice, err := injector.NoticeDao.GetNoticeByID(ctx, noticeID)
+	err = noticeService.UpdateNotice(ctx, &noticeID, &version, &title, &content, &noticeType)
+	assert.NoError(t, err)
+
+	notice, err = injector.NoticeDao.GetNoticeByID(ctx, noticeID)
 	assert.NoError(t, err)
 	assert.NotNil(t, notice)
 	assert.Equal(t, title, notice.Title)
 	assert.Equal(t, content, notice.Content)
 	assert.Equal(t, noticeType, notice.NoticeType)
 
+	// A second update based on the same version lost the race.
+	err = noticeService.UpdateNotice(ctx, &noticeID, &version, &title, &content, &noticeType)
+	var appErr *errors.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, fiber.StatusPreconditionFailed, appErr.Status())
+
 	t.Logf("Notice Data: %+v", notice)
 }
 
@@ -64,7 +76,7 @@ func TestDeleteNotice(t *testing.T) {
 		noticeService = injector.AdminNoticeService
 		noticeID      = injector.NoticeDaoMock.RandomNoticeID()
 	)
-	err := noticeService.DeleteNotice(ctx, &noticeID)
+	err := noticeService.DeleteNotice(ctx, &noticeID, nil)
 	assert.NoError(t, err)
 
 	notice, err := injector.NoticeDao.GetNoticeByID(ctx, noticeID)
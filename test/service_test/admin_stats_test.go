@@ -0,0 +1,81 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"fiber-admin/internal/pkg/config"
+	"fiber-admin/test/wire"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetUserStats(t *testing.T) {
+	var (
+		injector     = wire.GetInjector()
+		ctx          = injector.Ctx
+		statsService = injector.AdminStatsService
+	)
+	resp, err := statsService.GetUserStats(ctx)
+	assert.NoError(t, err)
+	var total int64
+	for _, count := range resp.ByRole {
+		total += count.Count
+	}
+	assert.Equal(t, resp.Total, total)
+}
+
+func TestGetNewUserStats(t *testing.T) {
+	var (
+		injector     = wire.GetInjector()
+		ctx          = injector.Ctx
+		statsService = injector.AdminStatsService
+		bucket       = config.StatsBucketDay
+		endTime      = time.Date(2024, 3, 8, 12, 0, 0, 0, time.UTC)
+		startTime    = endTime.AddDate(0, 0, -7)
+	)
+	resp, err := statsService.GetNewUserStats(ctx, &bucket, &startTime, &endTime)
+	assert.NoError(t, err)
+	// Every day from March 1st to 8th, empty ones included
+	assert.Len(t, resp.Series, 8)
+	assert.Equal(t, "2024-03-01T00:00:00Z", resp.Series[0].Bucket)
+
+	bucket = config.StatsBucketHour
+	startTime = endTime.AddDate(-1, 0, 0)
+	_, err = statsService.GetNewUserStats(ctx, &bucket, &startTime, &endTime)
+	assert.Error(t, err, "too many buckets")
+}
+
+func TestGetActiveUserStats(t *testing.T) {
+	var (
+		injector     = wire.GetInjector()
+		ctx          = injector.Ctx
+		statsService = injector.AdminStatsService
+	)
+	resp, err := statsService.GetActiveUserStats(ctx, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, config.StatsBucketDay, resp.Bucket)
+	assert.NotEmpty(t, resp.Series)
+}
+
+func TestGetOperationStats(t *testing.T) {
+	var (
+		injector     = wire.GetInjector()
+		ctx          = injector.Ctx
+		statsService = injector.AdminStatsService
+		bucket       = config.StatsBucketMonth
+	)
+	resp, err := statsService.GetOperationStats(ctx, &bucket, nil, nil)
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+}
+
+func TestGetContentStats(t *testing.T) {
+	var (
+		injector     = wire.GetInjector()
+		ctx          = injector.Ctx
+		statsService = injector.AdminStatsService
+	)
+	resp, err := statsService.GetContentStats(ctx)
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+}
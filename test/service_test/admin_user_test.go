@@ -68,7 +68,7 @@ func TestUpdateUser(t *testing.T) {
 		email        = mock.RandomString(10) + "@user.com"
 		organization = mock.RandomString(10)
 	)
-	err := userService.UpdateUser(ctx, &userID, &username, &email, &organization)
+	err := userService.UpdateUser(ctx, &userID, nil, &username, &email, &organization)
 	assert.NoError(t, err)
 
 	user, err := injector.UserDao.GetUserByID(ctx, userID)
@@ -88,7 +88,7 @@ func TestDeleteUser(t *testing.T) {
 		userID      = injector.UserDaoMock.RandomUserID()
 	)
 
-	err := userService.DeleteUser(ctx, &userID)
+	err := userService.DeleteUser(ctx, &userID, nil)
 	assert.NoError(t, err)
 
 	user, err := injector.UserDao.GetUserByID(ctx, userID)
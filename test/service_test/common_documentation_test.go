@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"fiber-admin/internal/pkg/config"
 	"fiber-admin/test/wire"
 	"github.com/stretchr/testify/assert"
 )
@@ -14,11 +15,18 @@ func TestGetDocumentation(t *testing.T) {
 		ctx                  = injector.Ctx
 		documentationService = injector.CommonDocumentationService
 		documentationID      = injector.DocumentationDaoMock.RandomDocumentationID()
+		format               = config.ContentFormatHTML
 	)
 
-	resp, err := documentationService.GetDocumentation(ctx, &documentationID)
+	resp, err := documentationService.GetDocumentation(ctx, &documentationID, nil, nil)
 	assert.NoError(t, err)
 	assert.NotNil(t, resp)
+	assert.Equal(t, config.ContentFormatMarkdown, resp.Format)
+
+	resp, err = documentationService.GetDocumentation(ctx, &documentationID, nil, &format)
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+	assert.Equal(t, config.ContentFormatHTML, resp.Format)
 	t.Logf("Response Data: %+v", resp)
 }
 
@@ -33,11 +41,13 @@ func TestGetDocumentationList(t *testing.T) {
 		updateEndTime        = time.Now()
 	)
 
-	resp, err := documentationService.GetDocumentationList(ctx, &page, &pageSize, &updateStartTime, &updateEndTime)
+	resp, err := documentationService.GetDocumentationList(
+		ctx, &page, &pageSize, &updateStartTime, &updateEndTime, nil, nil,
+	)
 	assert.NoError(t, err)
 	assert.NotNil(t, resp)
 
-	resp, err = documentationService.GetDocumentationList(ctx, &page, &pageSize, nil, nil)
+	resp, err = documentationService.GetDocumentationList(ctx, &page, &pageSize, nil, nil, nil, nil)
 	assert.NoError(t, err)
 	assert.NotNil(t, resp)
 	assert.NotEmpty(t, resp.DocumentationSummaryList)
@@ -3,6 +3,7 @@ package service_test
 import (
 	"testing"
 
+	"fiber-admin/internal/pkg/domain/entity"
 	"fiber-admin/test/wire"
 	"github.com/stretchr/testify/assert"
 )
@@ -19,16 +20,37 @@ func TestGenerateIdempotencyToken(t *testing.T) {
 	t.Logf("Response Data: %+v", resp)
 }
 
-func TestCheckIdempotencyToken(t *testing.T) {
+func TestIdempotentRequest(t *testing.T) {
 	var (
 		injector           = wire.GetInjector()
 		ctx                = injector.Ctx
 		idempotencyService = injector.CommonIdempotencyService
 	)
-	token, err := idempotencyService.GenerateIdempotencyToken(ctx)
+	key, err := idempotencyService.GenerateIdempotencyToken(ctx)
 	assert.NoError(t, err)
-	assert.NotNil(t, token)
 
-	err = idempotencyService.CheckIdempotencyToken(ctx, token)
+	record, err := idempotencyService.BeginIdempotentRequest(ctx, key, "fingerprint")
 	assert.NoError(t, err)
+	assert.Nil(t, record)
+	// In flight
+	_, err = idempotencyService.BeginIdempotentRequest(ctx, key, "fingerprint")
+	assert.Error(t, err)
+
+	err = idempotencyService.CompleteIdempotentRequest(
+		ctx, key, &entity.IdempotencyRecord{Fingerprint: "fingerprint", Status: 200, Body: []byte("body")},
+	)
+	assert.NoError(t, err)
+	record, err = idempotencyService.BeginIdempotentRequest(ctx, key, "fingerprint")
+	assert.NoError(t, err)
+	assert.Equal(t, 200, record.Status)
+	assert.Equal(t, []byte("body"), record.Body)
+	// Reused for a different request
+	_, err = idempotencyService.BeginIdempotentRequest(ctx, key, "other")
+	assert.Error(t, err)
+
+	assert.NoError(t, idempotencyService.ReleaseIdempotentRequest(ctx, key))
+	record, err = idempotencyService.BeginIdempotentRequest(ctx, key, "other")
+	assert.NoError(t, err)
+	assert.Nil(t, record)
+	assert.NoError(t, idempotencyService.ReleaseIdempotentRequest(ctx, key))
 }
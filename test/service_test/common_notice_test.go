@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"fiber-admin/internal/pkg/config"
 	"fiber-admin/test/wire"
 	"github.com/stretchr/testify/assert"
 )
@@ -14,11 +15,18 @@ func TestGetNotice(t *testing.T) {
 		ctx           = injector.Ctx
 		noticeService = injector.CommonNoticeService
 		noticeID      = injector.NoticeDaoMock.RandomNoticeID()
+		format        = config.ContentFormatHTML
 	)
 
-	resp, err := noticeService.GetNotice(ctx, &noticeID)
+	resp, err := noticeService.GetNotice(ctx, &noticeID, nil)
 	assert.NoError(t, err)
 	assert.NotNil(t, resp)
+	assert.Equal(t, config.ContentFormatMarkdown, resp.Format)
+
+	resp, err = noticeService.GetNotice(ctx, &noticeID, &format)
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+	assert.Equal(t, config.ContentFormatHTML, resp.Format)
 
 	t.Logf("Response Data: %+v", resp)
 }
@@ -0,0 +1,42 @@
+package service_test
+
+import (
+	"testing"
+
+	"fiber-admin/internal/pkg/config"
+	"fiber-admin/test/wire"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearch(t *testing.T) {
+	var (
+		injector      = wire.GetInjector()
+		ctx           = injector.Ctx
+		searchService = injector.CommonSearchService
+		notice, _     = injector.NoticeDaoMock.Get(injector.NoticeDaoMock.RandomNoticeID())
+		page          = int64(1)
+		pageSize      = int64(10)
+		scopeNotice   = config.EntityTypeNotice
+		scopeDoc      = config.EntityTypeDocumentation
+	)
+
+	resp, err := searchService.Search(ctx, notice.Title, nil, &page, &pageSize)
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+	assert.NotEmpty(t, resp.SearchResultList)
+	assert.Equal(t, notice.NoticeID.Hex(), resp.SearchResultList[0].EntityID)
+	assert.Contains(t, resp.SearchResultList[0].Title, injector.Config.SearchConfig.HighlightPreTag)
+
+	resp, err = searchService.Search(ctx, notice.Title, &scopeNotice, &page, &pageSize)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, resp.SearchResultList)
+	for _, result := range resp.SearchResultList {
+		assert.Equal(t, config.EntityTypeNotice, result.EntityType)
+	}
+
+	resp, err = searchService.Search(ctx, notice.Title, &scopeDoc, &page, &pageSize)
+	assert.NoError(t, err)
+	assert.Empty(t, resp.SearchResultList)
+
+	t.Logf("Response Data: %+v", resp)
+}
@@ -0,0 +1,53 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"fiber-admin/internal/pkg/config"
+	"fiber-admin/internal/pkg/domain/entity"
+	sysservice "fiber-admin/internal/pkg/service/sys/mods"
+	"fiber-admin/test/wire"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTailLogs(t *testing.T) {
+	var (
+		injector          = wire.GetInjector()
+		ctx               = injector.Ctx
+		sysLogTailService = injector.SysLogTailService
+		userID            = primitive.NewObjectID().Hex()
+		status            = config.OperationStatusFailure
+	)
+	events, stop, err := sysLogTailService.Tail(
+		ctx, []string{config.LogTailTypeOperation, config.LogTailTypeRequest},
+		&sysservice.LogTailFilter{UserID: &userID, Status: &status},
+	)
+	assert.NoError(t, err)
+	assert.True(t, sysLogTailService.Tailed(ctx, config.LogTailTypeOperation))
+	assert.True(t, sysLogTailService.Tailed(ctx, config.LogTailTypeRequest))
+
+	sysLogTailService.Publish(
+		ctx, &entity.LogTailEvent{
+			Type: config.LogTailTypeOperation, Time: time.Now(), UserID: userID, Status: config.OperationStatusSuccess,
+		},
+	)
+	sysLogTailService.Publish(
+		ctx, &entity.LogTailEvent{
+			Type: config.LogTailTypeRequest, Time: time.Now(), UserID: userID, Status: status, StatusCode: 500,
+		},
+	)
+	select {
+	case event := <-events:
+		// The successful operation is filtered out
+		assert.Equal(t, config.LogTailTypeRequest, event.Type)
+		assert.Equal(t, 500, event.StatusCode)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no log tail event received")
+	}
+
+	stop()
+	for range events {
+	}
+}
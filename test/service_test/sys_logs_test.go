@@ -2,7 +2,10 @@ package service_test
 
 import (
 	"testing"
+	"time"
 
+	"fiber-admin/internal/pkg/config"
+	"fiber-admin/internal/pkg/config/mods"
 	"fiber-admin/test/mock"
 	"fiber-admin/test/wire"
 	"github.com/stretchr/testify/assert"
@@ -28,3 +31,50 @@ func TestInsertOperationLog(t *testing.T) {
 }
 
 func TestCacheOperationLog(t *testing.T) {}
+
+func TestArchiveExpiredLogs(t *testing.T) {
+	var (
+		injector         = wire.GetInjector()
+		ctx              = injector.Ctx
+		sysLogsService   = injector.SysLogsService
+		adminLogsService = injector.AdminLogsService
+		loginLogDao      = injector.LoginLogDao
+		retention        = injector.Config.RetentionConfig
+	)
+	defer func() {
+		injector.Config.RetentionConfig = retention
+	}()
+	_ = injector.LoginLogDaoMock.GenerateLoginLogModel()
+	_, before, err := loginLogDao.GetLoginLogList(ctx, 0, 1, false, nil, nil, nil, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.NotZero(t, *before)
+
+	// Expire every login log and keep the operation logs, whose chain has no checkpoint yet.
+	injector.Config.RetentionConfig.LoginLog = mods.LogRetentionConfig{MaxAge: time.Nanosecond, Archive: true}
+	injector.Config.RetentionConfig.OperationLog = mods.LogRetentionConfig{}
+	time.Sleep(time.Millisecond)
+	assert.NoError(t, sysLogsService.ArchiveExpiredLogs(ctx))
+	_, after, err := loginLogDao.GetLoginLogList(ctx, 0, 1, false, nil, nil, nil, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Zero(t, *after)
+
+	archives, err := adminLogsService.GetLogArchiveList(ctx)
+	assert.NoError(t, err)
+	assert.NotZero(t, archives.Total)
+	name := archives.LogArchiveList[0].Name
+	assert.Equal(t, config.LoginLogCollectionName, archives.LogArchiveList[0].Collection)
+
+	resp, err := adminLogsService.ImportLogArchive(ctx, &name)
+	assert.NoError(t, err)
+	assert.Equal(t, *before, resp.Total)
+	assert.Equal(t, *before, resp.Imported)
+	_, restored, err := loginLogDao.GetLoginLogList(ctx, 0, 1, false, nil, nil, nil, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, *before, *restored)
+
+	// Importing again skips the logs already present.
+	resp, err = adminLogsService.ImportLogArchive(ctx, &name)
+	assert.NoError(t, err)
+	assert.Zero(t, resp.Imported)
+	_ = injector.ArchiveStore.Remove(ctx, name)
+}
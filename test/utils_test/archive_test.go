@@ -0,0 +1,74 @@
+package utils_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"fiber-admin/pkg/archive"
+	"github.com/stretchr/testify/assert"
+)
+
+type archivedRecord struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestArchive(t *testing.T) {
+	ctx := context.Background()
+	store, err := archive.NewLocalStore(t.TempDir())
+	assert.NoError(t, err)
+
+	name := archive.Name("login_log", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	assert.Equal(t, "login_log-20240102T030405Z.ndjson.gz", name)
+	assert.Equal(t, "login_log", archive.Prefix(name))
+	assert.Equal(t, "", archive.Prefix("login_log.txt"))
+
+	file, err := store.Create(ctx, name)
+	assert.NoError(t, err)
+	writer := archive.NewWriter(file)
+	for i := 1; i <= 3; i++ {
+		assert.NoError(t, writer.Write(archivedRecord{ID: i, Name: "record"}))
+	}
+	list, err := store.List(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, list, "archive visible before it is completely written")
+	assert.NoError(t, writer.Close())
+	assert.Equal(t, int64(3), writer.Count)
+
+	list, err = store.List(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Equal(t, name, list[0].Name)
+	assert.NotZero(t, list[0].Size)
+
+	opened, err := store.Open(ctx, name)
+	assert.NoError(t, err)
+	reader, err := archive.NewReader(opened)
+	assert.NoError(t, err)
+	var records []archivedRecord
+	for {
+		var record archivedRecord
+		if err := reader.Read(&record); err == io.EOF {
+			break
+		} else {
+			assert.NoError(t, err)
+		}
+		records = append(records, record)
+	}
+	assert.NoError(t, reader.Close())
+	assert.Equal(t, []archivedRecord{{1, "record"}, {2, "record"}, {3, "record"}}, records)
+
+	aborted := archive.Name("operation_log", time.Now())
+	file, err = store.Create(ctx, aborted)
+	assert.NoError(t, err)
+	writer = archive.NewWriter(file)
+	assert.NoError(t, writer.Write(archivedRecord{ID: 1}))
+	assert.NoError(t, writer.Abort())
+	_, err = store.Open(ctx, aborted)
+	assert.ErrorIs(t, err, archive.ErrNotFound)
+
+	_, err = store.Open(ctx, "../"+name)
+	assert.ErrorIs(t, err, archive.ErrInvalidName)
+}
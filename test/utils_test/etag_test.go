@@ -0,0 +1,45 @@
+package utils_test
+
+import (
+	"testing"
+
+	"fiber-admin/pkg/utils/etag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestETagParseIfMatch(t *testing.T) {
+	version, err := etag.ParseIfMatch(etag.Format(3))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), *version)
+
+	for _, header := range []string{"", "*"} {
+		version, err = etag.ParseIfMatch(header)
+		assert.NoError(t, err)
+		assert.Nil(t, version)
+	}
+	for _, header := range []string{`W/"3"`, `"3", "4"`, `3`, `"abc"`, `"-1"`} {
+		_, err = etag.ParseIfMatch(header)
+		assert.Error(t, err, header)
+	}
+}
+
+func TestETagNoneMatch(t *testing.T) {
+	tag := etag.Format(3)
+	assert.True(t, etag.NoneMatch("", tag))
+	assert.True(t, etag.NoneMatch(`"2"`, tag))
+	assert.False(t, etag.NoneMatch("*", tag))
+	assert.False(t, etag.NoneMatch(`"3"`, tag))
+	assert.False(t, etag.NoneMatch(`"1", W/"3"`, tag))
+}
+
+func TestETagFormatRepresentation(t *testing.T) {
+	tag := etag.FormatRepresentation(3, "html")
+	assert.Equal(t, `"3-html"`, tag)
+	assert.NotEqual(t, etag.Format(3), tag)
+	assert.True(t, etag.NoneMatch(etag.Format(3), tag))
+	assert.False(t, etag.NoneMatch(tag, tag))
+	assert.True(t, etag.NoneMatch(tag, etag.Format(3)))
+
+	_, err := etag.ParseIfMatch(tag)
+	assert.Error(t, err)
+}
@@ -0,0 +1,160 @@
+package utils_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"net"
+	"testing"
+
+	"fiber-admin/pkg/geoip"
+	"github.com/stretchr/testify/assert"
+)
+
+// mmdb encodes the MaxMind DB data types the test database needs.
+type mmdb struct {
+	bytes.Buffer
+}
+
+func (m *mmdb) str(s string) {
+	m.WriteByte(2<<5 | byte(len(s)))
+	m.WriteString(s)
+}
+
+func (m *mmdb) double(f float64) {
+	m.WriteByte(3<<5 | 8)
+	_ = binary.Write(m, binary.BigEndian, math.Float64bits(f))
+}
+
+func (m *mmdb) uint16(v uint16) {
+	m.WriteByte(5<<5 | 2)
+	_ = binary.Write(m, binary.BigEndian, v)
+}
+
+func (m *mmdb) uint32(v uint32) {
+	m.WriteByte(6<<5 | 4)
+	_ = binary.Write(m, binary.BigEndian, v)
+}
+
+func (m *mmdb) mapOf(n int) {
+	m.WriteByte(7<<5 | byte(n))
+}
+
+// newTestDatabase builds an IPv4 database locating 1.2.3.0/24 in Paris.
+func newTestDatabase() []byte {
+	var data mmdb
+	data.mapOf(3)
+	data.str("country")
+	data.mapOf(2)
+	data.str("iso_code")
+	data.str("FR")
+	data.str("names")
+	data.mapOf(1)
+	data.str("en")
+	data.str("France")
+	data.str("city")
+	data.mapOf(1)
+	data.str("names")
+	data.mapOf(1)
+	data.str("en")
+	data.str("Paris")
+	data.str("location")
+	data.mapOf(2)
+	data.str("latitude")
+	data.double(48.8566)
+	data.str("longitude")
+	data.double(2.3522)
+	return newTestDatabaseOf(data.Bytes())
+}
+
+// newTestDatabaseOf builds an IPv4 database with data as the record of 1.2.3.0/24.
+func newTestDatabaseOf(data []byte) []byte {
+	const prefixBits = 24
+	prefix := []byte{1, 2, 3}
+	nodeCount := uint32(prefixBits)
+
+	var tree bytes.Buffer
+	for i := 0; i < prefixBits; i++ {
+		next := uint32(i + 1)
+		if i == prefixBits-1 {
+			next = nodeCount + 16 // The record at the start of the data section
+		}
+		left, right := nodeCount, nodeCount // Not found
+		if prefix[i/8]>>(7-uint(i%8))&1 == 0 {
+			left = next
+		} else {
+			right = next
+		}
+		tree.Write([]byte{byte(left >> 16), byte(left >> 8), byte(left)})
+		tree.Write([]byte{byte(right >> 16), byte(right >> 8), byte(right)})
+	}
+
+	var metadata mmdb
+	metadata.mapOf(3)
+	metadata.str("node_count")
+	metadata.uint32(nodeCount)
+	metadata.str("record_size")
+	metadata.uint16(24)
+	metadata.str("ip_version")
+	metadata.uint16(4)
+
+	var file bytes.Buffer
+	file.Write(tree.Bytes())
+	file.Write(make([]byte, 16))
+	file.Write(data)
+	file.WriteString("\xAB\xCD\xEFMaxMind.com")
+	file.Write(metadata.Bytes())
+	return file.Bytes()
+}
+
+func TestGeoIP(t *testing.T) {
+	reader, err := geoip.New(newTestDatabase())
+	assert.NoError(t, err)
+
+	location, err := reader.Lookup(net.ParseIP("1.2.3.4"))
+	assert.NoError(t, err)
+	if assert.NotNil(t, location) {
+		assert.Equal(t, "FR", location.CountryCode)
+		assert.Equal(t, "France", location.Country)
+		assert.Equal(t, "Paris", location.City)
+		assert.InDelta(t, 48.8566, *location.Latitude, 1e-9)
+		assert.InDelta(t, 2.3522, *location.Longitude, 1e-9)
+	}
+
+	location, err = reader.Lookup(net.ParseIP("1.2.4.4"))
+	assert.NoError(t, err)
+	assert.Nil(t, location)
+
+	location, err = reader.Lookup(net.ParseIP("2001:db8::1"))
+	assert.NoError(t, err)
+	assert.Nil(t, location)
+
+	_, err = geoip.New([]byte("not a database"))
+	assert.ErrorIs(t, err, geoip.ErrInvalidDatabase)
+}
+
+func TestGeoIPCorruptData(t *testing.T) {
+	// A map whose value points back at the map
+	var loop mmdb
+	loop.mapOf(1)
+	loop.str("city")
+	loop.WriteString("\x20\x00") // Pointer to offset 0
+	reader, err := geoip.New(newTestDatabaseOf(loop.Bytes()))
+	assert.NoError(t, err)
+	_, err = reader.Lookup(net.ParseIP("1.2.3.4"))
+	assert.Error(t, err)
+
+	// A pointer to a pointer
+	reader, err = geoip.New(newTestDatabaseOf([]byte("\x20\x02\x20\x00")))
+	assert.NoError(t, err)
+	_, err = reader.Lookup(net.ParseIP("1.2.3.4"))
+	assert.Error(t, err)
+
+	// A map and an array claiming more entries than there are bytes left
+	for _, data := range []string{"\xFF\xFF\xFF\xFF", "\x1F\x04\xFF\xFF\xFF"} {
+		reader, err = geoip.New(newTestDatabaseOf([]byte(data)))
+		assert.NoError(t, err)
+		_, err = reader.Lookup(net.ParseIP("1.2.3.4"))
+		assert.ErrorContains(t, err, "exceeds the data section")
+	}
+}
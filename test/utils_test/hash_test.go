@@ -21,3 +21,11 @@ func TestHash(t *testing.T) {
 
 	t.Logf("Hash: %s", pwd)
 }
+
+func TestSHA256(t *testing.T) {
+	assert.Equal(t, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", crypt.SHA256("abc"))
+	assert.Equal(
+		t, "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8",
+		crypt.HMACSHA256("key", "The quick brown fox jumps over the lazy dog"),
+	)
+}
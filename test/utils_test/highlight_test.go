@@ -0,0 +1,37 @@
+package utils_test
+
+import (
+	"testing"
+
+	"fiber-admin/pkg/utils/highlight"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHighlightTerms(t *testing.T) {
+	assert.Equal(t, []string{"foo", "bar"}, highlight.Terms(`foo -qux "bar" FOO`))
+	assert.Empty(t, highlight.Terms("  -foo "))
+}
+
+func TestHighlight(t *testing.T) {
+	assert.Equal(
+		t, "<mark>Fiber</mark> admin &amp; <mark>fiber</mark>s",
+		highlight.Highlight("Fiber admin & fibers", []string{"fiber"}, "<mark>", "</mark>"),
+	)
+	assert.Equal(
+		t, "&lt;b&gt;<mark>fooba</mark>r&lt;/b&gt;",
+		highlight.Highlight("<b>foobar</b>", []string{"foo", "oba"}, "<mark>", "</mark>"),
+	)
+	assert.Equal(t, "文档<mark>搜索</mark>", highlight.Highlight("文档搜索", []string{"搜索"}, "<mark>", "</mark>"))
+}
+
+func TestSnippet(t *testing.T) {
+	text := "The quick brown fox jumps over the lazy dog while the cat sleeps"
+
+	snippet := highlight.Snippet(text, []string{"lazy"}, 20, "[", "]")
+	assert.Contains(t, snippet, "[lazy]")
+	assert.True(t, len([]rune(snippet)) <= 20+2+2) // excerpt + tags + ellipses
+	assert.Equal(t, "…", string([]rune(snippet)[0]))
+
+	assert.Equal(t, "The quick…", highlight.Snippet(text, []string{"absent"}, 9, "[", "]"))
+	assert.Equal(t, "short [text]", highlight.Snippet("short text", []string{"text"}, 100, "[", "]"))
+}
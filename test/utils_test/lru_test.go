@@ -0,0 +1,50 @@
+package utils_test
+
+import (
+	"testing"
+	"time"
+
+	"fiber-admin/pkg/lru"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	cache := lru.New[string, int](2)
+	cache.Set("a", 1, time.Minute)
+	cache.Set("b", 2, time.Minute)
+	// Reading a makes b the least recently used entry
+	value, ok := cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+	cache.Set("c", 3, time.Minute)
+	assert.Equal(t, 2, cache.Len())
+	_, ok = cache.Get("b")
+	assert.False(t, ok)
+	_, ok = cache.Get("c")
+	assert.True(t, ok)
+
+	cache.Set("a", 4, time.Minute)
+	value, _ = cache.Get("a")
+	assert.Equal(t, 4, value)
+
+	cache.Remove("a")
+	_, ok = cache.Get("a")
+	assert.False(t, ok)
+	cache.Purge()
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestLRUExpiry(t *testing.T) {
+	cache := lru.New[string, int](2)
+	cache.Set("a", 1, 50*time.Millisecond)
+	cache.Set("b", 2, 0) // Not cached
+	_, ok := cache.Get("a")
+	assert.True(t, ok)
+	_, ok = cache.Get("b")
+	assert.False(t, ok)
+
+	time.Sleep(100 * time.Millisecond)
+	_, ok = cache.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, cache.Len())
+}
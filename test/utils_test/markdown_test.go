@@ -0,0 +1,46 @@
+package utils_test
+
+import (
+	"testing"
+
+	"fiber-admin/pkg/markdown"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownRender(t *testing.T) {
+	md := markdown.New("github", 2)
+
+	result, err := md.Render(
+		"# Guide\n\n## Install\n\n### Details\n\n## Usage\n\n" +
+			"| a | b |\n|:--|--:|\n| 1 | 2 |\n\n- [x] done\n- [ ] todo\n\n~~old~~ https://example.com\n\n" +
+			"```go\nfunc main() {}\n```\n",
+	)
+	assert.NoError(t, err)
+	assert.Contains(t, result.HTML, `<h1 id="guide">Guide</h1>`)
+	assert.Contains(t, result.HTML, "<table>")
+	assert.Contains(t, result.HTML, `type="checkbox"`)
+	assert.Contains(t, result.HTML, "<del>old</del>")
+	assert.Contains(t, result.HTML, `href="https://example.com"`)
+	assert.Contains(t, result.HTML, "style=")
+
+	// Headings deeper than the configured depth are left out of the table of contents.
+	assert.Len(t, result.TOC, 1)
+	assert.Equal(t, "guide", result.TOC[0].ID)
+	assert.Len(t, result.TOC[0].Children, 2)
+	assert.Equal(t, "Install", result.TOC[0].Children[0].Title)
+	assert.Empty(t, result.TOC[0].Children[0].Children)
+	assert.Equal(t, "usage", result.TOC[0].Children[1].ID)
+}
+
+func TestMarkdownSanitize(t *testing.T) {
+	md := markdown.New("github", 3)
+
+	result, err := md.Render(
+		"<script>alert(1)</script>\n\n[link](javascript:alert(1))\n\n<img src=x onerror=alert(1)>",
+	)
+	assert.NoError(t, err)
+	assert.NotContains(t, result.HTML, "<script")
+	assert.NotContains(t, result.HTML, "javascript:")
+	assert.NotContains(t, result.HTML, "onerror")
+	assert.Empty(t, result.TOC)
+}
@@ -0,0 +1,46 @@
+package utils_test
+
+import (
+	"testing"
+
+	"fiber-admin/pkg/redact"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactJSON(t *testing.T) {
+	redactor := redact.New([]string{"password", "data.access_token", "items.*.secret", "codes.*"}, nil)
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`{"email":"a@b.c","password":"hunter2"}`, `{"email":"a@b.c","password":"[REDACTED]"}`},
+		// Names match at any depth and regardless of case
+		{`{"user":{"Password":"hunter2","id":1}}`, `{"user":{"Password":"[REDACTED]","id":1}}`},
+		// Paths only match from the root
+		{
+			`{"code":0,"data":{"access_token":"jwt","expires_in":3600}}`,
+			`{"code":0,"data":{"access_token":"[REDACTED]","expires_in":3600}}`,
+		},
+		{`{"access_token":"jwt"}`, `{"access_token":"jwt"}`},
+		{
+			`{"items":[{"name":"a","secret":"s1"},{"name":"b","secret":"s2"}]}`,
+			`{"items":[{"name":"a","secret":"[REDACTED]"},{"name":"b","secret":"[REDACTED]"}]}`,
+		},
+		{`{"codes":["1","2"],"n":12345678901234567890}`, `{"codes":["[REDACTED]","[REDACTED]"],"n":12345678901234567890}`},
+		// Bodies without redacted fields or which are not JSON are left untouched
+		{`{ "email": "a@b.c" }`, `{ "email": "a@b.c" }`},
+		{`password=hunter2`, `password=hunter2`},
+		{`{"password":`, `{"password":`},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, string(redactor.JSON([]byte(test.body))), test.body)
+	}
+}
+
+func TestRedactFieldsAndHeaders(t *testing.T) {
+	redactor := redact.New([]string{"password", "data.token"}, []string{"Authorization"})
+	assert.True(t, redactor.Field("PASSWORD"))
+	assert.False(t, redactor.Field("token"))
+	assert.True(t, redactor.Header("authorization"))
+	assert.False(t, redactor.Header("Content-Type"))
+}
@@ -0,0 +1,51 @@
+package utils_test
+
+import (
+	"testing"
+
+	"fiber-admin/pkg/useragent"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUserAgent(t *testing.T) {
+	tests := []struct {
+		ua   string
+		want useragent.Agent
+	}{
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			useragent.Agent{Browser: "Chrome", BrowserVersion: "120.0.0.0", OS: "Windows", OSVersion: "10", Device: useragent.DeviceDesktop},
+		},
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91",
+			useragent.Agent{Browser: "Edge", BrowserVersion: "120.0.2210.91", OS: "Windows", OSVersion: "10", Device: useragent.DeviceDesktop},
+		},
+		{
+			"Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1",
+			useragent.Agent{Browser: "Safari", BrowserVersion: "17.2", OS: "iOS", OSVersion: "17.2", Device: useragent.DeviceMobile},
+		},
+		{
+			"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15",
+			useragent.Agent{Browser: "Safari", BrowserVersion: "17.1", OS: "macOS", OSVersion: "10.15.7", Device: useragent.DeviceDesktop},
+		},
+		{
+			"Mozilla/5.0 (Linux; Android 14; SM-X710) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			useragent.Agent{Browser: "Chrome", BrowserVersion: "120.0.0.0", OS: "Android", OSVersion: "14", Device: useragent.DeviceTablet},
+		},
+		{
+			"Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0",
+			useragent.Agent{Browser: "Firefox", BrowserVersion: "121.0", OS: "Linux", Device: useragent.DeviceDesktop},
+		},
+		{
+			"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			useragent.Agent{Device: useragent.DeviceBot},
+		},
+		{
+			"curl/8.4.0",
+			useragent.Agent{Browser: "curl", BrowserVersion: "8.4.0", Device: useragent.DeviceOther},
+		},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, useragent.Parse(test.ua), test.ua)
+	}
+}
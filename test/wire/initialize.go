@@ -5,9 +5,14 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
+	"fmt"
 
 	"fiber-admin/internal/pkg/config"
+	"fiber-admin/internal/pkg/dao"
+	"fiber-admin/pkg/archive"
+	"fiber-admin/pkg/geoip"
 	"fiber-admin/pkg/jwt"
+	"fiber-admin/pkg/markdown"
 	"fiber-admin/pkg/mongo"
 	"fiber-admin/pkg/prometheus"
 	"fiber-admin/pkg/redis"
@@ -27,13 +32,21 @@ func InitializeMongo(ctx context.Context, config *config.Config) (*mongo.Mongo,
 	return m, nil
 }
 
-// InitializeRedis initializes redis injection with context and config.
-func InitializeRedis(ctx context.Context, config *config.Config) (*redis.Redis, error) {
-	r, err := redis.New(ctx, config.CacheConfig.RedisConfig.GetRedisOptions())
-	if err != nil {
-		return nil, err
+// InitializeCacheBackend initializes the cache backend selected by config, connecting to Redis unless the cache is in
+// memory.
+func InitializeCacheBackend(ctx context.Context, cfg *config.Config) (dao.CacheBackend, error) {
+	switch cfg.CacheConfig.Backend {
+	case config.CacheBackendMemory:
+		return dao.NewMemoryCacheBackend(), nil
+	case config.CacheBackendRedis, "":
+		r, err := redis.New(ctx, cfg.CacheConfig.RedisConfig.GetRedisOptions())
+		if err != nil {
+			return nil, err
+		}
+		return dao.NewRedisCacheBackend(r), nil
+	default:
+		return nil, fmt.Errorf("unknown cache backend %q", cfg.CacheConfig.Backend)
 	}
-	return r, nil
 }
 
 // InitializeZap initializes zap logger injection with config.
@@ -67,6 +80,24 @@ func InitializePrometheus(config *config.Config) *prometheus.Prometheus {
 	)
 }
 
+// InitializeMarkdown initializes markdown renderer injection with config.
+func InitializeMarkdown(config *config.Config) *markdown.Markdown {
+	return markdown.New(config.MarkdownConfig.HighlightStyle, config.MarkdownConfig.TOCMaxDepth)
+}
+
+// InitializeArchiveStore initializes the log archive store injection with config.
+func InitializeArchiveStore(config *config.Config) (archive.Store, error) {
+	return archive.NewLocalStore(config.RetentionConfig.ArchiveDir)
+}
+
+// InitializeGeoIP initializes the GeoIP database injection with config. It is nil when no database is configured.
+func InitializeGeoIP(config *config.Config) (*geoip.Reader, error) {
+	if config.LoginAnalyticsConfig.GeoIPDatabase == "" {
+		return nil, nil
+	}
+	return geoip.Open(config.LoginAnalyticsConfig.GeoIPDatabase)
+}
+
 // InitializeCasbinEnforcer initializes casbin enforcer injection with config.
 func InitializeCasbinEnforcer(config *config.Config) (*casbin.Enforcer, error) {
 	adapter, err := mongodbadapter.NewAdapter(config.CasbinConfig.PolicyAdapterUrl)
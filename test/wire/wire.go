@@ -16,10 +16,10 @@ import (
 	commonservices "fiber-admin/internal/pkg/service/common/mods"
 	sysservice "fiber-admin/internal/pkg/service/sys"
 	sysservices "fiber-admin/internal/pkg/service/sys/mods"
+	"fiber-admin/pkg/archive"
 	"fiber-admin/pkg/jwt"
 	"fiber-admin/pkg/mongo"
 	"fiber-admin/pkg/prometheus"
-	"fiber-admin/pkg/redis"
 	logging "fiber-admin/pkg/zap"
 	"fiber-admin/test/mock"
 	"github.com/casbin/casbin/v2"
@@ -28,21 +28,25 @@ import (
 
 type Injector struct {
 	// Common
-	Ctx        context.Context
-	Config     *config.Config
-	Cache      *dao.Cache
-	Mongo      *mongo.Mongo
-	Redis      *redis.Redis
-	Zap        *logging.Zap
-	Jwt        *jwt.Jwt
-	Prometheus *prometheus.Prometheus
+	Ctx          context.Context
+	Config       *config.Config
+	Cache        *dao.Cache
+	Mongo        *mongo.Mongo
+	CacheBackend dao.CacheBackend
+	Zap          *logging.Zap
+	Jwt          *jwt.Jwt
+	Prometheus   *prometheus.Prometheus
+	ArchiveStore archive.Store
 
 	// DAOs
-	UserDao          daos.UserDao
-	NoticeDao        daos.NoticeDao
-	DocumentationDao daos.DocumentationDao
-	LoginLogDao      daos.LoginLogDao
-	OperationLogDao  daos.OperationLogDao
+	UserDao                  daos.UserDao
+	NoticeDao                daos.NoticeDao
+	DocumentationDao         daos.DocumentationDao
+	DocumentationRevisionDao daos.DocumentationRevisionDao
+	LoginLogDao              daos.LoginLogDao
+	OperationLogDao          daos.OperationLogDao
+	StatsDao                 daos.StatsDao
+	RateLimitOverrideDao     daos.RateLimitOverrideDao
 
 	// Mocks for DAOs
 	UserDaoMock          *mock.UserDaoMock
@@ -56,6 +60,8 @@ type Injector struct {
 	AdminDocumentationService adminservices.DocumentationService
 	AdminNoticeService        adminservices.NoticeService
 	AdminLogsService          adminservices.LogsService
+	AdminStatsService         adminservices.StatsService
+	AdminCacheService         adminservices.CacheService
 	AdminUserService          adminservices.UserService
 	// Common services
 	CommonAuthService          commonservices.AuthService
@@ -63,8 +69,11 @@ type Injector struct {
 	CommonDocumentationService commonservices.DocumentationService
 	CommonNoticeService        commonservices.NoticeService
 	CommonProfileService       commonservices.ProfileService
+	CommonSearchService        commonservices.SearchService
 	// Sys services
-	SysLogsService sysservices.LogsService
+	SysLogsService      sysservices.LogsService
+	SysLogTailService   sysservices.LogTailService
+	SysRateLimitService sysservices.RateLimitService
 
 	// Casbin enforcer
 	Enforcer *casbin.Enforcer
@@ -80,12 +89,17 @@ var (
 		adminservices.NewNoticeService,
 		adminservices.NewDocumentationService,
 		adminservices.NewLogsService,
+		adminservices.NewStatsService,
+		adminservices.NewCacheService,
 		commonservices.NewAuthService,
 		commonservices.NewProfileService,
 		commonservices.NewDocumentationService,
 		commonservices.NewNoticeService,
 		commonservices.NewIdempotencyService,
+		commonservices.NewSearchService,
 		sysservices.NewLogsService,
+		sysservices.NewLogTailService,
+		sysservices.NewRateLimitService,
 	)
 
 	DaoProviderSet = wire.NewSet(
@@ -96,6 +110,9 @@ var (
 		daos.NewLoginLogDao,
 		daos.NewOperationLogDao,
 		daos.NewDocumentationDao,
+		daos.NewDocumentationRevisionDao,
+		daos.NewStatsDao,
+		daos.NewRateLimitOverrideDao,
 	)
 
 	MockProviderSet = wire.NewSet(
@@ -110,10 +127,13 @@ var (
 func InitializeTestInjector(ctx context.Context, config *config.Config, n int) (*Injector, error) {
 	wire.Build(
 		InitializeMongo,
-		InitializeRedis,
+		InitializeCacheBackend,
 		InitializeZap,
 		InitializeJwt,
 		InitializePrometheus,
+		InitializeMarkdown,
+		InitializeArchiveStore,
+		InitializeGeoIP,
 		InitializeCasbinEnforcer,
 		MockProviderSet,
 		ServiceProviderSet,
@@ -18,10 +18,10 @@ import (
 	mods3 "fiber-admin/internal/pkg/service/common/mods"
 	"fiber-admin/internal/pkg/service/sys"
 	mods4 "fiber-admin/internal/pkg/service/sys/mods"
+	"fiber-admin/pkg/archive"
 	"fiber-admin/pkg/jwt"
 	"fiber-admin/pkg/mongo"
 	"fiber-admin/pkg/prometheus"
-	"fiber-admin/pkg/redis"
 	"fiber-admin/pkg/zap"
 	"fiber-admin/test/mock"
 	"github.com/casbin/casbin/v2"
@@ -31,11 +31,12 @@ import (
 // Injectors from wire.go:
 
 func InitializeTestInjector(ctx context.Context, config2 *config.Config, n int) (*Injector, error) {
-	redis, err := InitializeRedis(ctx, config2)
+	cacheBackend, err := InitializeCacheBackend(ctx, config2)
 	if err != nil {
 		return nil, err
 	}
-	cache := dao.NewCache(redis, config2)
+	prometheus := InitializePrometheus(config2)
+	cache := dao.NewCache(cacheBackend, config2, prometheus)
 	mongo, err := InitializeMongo(ctx, config2)
 	if err != nil {
 		return nil, err
@@ -48,7 +49,6 @@ func InitializeTestInjector(ctx context.Context, config2 *config.Config, n int)
 	if err != nil {
 		return nil, err
 	}
-	prometheus := InitializePrometheus(config2)
 	core, err := dao.NewCore(ctx, mongo, zap, config2)
 	if err != nil {
 		return nil, err
@@ -65,7 +65,15 @@ func InitializeTestInjector(ctx context.Context, config2 *config.Config, n int)
 	if err != nil {
 		return nil, err
 	}
-	loginLogDao, err := mods.NewLoginLogDao(ctx, core, cache, userDao)
+	documentationRevisionDao, err := mods.NewDocumentationRevisionDao(ctx, core)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := InitializeGeoIP(config2)
+	if err != nil {
+		return nil, err
+	}
+	loginLogDao, err := mods.NewLoginLogDao(ctx, core, cache, userDao, reader)
 	if err != nil {
 		return nil, err
 	}
@@ -82,9 +90,13 @@ func InitializeTestInjector(ctx context.Context, config2 *config.Config, n int)
 	if err != nil {
 		return nil, err
 	}
-	documentationService := mods2.NewDocumentationService(serviceCore, documentationDao)
+	documentationService := mods2.NewDocumentationService(serviceCore, documentationDao, documentationRevisionDao)
 	noticeService := mods2.NewNoticeService(serviceCore, noticeDao)
-	logsService := mods2.NewLogsService(serviceCore, loginLogDao, operationLogDao)
+	store, err := InitializeArchiveStore(config2)
+	if err != nil {
+		return nil, err
+	}
+	logsService := mods2.NewLogsService(serviceCore, loginLogDao, operationLogDao, store)
 	enforcer, err := InitializeCasbinEnforcer(config2)
 	if err != nil {
 		return nil, err
@@ -92,24 +104,36 @@ func InitializeTestInjector(ctx context.Context, config2 *config.Config, n int)
 	userService := mods2.NewUserService(serviceCore, userDao, enforcer)
 	authService := mods3.NewAuthService(serviceCore, userDao, cache, jwt)
 	idempotencyService := mods3.NewIdempotencyService(serviceCore, cache)
-	modsDocumentationService := mods3.NewDocumentationService(serviceCore, documentationDao)
-	modsNoticeService := mods3.NewNoticeService(serviceCore, noticeDao)
+	markdown := InitializeMarkdown(config2)
+	modsDocumentationService := mods3.NewDocumentationService(serviceCore, documentationDao, cache, markdown)
+	modsNoticeService := mods3.NewNoticeService(serviceCore, noticeDao, cache, markdown)
 	profileService := mods3.NewProfileService(serviceCore, userDao)
-	modsLogsService := mods4.NewLogsService(serviceCore, loginLogDao, operationLogDao)
+	searchService := mods3.NewSearchService(serviceCore, documentationDao, noticeDao)
+	statsDao := mods.NewStatsDao(core, cache)
+	statsService := mods2.NewStatsService(serviceCore, statsDao)
+	cacheService := mods2.NewCacheService(serviceCore, cache, noticeDao, documentationDao)
+	modsLogsService := mods4.NewLogsService(serviceCore, loginLogDao, operationLogDao, store)
+	logTailService := mods4.NewLogTailService(serviceCore, cache)
+	rateLimitOverrideDao := mods.NewRateLimitOverrideDao(core, cache)
+	rateLimitService := mods4.NewRateLimitService(serviceCore, cache, rateLimitOverrideDao)
 	wireInjector := &Injector{
 		Ctx:                        ctx,
 		Config:                     config2,
 		Cache:                      cache,
 		Mongo:                      mongo,
-		Redis:                      redis,
+		CacheBackend:               cacheBackend,
 		Zap:                        zap,
 		Jwt:                        jwt,
 		Prometheus:                 prometheus,
+		ArchiveStore:               store,
 		UserDao:                    userDao,
 		NoticeDao:                  noticeDao,
 		DocumentationDao:           documentationDao,
+		DocumentationRevisionDao:   documentationRevisionDao,
 		LoginLogDao:                loginLogDao,
 		OperationLogDao:            operationLogDao,
+		StatsDao:                   statsDao,
+		RateLimitOverrideDao:       rateLimitOverrideDao,
 		UserDaoMock:                userDaoMock,
 		NoticeDaoMock:              noticeDaoMock,
 		DocumentationDaoMock:       documentationDaoMock,
@@ -118,13 +142,18 @@ func InitializeTestInjector(ctx context.Context, config2 *config.Config, n int)
 		AdminDocumentationService:  documentationService,
 		AdminNoticeService:         noticeService,
 		AdminLogsService:           logsService,
+		AdminStatsService:          statsService,
+		AdminCacheService:          cacheService,
 		AdminUserService:           userService,
 		CommonAuthService:          authService,
 		CommonIdempotencyService:   idempotencyService,
 		CommonDocumentationService: modsDocumentationService,
 		CommonNoticeService:        modsNoticeService,
 		CommonProfileService:       profileService,
+		CommonSearchService:        searchService,
 		SysLogsService:             modsLogsService,
+		SysLogTailService:          logTailService,
+		SysRateLimitService:        rateLimitService,
 		Enforcer:                   enforcer,
 	}
 	return wireInjector, nil
@@ -134,21 +163,25 @@ func InitializeTestInjector(ctx context.Context, config2 *config.Config, n int)
 
 type Injector struct {
 	// Common
-	Ctx        context.Context
-	Config     *config.Config
-	Cache      *dao.Cache
-	Mongo      *mongo.Mongo
-	Redis      *redis.Redis
-	Zap        *zap.Zap
-	Jwt        *jwt.Jwt
-	Prometheus *prometheus.Prometheus
+	Ctx          context.Context
+	Config       *config.Config
+	Cache        *dao.Cache
+	Mongo        *mongo.Mongo
+	CacheBackend dao.CacheBackend
+	Zap          *zap.Zap
+	Jwt          *jwt.Jwt
+	Prometheus   *prometheus.Prometheus
+	ArchiveStore archive.Store
 
 	// DAOs
-	UserDao          mods.UserDao
-	NoticeDao        mods.NoticeDao
-	DocumentationDao mods.DocumentationDao
-	LoginLogDao      mods.LoginLogDao
-	OperationLogDao  mods.OperationLogDao
+	UserDao                  mods.UserDao
+	NoticeDao                mods.NoticeDao
+	DocumentationDao         mods.DocumentationDao
+	DocumentationRevisionDao mods.DocumentationRevisionDao
+	LoginLogDao              mods.LoginLogDao
+	OperationLogDao          mods.OperationLogDao
+	StatsDao                 mods.StatsDao
+	RateLimitOverrideDao     mods.RateLimitOverrideDao
 
 	// Mocks for DAOs
 	UserDaoMock          *mock.UserDaoMock
@@ -162,6 +195,8 @@ type Injector struct {
 	AdminDocumentationService mods2.DocumentationService
 	AdminNoticeService        mods2.NoticeService
 	AdminLogsService          mods2.LogsService
+	AdminStatsService         mods2.StatsService
+	AdminCacheService         mods2.CacheService
 	AdminUserService          mods2.UserService
 	// Common services
 	CommonAuthService          mods3.AuthService
@@ -169,17 +204,20 @@ type Injector struct {
 	CommonDocumentationService mods3.DocumentationService
 	CommonNoticeService        mods3.NoticeService
 	CommonProfileService       mods3.ProfileService
+	CommonSearchService        mods3.SearchService
 	// Sys services
-	SysLogsService mods4.LogsService
+	SysLogsService      mods4.LogsService
+	SysLogTailService   mods4.LogTailService
+	SysRateLimitService mods4.RateLimitService
 
 	// Casbin enforcer
 	Enforcer *casbin.Enforcer
 }
 
 var (
-	ServiceProviderSet = wire.NewSet(service.NewCore, wire.Struct(new(admin.Admin), "*"), wire.Struct(new(common.Common), "*"), wire.Struct(new(sys.Sys), "*"), mods2.NewUserService, mods2.NewNoticeService, mods2.NewDocumentationService, mods2.NewLogsService, mods3.NewAuthService, mods3.NewProfileService, mods3.NewDocumentationService, mods3.NewNoticeService, mods3.NewIdempotencyService, mods4.NewLogsService)
+	ServiceProviderSet = wire.NewSet(service.NewCore, wire.Struct(new(admin.Admin), "*"), wire.Struct(new(common.Common), "*"), wire.Struct(new(sys.Sys), "*"), mods2.NewUserService, mods2.NewNoticeService, mods2.NewDocumentationService, mods2.NewLogsService, mods2.NewStatsService, mods2.NewCacheService, mods3.NewAuthService, mods3.NewProfileService, mods3.NewDocumentationService, mods3.NewNoticeService, mods3.NewIdempotencyService, mods3.NewSearchService, mods4.NewLogsService, mods4.NewLogTailService, mods4.NewRateLimitService)
 
-	DaoProviderSet = wire.NewSet(dao.NewCore, dao.NewCache, mods.NewUserDao, mods.NewNoticeDao, mods.NewLoginLogDao, mods.NewOperationLogDao, mods.NewDocumentationDao)
+	DaoProviderSet = wire.NewSet(dao.NewCore, dao.NewCache, mods.NewUserDao, mods.NewNoticeDao, mods.NewLoginLogDao, mods.NewOperationLogDao, mods.NewDocumentationDao, mods.NewDocumentationRevisionDao, mods.NewStatsDao, mods.NewRateLimitOverrideDao)
 
 	MockProviderSet = wire.NewSet(mock.NewUserDaoMockWithRandomData, mock.NewNoticeDaoMockWithRandomData, mock.NewLoginLogDaoMockWithRandomData, mock.NewOperationLogDaoMockWithRandomData, mock.NewDocumentationDaoMockWithRandomData)
 )